@@ -2,17 +2,24 @@ package core
 
 import (
 	"encoding/json"
+	"errors"
 	"github.com/gomodule/redigo/redis"
 	"github.com/mrwangjinjin/go-wechat/pkg/util"
 	"time"
 )
 
+// ErrCacheMiss 缓存不存在
+var ErrCacheMiss = errors.New("缓存不存在")
+
 // Cache
 type Cache interface {
 	Set(key string, val interface{}) error
 	SetEx(key string, val interface{}, expires int64) error
+	// SetNX 仅当key不存在时写入并设置过期时间, 返回是否写入成功
+	SetNX(key string, val interface{}, expires int64) (bool, error)
 	Get(key string) (string, error)
 	Exists(key string) bool
+	Delete(key string) error
 }
 
 // CacheConfig
@@ -98,6 +105,25 @@ func (self *CacheDefault) SetEx(key string, val interface{}, expires int64) erro
 	return nil
 }
 
+func (self *CacheDefault) SetNX(key string, val interface{}, expires int64) (bool, error) {
+	conn := self.redis.Get()
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	value, err := json.Marshal(val)
+	if err != nil {
+		return false, err
+	}
+
+	reply, err := conn.Do("SET", key, util.Base64Encoding(value), "EX", expires, "NX")
+	if err != nil {
+		return false, err
+	}
+
+	return reply != nil, nil
+}
+
 func (self *CacheDefault) Get(key string) (reply string, err error) {
 	conn := self.redis.Get()
 	defer func() {
@@ -105,6 +131,9 @@ func (self *CacheDefault) Get(key string) (reply string, err error) {
 	}()
 
 	result, err := redis.Bytes(conn.Do("GET", key))
+	if err == redis.ErrNil {
+		return "", ErrCacheMiss
+	}
 	if err != nil {
 		return "", err
 	}
@@ -125,3 +154,13 @@ func (self *CacheDefault) Exists(key string) bool {
 
 	return exists
 }
+
+func (self *CacheDefault) Delete(key string) error {
+	conn := self.redis.Get()
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	_, err := conn.Do("DEL", key)
+	return err
+}
@@ -0,0 +1,140 @@
+package lru
+
+import (
+	"container/list"
+	"encoding/json"
+	"github.com/mrwangjinjin/go-wechat/core"
+	"sync"
+	"time"
+)
+
+type entry struct {
+	key      string
+	value    string
+	expireAt time.Time
+}
+
+func (self *entry) expired(now time.Time) bool {
+	return !self.expireAt.IsZero() && !now.Before(self.expireAt)
+}
+
+// LRUCache 进程内LRU缓存, 超出容量时淘汰最久未使用的key, 适用于单机部署
+type LRUCache struct {
+	mu         sync.Mutex
+	maxEntries int
+	ll         *list.List
+	items      map[string]*list.Element
+}
+
+// NewLRUCache 创建LRU缓存, maxEntries小于等于0时不限制容量
+func NewLRUCache(maxEntries int) *LRUCache {
+	return &LRUCache{
+		maxEntries: maxEntries,
+		ll:         list.New(),
+		items:      map[string]*list.Element{},
+	}
+}
+
+var _ core.Cache = (*LRUCache)(nil)
+
+func (self *LRUCache) Set(key string, val interface{}) error {
+	return self.SetEx(key, val, 0)
+}
+
+// SetEx 写入并设置过期时间(秒), expires为0时不过期
+func (self *LRUCache) SetEx(key string, val interface{}, expires int64) error {
+	value, err := json.Marshal(val)
+	if err != nil {
+		return err
+	}
+	self.mu.Lock()
+	defer self.mu.Unlock()
+	self.set(key, string(value), expires)
+	return nil
+}
+
+func (self *LRUCache) SetNX(key string, val interface{}, expires int64) (bool, error) {
+	value, err := json.Marshal(val)
+	if err != nil {
+		return false, err
+	}
+	self.mu.Lock()
+	defer self.mu.Unlock()
+	if _, ok := self.get(key); ok {
+		return false, nil
+	}
+	self.set(key, string(value), expires)
+	return true, nil
+}
+
+// Get 读取缓存, 不存在或已过期时返回core.ErrCacheMiss
+func (self *LRUCache) Get(key string) (string, error) {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+	item, ok := self.get(key)
+	if !ok {
+		return "", core.ErrCacheMiss
+	}
+	return item.value, nil
+}
+
+func (self *LRUCache) Exists(key string) bool {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+	_, ok := self.get(key)
+	return ok
+}
+
+func (self *LRUCache) Delete(key string) error {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+	if element, ok := self.items[key]; ok {
+		self.remove(element)
+	}
+	return nil
+}
+
+// Len 当前缓存的key数量, 包含尚未清理的过期key
+func (self *LRUCache) Len() int {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+	return self.ll.Len()
+}
+
+func (self *LRUCache) set(key, value string, expires int64) {
+	var expireAt time.Time
+	if expires > 0 {
+		expireAt = time.Now().Add(time.Duration(expires) * time.Second)
+	}
+	if element, ok := self.items[key]; ok {
+		self.ll.MoveToFront(element)
+		item := element.Value.(*entry)
+		item.value = value
+		item.expireAt = expireAt
+		return
+	}
+	self.items[key] = self.ll.PushFront(&entry{key: key, value: value, expireAt: expireAt})
+	if self.maxEntries > 0 && self.ll.Len() > self.maxEntries {
+		self.remove(self.ll.Back())
+	}
+}
+
+// get 查找未过期的key并标记为最近使用, 过期的key直接删除
+func (self *LRUCache) get(key string) (*entry, bool) {
+	element, ok := self.items[key]
+	if !ok {
+		return nil, false
+	}
+	item := element.Value.(*entry)
+	if item.expired(time.Now()) {
+		self.remove(element)
+		return nil, false
+	}
+	self.ll.MoveToFront(element)
+	return item, true
+}
+
+func (self *LRUCache) remove(element *list.Element) {
+	self.ll.Remove(element)
+	delete(self.items, element.Value.(*entry).key)
+}
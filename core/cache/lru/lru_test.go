@@ -0,0 +1,83 @@
+package lru
+
+import (
+	"errors"
+	"github.com/mrwangjinjin/go-wechat/core"
+	"testing"
+	"time"
+)
+
+func TestSetNX(t *testing.T) {
+	cache := NewLRUCache(0)
+	ok, err := cache.SetNX("lock", 1, 10)
+	if err != nil || !ok {
+		t.Fatalf("first SetNX: got (%v, %v), want (true, nil)", ok, err)
+	}
+	ok, err = cache.SetNX("lock", 2, 10)
+	if err != nil || ok {
+		t.Fatalf("second SetNX: got (%v, %v), want (false, nil)", ok, err)
+	}
+	if got, _ := cache.Get("lock"); got != "1" {
+		t.Fatalf("Get: got %q, want the first value", got)
+	}
+	if err := cache.Delete("lock"); err != nil {
+		t.Fatal(err)
+	}
+	if ok, _ := cache.SetNX("lock", 3, 10); !ok {
+		t.Fatal("SetNX after Delete: got false")
+	}
+}
+
+func TestEviction(t *testing.T) {
+	cache := NewLRUCache(2)
+	_ = cache.Set("a", 1)
+	_ = cache.Set("b", 2)
+	_, _ = cache.Get("a")
+	_ = cache.Set("c", 3)
+	if _, err := cache.Get("b"); !errors.Is(err, core.ErrCacheMiss) {
+		t.Fatalf("least recently used key: got %v, want core.ErrCacheMiss", err)
+	}
+	if cache.Len() != 2 {
+		t.Fatalf("Len: got %d, want 2", cache.Len())
+	}
+}
+
+func TestEntryExpired(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	if (&entry{}).expired(now) {
+		t.Fatal("entry without TTL expired")
+	}
+	item := &entry{expireAt: now.Add(time.Second)}
+	if item.expired(now) {
+		t.Fatal("entry expired before its TTL")
+	}
+	if !item.expired(now.Add(time.Second)) {
+		t.Fatal("entry not expired at its TTL")
+	}
+}
+
+func TestTTLExpiry(t *testing.T) {
+	cache := NewLRUCache(0)
+	_ = cache.SetEx("token", "TOKEN", 1)
+	_ = cache.Set("ticket", "TICKET")
+	if !cache.Exists("token") {
+		t.Fatal("token expired early")
+	}
+
+	time.Sleep(1100 * time.Millisecond)
+	if _, err := cache.Get("token"); !errors.Is(err, core.ErrCacheMiss) {
+		t.Fatalf("expired key: got %v, want core.ErrCacheMiss", err)
+	}
+	if cache.Exists("token") {
+		t.Fatal("expired key still exists")
+	}
+	if got, err := cache.Get("ticket"); err != nil || got != `"TICKET"` {
+		t.Fatalf("key without TTL: got (%q, %v)", got, err)
+	}
+	if cache.Len() != 1 {
+		t.Fatalf("Len: got %d, want the expired key removed", cache.Len())
+	}
+	if ok, _ := cache.SetNX("token", "NEW_TOKEN", 1); !ok {
+		t.Fatal("SetNX on an expired key: got false")
+	}
+}
@@ -0,0 +1,27 @@
+module github.com/mrwangjinjin/go-wechat/core/cache/redis
+
+go 1.18
+
+require (
+	github.com/alicebob/miniredis/v2 v2.30.4
+	github.com/mrwangjinjin/go-wechat v0.1.0
+	github.com/redis/go-redis/v9 v9.0.5
+)
+
+require (
+	github.com/davecgh/go-spew v1.1.0 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	gopkg.in/yaml.v2 v2.2.2 // indirect
+)
+
+require (
+	github.com/alicebob/gopher-json v0.0.0-20200520072559-a9ecdc9d1d3a // indirect
+	github.com/cespare/xxhash/v2 v2.2.0 // indirect
+	github.com/conetse/WXBizMsgCrypt v0.0.0-20180416085802-b5a6c8e8b043 // indirect
+	github.com/dgryski/go-rendezvous v0.0.0-20200823014737-9f7001d12a5f // indirect
+	github.com/gomodule/redigo v2.0.0+incompatible // indirect
+	github.com/tidwall/gjson v1.3.2 // indirect
+	github.com/tidwall/match v1.0.1 // indirect
+	github.com/tidwall/pretty v1.0.0 // indirect
+	github.com/yuin/gopher-lua v1.1.0 // indirect
+)
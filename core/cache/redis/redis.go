@@ -0,0 +1,113 @@
+// Package redis 基于go-redis v9的缓存实现, 作为独立模块发布, 避免主模块依赖go-redis
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/mrwangjinjin/go-wechat/core"
+	"github.com/mrwangjinjin/go-wechat/pkg/util"
+	"github.com/redis/go-redis/v9"
+	"time"
+)
+
+// RedisCache 基于go-redis v9的core.Cache实现, 值的编码方式与core.CacheDefault一致
+type RedisCache struct {
+	client    *redis.Client
+	keyPrefix string
+	timeout   time.Duration
+}
+
+// RedisOption RedisCache配置项
+type RedisOption func(cache *RedisCache)
+
+// WithKeyPrefix 为所有key添加前缀
+func WithKeyPrefix(prefix string) RedisOption {
+	return func(cache *RedisCache) {
+		cache.keyPrefix = prefix
+	}
+}
+
+// WithTimeout 设置单次命令超时时间, 默认3秒
+func WithTimeout(timeout time.Duration) RedisOption {
+	return func(cache *RedisCache) {
+		cache.timeout = timeout
+	}
+}
+
+func NewRedisCache(client *redis.Client, opts ...RedisOption) *RedisCache {
+	cache := &RedisCache{
+		client:  client,
+		timeout: 3 * time.Second,
+	}
+	for _, opt := range opts {
+		opt(cache)
+	}
+	return cache
+}
+
+var _ core.Cache = (*RedisCache)(nil)
+
+func (self *RedisCache) context() (context.Context, context.CancelFunc) {
+	return context.WithTimeout(context.Background(), self.timeout)
+}
+
+func encode(val interface{}) (string, error) {
+	value, err := json.Marshal(val)
+	if err != nil {
+		return "", err
+	}
+	return util.Base64Encoding(value), nil
+}
+
+func (self *RedisCache) Set(key string, val interface{}) error {
+	return self.SetEx(key, val, 0)
+}
+
+// SetEx 写入并设置过期时间(秒), expires为0时不过期
+func (self *RedisCache) SetEx(key string, val interface{}, expires int64) error {
+	value, err := encode(val)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := self.context()
+	defer cancel()
+	return self.client.Set(ctx, self.keyPrefix+key, value, time.Duration(expires)*time.Second).Err()
+}
+
+func (self *RedisCache) SetNX(key string, val interface{}, expires int64) (bool, error) {
+	value, err := encode(val)
+	if err != nil {
+		return false, err
+	}
+	ctx, cancel := self.context()
+	defer cancel()
+	return self.client.SetNX(ctx, self.keyPrefix+key, value, time.Duration(expires)*time.Second).Result()
+}
+
+// Get 读取缓存, 不存在时返回core.ErrCacheMiss
+func (self *RedisCache) Get(key string) (string, error) {
+	ctx, cancel := self.context()
+	defer cancel()
+	result, err := self.client.Get(ctx, self.keyPrefix+key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return "", core.ErrCacheMiss
+	}
+	if err != nil {
+		return "", err
+	}
+	return util.Base64Decoding(result)
+}
+
+func (self *RedisCache) Exists(key string) bool {
+	ctx, cancel := self.context()
+	defer cancel()
+	n, err := self.client.Exists(ctx, self.keyPrefix+key).Result()
+	return err == nil && n > 0
+}
+
+func (self *RedisCache) Delete(key string) error {
+	ctx, cancel := self.context()
+	defer cancel()
+	return self.client.Del(ctx, self.keyPrefix+key).Err()
+}
@@ -0,0 +1,74 @@
+package redis
+
+import (
+	"errors"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/mrwangjinjin/go-wechat/core"
+	"github.com/redis/go-redis/v9"
+	"testing"
+)
+
+func newTestCache(t *testing.T) (*RedisCache, *miniredis.Miniredis) {
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() {
+		_ = client.Close()
+	})
+	return NewRedisCache(client, WithKeyPrefix("test:")), server
+}
+
+func TestGetMissReturnsErrCacheMiss(t *testing.T) {
+	cache, _ := newTestCache(t)
+	_, err := cache.Get("missing")
+	if !errors.Is(err, core.ErrCacheMiss) {
+		t.Fatalf("Get missing key: got %v, want core.ErrCacheMiss", err)
+	}
+}
+
+func TestSetExAndGet(t *testing.T) {
+	cache, server := newTestCache(t)
+	if err := cache.SetEx("token", "abc", 60); err != nil {
+		t.Fatal(err)
+	}
+	got, err := cache.Get("token")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != `"abc"` {
+		t.Fatalf("Get: got %q, want %q", got, `"abc"`)
+	}
+	if ttl := server.TTL("test:token"); ttl.Seconds() != 60 {
+		t.Fatalf("TTL: got %v, want 60s", ttl)
+	}
+}
+
+func TestSetNX(t *testing.T) {
+	cache, _ := newTestCache(t)
+	ok, err := cache.SetNX("lock", 1, 10)
+	if err != nil || !ok {
+		t.Fatalf("first SetNX: got (%v, %v), want (true, nil)", ok, err)
+	}
+	ok, err = cache.SetNX("lock", 2, 10)
+	if err != nil || ok {
+		t.Fatalf("second SetNX: got (%v, %v), want (false, nil)", ok, err)
+	}
+}
+
+func TestDelete(t *testing.T) {
+	cache, _ := newTestCache(t)
+	if err := cache.Set("key", "value"); err != nil {
+		t.Fatal(err)
+	}
+	if !cache.Exists("key") {
+		t.Fatal("Exists after Set: got false")
+	}
+	if err := cache.Delete("key"); err != nil {
+		t.Fatal(err)
+	}
+	if cache.Exists("key") {
+		t.Fatal("Exists after Delete: got true")
+	}
+	if _, err := cache.Get("key"); !errors.Is(err, core.ErrCacheMiss) {
+		t.Fatalf("Get after Delete: got %v, want core.ErrCacheMiss", err)
+	}
+}
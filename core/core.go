@@ -1,5 +1,11 @@
 package core
 
+// Version SDK版本号
+const Version = "0.1.0"
+
+// DefaultUserAgent 默认请求User-Agent
+const DefaultUserAgent = "gowechat/" + Version
+
 type IClient interface {
 	GetToken() (map[string]interface{}, error)
 	RefreshToken() (map[string]interface{}, error)
@@ -11,4 +17,15 @@ type ClientConfig struct {
 	Token     string
 	AesKey    string
 	BaseUrl   string
+	UserAgent string
+	// RateLimiter 请求限流器, 为nil时不限流
+	RateLimiter RateLimiter
+	// Logger 日志, 为nil时使用DefaultLogger
+	Logger Logger
+	// ErrorReporter 内部错误上报, 为nil时仅记录日志
+	ErrorReporter ErrorReporter
+	// DryRun 为true时提交审核、发布等操作只校验并记录请求, 不实际发送
+	DryRun bool
+	// MaxResponseBytes 响应体最大字节数, 超出时返回*ResponseTooLargeError, 小于等于0时不限制
+	MaxResponseBytes int64
 }
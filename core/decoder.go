@@ -51,9 +51,9 @@ type NotifyHeaderMessage struct {
 
 type InfoMessage struct {
 	XMLName            xml.Name `xml:"info"`
-	Name               int64    `xml:"name"`
+	Name               string   `xml:"name"`
 	Code               string   `xml:"code"`
-	CodeType           int      `xml:code_type`
+	CodeType           int      `xml:"code_type"`
 	LegalPersonaWechat string   `xml:"legal_persona_wechat"`
 	LegalPersonaName   string   `xml:"legal_persona_name"`
 	ComponentPhone     string   `xml:"component_phone"`
@@ -61,14 +61,14 @@ type InfoMessage struct {
 
 type NotifyMessage struct {
 	NotifyHeaderMessage
-	ComponentVerifyTicket string `xml:"ComponentVerifyTicket"`
-	AuthorizerAppid       string `xml:"AuthorizerAppid"`
-	AuthorizationCode     string `xml:"AuthorizationCode"`
-	PreAuthCode           string `xml:"PreAuthCode"`
-	Appid                 string `xml:"appid"`
-	AuthCode              string `xml:"auth_code"`
-	Status                int    `xml:"status"`
-	InfoMessage
+	ComponentVerifyTicket string      `xml:"ComponentVerifyTicket"`
+	AuthorizerAppid       string      `xml:"AuthorizerAppid"`
+	AuthorizationCode     string      `xml:"AuthorizationCode"`
+	PreAuthCode           string      `xml:"PreAuthCode"`
+	Appid                 string      `xml:"appid"`
+	AuthCode              string      `xml:"auth_code"`
+	Status                int         `xml:"status"`
+	Info                  InfoMessage `xml:"info"`
 }
 
 type MessageDecoder struct {
@@ -119,17 +119,26 @@ func (self *MessageDecoder) VerifySignature(token string) bool {
 	return true
 }
 
-func (self *MessageDecoder) DecodeComponentVerifyTicket(appId, aesKey string) (NotifyMessage, error) {
+// Decrypt 解密消息, 返回明文XML
+func (self *MessageDecoder) Decrypt(appId, aesKey string) ([]byte, error) {
 	var msg CipherRequestHttpBody
 	err := xml.Unmarshal(self.EncryptMsg, &msg)
 	if err != nil {
-		return NotifyMessage{}, err
+		return nil, err
 	}
 	random, msgPlaintext, err := util.DecryptMsg(appId, aesKey, string(msg.Base64EncryptedMsg))
 	if err != nil {
-		return NotifyMessage{}, err
+		return nil, err
 	}
 	self.Random = random
+	return msgPlaintext, nil
+}
+
+func (self *MessageDecoder) DecodeComponentVerifyTicket(appId, aesKey string) (NotifyMessage, error) {
+	msgPlaintext, err := self.Decrypt(appId, aesKey)
+	if err != nil {
+		return NotifyMessage{}, err
+	}
 	var ticketMsg NotifyMessage
 	err = xml.Unmarshal(msgPlaintext, &ticketMsg)
 	if err != nil {
@@ -139,16 +148,10 @@ func (self *MessageDecoder) DecodeComponentVerifyTicket(appId, aesKey string) (N
 }
 
 func (self *MessageDecoder) DecodeEventMessage(appId, aesKey string) (EventMessage, error) {
-	var msg CipherRequestHttpBody
-	err := xml.Unmarshal(self.EncryptMsg, &msg)
-	if err != nil {
-		return EventMessage{}, err
-	}
-	random, msgPlaintext, err := util.DecryptMsg(appId, aesKey, string(msg.Base64EncryptedMsg))
+	msgPlaintext, err := self.Decrypt(appId, aesKey)
 	if err != nil {
 		return EventMessage{}, err
 	}
-	self.Random = random
 	var eventMsg EventMessage
 	err = xml.Unmarshal(msgPlaintext, &eventMsg)
 	if err != nil {
@@ -0,0 +1,23 @@
+package core
+
+import (
+	"encoding/xml"
+	"testing"
+)
+
+func TestNotifyMessageFastRegisterInfo(t *testing.T) {
+	push := `<xml><AppId><![CDATA[wx_component]]></AppId><CreateTime>1535442403</CreateTime><InfoType><![CDATA[notify_third_fasteregister]]></InfoType><appid><![CDATA[wx_new]]></appid><status>0</status><auth_code><![CDATA[AUTH_CODE]]></auth_code><msg><![CDATA[OK]]></msg><info><name><![CDATA[某某科技有限公司]]></name><code><![CDATA[91440300MA5EXAMPLE]]></code><code_type>1</code_type><legal_persona_wechat><![CDATA[legal_wechat]]></legal_persona_wechat><legal_persona_name><![CDATA[张三]]></legal_persona_name><component_phone><![CDATA[13800000000]]></component_phone></info></xml>`
+
+	var message NotifyMessage
+	if err := xml.Unmarshal([]byte(push), &message); err != nil {
+		t.Fatal(err)
+	}
+	if message.InfoType != "notify_third_fasteregister" || message.Appid != "wx_new" || message.AuthCode != "AUTH_CODE" {
+		t.Fatalf("unexpected header: %+v", message)
+	}
+	info := message.Info
+	if info.Name != "某某科技有限公司" || info.Code != "91440300MA5EXAMPLE" || info.CodeType != 1 ||
+		info.LegalPersonaWechat != "legal_wechat" || info.LegalPersonaName != "张三" || info.ComponentPhone != "13800000000" {
+		t.Fatalf("info not decoded: %+v", info)
+	}
+}
@@ -0,0 +1,197 @@
+package core
+
+import (
+	"encoding/xml"
+	"sync"
+	"time"
+)
+
+// DefaultMediaCheckWaiterTTL 按trace_id注册的处理函数的默认有效期, 超时未收到推送时自动移除
+const DefaultMediaCheckWaiterTTL = time.Hour
+
+// DispatchHandler 处理解密后的推送消息明文
+type DispatchHandler func(plaintext []byte) error
+
+// EventDispatcher 按InfoType/Event分发推送消息
+type EventDispatcher struct {
+	mu                sync.Mutex
+	handlers          map[string]DispatchHandler
+	mediaCheckHandler func(event *MediaCheckEvent)
+	mediaCheckWaiters map[string]*mediaCheckWaiter
+}
+
+// mediaCheckWaiter 按trace_id注册的一次性处理函数
+type mediaCheckWaiter struct {
+	handler   func(event *MediaCheckEvent)
+	expiresAt time.Time
+}
+
+func NewEventDispatcher() *EventDispatcher {
+	dispatcher := &EventDispatcher{
+		handlers:          map[string]DispatchHandler{},
+		mediaCheckWaiters: map[string]*mediaCheckWaiter{},
+	}
+	dispatcher.Handle(EventWxaMediaCheck, dispatcher.dispatchMediaCheck)
+	return dispatcher
+}
+
+// Handle 注册事件处理函数, event为InfoType或Event的值
+func (self *EventDispatcher) Handle(event string, handler DispatchHandler) {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+	self.handlers[event] = handler
+}
+
+// Dispatch 分发推送消息, 未注册的事件直接忽略
+func (self *EventDispatcher) Dispatch(plaintext []byte) error {
+	var header struct {
+		InfoType string `xml:"InfoType"`
+		Event    string `xml:"Event"`
+	}
+	if err := xml.Unmarshal(plaintext, &header); err != nil {
+		return err
+	}
+	event := header.InfoType
+	if event == "" {
+		event = header.Event
+	}
+	self.mu.Lock()
+	handler, ok := self.handlers[event]
+	self.mu.Unlock()
+	if !ok {
+		return nil
+	}
+	return handler(plaintext)
+}
+
+// OnMediaCheck 注册音视频内容安全检测结果的处理函数
+func (self *EventDispatcher) OnMediaCheck(handler func(event *MediaCheckEvent)) {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+	self.mediaCheckHandler = handler
+}
+
+// OnMediaCheckTrace 按trace_id注册一次性处理函数, 优先于OnMediaCheck,
+// DefaultMediaCheckWaiterTTL内未收到推送时自动移除, 返回的函数用于提前注销
+func (self *EventDispatcher) OnMediaCheckTrace(traceId string, handler func(event *MediaCheckEvent)) func() {
+	return self.OnMediaCheckTraceTimeout(traceId, DefaultMediaCheckWaiterTTL, handler)
+}
+
+// OnMediaCheckTraceTimeout 同OnMediaCheckTrace, ttl为处理函数的有效期,
+// 过期后该trace_id的推送交给OnMediaCheck注册的处理函数
+func (self *EventDispatcher) OnMediaCheckTraceTimeout(traceId string, ttl time.Duration, handler func(event *MediaCheckEvent)) func() {
+	waiter := &mediaCheckWaiter{handler: handler, expiresAt: time.Now().Add(ttl)}
+	self.mu.Lock()
+	defer self.mu.Unlock()
+	self.purgeMediaCheckWaiters()
+	self.mediaCheckWaiters[traceId] = waiter
+	return func() {
+		self.mu.Lock()
+		defer self.mu.Unlock()
+		// 同一trace_id可能已重新注册, 只移除本次注册的处理函数
+		if self.mediaCheckWaiters[traceId] == waiter {
+			delete(self.mediaCheckWaiters, traceId)
+		}
+	}
+}
+
+// purgeMediaCheckWaiters 移除已过期的处理函数, 调用方需持有锁
+func (self *EventDispatcher) purgeMediaCheckWaiters() {
+	now := time.Now()
+	for traceId, waiter := range self.mediaCheckWaiters {
+		if now.After(waiter.expiresAt) {
+			delete(self.mediaCheckWaiters, traceId)
+		}
+	}
+}
+
+func (self *EventDispatcher) dispatchMediaCheck(plaintext []byte) error {
+	var event MediaCheckEvent
+	if err := xml.Unmarshal(plaintext, &event); err != nil {
+		return err
+	}
+	self.mu.Lock()
+	self.purgeMediaCheckWaiters()
+	handler := self.mediaCheckHandler
+	if waiter, ok := self.mediaCheckWaiters[event.TraceId]; ok {
+		delete(self.mediaCheckWaiters, event.TraceId)
+		handler = waiter.handler
+	}
+	self.mu.Unlock()
+	if handler != nil {
+		handler(&event)
+	}
+	return nil
+}
+
+// OnFastRegisterBeta 注册创建试用小程序结果的处理函数
+func (self *EventDispatcher) OnFastRegisterBeta(handler func(event *FastRegisterBetaEvent)) {
+	self.Handle(EventFastRegisterBetaApp, func(plaintext []byte) error {
+		var event FastRegisterBetaEvent
+		if err := xml.Unmarshal(plaintext, &event); err != nil {
+			return err
+		}
+		handler(&event)
+		return nil
+	})
+}
+
+// OnVerifyBeta 注册试用小程序转正结果的处理函数
+func (self *EventDispatcher) OnVerifyBeta(handler func(event *VerifyBetaEvent)) {
+	self.Handle(EventFastVerifyBetaApp, func(plaintext []byte) error {
+		var event VerifyBetaEvent
+		if err := xml.Unmarshal(plaintext, &event); err != nil {
+			return err
+		}
+		handler(&event)
+		return nil
+	})
+}
+
+// OnFastRegisterPersonal 注册个人主体小程序注册结果的处理函数
+func (self *EventDispatcher) OnFastRegisterPersonal(handler func(event *FastRegisterPersonalEvent)) {
+	self.Handle(EventFastRegisterPersonal, func(plaintext []byte) error {
+		var event FastRegisterPersonalEvent
+		if err := xml.Unmarshal(plaintext, &event); err != nil {
+			return err
+		}
+		handler(&event)
+		return nil
+	})
+}
+
+// OnTemplateSendJobFinish 注册公众号模板消息发送结果的处理函数
+func (self *EventDispatcher) OnTemplateSendJobFinish(handler func(event *TemplateSendJobFinishEvent)) {
+	self.Handle(EventTemplateSendJobFinish, func(plaintext []byte) error {
+		var event TemplateSendJobFinishEvent
+		if err := xml.Unmarshal(plaintext, &event); err != nil {
+			return err
+		}
+		handler(&event)
+		return nil
+	})
+}
+
+// OnPublishJobFinish 注册公众号发布结果的处理函数
+func (self *EventDispatcher) OnPublishJobFinish(handler func(event *PublishJobFinishEvent)) {
+	self.Handle(EventPublishJobFinish, func(plaintext []byte) error {
+		var event PublishJobFinishEvent
+		if err := xml.Unmarshal(plaintext, &event); err != nil {
+			return err
+		}
+		handler(&event)
+		return nil
+	})
+}
+
+// OnMassSendJobFinish 注册公众号群发结果的处理函数
+func (self *EventDispatcher) OnMassSendJobFinish(handler func(event *MassSendJobFinishEvent)) {
+	self.Handle(EventMassSendJobFinish, func(plaintext []byte) error {
+		var event MassSendJobFinishEvent
+		if err := xml.Unmarshal(plaintext, &event); err != nil {
+			return err
+		}
+		handler(&event)
+		return nil
+	})
+}
@@ -0,0 +1,183 @@
+package core
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func mediaCheckPush(traceId string) []byte {
+	return []byte(fmt.Sprintf(`<xml><ToUserName><![CDATA[gh_38cc49f9733b]]></ToUserName><FromUserName><![CDATA[oH1fu0FdHqpToe2T6gBj0WyB8iS1]]></FromUserName><CreateTime>1626959646</CreateTime><MsgType><![CDATA[event]]></MsgType><Event><![CDATA[wxa_media_check]]></Event><appid><![CDATA[wx_authorizer]]></appid><trace_id><![CDATA[%s]]></trace_id><version>2</version><result><suggest><![CDATA[pass]]></suggest><label>100</label></result></xml>`, traceId))
+}
+
+func TestMediaCheckTraceWaiterIsOneShot(t *testing.T) {
+	dispatcher := NewEventDispatcher()
+	var fallback, traced []string
+	dispatcher.OnMediaCheck(func(event *MediaCheckEvent) { fallback = append(fallback, event.TraceId) })
+	dispatcher.OnMediaCheckTrace("TRACE_1", func(event *MediaCheckEvent) { traced = append(traced, event.TraceId) })
+
+	for i := 0; i < 2; i++ {
+		if err := dispatcher.Dispatch(mediaCheckPush("TRACE_1")); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(traced) != 1 || len(fallback) != 1 {
+		t.Fatalf("traced %v, fallback %v; want one each", traced, fallback)
+	}
+	if n := len(dispatcher.mediaCheckWaiters); n != 0 {
+		t.Fatalf("%d waiters left", n)
+	}
+}
+
+func TestMediaCheckTraceWaiterExpires(t *testing.T) {
+	dispatcher := NewEventDispatcher()
+	var fallback, traced int
+	dispatcher.OnMediaCheck(func(event *MediaCheckEvent) { fallback++ })
+	dispatcher.OnMediaCheckTraceTimeout("TRACE_1", 10*time.Millisecond, func(event *MediaCheckEvent) { traced++ })
+	time.Sleep(20 * time.Millisecond)
+
+	// 过期的处理函数在下次注册时被清理
+	dispatcher.OnMediaCheckTrace("TRACE_2", func(event *MediaCheckEvent) {})
+	dispatcher.mu.Lock()
+	_, ok := dispatcher.mediaCheckWaiters["TRACE_1"]
+	dispatcher.mu.Unlock()
+	if ok {
+		t.Fatal("expired waiter was not purged")
+	}
+	if err := dispatcher.Dispatch(mediaCheckPush("TRACE_1")); err != nil {
+		t.Fatal(err)
+	}
+	if traced != 0 || fallback != 1 {
+		t.Fatalf("traced %d, fallback %d; want expired push handled by OnMediaCheck", traced, fallback)
+	}
+}
+
+func TestMediaCheckTraceDeregister(t *testing.T) {
+	dispatcher := NewEventDispatcher()
+	called := 0
+	cancel := dispatcher.OnMediaCheckTrace("TRACE_1", func(event *MediaCheckEvent) { called++ })
+	cancel()
+	if err := dispatcher.Dispatch(mediaCheckPush("TRACE_1")); err != nil {
+		t.Fatal(err)
+	}
+	if called != 0 {
+		t.Fatal("deregistered waiter was called")
+	}
+
+	// 旧的注销函数不影响同一trace_id的新注册
+	dispatcher.OnMediaCheckTrace("TRACE_1", func(event *MediaCheckEvent) { called++ })
+	cancel()
+	if err := dispatcher.Dispatch(mediaCheckPush("TRACE_1")); err != nil {
+		t.Fatal(err)
+	}
+	if called != 1 {
+		t.Fatalf("new waiter called %d times, want 1", called)
+	}
+}
+
+func TestDispatchByInfoType(t *testing.T) {
+	dispatcher := NewEventDispatcher()
+	var got *FastRegisterBetaEvent
+	dispatcher.OnFastRegisterBeta(func(event *FastRegisterBetaEvent) { got = event })
+
+	push := `<xml><AppId><![CDATA[wx_component]]></AppId><CreateTime>1555919839</CreateTime><InfoType><![CDATA[notify_third_fastregisterbetaapp]]></InfoType><appid><![CDATA[wx_beta]]></appid><status>0</status><msg><![CDATA[OK]]></msg><info><unique_id><![CDATA[UNIQUE]]></unique_id><name><![CDATA[demo]]></name></info></xml>`
+	if err := dispatcher.Dispatch([]byte(push)); err != nil {
+		t.Fatal(err)
+	}
+	if got == nil || got.BetaAppId != "wx_beta" || got.Info.UniqueId != "UNIQUE" {
+		t.Fatalf("unexpected event: %+v", got)
+	}
+	if err := dispatcher.Dispatch([]byte(`<xml><InfoType><![CDATA[unknown]]></InfoType></xml>`)); err != nil {
+		t.Fatalf("unregistered event must be ignored, got %v", err)
+	}
+	if err := dispatcher.Dispatch([]byte(`not xml`)); err == nil {
+		t.Fatal("expected error for invalid XML")
+	}
+}
+
+func TestDispatchMassSendJobFinish(t *testing.T) {
+	dispatcher := NewEventDispatcher()
+	var got *MassSendJobFinishEvent
+	dispatcher.OnMassSendJobFinish(func(event *MassSendJobFinishEvent) { got = event })
+
+	push := `<xml><ToUserName><![CDATA[gh_4d00ed8d6399]]></ToUserName><FromUserName><![CDATA[oV5CrjpxgaGXNHIQigzNlgLTnwic]]></FromUserName><CreateTime>1481013459</CreateTime><MsgType><![CDATA[event]]></MsgType><Event><![CDATA[MASSSENDJOBFINISH]]></Event><MsgID>1000001625</MsgID><Status><![CDATA[err(30003)]]></Status><TotalCount>0</TotalCount><FilterCount>0</FilterCount><SentCount>0</SentCount><ErrorCount>0</ErrorCount></xml>`
+	if err := dispatcher.Dispatch([]byte(push)); err != nil {
+		t.Fatal(err)
+	}
+	if got == nil || got.MsgId != 1000001625 || got.Status != "err(30003)" {
+		t.Fatalf("unexpected event: %+v", got)
+	}
+}
+
+func TestDispatchTemplateSendJobFinish(t *testing.T) {
+	dispatcher := NewEventDispatcher()
+	var got *TemplateSendJobFinishEvent
+	dispatcher.OnTemplateSendJobFinish(func(event *TemplateSendJobFinishEvent) { got = event })
+
+	push := `<xml><ToUserName><![CDATA[gh_7f083739789a]]></ToUserName><FromUserName><![CDATA[oia2TjuEGTNoeX76QEjQNrcURxG8]]></FromUserName><CreateTime>1395658920</CreateTime><MsgType><![CDATA[event]]></MsgType><Event><![CDATA[TEMPLATESENDJOBFINISH]]></Event><MsgID>200163836</MsgID><Status><![CDATA[success]]></Status></xml>`
+	if err := dispatcher.Dispatch([]byte(push)); err != nil {
+		t.Fatal(err)
+	}
+	if got == nil || got.MsgId != 200163836 || got.Status != "success" {
+		t.Fatalf("unexpected event: %+v", got)
+	}
+}
+
+func TestMediaCheckEventDecoding(t *testing.T) {
+	dispatcher := NewEventDispatcher()
+	var events []*MediaCheckEvent
+	dispatcher.OnMediaCheck(func(event *MediaCheckEvent) { events = append(events, event) })
+
+	push := `<xml><ToUserName><![CDATA[gh_38cc49f9733b]]></ToUserName><FromUserName><![CDATA[oH1fu0FdHqpToe2T6gBj0WyB8iS1]]></FromUserName><CreateTime>1626959646</CreateTime><MsgType><![CDATA[event]]></MsgType><Event><![CDATA[wxa_media_check]]></Event><appid><![CDATA[wx_authorizer]]></appid><trace_id><![CDATA[TRACE_RISKY]]></trace_id><version>2</version><detail><strategy><![CDATA[content_model]]></strategy><errcode>0</errcode><suggest><![CDATA[risky]]></suggest><label>20002</label><prob>90</prob></detail><detail><strategy><![CDATA[keyword]]></strategy><errcode>0</errcode><suggest><![CDATA[pass]]></suggest><label>100</label></detail><errcode>0</errcode><errmsg><![CDATA[ok]]></errmsg><result><suggest><![CDATA[risky]]></suggest><label>20002</label></result></xml>`
+	if err := dispatcher.Dispatch([]byte(push)); err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 1 {
+		t.Fatalf("got %d events, want 1", len(events))
+	}
+	event := events[0]
+	if event.Event != EventWxaMediaCheck || event.AppId != "wx_authorizer" || event.TraceId != "TRACE_RISKY" || event.Version != 2 || event.CreateTime != 1626959646 {
+		t.Fatalf("unexpected event: %+v", event)
+	}
+	if !event.Result.Suggest.IsRisky() || event.Result.Label != 20002 {
+		t.Fatalf("unexpected result: %+v", event.Result)
+	}
+	if len(event.Detail) != 2 || event.Detail[0].Strategy != "content_model" || event.Detail[0].Prob != 90 || event.Detail[1].Suggest != SuggestPass {
+		t.Fatalf("unexpected detail: %+v", event.Detail)
+	}
+}
+
+func TestVerifyBetaEventDecoding(t *testing.T) {
+	dispatcher := NewEventDispatcher()
+	var got *VerifyBetaEvent
+	dispatcher.OnVerifyBeta(func(event *VerifyBetaEvent) { got = event })
+
+	push := `<xml><AppId><![CDATA[wx_component]]></AppId><CreateTime>1555919839</CreateTime><InfoType><![CDATA[notify_third_fastverifybetaapp]]></InfoType><appid><![CDATA[wx_beta]]></appid><status>89249</status><msg><![CDATA[该主体已有任务执行中，距上次任务24h后再提交]]></msg><info><name><![CDATA[腾讯计算机系统有限公司]]></name><code><![CDATA[91440300708461136T]]></code><code_type>1</code_type><legal_persona_wechat><![CDATA[legal_wechat]]></legal_persona_wechat><legal_persona_name><![CDATA[张三]]></legal_persona_name><component_phone><![CDATA[13800138000]]></component_phone></info></xml>`
+	if err := dispatcher.Dispatch([]byte(push)); err != nil {
+		t.Fatal(err)
+	}
+	if got == nil || got.InfoType != EventFastVerifyBetaApp || got.AppId != "wx_component" || got.BetaAppId != "wx_beta" || got.Status != 89249 || got.CreateTime != 1555919839 {
+		t.Fatalf("unexpected event: %+v", got)
+	}
+	if got.Info.Name != "腾讯计算机系统有限公司" || got.Info.Code != "91440300708461136T" || got.Info.CodeType != 1 ||
+		got.Info.LegalPersonaWechat != "legal_wechat" || got.Info.LegalPersonaName != "张三" || got.Info.ComponentPhone != "13800138000" {
+		t.Fatalf("unexpected info: %+v", got.Info)
+	}
+}
+
+func TestFastRegisterPersonalEventDecoding(t *testing.T) {
+	dispatcher := NewEventDispatcher()
+	var got *FastRegisterPersonalEvent
+	dispatcher.OnFastRegisterPersonal(func(event *FastRegisterPersonalEvent) { got = event })
+
+	push := `<xml><AppId><![CDATA[wx_component]]></AppId><CreateTime>1535442403</CreateTime><InfoType><![CDATA[notify_third_fastregisterpersonalweapp]]></InfoType><appid><![CDATA[wx_personal]]></appid><status>0</status><msg><![CDATA[OK]]></msg><info><taskid><![CDATA[TASK_ID]]></taskid><idname><![CDATA[张三]]></idname><wxuser><![CDATA[zhangsan]]></wxuser><component_phone><![CDATA[13800138000]]></component_phone></info></xml>`
+	if err := dispatcher.Dispatch([]byte(push)); err != nil {
+		t.Fatal(err)
+	}
+	if got == nil || got.InfoType != EventFastRegisterPersonal || got.WeappAppId != "wx_personal" || got.Status != 0 || got.Msg != "OK" {
+		t.Fatalf("unexpected event: %+v", got)
+	}
+	if got.Info.TaskId != "TASK_ID" || got.Info.IdName != "张三" || got.Info.WxUser != "zhangsan" || got.Info.ComponentPhone != "13800138000" {
+		t.Fatalf("unexpected info: %+v", got.Info)
+	}
+}
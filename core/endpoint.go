@@ -2,6 +2,15 @@ package core
 
 import "fmt"
 
+// ComponentLoginPageUrl 第三方平台授权页地址, 不随BaseUrl变化
+const ComponentLoginPageUrl = "https://mp.weixin.qq.com/cgi-bin/componentloginpage"
+
+// OAuth2AuthorizeUrl 网页授权页地址, 不随BaseUrl变化
+const OAuth2AuthorizeUrl = "https://open.weixin.qq.com/connect/oauth2/authorize"
+
+// ShowQrcodeUrl 通过ticket换取二维码的地址, 不随BaseUrl变化
+const ShowQrcodeUrl = "https://mp.weixin.qq.com/cgi-bin/showqrcode"
+
 type Endpoint struct {
 	baseUrl string
 }
@@ -16,6 +25,10 @@ func (self *Endpoint) SetBaseUrl(base string) {
 	self.baseUrl = base
 }
 
+func (self *Endpoint) ComponentLoginPage(componentAppId, preAuthCode, redirectUri string, authType uint8) string {
+	return fmt.Sprintf("%s?component_appid=%s&pre_auth_code=%s&redirect_uri=%s&auth_type=%d", ComponentLoginPageUrl, componentAppId, preAuthCode, redirectUri, authType)
+}
+
 func (self *Endpoint) ComponentAccessTokenUrl() string {
 	return fmt.Sprintf("%s/cgi-bin/component/api_component_token", self.baseUrl)
 }
@@ -36,6 +49,18 @@ func (self *Endpoint) ApiAuthorizerInfo(componentToken string) string {
 	return fmt.Sprintf("%s/cgi-bin/component/api_get_authorizer_info?component_access_token=%s", self.baseUrl, componentToken)
 }
 
+func (self *Endpoint) ApiGetAuthorizerList(componentToken string) string {
+	return fmt.Sprintf("%s/cgi-bin/component/api_get_authorizer_list?component_access_token=%s", self.baseUrl, componentToken)
+}
+
+func (self *Endpoint) ApiGetAuthorizerOption(componentToken string) string {
+	return fmt.Sprintf("%s/cgi-bin/component/api_get_authorizer_option?component_access_token=%s", self.baseUrl, componentToken)
+}
+
+func (self *Endpoint) ApiSetAuthorizerOption(componentToken string) string {
+	return fmt.Sprintf("%s/cgi-bin/component/api_set_authorizer_option?component_access_token=%s", self.baseUrl, componentToken)
+}
+
 func (self *Endpoint) FastRegisterWeapp(componentToken string) string {
 	return fmt.Sprintf("%s/cgi-bin/component/fastregisterweapp?action=create&component_access_token=%s", self.baseUrl, componentToken)
 }
@@ -75,10 +100,18 @@ func (self *Endpoint) GetWxaCode(authorizerAccessToken string) string {
 	return fmt.Sprintf("%s/wxa/getwxacode?access_token=%s", self.baseUrl, authorizerAccessToken)
 }
 
+func (self *Endpoint) GetWxaCodeUnlimit(authorizerAccessToken string) string {
+	return fmt.Sprintf("%s/wxa/getwxacodeunlimit?access_token=%s", self.baseUrl, authorizerAccessToken)
+}
+
 func (self *Endpoint) CustomService(authorizerAccessToken string) string {
 	return fmt.Sprintf("%s/cgi-bin/message/custom/send?access_token=%s", self.baseUrl, authorizerAccessToken)
 }
 
+func (self *Endpoint) CustomTyping(authorizerAccessToken string) string {
+	return fmt.Sprintf("%s/cgi-bin/message/custom/typing?access_token=%s", self.baseUrl, authorizerAccessToken)
+}
+
 func (self *Endpoint) GetLastAuditStatus(authorizerAccessToken string) string {
 	return fmt.Sprintf("%s/wxa/get_latest_auditstatus?access_token=%s", self.baseUrl, authorizerAccessToken)
 }
@@ -92,7 +125,7 @@ func (self *Endpoint) GetTemplateList(authorizerAccessToken string) string {
 }
 
 func (self *Endpoint) GetQrCode(authorizerAccessToken, path string) string {
-	return fmt.Sprintf("%s/wxa/get_qrcode?access_token=%s&path=%s", self.baseUrl, authorizerAccessToken)
+	return fmt.Sprintf("%s/wxa/get_qrcode?access_token=%s&path=%s", self.baseUrl, authorizerAccessToken, path)
 }
 
 func (self *Endpoint) GetQrCodeWithoutPath(authorizerAccessToken string) string {
@@ -112,7 +145,7 @@ func (self *Endpoint) GetPage(authorizerAccessToken string) string {
 }
 
 func (self *Endpoint) OAuth2Authorize(authorizerAppId, redirectUrl, componentAppId string) string {
-	return fmt.Sprintf("%s/connect/oauth2/authorize?appid=%s&redirect_uri=%s&response_type=code&scope=SCOPE&state=STATE&component_appid=%s#wechat_redirect", self.baseUrl, authorizerAppId, redirectUrl, componentAppId)
+	return fmt.Sprintf("%s?appid=%s&redirect_uri=%s&response_type=code&scope=SCOPE&state=STATE&component_appid=%s#wechat_redirect", OAuth2AuthorizeUrl, authorizerAppId, redirectUrl, componentAppId)
 }
 
 func (self *Endpoint) OAuth2AccessToken(authorizerAppId, code, componentAppId, componentAccessToken string) string {
@@ -122,3 +155,511 @@ func (self *Endpoint) OAuth2AccessToken(authorizerAppId, code, componentAppId, c
 func (self *Endpoint) OAuth2RefreshToken(authorizerAppId, componentAppId, componentAccessToken, refreshToken string) string {
 	return fmt.Sprintf("%s/sns/oauth2/component/refresh_token?appid=%s&grant_type=refresh_token&component_appid=%s&component_access_token=%s&refresh_token=%s", self.baseUrl, authorizerAppId, componentAppId, componentAccessToken, refreshToken)
 }
+
+func (self *Endpoint) GetUserPhoneNumber(authorizerAccessToken string) string {
+	return fmt.Sprintf("%s/wxa/business/getuserphonenumber?access_token=%s", self.baseUrl, authorizerAccessToken)
+}
+
+func (self *Endpoint) MsgSecCheck(authorizerAccessToken string) string {
+	return fmt.Sprintf("%s/wxa/msg_sec_check?access_token=%s", self.baseUrl, authorizerAccessToken)
+}
+
+func (self *Endpoint) MediaCheckAsync(authorizerAccessToken string) string {
+	return fmt.Sprintf("%s/wxa/media_check_async?access_token=%s", self.baseUrl, authorizerAccessToken)
+}
+
+func (self *Endpoint) AddSubscribeTemplate(authorizerAccessToken string) string {
+	return fmt.Sprintf("%s/wxaapi/newtmpl/addtemplate?access_token=%s", self.baseUrl, authorizerAccessToken)
+}
+
+func (self *Endpoint) DeleteSubscribeTemplate(authorizerAccessToken string) string {
+	return fmt.Sprintf("%s/wxaapi/newtmpl/deltemplate?access_token=%s", self.baseUrl, authorizerAccessToken)
+}
+
+func (self *Endpoint) GetSubscribeTemplates(authorizerAccessToken string) string {
+	return fmt.Sprintf("%s/wxaapi/newtmpl/gettemplate?access_token=%s", self.baseUrl, authorizerAccessToken)
+}
+
+func (self *Endpoint) GetSubscribeCategory(authorizerAccessToken string) string {
+	return fmt.Sprintf("%s/wxaapi/newtmpl/getcategory?access_token=%s", self.baseUrl, authorizerAccessToken)
+}
+
+func (self *Endpoint) GetPubTemplateTitles(authorizerAccessToken, ids string, start, limit int) string {
+	return fmt.Sprintf("%s/wxaapi/newtmpl/getpubtemplatetitles?access_token=%s&ids=%s&start=%d&limit=%d", self.baseUrl, authorizerAccessToken, ids, start, limit)
+}
+
+func (self *Endpoint) GetPubTemplateKeywords(authorizerAccessToken, tid string) string {
+	return fmt.Sprintf("%s/wxaapi/newtmpl/getpubtemplatekeywords?access_token=%s&tid=%s", self.baseUrl, authorizerAccessToken, tid)
+}
+
+func (self *Endpoint) SendSubscribeMessage(authorizerAccessToken string) string {
+	return fmt.Sprintf("%s/cgi-bin/message/subscribe/send?access_token=%s", self.baseUrl, authorizerAccessToken)
+}
+
+func (self *Endpoint) StableToken() string {
+	return fmt.Sprintf("%s/cgi-bin/stable_token", self.baseUrl)
+}
+
+func (self *Endpoint) SendUniformMessage(authorizerAccessToken string) string {
+	return fmt.Sprintf("%s/cgi-bin/message/wxopen/template/uniform_send?access_token=%s", self.baseUrl, authorizerAccessToken)
+}
+
+func (self *Endpoint) UploadMedia(authorizerAccessToken, mediaType string) string {
+	return fmt.Sprintf("%s/cgi-bin/media/upload?access_token=%s&type=%s", self.baseUrl, authorizerAccessToken, mediaType)
+}
+
+func (self *Endpoint) DailyVisitTrend(authorizerAccessToken string) string {
+	return fmt.Sprintf("%s/datacube/getweanalysisappiddailyvisittrend?access_token=%s", self.baseUrl, authorizerAccessToken)
+}
+
+func (self *Endpoint) WeeklyVisitTrend(authorizerAccessToken string) string {
+	return fmt.Sprintf("%s/datacube/getweanalysisappidweeklyvisittrend?access_token=%s", self.baseUrl, authorizerAccessToken)
+}
+
+func (self *Endpoint) MonthlyVisitTrend(authorizerAccessToken string) string {
+	return fmt.Sprintf("%s/datacube/getweanalysisappidmonthlyvisittrend?access_token=%s", self.baseUrl, authorizerAccessToken)
+}
+
+func (self *Endpoint) DailyRetainInfo(authorizerAccessToken string) string {
+	return fmt.Sprintf("%s/datacube/getweanalysisappiddailyretaininfo?access_token=%s", self.baseUrl, authorizerAccessToken)
+}
+
+func (self *Endpoint) WeeklyRetainInfo(authorizerAccessToken string) string {
+	return fmt.Sprintf("%s/datacube/getweanalysisappidweeklyretaininfo?access_token=%s", self.baseUrl, authorizerAccessToken)
+}
+
+func (self *Endpoint) MonthlyRetainInfo(authorizerAccessToken string) string {
+	return fmt.Sprintf("%s/datacube/getweanalysisappidmonthlyretaininfo?access_token=%s", self.baseUrl, authorizerAccessToken)
+}
+
+func (self *Endpoint) VisitDistribution(authorizerAccessToken string) string {
+	return fmt.Sprintf("%s/datacube/getweanalysisappidvisitdistribution?access_token=%s", self.baseUrl, authorizerAccessToken)
+}
+
+func (self *Endpoint) VisitPage(authorizerAccessToken string) string {
+	return fmt.Sprintf("%s/datacube/getweanalysisappidvisitpage?access_token=%s", self.baseUrl, authorizerAccessToken)
+}
+
+func (self *Endpoint) UserPortrait(authorizerAccessToken string) string {
+	return fmt.Sprintf("%s/datacube/getweanalysisappiduserportrait?access_token=%s", self.baseUrl, authorizerAccessToken)
+}
+
+func (self *Endpoint) GetPerformance(authorizerAccessToken string) string {
+	return fmt.Sprintf("%s/wxaapi/log/get_performance?access_token=%s", self.baseUrl, authorizerAccessToken)
+}
+
+func (self *Endpoint) SetIndustry(authorizerAccessToken string) string {
+	return fmt.Sprintf("%s/cgi-bin/template/api_set_industry?access_token=%s", self.baseUrl, authorizerAccessToken)
+}
+
+func (self *Endpoint) GetIndustry(authorizerAccessToken string) string {
+	return fmt.Sprintf("%s/cgi-bin/template/get_industry?access_token=%s", self.baseUrl, authorizerAccessToken)
+}
+
+func (self *Endpoint) AddTemplateFromLibrary(authorizerAccessToken string) string {
+	return fmt.Sprintf("%s/cgi-bin/template/api_add_template?access_token=%s", self.baseUrl, authorizerAccessToken)
+}
+
+func (self *Endpoint) GetAllPrivateTemplates(authorizerAccessToken string) string {
+	return fmt.Sprintf("%s/cgi-bin/template/get_all_private_template?access_token=%s", self.baseUrl, authorizerAccessToken)
+}
+
+func (self *Endpoint) DeletePrivateTemplate(authorizerAccessToken string) string {
+	return fmt.Sprintf("%s/cgi-bin/template/del_private_template?access_token=%s", self.baseUrl, authorizerAccessToken)
+}
+
+func (self *Endpoint) SendTemplateMessage(authorizerAccessToken string) string {
+	return fmt.Sprintf("%s/cgi-bin/message/template/send?access_token=%s", self.baseUrl, authorizerAccessToken)
+}
+
+func (self *Endpoint) UserLogSearch(authorizerAccessToken, query string) string {
+	return fmt.Sprintf("%s/wxaapi/userlog/userlog_search?access_token=%s&%s", self.baseUrl, authorizerAccessToken, query)
+}
+
+func (self *Endpoint) JSErrSearch(authorizerAccessToken string) string {
+	return fmt.Sprintf("%s/wxaapi/log/jserr_search?access_token=%s", self.baseUrl, authorizerAccessToken)
+}
+
+func (self *Endpoint) FeedbackList(authorizerAccessToken, query string) string {
+	return fmt.Sprintf("%s/wxaapi/feedback/list?access_token=%s&%s", self.baseUrl, authorizerAccessToken, query)
+}
+
+func (self *Endpoint) GetFeedbackMedia(authorizerAccessToken string, recordId int64, mediaId string) string {
+	return fmt.Sprintf("%s/cgi-bin/media/getfeedbackmedia?access_token=%s&record_id=%d&media_id=%s", self.baseUrl, authorizerAccessToken, recordId, mediaId)
+}
+
+func (self *Endpoint) GetVersionInfo(authorizerAccessToken string) string {
+	return fmt.Sprintf("%s/wxa/getversioninfo?access_token=%s", self.baseUrl, authorizerAccessToken)
+}
+
+func (self *Endpoint) GetWeappSupportVersion(authorizerAccessToken string) string {
+	return fmt.Sprintf("%s/cgi-bin/wxopen/getweappsupportversion?access_token=%s", self.baseUrl, authorizerAccessToken)
+}
+
+func (self *Endpoint) SetWeappSupportVersion(authorizerAccessToken string) string {
+	return fmt.Sprintf("%s/cgi-bin/wxopen/setweappsupportversion?access_token=%s", self.baseUrl, authorizerAccessToken)
+}
+
+func (self *Endpoint) GetLiveInfo(authorizerAccessToken string) string {
+	return fmt.Sprintf("%s/wxa/business/getliveinfo?access_token=%s", self.baseUrl, authorizerAccessToken)
+}
+
+func (self *Endpoint) QueryIcpEntranceInfo(authorizerAccessToken string) string {
+	return fmt.Sprintf("%s/wxa/icp/query_icp_entrance_info?access_token=%s", self.baseUrl, authorizerAccessToken)
+}
+
+func (self *Endpoint) CreateIcpVerifyTask(authorizerAccessToken string) string {
+	return fmt.Sprintf("%s/wxa/icp/create_icp_verifytask?access_token=%s", self.baseUrl, authorizerAccessToken)
+}
+
+func (self *Endpoint) QueryIcpVerifyTask(authorizerAccessToken string) string {
+	return fmt.Sprintf("%s/wxa/icp/query_icp_verifytask?access_token=%s", self.baseUrl, authorizerAccessToken)
+}
+
+func (self *Endpoint) UploadIcpMedia(authorizerAccessToken string) string {
+	return fmt.Sprintf("%s/wxa/icp/upload_icp_media?access_token=%s", self.baseUrl, authorizerAccessToken)
+}
+
+func (self *Endpoint) ApplyIcpFiling(authorizerAccessToken string) string {
+	return fmt.Sprintf("%s/wxa/icp/apply_icp_filing?access_token=%s", self.baseUrl, authorizerAccessToken)
+}
+
+func (self *Endpoint) CancelApplyIcpFiling(authorizerAccessToken string) string {
+	return fmt.Sprintf("%s/wxa/icp/cancel_apply_icp_filing?access_token=%s", self.baseUrl, authorizerAccessToken)
+}
+
+func (self *Endpoint) FastRegisterBetaWeapp(componentToken string) string {
+	return fmt.Sprintf("%s/wxa/component/fastregisterbetaweapp?access_token=%s", self.baseUrl, componentToken)
+}
+
+func (self *Endpoint) VerifyBetaWeapp(authorizerAccessToken string) string {
+	return fmt.Sprintf("%s/wxa/verifybetaweapp?access_token=%s", self.baseUrl, authorizerAccessToken)
+}
+
+func (self *Endpoint) FastRegisterPersonalWeapp(componentToken, action string) string {
+	return fmt.Sprintf("%s/wxa/component/fastregisterpersonalweapp?action=%s&component_access_token=%s", self.baseUrl, action, componentToken)
+}
+
+func (self *Endpoint) GetPaidUnionId(authorizerAccessToken, query string) string {
+	return fmt.Sprintf("%s/wxa/getpaidunionid?access_token=%s&%s", self.baseUrl, authorizerAccessToken, query)
+}
+
+func (self *Endpoint) GetUserEncryptKey(authorizerAccessToken, query string) string {
+	return fmt.Sprintf("%s/wxa/business/getuserencryptkey?access_token=%s&%s", self.baseUrl, authorizerAccessToken, query)
+}
+
+func (self *Endpoint) GetPrivacyInterface(authorizerAccessToken string) string {
+	return fmt.Sprintf("%s/wxa/security/get_privacy_interface?access_token=%s", self.baseUrl, authorizerAccessToken)
+}
+
+func (self *Endpoint) ApplyPrivacyInterface(authorizerAccessToken string) string {
+	return fmt.Sprintf("%s/wxa/security/apply_privacy_interface?access_token=%s", self.baseUrl, authorizerAccessToken)
+}
+
+func (self *Endpoint) UploadShippingInfo(authorizerAccessToken string) string {
+	return fmt.Sprintf("%s/wxa/sec/order/upload_shipping_info?access_token=%s", self.baseUrl, authorizerAccessToken)
+}
+
+func (self *Endpoint) GetShippingOrder(authorizerAccessToken string) string {
+	return fmt.Sprintf("%s/wxa/sec/order/get_order?access_token=%s", self.baseUrl, authorizerAccessToken)
+}
+
+func (self *Endpoint) IsTradeManaged(authorizerAccessToken string) string {
+	return fmt.Sprintf("%s/wxa/sec/order/is_trade_managed?access_token=%s", self.baseUrl, authorizerAccessToken)
+}
+
+func (self *Endpoint) GetUserRiskRank(authorizerAccessToken string) string {
+	return fmt.Sprintf("%s/wxa/getuserriskrank?access_token=%s", self.baseUrl, authorizerAccessToken)
+}
+
+func (self *Endpoint) GetOrderPathInfo(componentToken string) string {
+	return fmt.Sprintf("%s/wxaapi/wxamptrade/get_order_path_info?access_token=%s", self.baseUrl, componentToken)
+}
+
+func (self *Endpoint) ApplyOrderPathInfo(componentToken string) string {
+	return fmt.Sprintf("%s/wxaapi/wxamptrade/apply_order_path_info?access_token=%s", self.baseUrl, componentToken)
+}
+
+func (self *Endpoint) GenerateNfcScheme(authorizerAccessToken string) string {
+	return fmt.Sprintf("%s/wxa/generatenfcscheme?access_token=%s", self.baseUrl, authorizerAccessToken)
+}
+
+func (self *Endpoint) ComponentOAuth2Authorize(query string) string {
+	return fmt.Sprintf("%s?%s#wechat_redirect", OAuth2AuthorizeUrl, query)
+}
+
+func (self *Endpoint) SnsUserInfo(accessToken, openId, lang string) string {
+	return fmt.Sprintf("%s/sns/userinfo?access_token=%s&openid=%s&lang=%s", self.baseUrl, accessToken, openId, lang)
+}
+
+func (self *Endpoint) GetTicket(authorizerAccessToken, ticketType string) string {
+	return fmt.Sprintf("%s/cgi-bin/ticket/getticket?access_token=%s&type=%s", self.baseUrl, authorizerAccessToken, ticketType)
+}
+
+func (self *Endpoint) AddConditionalMenu(authorizerAccessToken string) string {
+	return fmt.Sprintf("%s/cgi-bin/menu/addconditional?access_token=%s", self.baseUrl, authorizerAccessToken)
+}
+
+func (self *Endpoint) DeleteConditionalMenu(authorizerAccessToken string) string {
+	return fmt.Sprintf("%s/cgi-bin/menu/delconditional?access_token=%s", self.baseUrl, authorizerAccessToken)
+}
+
+func (self *Endpoint) TryMatchMenu(authorizerAccessToken string) string {
+	return fmt.Sprintf("%s/cgi-bin/menu/trymatch?access_token=%s", self.baseUrl, authorizerAccessToken)
+}
+
+func (self *Endpoint) AddKfAccount(authorizerAccessToken string) string {
+	return fmt.Sprintf("%s/customservice/kfaccount/add?access_token=%s", self.baseUrl, authorizerAccessToken)
+}
+
+func (self *Endpoint) UpdateKfAccount(authorizerAccessToken string) string {
+	return fmt.Sprintf("%s/customservice/kfaccount/update?access_token=%s", self.baseUrl, authorizerAccessToken)
+}
+
+func (self *Endpoint) DeleteKfAccount(authorizerAccessToken, kfAccount string) string {
+	return fmt.Sprintf("%s/customservice/kfaccount/del?access_token=%s&kf_account=%s", self.baseUrl, authorizerAccessToken, kfAccount)
+}
+
+func (self *Endpoint) UploadKfHeadImg(authorizerAccessToken, kfAccount string) string {
+	return fmt.Sprintf("%s/customservice/kfaccount/uploadheadimg?access_token=%s&kf_account=%s", self.baseUrl, authorizerAccessToken, kfAccount)
+}
+
+func (self *Endpoint) GetKfList(authorizerAccessToken string) string {
+	return fmt.Sprintf("%s/cgi-bin/customservice/getkflist?access_token=%s", self.baseUrl, authorizerAccessToken)
+}
+
+func (self *Endpoint) GetOnlineKfList(authorizerAccessToken string) string {
+	return fmt.Sprintf("%s/cgi-bin/customservice/getonlinekflist?access_token=%s", self.baseUrl, authorizerAccessToken)
+}
+
+func (self *Endpoint) CreateKfSession(authorizerAccessToken string) string {
+	return fmt.Sprintf("%s/customservice/kfsession/create?access_token=%s", self.baseUrl, authorizerAccessToken)
+}
+
+func (self *Endpoint) CloseKfSession(authorizerAccessToken string) string {
+	return fmt.Sprintf("%s/customservice/kfsession/close?access_token=%s", self.baseUrl, authorizerAccessToken)
+}
+
+func (self *Endpoint) GetKfSession(authorizerAccessToken, openId string) string {
+	return fmt.Sprintf("%s/customservice/kfsession/getsession?access_token=%s&openid=%s", self.baseUrl, authorizerAccessToken, openId)
+}
+
+func (self *Endpoint) GetKfSessionList(authorizerAccessToken, kfAccount string) string {
+	return fmt.Sprintf("%s/customservice/kfsession/getsessionlist?access_token=%s&kf_account=%s", self.baseUrl, authorizerAccessToken, kfAccount)
+}
+
+func (self *Endpoint) GetKfWaitCase(authorizerAccessToken string) string {
+	return fmt.Sprintf("%s/customservice/kfsession/getwaitcase?access_token=%s", self.baseUrl, authorizerAccessToken)
+}
+
+func (self *Endpoint) GetKfMsgList(authorizerAccessToken string) string {
+	return fmt.Sprintf("%s/customservice/msgrecord/getmsglist?access_token=%s", self.baseUrl, authorizerAccessToken)
+}
+
+func (self *Endpoint) GetUserInfo(authorizerAccessToken, openId, lang string) string {
+	return fmt.Sprintf("%s/cgi-bin/user/info?access_token=%s&openid=%s&lang=%s", self.baseUrl, authorizerAccessToken, openId, lang)
+}
+
+func (self *Endpoint) BatchGetUserInfo(authorizerAccessToken string) string {
+	return fmt.Sprintf("%s/cgi-bin/user/info/batchget?access_token=%s", self.baseUrl, authorizerAccessToken)
+}
+
+func (self *Endpoint) WxampLinkGet(authorizerAccessToken string) string {
+	return fmt.Sprintf("%s/cgi-bin/wxopen/wxamplinkget?access_token=%s", self.baseUrl, authorizerAccessToken)
+}
+
+func (self *Endpoint) WxampLink(authorizerAccessToken string) string {
+	return fmt.Sprintf("%s/cgi-bin/wxopen/wxamplink?access_token=%s", self.baseUrl, authorizerAccessToken)
+}
+
+func (self *Endpoint) WxampUnlink(authorizerAccessToken string) string {
+	return fmt.Sprintf("%s/cgi-bin/wxopen/wxampunlink?access_token=%s", self.baseUrl, authorizerAccessToken)
+}
+
+func (self *Endpoint) CreateTag(authorizerAccessToken string) string {
+	return fmt.Sprintf("%s/cgi-bin/tags/create?access_token=%s", self.baseUrl, authorizerAccessToken)
+}
+
+func (self *Endpoint) GetTags(authorizerAccessToken string) string {
+	return fmt.Sprintf("%s/cgi-bin/tags/get?access_token=%s", self.baseUrl, authorizerAccessToken)
+}
+
+func (self *Endpoint) UpdateTag(authorizerAccessToken string) string {
+	return fmt.Sprintf("%s/cgi-bin/tags/update?access_token=%s", self.baseUrl, authorizerAccessToken)
+}
+
+func (self *Endpoint) DeleteTag(authorizerAccessToken string) string {
+	return fmt.Sprintf("%s/cgi-bin/tags/delete?access_token=%s", self.baseUrl, authorizerAccessToken)
+}
+
+func (self *Endpoint) BatchTagging(authorizerAccessToken string) string {
+	return fmt.Sprintf("%s/cgi-bin/tags/members/batchtagging?access_token=%s", self.baseUrl, authorizerAccessToken)
+}
+
+func (self *Endpoint) BatchUntagging(authorizerAccessToken string) string {
+	return fmt.Sprintf("%s/cgi-bin/tags/members/batchuntagging?access_token=%s", self.baseUrl, authorizerAccessToken)
+}
+
+func (self *Endpoint) GetUserTagIds(authorizerAccessToken string) string {
+	return fmt.Sprintf("%s/cgi-bin/tags/getidlist?access_token=%s", self.baseUrl, authorizerAccessToken)
+}
+
+func (self *Endpoint) GetTagUsers(authorizerAccessToken string) string {
+	return fmt.Sprintf("%s/cgi-bin/user/tag/get?access_token=%s", self.baseUrl, authorizerAccessToken)
+}
+
+func (self *Endpoint) OpenComment(authorizerAccessToken string) string {
+	return fmt.Sprintf("%s/cgi-bin/comment/open?access_token=%s", self.baseUrl, authorizerAccessToken)
+}
+
+func (self *Endpoint) CloseComment(authorizerAccessToken string) string {
+	return fmt.Sprintf("%s/cgi-bin/comment/close?access_token=%s", self.baseUrl, authorizerAccessToken)
+}
+
+func (self *Endpoint) ListComments(authorizerAccessToken string) string {
+	return fmt.Sprintf("%s/cgi-bin/comment/list?access_token=%s", self.baseUrl, authorizerAccessToken)
+}
+
+func (self *Endpoint) MarkElectComment(authorizerAccessToken string) string {
+	return fmt.Sprintf("%s/cgi-bin/comment/markelect?access_token=%s", self.baseUrl, authorizerAccessToken)
+}
+
+func (self *Endpoint) UnmarkElectComment(authorizerAccessToken string) string {
+	return fmt.Sprintf("%s/cgi-bin/comment/unmarkelect?access_token=%s", self.baseUrl, authorizerAccessToken)
+}
+
+func (self *Endpoint) DeleteComment(authorizerAccessToken string) string {
+	return fmt.Sprintf("%s/cgi-bin/comment/delete?access_token=%s", self.baseUrl, authorizerAccessToken)
+}
+
+func (self *Endpoint) ReplyComment(authorizerAccessToken string) string {
+	return fmt.Sprintf("%s/cgi-bin/comment/reply/add?access_token=%s", self.baseUrl, authorizerAccessToken)
+}
+
+func (self *Endpoint) DeleteCommentReply(authorizerAccessToken string) string {
+	return fmt.Sprintf("%s/cgi-bin/comment/reply/delete?access_token=%s", self.baseUrl, authorizerAccessToken)
+}
+
+func (self *Endpoint) UpdateUserRemark(authorizerAccessToken string) string {
+	return fmt.Sprintf("%s/cgi-bin/user/info/updateremark?access_token=%s", self.baseUrl, authorizerAccessToken)
+}
+
+func (self *Endpoint) GetBlacklist(authorizerAccessToken string) string {
+	return fmt.Sprintf("%s/cgi-bin/tags/members/getblacklist?access_token=%s", self.baseUrl, authorizerAccessToken)
+}
+
+func (self *Endpoint) BatchBlacklist(authorizerAccessToken string) string {
+	return fmt.Sprintf("%s/cgi-bin/tags/members/batchblacklist?access_token=%s", self.baseUrl, authorizerAccessToken)
+}
+
+func (self *Endpoint) BatchUnblacklist(authorizerAccessToken string) string {
+	return fmt.Sprintf("%s/cgi-bin/tags/members/batchunblacklist?access_token=%s", self.baseUrl, authorizerAccessToken)
+}
+
+func (self *Endpoint) CreateQrcode(authorizerAccessToken string) string {
+	return fmt.Sprintf("%s/cgi-bin/qrcode/create?access_token=%s", self.baseUrl, authorizerAccessToken)
+}
+
+func (self *Endpoint) ShowQrcode(ticket string) string {
+	return fmt.Sprintf("%s?ticket=%s", ShowQrcodeUrl, ticket)
+}
+
+func (self *Endpoint) GetMedia(authorizerAccessToken, mediaId string) string {
+	return fmt.Sprintf("%s/cgi-bin/media/get?access_token=%s&media_id=%s", self.baseUrl, authorizerAccessToken, mediaId)
+}
+
+func (self *Endpoint) AddMaterial(authorizerAccessToken, mediaType string) string {
+	return fmt.Sprintf("%s/cgi-bin/material/add_material?access_token=%s&type=%s", self.baseUrl, authorizerAccessToken, mediaType)
+}
+
+func (self *Endpoint) AddNews(authorizerAccessToken string) string {
+	return fmt.Sprintf("%s/cgi-bin/material/add_news?access_token=%s", self.baseUrl, authorizerAccessToken)
+}
+
+func (self *Endpoint) GetMaterial(authorizerAccessToken string) string {
+	return fmt.Sprintf("%s/cgi-bin/material/get_material?access_token=%s", self.baseUrl, authorizerAccessToken)
+}
+
+func (self *Endpoint) DeleteMaterial(authorizerAccessToken string) string {
+	return fmt.Sprintf("%s/cgi-bin/material/del_material?access_token=%s", self.baseUrl, authorizerAccessToken)
+}
+
+func (self *Endpoint) GetMaterialCount(authorizerAccessToken string) string {
+	return fmt.Sprintf("%s/cgi-bin/material/get_materialcount?access_token=%s", self.baseUrl, authorizerAccessToken)
+}
+
+func (self *Endpoint) BatchGetMaterial(authorizerAccessToken string) string {
+	return fmt.Sprintf("%s/cgi-bin/material/batchget_material?access_token=%s", self.baseUrl, authorizerAccessToken)
+}
+
+func (self *Endpoint) UploadArticleImage(authorizerAccessToken string) string {
+	return fmt.Sprintf("%s/cgi-bin/media/uploadimg?access_token=%s", self.baseUrl, authorizerAccessToken)
+}
+
+func (self *Endpoint) AddDraft(authorizerAccessToken string) string {
+	return fmt.Sprintf("%s/cgi-bin/draft/add?access_token=%s", self.baseUrl, authorizerAccessToken)
+}
+
+func (self *Endpoint) GetDraft(authorizerAccessToken string) string {
+	return fmt.Sprintf("%s/cgi-bin/draft/get?access_token=%s", self.baseUrl, authorizerAccessToken)
+}
+
+func (self *Endpoint) DeleteDraft(authorizerAccessToken string) string {
+	return fmt.Sprintf("%s/cgi-bin/draft/delete?access_token=%s", self.baseUrl, authorizerAccessToken)
+}
+
+func (self *Endpoint) UpdateDraft(authorizerAccessToken string) string {
+	return fmt.Sprintf("%s/cgi-bin/draft/update?access_token=%s", self.baseUrl, authorizerAccessToken)
+}
+
+func (self *Endpoint) GetDraftCount(authorizerAccessToken string) string {
+	return fmt.Sprintf("%s/cgi-bin/draft/count?access_token=%s", self.baseUrl, authorizerAccessToken)
+}
+
+func (self *Endpoint) BatchGetDraft(authorizerAccessToken string) string {
+	return fmt.Sprintf("%s/cgi-bin/draft/batchget?access_token=%s", self.baseUrl, authorizerAccessToken)
+}
+
+func (self *Endpoint) SubmitPublish(authorizerAccessToken string) string {
+	return fmt.Sprintf("%s/cgi-bin/freepublish/submit?access_token=%s", self.baseUrl, authorizerAccessToken)
+}
+
+func (self *Endpoint) GetPublishStatus(authorizerAccessToken string) string {
+	return fmt.Sprintf("%s/cgi-bin/freepublish/get?access_token=%s", self.baseUrl, authorizerAccessToken)
+}
+
+func (self *Endpoint) DeletePublished(authorizerAccessToken string) string {
+	return fmt.Sprintf("%s/cgi-bin/freepublish/delete?access_token=%s", self.baseUrl, authorizerAccessToken)
+}
+
+func (self *Endpoint) GetPublishedArticle(authorizerAccessToken string) string {
+	return fmt.Sprintf("%s/cgi-bin/freepublish/getarticle?access_token=%s", self.baseUrl, authorizerAccessToken)
+}
+
+func (self *Endpoint) BatchGetPublished(authorizerAccessToken string) string {
+	return fmt.Sprintf("%s/cgi-bin/freepublish/batchget?access_token=%s", self.baseUrl, authorizerAccessToken)
+}
+
+func (self *Endpoint) MassSendAll(authorizerAccessToken string) string {
+	return fmt.Sprintf("%s/cgi-bin/message/mass/sendall?access_token=%s", self.baseUrl, authorizerAccessToken)
+}
+
+func (self *Endpoint) MassSend(authorizerAccessToken string) string {
+	return fmt.Sprintf("%s/cgi-bin/message/mass/send?access_token=%s", self.baseUrl, authorizerAccessToken)
+}
+
+func (self *Endpoint) MassPreview(authorizerAccessToken string) string {
+	return fmt.Sprintf("%s/cgi-bin/message/mass/preview?access_token=%s", self.baseUrl, authorizerAccessToken)
+}
+
+func (self *Endpoint) MassGet(authorizerAccessToken string) string {
+	return fmt.Sprintf("%s/cgi-bin/message/mass/get?access_token=%s", self.baseUrl, authorizerAccessToken)
+}
+
+func (self *Endpoint) MassDelete(authorizerAccessToken string) string {
+	return fmt.Sprintf("%s/cgi-bin/message/mass/delete?access_token=%s", self.baseUrl, authorizerAccessToken)
+}
+
+func (self *Endpoint) GetCurrentAutoReplyInfo(authorizerAccessToken string) string {
+	return fmt.Sprintf("%s/cgi-bin/get_current_autoreply_info?access_token=%s", self.baseUrl, authorizerAccessToken)
+}
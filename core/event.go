@@ -0,0 +1,116 @@
+package core
+
+import "encoding/xml"
+
+const (
+	EventWxaMediaCheck         = "wxa_media_check"
+	EventFastRegisterBetaApp   = "notify_third_fastregisterbetaapp"
+	EventFastVerifyBetaApp     = "notify_third_fastverifybetaapp"
+	EventFastRegisterPersonal  = "notify_third_fastregisterpersonalweapp"
+	EventTemplateSendJobFinish = "TEMPLATESENDJOBFINISH"
+	EventPublishJobFinish      = "PUBLISHJOBFINISH"
+	EventMassSendJobFinish     = "MASSSENDJOBFINISH"
+)
+
+// MediaCheckEvent 音视频内容安全异步检测结果推送
+type MediaCheckEvent struct {
+	EventHeaderMessage
+	Event   string           `xml:"Event"`
+	AppId   string           `xml:"appid"`
+	TraceId string           `xml:"trace_id"`
+	Version int              `xml:"version"`
+	Detail  []SecurityDetail `xml:"detail"`
+	ErrCode int64            `xml:"errcode"`
+	ErrMsg  string           `xml:"errmsg"`
+	Result  SecurityResult   `xml:"result"`
+}
+
+// BetaAppEventHeader 试用小程序相关推送的公共字段, AppId为第三方平台appid, BetaAppId为试用小程序appid
+type BetaAppEventHeader struct {
+	XMLName    xml.Name `xml:"xml"`
+	AppId      string   `xml:"AppId"`
+	CreateTime int64    `xml:"CreateTime"`
+	InfoType   string   `xml:"InfoType"`
+	BetaAppId  string   `xml:"appid"`
+	Status     int64    `xml:"status"`
+	Msg        string   `xml:"msg"`
+}
+
+// FastRegisterBetaEvent 创建试用小程序结果推送
+type FastRegisterBetaEvent struct {
+	BetaAppEventHeader
+	Info struct {
+		UniqueId string `xml:"unique_id"`
+		Name     string `xml:"name"`
+	} `xml:"info"`
+}
+
+// VerifyBetaEvent 试用小程序转正结果推送
+type VerifyBetaEvent struct {
+	BetaAppEventHeader
+	Info struct {
+		Name               string `xml:"name"`
+		Code               string `xml:"code"`
+		CodeType           int    `xml:"code_type"`
+		LegalPersonaWechat string `xml:"legal_persona_wechat"`
+		LegalPersonaName   string `xml:"legal_persona_name"`
+		ComponentPhone     string `xml:"component_phone"`
+	} `xml:"info"`
+}
+
+// FastRegisterPersonalEvent 个人主体小程序注册结果推送, Status为0时注册成功
+type FastRegisterPersonalEvent struct {
+	XMLName    xml.Name `xml:"xml"`
+	AppId      string   `xml:"AppId"`
+	CreateTime int64    `xml:"CreateTime"`
+	InfoType   string   `xml:"InfoType"`
+	WeappAppId string   `xml:"appid"`
+	Status     int64    `xml:"status"`
+	Msg        string   `xml:"msg"`
+	Info       struct {
+		TaskId         string `xml:"taskid"`
+		IdName         string `xml:"idname"`
+		WxUser         string `xml:"wxuser"`
+		ComponentPhone string `xml:"component_phone"`
+	} `xml:"info"`
+}
+
+// TemplateSendJobFinishEvent 公众号模板消息发送结果推送, Status为success时发送成功,
+// failed:user block为用户拒收, failed: system failed为其它原因失败
+type TemplateSendJobFinishEvent struct {
+	EventHeaderMessage
+	Event  string `xml:"Event"`
+	MsgId  int64  `xml:"MsgID"`
+	Status string `xml:"Status"`
+}
+
+// PublishJobFinishEvent 公众号发布结果推送, PublishStatus为0时发布成功, FailIdx为失败的文章序号
+type PublishJobFinishEvent struct {
+	EventHeaderMessage
+	Event            string `xml:"Event"`
+	PublishEventInfo struct {
+		PublishId     string `xml:"publish_id"`
+		PublishStatus int    `xml:"publish_status"`
+		ArticleId     string `xml:"article_id"`
+		ArticleDetail struct {
+			Count int `xml:"count"`
+			Item  []struct {
+				Idx        int    `xml:"idx"`
+				ArticleUrl string `xml:"article_url"`
+			} `xml:"item"`
+		} `xml:"article_detail"`
+		FailIdx []int `xml:"fail_idx"`
+	} `xml:"PublishEventInfo"`
+}
+
+// MassSendJobFinishEvent 公众号群发结果推送, Status为send success时群发成功
+type MassSendJobFinishEvent struct {
+	EventHeaderMessage
+	Event       string `xml:"Event"`
+	MsgId       int64  `xml:"MsgID"`
+	Status      string `xml:"Status"`
+	TotalCount  int    `xml:"TotalCount"`
+	FilterCount int    `xml:"FilterCount"`
+	SentCount   int    `xml:"SentCount"`
+	ErrorCount  int    `xml:"ErrorCount"`
+}
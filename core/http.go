@@ -2,48 +2,196 @@ package core
 
 import (
 	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
 	"io/ioutil"
+	"mime/multipart"
 	"net/http"
+	"strings"
+	"time"
 )
 
+// ResponseTooLargeError 响应体超出HttpClient设置的最大字节数
+type ResponseTooLargeError struct {
+	Limit int64
+}
+
+func (self *ResponseTooLargeError) Error() string {
+	return fmt.Sprintf("响应体超过%d字节", self.Limit)
+}
+
+// ContentTypeJSON JSON请求的Content-Type
+const ContentTypeJSON = "application/json"
+
 type HttpClient struct {
-	http *http.Client
+	http      *http.Client
+	userAgent string
+	limiter   RateLimiter
+	// maxResponseBytes 响应体最大字节数, 小于等于0时不限制
+	maxResponseBytes int64
+	// timeout 单次请求超时时间, 小于等于0时不限制
+	timeout time.Duration
 }
 
 func NewHttpClient() *HttpClient {
 	return &HttpClient{
-		http: &http.Client{},
+		http:      &http.Client{},
+		userAgent: DefaultUserAgent,
+	}
+}
+
+// SetUserAgent 设置请求User-Agent, 为空时使用默认值
+func (self *HttpClient) SetUserAgent(userAgent string) {
+	if userAgent == "" {
+		userAgent = DefaultUserAgent
+	}
+	self.userAgent = userAgent
+}
+
+// SetHttpClient 使用指定的http.Client发起请求, 为nil时忽略
+func (self *HttpClient) SetHttpClient(client *http.Client) {
+	if client != nil {
+		self.http = client
 	}
 }
 
+// SetTimeout 设置单次请求(含读取响应体)的超时时间, 通过请求的context实现, 与http.Client.Timeout同时生效, 小于等于0时不限制
+func (self *HttpClient) SetTimeout(timeout time.Duration) {
+	self.timeout = timeout
+}
+
+// SetMaxResponseBytes 设置响应体最大字节数, 超出时返回*ResponseTooLargeError, 小于等于0时不限制(默认)
+func (self *HttpClient) SetMaxResponseBytes(limit int64) {
+	self.maxResponseBytes = limit
+}
+
+// SetRateLimiter 设置限流器, 为nil时不限流
+func (self *HttpClient) SetRateLimiter(limiter RateLimiter) {
+	self.limiter = limiter
+}
+
 func (self *HttpClient) Get(url string) (status int, body []byte, err error) {
-	resp, err := self.http.Get(url)
+	return self.GetContext(context.Background(), url)
+}
+
+func (self *HttpClient) Post(url, contentType string, data []byte) (status int, body []byte, err error) {
+	return self.PostContext(context.Background(), url, contentType, data)
+}
+
+// GetContext 发起GET请求, ctx取消时中止请求
+func (self *HttpClient) GetContext(ctx context.Context, url string) (status int, body []byte, err error) {
+	return self.do(ctx, http.MethodGet, url, "", nil)
+}
+
+// PostContext 发起POST请求, ctx取消时中止请求
+func (self *HttpClient) PostContext(ctx context.Context, url, contentType string, data []byte) (status int, body []byte, err error) {
+	return self.do(ctx, http.MethodPost, url, contentType, bytes.NewReader(data))
+}
+
+// PostJSON 将v编码为JSON后以application/json提交, 编码失败时不发起请求
+func (self *HttpClient) PostJSON(ctx context.Context, url string, v interface{}) (status int, body []byte, err error) {
+	data, err := json.Marshal(v)
 	if err != nil {
-		return http.StatusInternalServerError, nil, err
+		return 0, nil, err
 	}
-	defer func() {
-		_ = resp.Body.Close()
-	}()
-	body, err = ioutil.ReadAll(resp.Body)
+	return self.PostContext(ctx, url, ContentTypeJSON, data)
+}
+
+// GetWithHeader 发起GET请求, 同时返回响应头, 用于需要根据Content-Type区分响应格式的接口
+func (self *HttpClient) GetWithHeader(ctx context.Context, url string) (status int, header http.Header, body []byte, err error) {
+	return self.doWithHeader(ctx, http.MethodGet, url, "", nil)
+}
+
+// PostJSONWithHeader 将v编码为JSON后提交, 同时返回响应头
+func (self *HttpClient) PostJSONWithHeader(ctx context.Context, url string, v interface{}) (status int, header http.Header, body []byte, err error) {
+	data, err := json.Marshal(v)
 	if err != nil {
-		return http.StatusBadRequest, nil, err
+		return 0, nil, nil, err
 	}
-	return resp.StatusCode, body, nil
+	return self.doWithHeader(ctx, http.MethodPost, url, ContentTypeJSON, bytes.NewReader(data))
 }
 
-func (self *HttpClient) Post(url, contentType string, data []byte) (status int, body []byte, err error) {
-	resp, err := self.http.Post(url, contentType, bytes.NewReader(data))
+// PostMultipart 以multipart/form-data格式上传文件, fields为附加的表单字段
+func (self *HttpClient) PostMultipart(url, fieldName, filename string, file io.Reader, fields map[string]string) (status int, body []byte, err error) {
+	buf := &bytes.Buffer{}
+	writer := multipart.NewWriter(buf)
+	for key, value := range fields {
+		if err := writer.WriteField(key, value); err != nil {
+			return http.StatusInternalServerError, nil, err
+		}
+	}
+	part, err := writer.CreateFormFile(fieldName, filename)
 	if err != nil {
 		return http.StatusInternalServerError, nil, err
 	}
+	if _, err := io.Copy(part, file); err != nil {
+		return http.StatusInternalServerError, nil, err
+	}
+	if err := writer.Close(); err != nil {
+		return http.StatusInternalServerError, nil, err
+	}
+	return self.do(context.Background(), http.MethodPost, url, writer.FormDataContentType(), buf)
+}
+
+func (self *HttpClient) do(ctx context.Context, method, url, contentType string, data io.Reader) (status int, body []byte, err error) {
+	status, _, body, err = self.doWithHeader(ctx, method, url, contentType, data)
+	return status, body, err
+}
+
+func (self *HttpClient) doWithHeader(ctx context.Context, method, url, contentType string, data io.Reader) (status int, header http.Header, body []byte, err error) {
+	if self.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, self.timeout)
+		defer cancel()
+	}
+	req, err := http.NewRequestWithContext(ctx, method, url, data)
+	if err != nil {
+		return http.StatusInternalServerError, nil, nil, err
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	req.Header.Set("User-Agent", self.userAgent)
+	if self.limiter != nil {
+		if err := self.limiter.Wait(ctx, req.URL.Path); err != nil {
+			return http.StatusTooManyRequests, nil, nil, err
+		}
+	}
+	resp, err := self.http.Do(req)
+	if err != nil {
+		return http.StatusInternalServerError, nil, nil, err
+	}
 	defer func() {
 		_ = resp.Body.Close()
 	}()
-	body, err = ioutil.ReadAll(resp.Body)
+	// Transport仅在自行添加Accept-Encoding时透明解压, 代理等主动返回的gzip响应需要手动解压
+	var reader io.Reader = resp.Body
+	if !resp.Uncompressed && strings.EqualFold(resp.Header.Get("Content-Encoding"), "gzip") {
+		gz, err := gzip.NewReader(resp.Body)
+		if err != nil {
+			return resp.StatusCode, resp.Header, nil, err
+		}
+		defer func() {
+			_ = gz.Close()
+		}()
+		reader = gz
+	}
+	limit := self.maxResponseBytes
+	if limit <= 0 {
+		body, err = ioutil.ReadAll(reader)
+	} else {
+		body, err = ioutil.ReadAll(io.LimitReader(reader, limit+1))
+	}
 	if err != nil {
-		return http.StatusBadRequest, nil, err
+		return http.StatusBadRequest, resp.Header, nil, err
+	}
+	if limit > 0 && int64(len(body)) > limit {
+		return resp.StatusCode, resp.Header, nil, &ResponseTooLargeError{Limit: limit}
 	}
-	return resp.StatusCode, body, nil
+	return resp.StatusCode, resp.Header, body, nil
 }
 
 func (self *HttpClient) ReadXML(r *http.Request) []byte {
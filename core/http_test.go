@@ -0,0 +1,182 @@
+package core
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+func newBodyServer(t *testing.T, body []byte) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(body)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestResponseSizeUnlimitedByDefault(t *testing.T) {
+	payload := bytes.Repeat([]byte("x"), 11<<20)
+	server := newBodyServer(t, payload)
+
+	_, body, err := NewHttpClient().Get(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(body) != len(payload) {
+		t.Fatalf("got %d bytes, want %d", len(body), len(payload))
+	}
+}
+
+func TestResponseSizeLimit(t *testing.T) {
+	server := newBodyServer(t, bytes.Repeat([]byte("x"), 101))
+	client := NewHttpClient()
+	client.SetMaxResponseBytes(100)
+
+	_, _, err := client.Get(server.URL)
+	var tooLarge *ResponseTooLargeError
+	if !errors.As(err, &tooLarge) || tooLarge.Limit != 100 {
+		t.Fatalf("got %v, want *ResponseTooLargeError with limit 100", err)
+	}
+
+	client.SetMaxResponseBytes(101)
+	if _, body, err := client.Get(server.URL); err != nil || len(body) != 101 {
+		t.Fatalf("at the limit: got (%d bytes, %v)", len(body), err)
+	}
+}
+
+// newGzipServer 无论请求是否声明Accept-Encoding都返回gzip压缩的JSON, 模拟强制压缩的代理
+func newGzipServer(t *testing.T, body string) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Content-Encoding", "gzip")
+		gz := gzip.NewWriter(w)
+		_, _ = gz.Write([]byte(body))
+		_ = gz.Close()
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestGzipResponse(t *testing.T) {
+	server := newGzipServer(t, `{"component_access_token":"TOKEN","expires_in":7200}`)
+	for name, transport := range map[string]*http.Transport{
+		"transparent": {},
+		"manual":      {DisableCompression: true},
+	} {
+		client := NewHttpClient()
+		client.SetHttpClient(&http.Client{Transport: transport})
+
+		_, body, err := client.Get(server.URL)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		var token struct {
+			ComponentAccessToken string `json:"component_access_token"`
+			ExpiresIn            int64  `json:"expires_in"`
+		}
+		if err := json.Unmarshal(body, &token); err != nil {
+			t.Fatalf("%s: body not decoded: %v, body: %q", name, err, body)
+		}
+		if token.ComponentAccessToken != "TOKEN" || token.ExpiresIn != 7200 {
+			t.Fatalf("%s: got %+v", name, token)
+		}
+	}
+}
+
+// recordingServer 记录最后一次请求的请求头和请求体
+type recordingServer struct {
+	*httptest.Server
+	header   atomic.Value
+	body     atomic.Value
+	requests int32
+}
+
+func newRecordingServer(t *testing.T) *recordingServer {
+	server := &recordingServer{}
+	server.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		server.header.Store(r.Header.Clone())
+		server.body.Store(body)
+		atomic.AddInt32(&server.requests, 1)
+		_, _ = w.Write([]byte(`{"errcode":0}`))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func (self *recordingServer) lastHeader() http.Header {
+	header, _ := self.header.Load().(http.Header)
+	return header
+}
+
+func (self *recordingServer) lastBody() []byte {
+	body, _ := self.body.Load().([]byte)
+	return body
+}
+
+func TestPostJSON(t *testing.T) {
+	server := newRecordingServer(t)
+
+	status, body, err := NewHttpClient().PostJSON(context.Background(), server.URL, map[string]interface{}{"scene": "a&b<c>"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status != http.StatusOK || string(body) != `{"errcode":0}` {
+		t.Fatalf("got (%d, %s)", status, body)
+	}
+	if contentType := server.lastHeader().Get("Content-Type"); contentType != ContentTypeJSON {
+		t.Fatalf("Content-Type: got %q, want %q", contentType, ContentTypeJSON)
+	}
+	var sent map[string]interface{}
+	if err := json.Unmarshal(server.lastBody(), &sent); err != nil || sent["scene"] != "a&b<c>" {
+		t.Fatalf("body: got %s (%v)", server.lastBody(), err)
+	}
+}
+
+func TestPostJSONMarshalError(t *testing.T) {
+	server := newRecordingServer(t)
+
+	_, _, err := NewHttpClient().PostJSON(context.Background(), server.URL, map[string]interface{}{"bad": make(chan int)})
+	var unsupported *json.UnsupportedTypeError
+	if !errors.As(err, &unsupported) {
+		t.Fatalf("got %v, want *json.UnsupportedTypeError", err)
+	}
+	if n := atomic.LoadInt32(&server.requests); n != 0 {
+		t.Fatalf("sent %d requests after a marshal error", n)
+	}
+}
+
+func TestUserAgent(t *testing.T) {
+	server := newRecordingServer(t)
+	client := NewHttpClient()
+
+	if _, _, err := client.Get(server.URL); err != nil {
+		t.Fatal(err)
+	}
+	if got := server.lastHeader().Get("User-Agent"); got != DefaultUserAgent {
+		t.Fatalf("default User-Agent: got %q, want %q", got, DefaultUserAgent)
+	}
+
+	client.SetUserAgent("my-app/1.0")
+	if _, _, err := client.PostJSON(context.Background(), server.URL, nil); err != nil {
+		t.Fatal(err)
+	}
+	if got := server.lastHeader().Get("User-Agent"); got != "my-app/1.0" {
+		t.Fatalf("custom User-Agent: got %q", got)
+	}
+
+	client.SetUserAgent("")
+	if _, _, err := client.Get(server.URL); err != nil {
+		t.Fatal(err)
+	}
+	if got := server.lastHeader().Get("User-Agent"); got != DefaultUserAgent {
+		t.Fatalf("reset User-Agent: got %q, want %q", got, DefaultUserAgent)
+	}
+}
@@ -0,0 +1,20 @@
+package core
+
+import "log"
+
+// Logger 日志接口, *log.Logger可直接使用
+type Logger interface {
+	Printf(format string, v ...interface{})
+}
+
+// ErrorReporter 上报不影响调用结果的内部错误, 如缓存写入失败
+type ErrorReporter func(err error)
+
+type stdLogger struct{}
+
+func (stdLogger) Printf(format string, v ...interface{}) {
+	log.Printf(format, v...)
+}
+
+// DefaultLogger 默认日志, 输出到标准库log
+var DefaultLogger Logger = stdLogger{}
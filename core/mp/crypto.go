@@ -0,0 +1,64 @@
+package mp
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"github.com/mrwangjinjin/go-wechat/core/open"
+)
+
+// DecryptData 使用session_key和iv解密小程序encryptedData(AES-128-CBC, PKCS#7填充)
+func DecryptData(sessionKey, encryptedData, iv string) ([]byte, error) {
+	key, err := base64.StdEncoding.DecodeString(sessionKey)
+	if err != nil || len(key) != 16 {
+		return nil, errors.New("session_key格式错误")
+	}
+	ivBytes, err := base64.StdEncoding.DecodeString(iv)
+	if err != nil || len(ivBytes) != aes.BlockSize {
+		return nil, errors.New("iv格式错误")
+	}
+	ciphertext, err := base64.StdEncoding.DecodeString(encryptedData)
+	if err != nil || len(ciphertext) == 0 || len(ciphertext)%aes.BlockSize != 0 {
+		return nil, errors.New("encryptedData格式错误")
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	plaintext := make([]byte, len(ciphertext))
+	cipher.NewCBCDecrypter(block, ivBytes).CryptBlocks(plaintext, ciphertext)
+	return pkcs7Unpad(plaintext)
+}
+
+func pkcs7Unpad(data []byte) ([]byte, error) {
+	pad := int(data[len(data)-1])
+	if pad == 0 || pad > aes.BlockSize || pad > len(data) {
+		return nil, errors.New("解密失败, session_key可能已过期")
+	}
+	if !bytes.Equal(data[len(data)-pad:], bytes.Repeat([]byte{byte(pad)}, pad)) {
+		return nil, errors.New("解密失败, session_key可能已过期")
+	}
+	return data[:len(data)-pad], nil
+}
+
+// DecryptPhoneNumber 解密getPhoneNumber返回的手机号, 并校验数据水印appid
+func DecryptPhoneNumber(appId, sessionKey, encryptedData, iv string) (*open.PhoneInfo, error) {
+	plaintext, err := DecryptData(sessionKey, encryptedData, iv)
+	if err != nil {
+		return nil, err
+	}
+	var info open.PhoneInfo
+	if err := json.Unmarshal(plaintext, &info); err != nil {
+		return nil, err
+	}
+	if info.Watermark.AppId != appId {
+		return nil, &open.WatermarkMismatchError{
+			AppId:          appId,
+			WatermarkAppId: info.Watermark.AppId,
+		}
+	}
+	return &info, nil
+}
@@ -0,0 +1,78 @@
+package mp
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/base64"
+	"errors"
+	"github.com/mrwangjinjin/go-wechat/core/open"
+	"testing"
+)
+
+// 微信官方文档中的解密示例数据
+const (
+	sampleAppId         = "wx4f4bc4dec97d474b"
+	sampleSessionKey    = "tiihtNczf5v6AKRyjwEUhQ=="
+	sampleIv            = "r7BXXKkLb8qrSNn05n0qiA=="
+	sampleEncryptedData = "CiyLU1Aw2KjvrjMdj8YKliAjtP4gsMZMQmRzooG2xrDcvSnxIMXFufNstNGTyaGS9uT5geRa0W4oTOb1WT7fJlAC+oNPdbB+3hVbJSRgv+4lGOETKUQz6OYStslQ142dNCuabNPGBzlooOmB231qMM85d2/fV6ChevvXvQP8Hkue1poOFtnEtpyxVLW1zAo6/1Xx1COxFvrc2d7UL/lmHInNlxuacJXwu0fjpXfz/YqYzBIBzD6WUfTIF9GRHpOn/Hz7saL8xz+W//FRAUid1OksQaQx4CMs8LOddcQhULW4ucetDf96JcR3g0gfRK4PC7E/r7Z6xNrXd2UIeorGj5Ef7b1pJAYB6Y5anaHqZ9J6nKEBvB4DnNLIVWSgARns/8wR2SiRS7MNACwTyrGvt9ts8p12PKFdlqYTopNHR1Vf7XjfhQlVsAJdNiKdYmYVoKlaRv85IfVunYzO0IKXsyl7JCUjCpoG20f0a04COwfneQAGGwd5oa+T8yO5hzuyDb/XcxxmK01EpqOyuxINew=="
+	samplePlaintext     = `{"openId":"oGZUI0egBJY1zhBYw2KhdUfwVJJE","nickName":"Band","gender":1,"language":"zh_CN","city":"Guangzhou","province":"Guangdong","country":"CN","avatarUrl":"http://wx.qlogo.cn/mmopen/vi_32/aSKcBBPpibyKNicHNTMM0qJVh8Kjgiak2AHWr8MHM4WgMEm7GFhsf8OYrySdbvAMvTsw3mo8ibKicsnfN5pRjl1p8HQ/0","unionId":"ocMvos6NjeKLIBqg5Mr9QjxrP1FA","watermark":{"timestamp":1477314187,"appid":"wx4f4bc4dec97d474b"}}`
+)
+
+// encryptSample 使用示例session_key和iv加密plaintext, 生成encryptedData
+func encryptSample(t *testing.T, plaintext string) string {
+	t.Helper()
+	key, _ := base64.StdEncoding.DecodeString(sampleSessionKey)
+	iv, _ := base64.StdEncoding.DecodeString(sampleIv)
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pad := aes.BlockSize - len(plaintext)%aes.BlockSize
+	data := append([]byte(plaintext), bytes.Repeat([]byte{byte(pad)}, pad)...)
+	cipher.NewCBCEncrypter(block, iv).CryptBlocks(data, data)
+	return base64.StdEncoding.EncodeToString(data)
+}
+
+func TestDecryptDataSample(t *testing.T) {
+	plaintext, err := DecryptData(sampleSessionKey, sampleEncryptedData, sampleIv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(plaintext) != samplePlaintext {
+		t.Fatalf("got %s", plaintext)
+	}
+}
+
+func TestDecryptDataErrors(t *testing.T) {
+	for name, args := range map[string][3]string{
+		"session_key not base64": {"!!!", sampleEncryptedData, sampleIv},
+		"session_key length":     {"AAAA", sampleEncryptedData, sampleIv},
+		"iv length":              {sampleSessionKey, sampleEncryptedData, "AAAA"},
+		"encryptedData empty":    {sampleSessionKey, "", sampleIv},
+		"encryptedData blocks":   {sampleSessionKey, "AAAA", sampleIv},
+		"wrong session_key":      {"AAAAAAAAAAAAAAAAAAAAAA==", sampleEncryptedData, sampleIv},
+	} {
+		if _, err := DecryptData(args[0], args[1], args[2]); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
+
+func TestDecryptPhoneNumber(t *testing.T) {
+	encrypted := encryptSample(t, `{"phoneNumber":"+86 13580006666","purePhoneNumber":"13580006666","countryCode":"86","watermark":{"timestamp":1637744274,"appid":"`+sampleAppId+`"}}`)
+
+	info, err := DecryptPhoneNumber(sampleAppId, sampleSessionKey, encrypted, sampleIv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.PurePhoneNumber != "13580006666" || info.CountryCode != "86" {
+		t.Fatalf("unexpected phone info: %+v", info)
+	}
+
+	_, err = DecryptPhoneNumber("wx_other", sampleSessionKey, encrypted, sampleIv)
+	var mismatch *open.WatermarkMismatchError
+	if !errors.As(err, &mismatch) || mismatch.WatermarkAppId != sampleAppId {
+		t.Fatalf("got %v, want *open.WatermarkMismatchError", err)
+	}
+}
@@ -0,0 +1,206 @@
+package open
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	auditMaxItems    = 5
+	auditMaxTags     = 10
+	auditMaxTagLen   = 20
+	auditMaxTitleLen = 32
+)
+
+// UGC场景
+const (
+	UgcSceneNone    = 0 // 不涉及用户生成内容
+	UgcSceneProfile = 1 // 用户资料
+	UgcSceneImage   = 2 // 图片
+	UgcSceneVideo   = 3 // 视频
+	UgcSceneText    = 4 // 文本
+	UgcSceneOther   = 5 // 其他
+)
+
+// UGC内容安全机制
+const (
+	UgcMethodSecApi    = 1 // 使用平台建议的内容安全API
+	UgcMethodOtherApi  = 2 // 使用其他的内容审核产品
+	UgcMethodManual    = 3 // 通过人工审核把关
+	UgcMethodNoControl = 4 // 未做内容审核把关
+)
+
+// AuditItem 提交审核的页面配置
+type AuditItem struct {
+	Address     string `json:"address"`
+	Tag         string `json:"tag,omitempty"`
+	FirstClass  string `json:"first_class,omitempty"`
+	SecondClass string `json:"second_class,omitempty"`
+	ThirdClass  string `json:"third_class,omitempty"`
+	FirstId     int64  `json:"first_id,omitempty"`
+	SecondId    int64  `json:"second_id,omitempty"`
+	ThirdId     int64  `json:"third_id,omitempty"`
+	Title       string `json:"title,omitempty"`
+}
+
+// PreviewInfo 提交审核的预览信息
+type PreviewInfo struct {
+	VideoIdList []string `json:"video_id_list,omitempty"`
+	PicIdList   []string `json:"pic_id_list,omitempty"`
+}
+
+// UgcDeclare 用户生成内容场景信息
+type UgcDeclare struct {
+	Scene          []int  `json:"scene,omitempty"`
+	OtherSceneDesc string `json:"other_scene_desc,omitempty"`
+	Method         []int  `json:"method,omitempty"`
+	HasAuditTeam   int    `json:"has_audit_team,omitempty"`
+	AuditDesc      string `json:"audit_desc,omitempty"`
+}
+
+// SubmitAuditRequest 提交审核请求, 未填写的字段不会提交; Extra用于提交尚未支持的新字段
+type SubmitAuditRequest struct {
+	ItemList         []AuditItem            `json:"item_list,omitempty"`
+	PreviewInfo      *PreviewInfo           `json:"preview_info,omitempty"`
+	VersionDesc      string                 `json:"version_desc,omitempty"`
+	FeedbackInfo     string                 `json:"feedback_info,omitempty"`
+	FeedbackStuff    string                 `json:"feedback_stuff,omitempty"`
+	UgcDeclare       *UgcDeclare            `json:"ugc_declare,omitempty"`
+	PrivacyApiNotUse bool                   `json:"privacy_api_not_use,omitempty"`
+	OrderPath        string                 `json:"order_path,omitempty"`
+	Extra            map[string]interface{} `json:"-"`
+}
+
+// MarshalJSON 合并Extra中的字段, 已定义的字段优先
+func (self SubmitAuditRequest) MarshalJSON() ([]byte, error) {
+	type request SubmitAuditRequest
+	body, err := json.Marshal(request(self))
+	if err != nil || len(self.Extra) == 0 {
+		return body, err
+	}
+	data := map[string]interface{}{}
+	if err := json.Unmarshal(body, &data); err != nil {
+		return nil, err
+	}
+	for key, value := range self.Extra {
+		if _, ok := data[key]; !ok {
+			data[key] = value
+		}
+	}
+	return json.Marshal(data)
+}
+
+func (self *SubmitAuditRequest) validate() error {
+	if len(self.ItemList) > auditMaxItems {
+		return errors.New("审核项最多5个")
+	}
+	for i, item := range self.ItemList {
+		if item.Address == "" {
+			return fmt.Errorf("第%d个审核项缺少address", i+1)
+		}
+		if item.Tag != "" {
+			tags := strings.Fields(item.Tag)
+			if len(tags) > auditMaxTags {
+				return fmt.Errorf("第%d个审核项标签最多10个", i+1)
+			}
+			for _, tag := range tags {
+				if utf8.RuneCountInString(tag) > auditMaxTagLen {
+					return fmt.Errorf("第%d个审核项标签长度不能超过20个字符", i+1)
+				}
+			}
+		}
+		if utf8.RuneCountInString(item.Title) > auditMaxTitleLen {
+			return fmt.Errorf("第%d个审核项标题长度不能超过32个字符", i+1)
+		}
+	}
+	if self.UgcDeclare != nil {
+		for _, scene := range self.UgcDeclare.Scene {
+			if scene < UgcSceneNone || scene > UgcSceneOther {
+				return errors.New("ugc_declare.scene取值范围为0-5")
+			}
+			if scene == UgcSceneNone && len(self.UgcDeclare.Scene) > 1 {
+				return errors.New("ugc_declare.scene为0时不能同时填写其他场景")
+			}
+			if scene == UgcSceneOther && self.UgcDeclare.OtherSceneDesc == "" {
+				return errors.New("ugc_declare.scene包含其他时必须填写other_scene_desc")
+			}
+		}
+		for _, method := range self.UgcDeclare.Method {
+			if method < UgcMethodSecApi || method > UgcMethodNoControl {
+				return errors.New("ugc_declare.method取值范围为1-4")
+			}
+		}
+		if self.UgcDeclare.HasAuditTeam != 0 && self.UgcDeclare.HasAuditTeam != 1 {
+			return errors.New("ugc_declare.has_audit_team取值为0或1")
+		}
+	}
+	return nil
+}
+
+// CodeResponse 代码管理接口的响应
+type CodeResponse struct {
+	ErrCode int64  `json:"errcode"`
+	ErrMsg  string `json:"errmsg"`
+}
+
+// SubmitAuditResponse 提交审核的响应
+type SubmitAuditResponse struct {
+	CodeResponse
+	AuditId int64 `json:"auditid"`
+}
+
+// SubmitAuditWithRequest 提交审核, 返回审核编号auditid
+//
+// Deprecated: 使用SubmitAuditWithResponse
+func (self *Client) SubmitAuditWithRequest(authorizerAccessToken string, req SubmitAuditRequest) (int64, error) {
+	resp, err := self.SubmitAuditWithResponse(authorizerAccessToken, req)
+	if err != nil {
+		return 0, err
+	}
+	return resp.AuditId, nil
+}
+
+// SubmitAuditWithResponse 提交审核, 返回包含审核编号auditid的完整响应, 提交前在本地校验请求
+// 试运行模式下不发送请求, 返回的AuditId为0
+func (self *Client) SubmitAuditWithResponse(authorizerAccessToken string, req SubmitAuditRequest) (*SubmitAuditResponse, error) {
+	if err := req.validate(); err != nil {
+		return nil, err
+	}
+	if ok, err := self.dryRun(self.Endpoint.SubmitAudit(authorizerAccessToken), req); ok {
+		if err != nil {
+			return nil, err
+		}
+		return &SubmitAuditResponse{CodeResponse: CodeResponse{ErrMsg: "ok"}}, nil
+	}
+	var resp SubmitAuditResponse
+	if err := self.postJSON(self.Endpoint.SubmitAudit(authorizerAccessToken), req, &resp); err != nil {
+		return nil, err
+	}
+	if resp.AuditId == 0 {
+		return nil, fmt.Errorf("响应缺少auditid: %s", resp.ErrMsg)
+	}
+	return &resp, nil
+}
+
+// SubmitAuditTyped 提交审核, 返回审核编号auditid
+//
+// Deprecated: 使用SubmitAuditWithResponse
+func (self *Client) SubmitAuditTyped(authorizerAccessToken string, items []AuditItem, previewInfo *PreviewInfo, versionDesc, feedbackInfo, feedbackStuff string) (int64, error) {
+	if len(items) == 0 {
+		return 0, errors.New("审核项不能为空")
+	}
+	resp, err := self.SubmitAuditWithResponse(authorizerAccessToken, SubmitAuditRequest{
+		ItemList:      items,
+		PreviewInfo:   previewInfo,
+		VersionDesc:   versionDesc,
+		FeedbackInfo:  feedbackInfo,
+		FeedbackStuff: feedbackStuff,
+	})
+	if err != nil {
+		return 0, err
+	}
+	return resp.AuditId, nil
+}
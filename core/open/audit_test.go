@@ -0,0 +1,135 @@
+package open
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSubmitAuditWithResponse(t *testing.T) {
+	server := newTestServer(t)
+	server.respond("/wxa/submit_audit", `{"errcode":0,"errmsg":"ok","auditid":1234567}`)
+	client, _ := newTestClient(t, server)
+
+	resp, err := client.SubmitAuditWithResponse(testAuthorizerToken, SubmitAuditRequest{
+		ItemList:    []AuditItem{{Address: "pages/index/index", Tag: "工具 效率", Title: "首页"}},
+		VersionDesc: "desc",
+		UgcDeclare:  &UgcDeclare{Scene: []int{UgcSceneNone}},
+		Extra:       map[string]interface{}{"new_field": "value", "version_desc": "ignored"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.AuditId != 1234567 || resp.ErrMsg != "ok" {
+		t.Fatalf("unexpected response: %+v", resp)
+	}
+	req := server.lastRequest(t)
+	if got := req.Query["access_token"]; len(got) != 1 || got[0] != testAuthorizerToken {
+		t.Fatalf("access_token: got %v", got)
+	}
+	want := `{"item_list":[{"address":"pages/index/index","tag":"工具 效率","title":"首页"}],"new_field":"value","ugc_declare":{"scene":[0]},"version_desc":"desc"}`
+	if string(req.Body) != want {
+		t.Fatalf("body:\n got %s\nwant %s", req.Body, want)
+	}
+}
+
+func TestSubmitAuditMissingAuditId(t *testing.T) {
+	server := newTestServer(t)
+	client, _ := newTestClient(t, server)
+
+	if _, err := client.SubmitAuditWithResponse(testAuthorizerToken, SubmitAuditRequest{}); err == nil {
+		t.Fatal("expected error for response without auditid")
+	}
+}
+
+func TestSubmitAuditDeprecatedEntryPoints(t *testing.T) {
+	server := newTestServer(t)
+	server.respond("/wxa/submit_audit", `{"errcode":0,"errmsg":"ok","auditid":42}`)
+	client, _ := newTestClient(t, server)
+	items := []AuditItem{{Address: "pages/index/index"}}
+
+	for name, submit := range map[string]func() (int64, error){
+		"SubmitAuditWithResult": func() (int64, error) {
+			return client.SubmitAuditWithResult(testAuthorizerToken, map[string]interface{}{"item_list": items})
+		},
+		"SubmitAuditWithRequest": func() (int64, error) {
+			return client.SubmitAuditWithRequest(testAuthorizerToken, SubmitAuditRequest{ItemList: items})
+		},
+		"SubmitAuditTyped": func() (int64, error) {
+			return client.SubmitAuditTyped(testAuthorizerToken, items, nil, "", "", "")
+		},
+	} {
+		auditId, err := submit()
+		if err != nil || auditId != 42 {
+			t.Errorf("%s: got (%d, %v), want (42, nil)", name, auditId, err)
+			continue
+		}
+		if body := string(server.lastRequest(t).Body); body != `{"item_list":[{"address":"pages/index/index"}]}` {
+			t.Errorf("%s: unexpected body %s", name, body)
+		}
+	}
+	if err := client.SubmitAudit(testAuthorizerToken, map[string]interface{}{"item_list": items}); err != nil {
+		t.Fatalf("SubmitAudit: %v", err)
+	}
+}
+
+func TestSubmitAuditValidation(t *testing.T) {
+	server := newTestServer(t)
+	client, _ := newTestClient(t, server)
+
+	for name, req := range map[string]SubmitAuditRequest{
+		"too many items":     {ItemList: make([]AuditItem, auditMaxItems+1)},
+		"missing address":    {ItemList: []AuditItem{{Title: "首页"}}},
+		"too many tags":      {ItemList: []AuditItem{{Address: "a", Tag: "1 2 3 4 5 6 7 8 9 10 11"}}},
+		"tag too long":       {ItemList: []AuditItem{{Address: "a", Tag: strings.Repeat("标", auditMaxTagLen+1)}}},
+		"title too long":     {ItemList: []AuditItem{{Address: "a", Title: strings.Repeat("题", auditMaxTitleLen+1)}}},
+		"scene out of range": {UgcDeclare: &UgcDeclare{Scene: []int{6}}},
+		"scene none mixed":   {UgcDeclare: &UgcDeclare{Scene: []int{UgcSceneNone, UgcSceneText}}},
+		"other scene desc":   {UgcDeclare: &UgcDeclare{Scene: []int{UgcSceneOther}}},
+		"method":             {UgcDeclare: &UgcDeclare{Method: []int{0}}},
+		"has audit team":     {UgcDeclare: &UgcDeclare{HasAuditTeam: 2}},
+	} {
+		if _, err := client.SubmitAuditWithResponse(testAuthorizerToken, req); err == nil {
+			t.Errorf("%s: expected validation error", name)
+		}
+	}
+	if n := server.requestCount(); n != 0 {
+		t.Fatalf("invalid requests sent %d requests", n)
+	}
+}
+
+func TestSubmitAuditDryRun(t *testing.T) {
+	server := newTestServer(t)
+	client, logger := newTestClient(t, server)
+	client.DryRun = true
+
+	resp, err := client.SubmitAuditWithResponse(testAuthorizerToken, SubmitAuditRequest{
+		ItemList: []AuditItem{{Address: "pages/index/index"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.AuditId != 0 || resp.ErrMsg != "ok" {
+		t.Fatalf("unexpected dry run response: %+v", resp)
+	}
+	if n := server.requestCount(); n != 0 {
+		t.Fatalf("dry run sent %d requests", n)
+	}
+	if logged := logger.String(); !strings.Contains(logged, "pages/index/index") {
+		t.Fatalf("dry run log missing request, got:\n%s", logged)
+	}
+}
+
+func TestReleaseWithResponse(t *testing.T) {
+	server := newTestServer(t)
+	server.respond("/wxa/release", `{"errcode":85052,"errmsg":"app is already released"}`)
+	client, _ := newTestClient(t, server)
+
+	if _, err := client.ReleaseWithResponse(testAuthorizerToken, nil); err == nil {
+		t.Fatal("expected error for errcode 85052")
+	}
+	server.respond("/wxa/release", `{"errcode":0,"errmsg":"ok"}`)
+	resp, err := client.ReleaseWithResponse(testAuthorizerToken, nil)
+	if err != nil || resp.ErrMsg != "ok" {
+		t.Fatalf("got (%+v, %v)", resp, err)
+	}
+}
@@ -0,0 +1,93 @@
+package open
+
+import (
+	"errors"
+	"github.com/mrwangjinjin/go-wechat/pkg/util"
+	"time"
+)
+
+// ErrAuthorizerNotAuthorized 授权方未授权或刷新令牌已失效, 需重新走授权流程
+var ErrAuthorizerNotAuthorized = errors.New("授权方未授权")
+
+// AuthorizerClient 代授权方(小程序/公众号)调用接口的客户端
+type AuthorizerClient struct {
+	client          *Client
+	AuthorizerAppId string
+}
+
+// Authorizer 获取指定授权方的客户端
+func (self *Client) Authorizer(authorizerAppId string) *AuthorizerClient {
+	return &AuthorizerClient{
+		client:          self,
+		AuthorizerAppId: authorizerAppId,
+	}
+}
+
+// AccessToken 获取授权方接口调用令牌authorizer_access_token
+func (self *AuthorizerClient) AccessToken() (string, error) {
+	return self.client.EnsureAuthorizerToken(self.AuthorizerAppId)
+}
+
+// EnsureAuthorizerToken 获取有效的authorizer_access_token, 过期时使用刷新令牌刷新
+// 没有可用的刷新令牌时返回ErrAuthorizerNotAuthorized
+func (self *Client) EnsureAuthorizerToken(authorizerAppId string) (string, error) {
+	token := self.cachedAuthorizerToken(authorizerAppId)
+	if authorizerTokenValid(token) {
+		return token["authorizer_access_token"].(string), nil
+	}
+
+	refreshToken, _ := token["authorizer_refresh_token"].(string)
+	if refreshToken == "" {
+		refreshToken = self.loadRefreshToken(authorizerAppId)
+	}
+	if refreshToken == "" {
+		return "", ErrAuthorizerNotAuthorized
+	}
+	refreshed, err := self.RefreshToken(authorizerAppId, refreshToken)
+	if err != nil {
+		return "", err
+	}
+	return requireString(refreshed, "authorizer_access_token")
+}
+
+// cachedAuthorizerToken 读取缓存的授权方令牌, 可能已过期, 不存在时返回nil
+func (self *Client) cachedAuthorizerToken(authorizerAppId string) map[string]interface{} {
+	if !self.Cache.Exists(AuthorizerTokenCacheKeyPrefix + authorizerAppId) {
+		return nil
+	}
+	resp, err := self.Cache.Get(AuthorizerTokenCacheKeyPrefix + authorizerAppId)
+	if err != nil {
+		return nil
+	}
+	return util.JsonUnmarshal(resp)
+}
+
+// authorizerTokenValid 授权方令牌是否存在且未过期
+func authorizerTokenValid(token map[string]interface{}) bool {
+	accessToken, _ := token["authorizer_access_token"].(string)
+	expiresIn, _ := token["expires_in"].(float64)
+	return accessToken != "" && time.Now().Unix() < int64(expiresIn)
+}
+
+// saveRefreshToken 持久保存授权方刷新令牌, 不随authorizer_access_token过期
+func (self *Client) saveRefreshToken(authorizerAppId string, refreshToken interface{}) {
+	if value, ok := refreshToken.(string); ok && value != "" {
+		key := AuthorizerRefreshTokenKeyPrefix + authorizerAppId
+		self.reportCacheError(key, self.Cache.Set(key, map[string]interface{}{
+			"authorizer_refresh_token": value,
+		}))
+	}
+}
+
+// loadRefreshToken 读取持久保存的授权方刷新令牌
+func (self *Client) loadRefreshToken(authorizerAppId string) string {
+	if !self.Cache.Exists(AuthorizerRefreshTokenKeyPrefix + authorizerAppId) {
+		return ""
+	}
+	resp, err := self.Cache.Get(AuthorizerRefreshTokenKeyPrefix + authorizerAppId)
+	if err != nil {
+		return ""
+	}
+	refreshToken, _ := util.JsonUnmarshal(resp)["authorizer_refresh_token"].(string)
+	return refreshToken
+}
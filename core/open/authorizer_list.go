@@ -0,0 +1,97 @@
+package open
+
+import (
+	"context"
+	"errors"
+)
+
+// authorizerListMaxCount 拉取授权方列表单次最大条数
+const authorizerListMaxCount = 500
+
+// AuthorizerEntry 已授权的授权方
+type AuthorizerEntry struct {
+	AuthorizerAppId string `json:"authorizer_appid"`
+	RefreshToken    string `json:"refresh_token"`
+	AuthTime        int64  `json:"auth_time"`
+}
+
+// AuthorizerList 授权方列表
+type AuthorizerList struct {
+	TotalCount int               `json:"total_count"`
+	List       []AuthorizerEntry `json:"list"`
+}
+
+// GetAuthorizerList 拉取已授权的授权方列表, count最大500
+func (self *Client) GetAuthorizerList(offset, count int) (*AuthorizerList, error) {
+	return self.GetAuthorizerListContext(context.Background(), offset, count)
+}
+
+// GetAuthorizerListContext 拉取已授权的授权方列表, ctx取消时中止请求
+func (self *Client) GetAuthorizerListContext(ctx context.Context, offset, count int) (*AuthorizerList, error) {
+	if offset < 0 || count <= 0 || count > authorizerListMaxCount {
+		return nil, errors.New("count取值范围为1-500")
+	}
+	token, err := self.ApiComponentToken()
+	if err != nil {
+		return nil, err
+	}
+	var list AuthorizerList
+	err = self.postJSONContext(ctx, self.Endpoint.ApiGetAuthorizerList(token), map[string]interface{}{
+		"component_appid": self.AppId,
+		"offset":          offset,
+		"count":           count,
+	}, &list)
+	if err != nil {
+		return nil, err
+	}
+	return &list, nil
+}
+
+// AuthorizerIterator 授权方列表迭代器, 自动翻页
+type AuthorizerIterator struct {
+	client *Client
+	ctx    context.Context
+	offset int
+	total  int
+	buf    []AuthorizerEntry
+	done   bool
+}
+
+// AuthorizerIterator 创建授权方列表迭代器
+func (self *Client) AuthorizerIterator() *AuthorizerIterator {
+	return self.AuthorizerIteratorContext(context.Background())
+}
+
+// AuthorizerIteratorContext 创建授权方列表迭代器, ctx取消后Next返回ctx的错误
+func (self *Client) AuthorizerIteratorContext(ctx context.Context) *AuthorizerIterator {
+	return &AuthorizerIterator{
+		client: self,
+		ctx:    ctx,
+		total:  -1,
+	}
+}
+
+// Next 返回下一个授权方, 遍历结束时第二个返回值为false
+func (self *AuthorizerIterator) Next() (AuthorizerEntry, bool, error) {
+	if len(self.buf) == 0 {
+		if self.done || (self.total >= 0 && self.offset >= self.total) {
+			return AuthorizerEntry{}, false, nil
+		}
+		list, err := self.client.GetAuthorizerListContext(self.ctx, self.offset, authorizerListMaxCount)
+		if err != nil {
+			return AuthorizerEntry{}, false, err
+		}
+		self.total = list.TotalCount
+		self.offset += len(list.List)
+		self.buf = list.List
+		if len(list.List) < authorizerListMaxCount {
+			self.done = true
+		}
+		if len(self.buf) == 0 {
+			return AuthorizerEntry{}, false, nil
+		}
+	}
+	entry := self.buf[0]
+	self.buf = self.buf[1:]
+	return entry, true, nil
+}
@@ -0,0 +1,64 @@
+package open
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+// authorizerListPage 生成第offset个起共count个授权方的列表响应
+func authorizerListPage(total, offset, count int) string {
+	entries := pageItems(offset, count, func(i int) string {
+		return fmt.Sprintf(`{"authorizer_appid":"wx_%d","refresh_token":"REFRESH_%d","auth_time":1558000607}`, i, i)
+	})
+	return fmt.Sprintf(`{"total_count":%d,"list":[%s]}`, total, entries)
+}
+
+func TestAuthorizerIterator(t *testing.T) {
+	server := newTestServer(t)
+	server.respondSequence("/cgi-bin/component/api_get_authorizer_list",
+		authorizerListPage(501, 0, 500),
+		authorizerListPage(501, 500, 1),
+	)
+	client, _ := newTestClient(t, server)
+
+	var entries []AuthorizerEntry
+	drain(t, client.AuthorizerIterator(), &entries)
+	if len(entries) != 501 || entries[0].AuthorizerAppId != "wx_0" || entries[500].AuthorizerAppId != "wx_500" || entries[500].RefreshToken != "REFRESH_500" {
+		t.Fatalf("got %d authorizers", len(entries))
+	}
+	bodies := server.requestBodies(t, "/cgi-bin/component/api_get_authorizer_list")
+	if len(bodies) != 2 || bodies[0]["offset"] != float64(0) || bodies[1]["offset"] != float64(500) || bodies[1]["count"] != float64(500) {
+		t.Fatalf("unexpected requests: %v", bodies)
+	}
+}
+
+func TestAuthorizerIteratorContext(t *testing.T) {
+	server := newTestServer(t)
+	client, _ := newTestClient(t, server)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, ok, err := client.AuthorizerIteratorContext(ctx).Next()
+	if ok || !errors.Is(err, context.Canceled) {
+		t.Fatalf("got (%v, %v), want context.Canceled", ok, err)
+	}
+	if n := server.requestCount(); n != 0 {
+		t.Fatalf("cancelled iterator sent %d requests", n)
+	}
+}
+
+func TestGetAuthorizerListCount(t *testing.T) {
+	server := newTestServer(t)
+	client, _ := newTestClient(t, server)
+
+	for _, count := range []int{0, 501} {
+		if _, err := client.GetAuthorizerList(0, count); err == nil {
+			t.Errorf("count %d: expected error", count)
+		}
+	}
+	if n := server.requestCount(); n != 0 {
+		t.Fatalf("invalid count sent %d requests", n)
+	}
+}
@@ -0,0 +1,73 @@
+package open
+
+import "fmt"
+
+// 授权方选项
+const (
+	OptionLocationReport  = "location_report"
+	OptionVoiceRecognize  = "voice_recognize"
+	OptionCustomerService = "customer_service"
+)
+
+// authorizerOptionValues 各选项允许的取值
+var authorizerOptionValues = map[string][]string{
+	OptionLocationReport:  {"0", "1", "2"},
+	OptionVoiceRecognize:  {"0", "1"},
+	OptionCustomerService: {"0", "1"},
+}
+
+// AuthorizerOption 授权方选项设置信息
+type AuthorizerOption struct {
+	AuthorizerAppId string `json:"authorizer_appid"`
+	OptionName      string `json:"option_name"`
+	OptionValue     string `json:"option_value"`
+}
+
+// GetAuthorizerOption 获取授权方选项信息
+func (self *Client) GetAuthorizerOption(authorizerAppId, optionName string) (*AuthorizerOption, error) {
+	if _, ok := authorizerOptionValues[optionName]; !ok {
+		return nil, fmt.Errorf("未知的选项:%s", optionName)
+	}
+	token, err := self.ApiComponentToken()
+	if err != nil {
+		return nil, err
+	}
+	var option AuthorizerOption
+	err = self.postJSON(self.Endpoint.ApiGetAuthorizerOption(token), map[string]interface{}{
+		"component_appid":  self.AppId,
+		"authorizer_appid": authorizerAppId,
+		"option_name":      optionName,
+	}, &option)
+	if err != nil {
+		return nil, err
+	}
+	return &option, nil
+}
+
+// SetAuthorizerOption 设置授权方选项信息
+func (self *Client) SetAuthorizerOption(authorizerAppId, optionName, optionValue string) error {
+	values, ok := authorizerOptionValues[optionName]
+	if !ok {
+		return fmt.Errorf("未知的选项:%s", optionName)
+	}
+	valid := false
+	for _, value := range values {
+		if value == optionValue {
+			valid = true
+			break
+		}
+	}
+	if !valid {
+		return fmt.Errorf("选项%s不支持取值:%s", optionName, optionValue)
+	}
+	token, err := self.ApiComponentToken()
+	if err != nil {
+		return err
+	}
+	return self.postJSON(self.Endpoint.ApiSetAuthorizerOption(token), map[string]interface{}{
+		"component_appid":  self.AppId,
+		"authorizer_appid": authorizerAppId,
+		"option_name":      optionName,
+		"option_value":     optionValue,
+	}, nil)
+}
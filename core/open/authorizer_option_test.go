@@ -0,0 +1,65 @@
+package open
+
+import "testing"
+
+func TestGetAuthorizerOption(t *testing.T) {
+	server := newTestServer(t)
+	server.respond("/cgi-bin/component/api_get_authorizer_option", `{"authorizer_appid":"wx_authorizer","option_name":"voice_recognize","option_value":"1"}`)
+	client, _ := newTestClient(t, server)
+
+	option, err := client.GetAuthorizerOption(testAuthorizerAppId, OptionVoiceRecognize)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *option != (AuthorizerOption{AuthorizerAppId: testAuthorizerAppId, OptionName: OptionVoiceRecognize, OptionValue: "1"}) {
+		t.Fatalf("option: got %+v", option)
+	}
+	req := server.lastRequest(t)
+	if got := req.Query["component_access_token"]; len(got) != 1 || got[0] != testComponentToken {
+		t.Fatalf("component_access_token: got %v", got)
+	}
+	want := `{"authorizer_appid":"wx_authorizer","component_appid":"wx_component","option_name":"voice_recognize"}`
+	if string(req.Body) != want {
+		t.Fatalf("body:\n got %s\nwant %s", req.Body, want)
+	}
+}
+
+func TestSetAuthorizerOptionTogglesVoiceRecognize(t *testing.T) {
+	server := newTestServer(t)
+	client, _ := newTestClient(t, server)
+
+	for _, value := range []string{"0", "1"} {
+		if err := client.SetAuthorizerOption(testAuthorizerAppId, OptionVoiceRecognize, value); err != nil {
+			t.Fatal(err)
+		}
+		req := server.lastRequest(t)
+		if req.Path != "/cgi-bin/component/api_set_authorizer_option" {
+			t.Fatalf("path: got %s", req.Path)
+		}
+		want := `{"authorizer_appid":"wx_authorizer","component_appid":"wx_component","option_name":"voice_recognize","option_value":"` + value + `"}`
+		if string(req.Body) != want {
+			t.Fatalf("body:\n got %s\nwant %s", req.Body, want)
+		}
+	}
+}
+
+func TestAuthorizerOptionValidation(t *testing.T) {
+	server := newTestServer(t)
+	client, _ := newTestClient(t, server)
+
+	if _, err := client.GetAuthorizerOption(testAuthorizerAppId, "unknown_option"); err == nil {
+		t.Error("get unknown option: expected validation error")
+	}
+	if err := client.SetAuthorizerOption(testAuthorizerAppId, "unknown_option", "1"); err == nil {
+		t.Error("set unknown option: expected validation error")
+	}
+	if err := client.SetAuthorizerOption(testAuthorizerAppId, OptionVoiceRecognize, "2"); err == nil {
+		t.Error("voice_recognize=2: expected validation error")
+	}
+	if err := client.SetAuthorizerOption(testAuthorizerAppId, OptionLocationReport, "2"); err != nil {
+		t.Errorf("location_report=2: %v", err)
+	}
+	if n := server.requestCount(); n != 1 {
+		t.Fatalf("sent %d requests, want only the valid one", n)
+	}
+}
@@ -0,0 +1,209 @@
+package open
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+const authorizerTokenPath = "/cgi-bin/component/api_authorizer_token"
+
+// staleAuthorizerToken 将缓存中的authorizer_access_token设为已过期, 其中的刷新令牌为refreshToken
+func staleAuthorizerToken(client *Client, refreshToken string) {
+	_ = client.Cache.SetEx(AuthorizerTokenCacheKeyPrefix+testAuthorizerAppId, map[string]interface{}{
+		"authorizer_access_token":  testAuthorizerToken,
+		"authorizer_refresh_token": refreshToken,
+		"expires_in":               time.Now().Unix() - 1,
+	}, 3600)
+}
+
+func TestEnsureAuthorizerTokenFresh(t *testing.T) {
+	server := newTestServer(t)
+	client, _ := newTestClient(t, server)
+
+	token, err := client.EnsureAuthorizerToken(testAuthorizerAppId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token != testAuthorizerToken {
+		t.Fatalf("got %s, want %s", token, testAuthorizerToken)
+	}
+	if n := server.requestCount(); n != 0 {
+		t.Fatalf("fresh token made %d requests", n)
+	}
+}
+
+func TestEnsureAuthorizerTokenRefreshes(t *testing.T) {
+	server := newTestServer(t)
+	server.respond(authorizerTokenPath, `{"authorizer_access_token":"NEW_TOKEN","expires_in":7200,"authorizer_refresh_token":"NEW_REFRESH_TOKEN"}`)
+	client, _ := newTestClient(t, server)
+	staleAuthorizerToken(client, "REFRESH_TOKEN")
+
+	token, err := client.EnsureAuthorizerToken(testAuthorizerAppId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token != "NEW_TOKEN" {
+		t.Fatalf("got %s, want NEW_TOKEN", token)
+	}
+	req := server.lastRequest(t)
+	if got := req.Query["component_access_token"]; len(got) != 1 || got[0] != testComponentToken {
+		t.Fatalf("component_access_token: got %v", got)
+	}
+	body := decodeBody(t, req)
+	if body["authorizer_appid"] != testAuthorizerAppId || body["authorizer_refresh_token"] != "REFRESH_TOKEN" || body["component_appid"] != testAppId {
+		t.Fatalf("unexpected body: %v", body)
+	}
+	if cached := client.cachedAuthorizerToken(testAuthorizerAppId); !authorizerTokenValid(cached) || cached["authorizer_access_token"] != "NEW_TOKEN" {
+		t.Fatalf("refreshed token not cached: %v", cached)
+	}
+	if refreshToken := client.loadRefreshToken(testAuthorizerAppId); refreshToken != "NEW_REFRESH_TOKEN" {
+		t.Fatalf("stored refresh token: got %q", refreshToken)
+	}
+}
+
+func TestEnsureAuthorizerTokenUsesStoredRefreshToken(t *testing.T) {
+	server := newTestServer(t)
+	server.respond(authorizerTokenPath, `{"authorizer_access_token":"NEW_TOKEN","expires_in":7200,"authorizer_refresh_token":"REFRESH_TOKEN"}`)
+	client, _ := newTestClient(t, server)
+	_ = client.Cache.Delete(AuthorizerTokenCacheKeyPrefix + testAuthorizerAppId)
+	client.saveRefreshToken(testAuthorizerAppId, "STORED_REFRESH_TOKEN")
+
+	token, err := client.EnsureAuthorizerToken(testAuthorizerAppId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token != "NEW_TOKEN" {
+		t.Fatalf("got %s, want NEW_TOKEN", token)
+	}
+	if body := decodeBody(t, server.lastRequest(t)); body["authorizer_refresh_token"] != "STORED_REFRESH_TOKEN" {
+		t.Fatalf("unexpected body: %v", body)
+	}
+}
+
+func TestEnsureAuthorizerTokenMissingRefreshToken(t *testing.T) {
+	server := newTestServer(t)
+	client, _ := newTestClient(t, server)
+	_ = client.Cache.Delete(AuthorizerTokenCacheKeyPrefix + testAuthorizerAppId)
+
+	if _, err := client.EnsureAuthorizerToken(testAuthorizerAppId); !errors.Is(err, ErrAuthorizerNotAuthorized) {
+		t.Fatalf("got %v, want ErrAuthorizerNotAuthorized", err)
+	}
+	staleAuthorizerToken(client, "")
+	if _, err := client.EnsureAuthorizerToken(testAuthorizerAppId); !errors.Is(err, ErrAuthorizerNotAuthorized) {
+		t.Fatalf("expired without refresh token: got %v, want ErrAuthorizerNotAuthorized", err)
+	}
+	if n := server.requestCount(); n != 0 {
+		t.Fatalf("made %d requests without a refresh token", n)
+	}
+}
+
+func TestRefreshTokenInvalidClearsCache(t *testing.T) {
+	server := newTestServer(t)
+	server.respond(authorizerTokenPath, `{"errcode":61023,"errmsg":"refresh_token is invalid"}`)
+	client, _ := newTestClient(t, server)
+	client.saveRefreshToken(testAuthorizerAppId, "REFRESH_TOKEN")
+	_ = client.Cache.Set(AuthorizerFuncInfoKeyPrefix+testAuthorizerAppId, []int64{17})
+
+	refreshed, err := client.RefreshToken(testAuthorizerAppId, "REFRESH_TOKEN")
+	if !errors.Is(err, ErrAuthorizerNotAuthorized) {
+		t.Fatalf("got %v, want ErrAuthorizerNotAuthorized", err)
+	}
+	if refreshed != nil {
+		t.Fatalf("got token %v with error", refreshed)
+	}
+	for _, key := range []string{
+		AuthorizerTokenCacheKeyPrefix + testAuthorizerAppId,
+		AuthorizerRefreshTokenKeyPrefix + testAuthorizerAppId,
+		AuthorizerFuncInfoKeyPrefix + testAuthorizerAppId,
+	} {
+		if client.Cache.Exists(key) {
+			t.Errorf("%s still cached after errcode 61023", key)
+		}
+	}
+
+	// 缓存已清除, 不再用失效的刷新令牌重试
+	if _, err := client.EnsureAuthorizerToken(testAuthorizerAppId); !errors.Is(err, ErrAuthorizerNotAuthorized) {
+		t.Fatalf("after 61023: got %v, want ErrAuthorizerNotAuthorized", err)
+	}
+	if n := server.requestCount(); n != 1 {
+		t.Fatalf("got %d requests, want 1", n)
+	}
+}
+
+func TestGetTokenRefreshesExpiredToken(t *testing.T) {
+	server := newTestServer(t)
+	server.respond(authorizerTokenPath, `{"authorizer_access_token":"NEW_TOKEN","expires_in":7200,"authorizer_refresh_token":"REFRESH_TOKEN"}`)
+	client, _ := newTestClient(t, server)
+	staleAuthorizerToken(client, "REFRESH_TOKEN")
+
+	token, err := client.GetToken(testAuthorizerAppId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token["authorizer_access_token"] != "NEW_TOKEN" {
+		t.Fatalf("got %v, want the refreshed token", token)
+	}
+	if n := server.requestCount(); n != 1 {
+		t.Fatalf("got %d requests, want 1", n)
+	}
+}
+
+func TestGetTokenRefreshesAfterCacheExpiry(t *testing.T) {
+	server := newTestServer(t)
+	server.respond(authorizerTokenPath, `{"authorizer_access_token":"NEW_TOKEN","expires_in":7200,"authorizer_refresh_token":"REFRESH_TOKEN"}`)
+	client, _ := newTestClient(t, server)
+	client.saveRefreshToken(testAuthorizerAppId, "REFRESH_TOKEN")
+	// LRU缓存中的令牌1秒后过期
+	_ = client.Cache.SetEx(AuthorizerTokenCacheKeyPrefix+testAuthorizerAppId, map[string]interface{}{
+		"authorizer_access_token":  testAuthorizerToken,
+		"authorizer_refresh_token": "REFRESH_TOKEN",
+		"expires_in":               time.Now().Unix() + 3600,
+	}, 1)
+
+	token, err := client.GetToken(testAuthorizerAppId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token["authorizer_access_token"] != testAuthorizerToken || server.requestCount() != 0 {
+		t.Fatalf("cached token not used: %v", token)
+	}
+
+	time.Sleep(1100 * time.Millisecond)
+	token, err = client.GetToken(testAuthorizerAppId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token["authorizer_access_token"] != "NEW_TOKEN" {
+		t.Fatalf("got %v, want the refreshed token", token)
+	}
+	if n := server.requestCount(); n != 1 {
+		t.Fatalf("got %d requests, want 1", n)
+	}
+}
+
+func TestRefreshTokenWithoutAccessToken(t *testing.T) {
+	server := newTestServer(t)
+	server.respond(authorizerTokenPath, `{"expires_in":7200,"authorizer_refresh_token":"REFRESH_TOKEN"}`)
+	client, _ := newTestClient(t, server)
+	_ = client.Cache.Delete(AuthorizerTokenCacheKeyPrefix + testAuthorizerAppId)
+	client.saveRefreshToken(testAuthorizerAppId, "REFRESH_TOKEN")
+
+	if _, err := client.RefreshToken(testAuthorizerAppId, "REFRESH_TOKEN"); err == nil {
+		t.Fatal("expected error for response without authorizer_access_token")
+	}
+	token, err := client.EnsureAuthorizerToken(testAuthorizerAppId)
+	if err == nil || token != "" {
+		t.Fatalf("got (%q, %v), want an error", token, err)
+	}
+	if client.Cache.Exists(AuthorizerTokenCacheKeyPrefix + testAuthorizerAppId) {
+		t.Fatal("token without authorizer_access_token was cached")
+	}
+	if _, err := client.Authorizer(testAuthorizerAppId).GetWxaCode(context.Background(), nil); err == nil {
+		t.Fatal("GetWxaCode: expected error instead of calling with an empty token")
+	}
+	if n := server.requestCount(); n != 3 {
+		t.Fatalf("got %d requests, want 3 token requests", n)
+	}
+}
@@ -0,0 +1,75 @@
+package open
+
+// AutoReply 自动回复内容, Type为text/img/voice/video时Content为文本或media_id
+type AutoReply struct {
+	Type    string `json:"type"`
+	Content string `json:"content"`
+}
+
+// AutoReplyNews 图文回复中的文章
+type AutoReplyNews struct {
+	Title      string `json:"title"`
+	Author     string `json:"author"`
+	Digest     string `json:"digest"`
+	ShowCover  int    `json:"show_cover"`
+	CoverUrl   string `json:"cover_url"`
+	ContentUrl string `json:"content_url"`
+	SourceUrl  string `json:"source_url"`
+}
+
+// AutoReplyKeyword 关键词, MatchMode为contain(部分匹配)或equal(完全匹配)
+type AutoReplyKeyword struct {
+	Type      string `json:"type"`
+	MatchMode string `json:"match_mode"`
+	Content   string `json:"content"`
+}
+
+// AutoReplyItem 关键词规则的回复, Type为news时文章在NewsInfo中
+type AutoReplyItem struct {
+	Type     string `json:"type"`
+	Content  string `json:"content"`
+	NewsInfo *struct {
+		List []AutoReplyNews `json:"list"`
+	} `json:"news_info,omitempty"`
+}
+
+// AutoReplyRule 关键词自动回复规则, ReplyMode为reply_all(全部回复)或random_one(随机回复一条)
+type AutoReplyRule struct {
+	RuleName        string             `json:"rule_name"`
+	CreateTime      int64              `json:"create_time"`
+	ReplyMode       string             `json:"reply_mode"`
+	KeywordListInfo []AutoReplyKeyword `json:"keyword_list_info"`
+	ReplyListInfo   []AutoReplyItem    `json:"reply_list_info"`
+}
+
+// AutoReplyInfo 公众号当前的自动回复配置, 未设置的部分为nil
+type AutoReplyInfo struct {
+	IsAddFriendReplyOpen        int        `json:"is_add_friend_reply_open"`
+	IsAutoReplyOpen             int        `json:"is_autoreply_open"`
+	AddFriendAutoReplyInfo      *AutoReply `json:"add_friend_autoreply_info"`
+	MessageDefaultAutoReplyInfo *AutoReply `json:"message_default_autoreply_info"`
+	KeywordAutoReplyInfo        *struct {
+		List []AutoReplyRule `json:"list"`
+	} `json:"keyword_autoreply_info"`
+}
+
+// KeywordRules 返回关键词自动回复规则, 未设置时返回nil
+func (self *AutoReplyInfo) KeywordRules() []AutoReplyRule {
+	if self.KeywordAutoReplyInfo == nil {
+		return nil
+	}
+	return self.KeywordAutoReplyInfo.List
+}
+
+// GetAutoReplyInfo 获取公众号当前的自动回复配置
+func (self *AuthorizerClient) GetAutoReplyInfo() (*AutoReplyInfo, error) {
+	token, err := self.AccessToken()
+	if err != nil {
+		return nil, err
+	}
+	var info AutoReplyInfo
+	if err := self.client.getJSON(self.client.Endpoint.GetCurrentAutoReplyInfo(token), &info); err != nil {
+		return nil, err
+	}
+	return &info, nil
+}
@@ -0,0 +1,75 @@
+package open
+
+import "testing"
+
+func TestGetAutoReplyInfoFullyConfigured(t *testing.T) {
+	server := newTestServer(t)
+	server.respond("/cgi-bin/get_current_autoreply_info", `{"is_add_friend_reply_open":1,"is_autoreply_open":1,`+
+		`"add_friend_autoreply_info":{"type":"text","content":"Thanks for your attention!"},`+
+		`"message_default_autoreply_info":{"type":"text","content":"Hello, this is autoreply!"},`+
+		`"keyword_autoreply_info":{"list":[`+
+		`{"rule_name":"autoreply-news","create_time":1423028166,"reply_mode":"reply_all","keyword_list_info":[{"type":"text","match_mode":"contain","content":"news测试"}],`+
+		`"reply_list_info":[{"type":"news","news_info":{"list":[{"title":"it's news","author":"jim","digest":"it's digest","show_cover":1,"cover_url":"http://mmbiz.qpic.cn/cover","content_url":"http://mp.weixin.qq.com/s?__biz=1","source_url":""}]}}]},`+
+		`{"rule_name":"autoreply-voice","create_time":1423027971,"reply_mode":"random_one","keyword_list_info":[{"type":"text","match_mode":"contain","content":"voice测试"},{"type":"text","match_mode":"equal","content":"语音"}],`+
+		`"reply_list_info":[{"type":"voice","content":"NESsxgHEvAcg3egJTtYj4uG1PTL6iPhratdWKDLAXYErhN6oEEfMdVyblWtBY5vp"},{"type":"text","content":"文本回复"}]}]}}`)
+	client, _ := newTestClient(t, server)
+
+	info, err := client.Authorizer(testAuthorizerAppId).GetAutoReplyInfo()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if req := server.lastRequest(t); req.Method != "GET" || req.RawQuery != "access_token="+testAuthorizerToken {
+		t.Fatalf("request: %s %s", req.Method, req.RawQuery)
+	}
+	if info.IsAddFriendReplyOpen != 1 || info.IsAutoReplyOpen != 1 {
+		t.Fatalf("switches: got %+v", info)
+	}
+	if info.AddFriendAutoReplyInfo == nil || *info.AddFriendAutoReplyInfo != (AutoReply{Type: "text", Content: "Thanks for your attention!"}) {
+		t.Fatalf("add_friend_autoreply_info: got %+v", info.AddFriendAutoReplyInfo)
+	}
+	if info.MessageDefaultAutoReplyInfo == nil || info.MessageDefaultAutoReplyInfo.Content != "Hello, this is autoreply!" {
+		t.Fatalf("message_default_autoreply_info: got %+v", info.MessageDefaultAutoReplyInfo)
+	}
+	rules := info.KeywordRules()
+	if len(rules) != 2 {
+		t.Fatalf("rules: got %+v", rules)
+	}
+
+	news := rules[0]
+	if news.RuleName != "autoreply-news" || news.CreateTime != 1423028166 || news.ReplyMode != "reply_all" ||
+		len(news.KeywordListInfo) != 1 || news.KeywordListInfo[0].MatchMode != "contain" || len(news.ReplyListInfo) != 1 {
+		t.Fatalf("news rule: got %+v", news)
+	}
+	newsInfo := news.ReplyListInfo[0].NewsInfo
+	if newsInfo == nil || len(newsInfo.List) != 1 || newsInfo.List[0].Title != "it's news" || newsInfo.List[0].ShowCover != 1 {
+		t.Fatalf("news_info: got %+v", newsInfo)
+	}
+
+	voice := rules[1]
+	if voice.ReplyMode != "random_one" || len(voice.KeywordListInfo) != 2 || voice.KeywordListInfo[1] != (AutoReplyKeyword{Type: "text", MatchMode: "equal", Content: "语音"}) {
+		t.Fatalf("voice rule: got %+v", voice)
+	}
+	if len(voice.ReplyListInfo) != 2 || voice.ReplyListInfo[0].Type != "voice" || voice.ReplyListInfo[0].NewsInfo != nil || voice.ReplyListInfo[1].Content != "文本回复" {
+		t.Fatalf("voice replies: got %+v", voice.ReplyListInfo)
+	}
+}
+
+func TestGetAutoReplyInfoMinimal(t *testing.T) {
+	server := newTestServer(t)
+	server.respond("/cgi-bin/get_current_autoreply_info", `{"is_add_friend_reply_open":0,"is_autoreply_open":1,"message_default_autoreply_info":{"type":"img","content":"IMAGE_MEDIA_ID"}}`)
+	client, _ := newTestClient(t, server)
+
+	info, err := client.Authorizer(testAuthorizerAppId).GetAutoReplyInfo()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.IsAddFriendReplyOpen != 0 || info.IsAutoReplyOpen != 1 || info.AddFriendAutoReplyInfo != nil {
+		t.Fatalf("info: got %+v", info)
+	}
+	if info.MessageDefaultAutoReplyInfo == nil || *info.MessageDefaultAutoReplyInfo != (AutoReply{Type: "img", Content: "IMAGE_MEDIA_ID"}) {
+		t.Fatalf("message_default_autoreply_info: got %+v", info.MessageDefaultAutoReplyInfo)
+	}
+	if rules := info.KeywordRules(); rules != nil {
+		t.Fatalf("rules: got %+v, want nil", rules)
+	}
+}
@@ -0,0 +1,51 @@
+package open
+
+import "errors"
+
+// VerifyBetaInfo 试用小程序转正的企业信息, CodeType: 1统一社会信用代码 2组织机构代码 3营业执照注册号
+type VerifyBetaInfo struct {
+	EnterpriseName     string `json:"enterprise_name"`
+	Code               string `json:"code"`
+	CodeType           int    `json:"code_type"`
+	LegalPersonaWechat string `json:"legal_persona_wechat"`
+	LegalPersonaName   string `json:"legal_persona_name"`
+	LegalPersonaIdCard string `json:"legal_persona_idcard,omitempty"`
+	ComponentPhone     string `json:"component_phone,omitempty"`
+}
+
+// VerifyBetaRequest 试用小程序转正请求
+type VerifyBetaRequest struct {
+	VerifyInfo VerifyBetaInfo `json:"verify_info"`
+}
+
+// FastRegisterBetaWeapp 创建试用小程序, 结果通过notify_third_fastregisterbetaapp事件推送,
+// 名称不合法或重复时返回ErrNameInvalid/ErrNameOccupied, 超出创建额度时返回ErrApiDailyQuota
+func (self *Client) FastRegisterBetaWeapp(name, openId string) error {
+	if name == "" || openId == "" {
+		return errors.New("名称和openid不能为空")
+	}
+	token, err := self.ApiComponentToken()
+	if err != nil {
+		return err
+	}
+	return self.postJSON(self.Endpoint.FastRegisterBetaWeapp(token), map[string]interface{}{
+		"name":   name,
+		"openid": openId,
+	}, nil)
+}
+
+// VerifyBetaWeapp 试用小程序转正, 结果通过notify_third_fastverifybetaapp事件推送
+func (self *AuthorizerClient) VerifyBetaWeapp(req VerifyBetaRequest) error {
+	info := req.VerifyInfo
+	if info.EnterpriseName == "" || info.Code == "" || info.LegalPersonaWechat == "" || info.LegalPersonaName == "" {
+		return errors.New("企业名称、企业代码、法人微信号和法人姓名不能为空")
+	}
+	if info.CodeType < 1 || info.CodeType > 3 {
+		return errors.New("code_type取值范围为1-3")
+	}
+	token, err := self.AccessToken()
+	if err != nil {
+		return err
+	}
+	return self.client.postJSON(self.client.Endpoint.VerifyBetaWeapp(token), req, nil)
+}
@@ -0,0 +1,99 @@
+package open
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestFastRegisterBetaWeappBody(t *testing.T) {
+	server := newTestServer(t)
+	client, _ := newTestClient(t, server)
+
+	if err := client.FastRegisterBetaWeapp("试用小程序", "OPENID"); err != nil {
+		t.Fatal(err)
+	}
+	req := server.lastRequest(t)
+	if req.Path != "/wxa/component/fastregisterbetaweapp" {
+		t.Fatalf("path: got %s", req.Path)
+	}
+	if got := req.Query["access_token"]; len(got) != 1 || got[0] != testComponentToken {
+		t.Fatalf("access_token: got %v, want component token", got)
+	}
+	if string(req.Body) != `{"name":"试用小程序","openid":"OPENID"}` {
+		t.Fatalf("body: got %s", req.Body)
+	}
+}
+
+func TestFastRegisterBetaWeappErrors(t *testing.T) {
+	for _, tc := range []struct {
+		body string
+		want error
+	}{
+		{`{"errcode":53010,"errmsg":"invalid nickname"}`, ErrNameInvalid},
+		{`{"errcode":53013,"errmsg":"nickname is occupied"}`, ErrNameOccupied},
+		{`{"errcode":45009,"errmsg":"reach max api daily quota limit"}`, ErrApiDailyQuota},
+	} {
+		server := newTestServer(t)
+		server.respond("/wxa/component/fastregisterbetaweapp", tc.body)
+		client, _ := newTestClient(t, server)
+
+		if err := client.FastRegisterBetaWeapp("试用小程序", "OPENID"); !errors.Is(err, tc.want) {
+			t.Errorf("%s: got %v, want %v", tc.body, err, tc.want)
+		}
+	}
+}
+
+func TestVerifyBetaWeappBody(t *testing.T) {
+	server := newTestServer(t)
+	client, _ := newTestClient(t, server)
+
+	err := client.Authorizer(testAuthorizerAppId).VerifyBetaWeapp(VerifyBetaRequest{VerifyInfo: VerifyBetaInfo{
+		EnterpriseName:     "腾讯计算机系统有限公司",
+		Code:               "91440300708461136T",
+		CodeType:           1,
+		LegalPersonaWechat: "legal_wechat",
+		LegalPersonaName:   "张三",
+		ComponentPhone:     "13800138000",
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	req := server.lastRequest(t)
+	if req.Path != "/wxa/verifybetaweapp" {
+		t.Fatalf("path: got %s", req.Path)
+	}
+	if got := req.Query["access_token"]; len(got) != 1 || got[0] != testAuthorizerToken {
+		t.Fatalf("access_token: got %v, want authorizer token", got)
+	}
+	want := `{"verify_info":{"enterprise_name":"腾讯计算机系统有限公司","code":"91440300708461136T","code_type":1,"legal_persona_wechat":"legal_wechat","legal_persona_name":"张三","component_phone":"13800138000"}}`
+	if string(req.Body) != want {
+		t.Fatalf("body:\n got %s\nwant %s", req.Body, want)
+	}
+}
+
+func TestBetaWeappValidation(t *testing.T) {
+	server := newTestServer(t)
+	client, _ := newTestClient(t, server)
+	authorizer := client.Authorizer(testAuthorizerAppId)
+	valid := VerifyBetaInfo{EnterpriseName: "企业", Code: "CODE", CodeType: 1, LegalPersonaWechat: "wechat", LegalPersonaName: "张三"}
+
+	if err := client.FastRegisterBetaWeapp("", "OPENID"); err == nil {
+		t.Error("missing name: expected validation error")
+	}
+	if err := client.FastRegisterBetaWeapp("试用小程序", ""); err == nil {
+		t.Error("missing openid: expected validation error")
+	}
+	missingCode := valid
+	missingCode.Code = ""
+	if err := authorizer.VerifyBetaWeapp(VerifyBetaRequest{VerifyInfo: missingCode}); err == nil {
+		t.Error("missing code: expected validation error")
+	}
+	badCodeType := valid
+	badCodeType.CodeType = 4
+	if err := authorizer.VerifyBetaWeapp(VerifyBetaRequest{VerifyInfo: badCodeType}); err == nil {
+		t.Error("code_type 4: expected validation error")
+	}
+	if n := server.requestCount(); n != 0 {
+		t.Fatalf("invalid requests sent %d requests", n)
+	}
+}
@@ -0,0 +1,145 @@
+package open
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// blacklistBatchMax 批量拉黑单次最大openid数
+const blacklistBatchMax = 20
+
+// BlacklistFailure 批量拉黑或取消拉黑中失败的一批openid
+type BlacklistFailure struct {
+	OpenIds []string
+	Err     error
+}
+
+// BlacklistError 批量拉黑或取消拉黑的部分失败, 未列出的openid均已处理成功
+type BlacklistError struct {
+	Failures []BlacklistFailure
+}
+
+func (self *BlacklistError) Error() string {
+	messages := make([]string, 0, len(self.Failures))
+	for _, failure := range self.Failures {
+		messages = append(messages, fmt.Sprintf("%d个openid: %v", len(failure.OpenIds), failure.Err))
+	}
+	return fmt.Sprintf("%d批处理失败: %s", len(self.Failures), strings.Join(messages, "; "))
+}
+
+// Unwrap 返回第一批的错误, 便于errors.Is判断
+func (self *BlacklistError) Unwrap() error {
+	if len(self.Failures) == 0 {
+		return nil
+	}
+	return self.Failures[0].Err
+}
+
+// FailedOpenIds 返回所有处理失败的openid
+func (self *BlacklistError) FailedOpenIds() []string {
+	var openIds []string
+	for _, failure := range self.Failures {
+		openIds = append(openIds, failure.OpenIds...)
+	}
+	return openIds
+}
+
+// GetBlacklist 获取黑名单列表, 返回本页openid及下一页的next_openid, 每页最多10000个
+func (self *AuthorizerClient) GetBlacklist(beginOpenId string) ([]string, string, error) {
+	token, err := self.AccessToken()
+	if err != nil {
+		return nil, "", err
+	}
+	var resp struct {
+		Total int `json:"total"`
+		Count int `json:"count"`
+		Data  struct {
+			OpenId []string `json:"openid"`
+		} `json:"data"`
+		NextOpenId string `json:"next_openid"`
+	}
+	err = self.client.postJSON(self.client.Endpoint.GetBlacklist(token), map[string]interface{}{
+		"begin_openid": beginOpenId,
+	}, &resp)
+	if err != nil {
+		return nil, "", err
+	}
+	return resp.Data.OpenId, resp.NextOpenId, nil
+}
+
+// BlacklistIterator 黑名单迭代器, 按next_openid自动翻页, 每次返回一个openid
+type BlacklistIterator struct {
+	client      *AuthorizerClient
+	beginOpenId string
+	buf         []string
+	done        bool
+}
+
+// Blacklist 创建黑名单迭代器
+func (self *AuthorizerClient) Blacklist() *BlacklistIterator {
+	return &BlacklistIterator{client: self}
+}
+
+// Next 返回下一个被拉黑用户的openid, 遍历结束时第二个返回值为false
+func (self *BlacklistIterator) Next() (string, bool, error) {
+	if len(self.buf) == 0 {
+		if self.done {
+			return "", false, nil
+		}
+		openIds, next, err := self.client.GetBlacklist(self.beginOpenId)
+		if err != nil {
+			return "", false, err
+		}
+		if next == "" || next == self.beginOpenId || len(openIds) == 0 {
+			self.done = true
+		}
+		self.beginOpenId = next
+		self.buf = openIds
+		if len(self.buf) == 0 {
+			return "", false, nil
+		}
+	}
+	openId := self.buf[0]
+	self.buf = self.buf[1:]
+	return openId, true, nil
+}
+
+// batchBlacklist 按20个openid分批处理, 单批失败不影响其它批次, 失败的批次汇总为BlacklistError
+func (self *AuthorizerClient) batchBlacklist(endpoint func(string) string, openIds []string) error {
+	if len(openIds) == 0 {
+		return errors.New("openid列表不能为空")
+	}
+	token, err := self.AccessToken()
+	if err != nil {
+		return err
+	}
+	var failures []BlacklistFailure
+	for start := 0; start < len(openIds); start += blacklistBatchMax {
+		end := start + blacklistBatchMax
+		if end > len(openIds) {
+			end = len(openIds)
+		}
+		batch := openIds[start:end]
+		err := self.client.postJSON(endpoint(token), map[string]interface{}{
+			"openid_list": batch,
+		}, nil)
+		if err != nil {
+			failures = append(failures, BlacklistFailure{OpenIds: batch, Err: err})
+		}
+	}
+	if len(failures) > 0 {
+		return &BlacklistError{Failures: failures}
+	}
+	return nil
+}
+
+// BlacklistUsers 拉黑用户, 超过20个openid时分批请求, 部分失败时返回*BlacklistError
+func (self *AuthorizerClient) BlacklistUsers(openIds []string) error {
+	return self.batchBlacklist(self.client.Endpoint.BatchBlacklist, openIds)
+}
+
+// UnblacklistUsers 取消拉黑用户, 超过20个openid时分批请求, 部分失败时返回*BlacklistError
+func (self *AuthorizerClient) UnblacklistUsers(openIds []string) error {
+	return self.batchBlacklist(self.client.Endpoint.BatchUnblacklist, openIds)
+}
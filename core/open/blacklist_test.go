@@ -0,0 +1,44 @@
+package open
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestBlacklistIterator(t *testing.T) {
+	server := newTestServer(t)
+	server.respondSequence("/cgi-bin/tags/members/getblacklist",
+		`{"total":3,"count":2,"data":{"openid":["OPENID_1","OPENID_2"]},"next_openid":"OPENID_2"}`,
+		`{"total":3,"count":1,"data":{"openid":["OPENID_3"]},"next_openid":"OPENID_3"}`,
+		`{"total":3,"count":0,"next_openid":""}`,
+	)
+	client, _ := newTestClient(t, server)
+
+	var openIds []string
+	drain(t, client.Authorizer(testAuthorizerAppId).Blacklist(), &openIds)
+	if want := []string{"OPENID_1", "OPENID_2", "OPENID_3"}; !reflect.DeepEqual(openIds, want) {
+		t.Fatalf("got %v, want %v", openIds, want)
+	}
+	bodies := server.requestBodies(t, "/cgi-bin/tags/members/getblacklist")
+	for i, want := range []string{"", "OPENID_2", "OPENID_3"} {
+		if i >= len(bodies) || bodies[i]["begin_openid"] != want {
+			t.Fatalf("requests: got %v, want begin_openid sequence \"\", OPENID_2, OPENID_3", bodies)
+		}
+	}
+}
+
+func TestBlacklistIteratorStopsOnRepeatedCursor(t *testing.T) {
+	server := newTestServer(t)
+	// 部分情况下最后一页返回的next_openid与请求的begin_openid相同
+	server.respondSequence("/cgi-bin/tags/members/getblacklist",
+		`{"total":2,"count":1,"data":{"openid":["OPENID_1"]},"next_openid":"OPENID_1"}`,
+		`{"total":2,"count":1,"data":{"openid":["OPENID_2"]},"next_openid":"OPENID_1"}`,
+	)
+	client, _ := newTestClient(t, server)
+
+	var openIds []string
+	drain(t, client.Authorizer(testAuthorizerAppId).Blacklist(), &openIds)
+	if len(openIds) != 2 || server.requestCount() != 2 {
+		t.Fatalf("got %d openids in %d requests, want 2 in 2", len(openIds), server.requestCount())
+	}
+}
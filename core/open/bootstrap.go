@@ -0,0 +1,58 @@
+package open
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+)
+
+// ErrComponentTicketMissing 尚未收到component_verify_ticket推送
+var ErrComponentTicketMissing = errors.New("component_verify_ticket不存在, 请确认已配置授权事件接收URL")
+
+// BootstrapResult 授权引导结果
+type BootstrapResult struct {
+	AuthUrl                 string
+	ComponentAccessToken    string
+	ComponentTokenExpiresAt time.Time
+	PreAuthCodeExpiresAt    time.Time
+}
+
+// Bootstrap 依次检查ticket、获取component_access_token、创建预授权码并生成授权链接,
+// 失败时返回所在步骤的错误
+func (self *Client) Bootstrap(ctx context.Context, redirectUri string, authType uint8) (*BootstrapResult, error) {
+	if self.getComponentTicket() == "" {
+		return nil, ErrComponentTicketMissing
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	token, err := self.ApiComponentToken()
+	if err != nil {
+		return nil, fmt.Errorf("获取component_access_token失败: %w", err)
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	issuedAt := time.Now()
+	preAuthCode, err := self.ApiCreatePreAuthCodeFull()
+	if err != nil {
+		return nil, fmt.Errorf("创建预授权码失败: %w", err)
+	}
+	result := &BootstrapResult{
+		AuthUrl: self.Endpoint.ComponentLoginPage(
+			url.QueryEscape(self.AppId),
+			url.QueryEscape(preAuthCode.PreAuthCode),
+			url.QueryEscape(redirectUri),
+			authType),
+		ComponentAccessToken: token,
+		PreAuthCodeExpiresAt: preAuthCode.ExpiresAt(issuedAt),
+	}
+	if componentToken := self.cachedComponentToken(); componentToken != nil {
+		if expiresIn, ok := componentToken["expires_in"].(float64); ok {
+			result.ComponentTokenExpiresAt = time.Unix(int64(expiresIn), 0)
+		}
+	}
+	return result, nil
+}
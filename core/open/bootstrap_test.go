@@ -0,0 +1,86 @@
+package open
+
+import (
+	"context"
+	"errors"
+	"github.com/mrwangjinjin/go-wechat/core"
+	"testing"
+	"time"
+)
+
+func TestBootstrap(t *testing.T) {
+	server := newTestServer(t)
+	server.respond("/cgi-bin/component/api_create_preauthcode", `{"pre_auth_code":"PRE/AUTH","expires_in":600}`)
+	client, _ := newTestClient(t, server)
+	seedComponentTicket(client, time.Now())
+	before := time.Now()
+
+	result, err := client.Bootstrap(context.Background(), "https://example.com/callback?tenant=1", 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := core.ComponentLoginPageUrl + "?component_appid=wx_component&pre_auth_code=PRE%2FAUTH&redirect_uri=https%3A%2F%2Fexample.com%2Fcallback%3Ftenant%3D1&auth_type=3"
+	if result.AuthUrl != want {
+		t.Fatalf("auth url:\n got %s\nwant %s", result.AuthUrl, want)
+	}
+	if result.ComponentAccessToken != testComponentToken {
+		t.Fatalf("component token: got %s", result.ComponentAccessToken)
+	}
+	if result.ComponentTokenExpiresAt.Before(before.Add(3590*time.Second)) || result.ComponentTokenExpiresAt.After(time.Now().Add(3600*time.Second)) {
+		t.Fatalf("component token expires at %v, want about an hour from now", result.ComponentTokenExpiresAt)
+	}
+	if result.PreAuthCodeExpiresAt.Before(before.Add(600*time.Second)) || result.PreAuthCodeExpiresAt.After(time.Now().Add(600*time.Second)) {
+		t.Fatalf("pre auth code expires at %v, want 10 minutes from now", result.PreAuthCodeExpiresAt)
+	}
+	req := server.lastRequest(t)
+	if req.Path != "/cgi-bin/component/api_create_preauthcode" {
+		t.Fatalf("path: got %s", req.Path)
+	}
+	if got := req.Query["component_access_token"]; len(got) != 1 || got[0] != testComponentToken {
+		t.Fatalf("component_access_token: got %v", got)
+	}
+}
+
+func TestBootstrapTicketMissing(t *testing.T) {
+	server := newTestServer(t)
+	client, _ := newTestClient(t, server)
+
+	if _, err := client.Bootstrap(context.Background(), "https://example.com/callback", 3); !errors.Is(err, ErrComponentTicketMissing) {
+		t.Fatalf("got %v, want ErrComponentTicketMissing", err)
+	}
+	if n := server.requestCount(); n != 0 {
+		t.Fatalf("sent %d requests, want 0", n)
+	}
+}
+
+func TestBootstrapComponentTokenFailure(t *testing.T) {
+	server := newTestServer(t)
+	server.respond("/cgi-bin/component/api_component_token", `{"errcode":61004,"errmsg":"access clientip is not registered"}`)
+	client, _ := newTestClient(t, server)
+	seedComponentTicket(client, time.Now())
+	expireComponentToken(client)
+
+	_, err := client.Bootstrap(context.Background(), "https://example.com/callback", 3)
+	var apiErr *Error
+	if !errors.As(err, &apiErr) || apiErr.ErrCode != 61004 {
+		t.Fatalf("got %v, want wrapped errcode 61004", err)
+	}
+	if server.requestCount() != 1 {
+		t.Fatalf("sent %d requests, want only the token request", server.requestCount())
+	}
+}
+
+func TestBootstrapCanceled(t *testing.T) {
+	server := newTestServer(t)
+	client, _ := newTestClient(t, server)
+	seedComponentTicket(client, time.Now())
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := client.Bootstrap(ctx, "https://example.com/callback", 3); !errors.Is(err, context.Canceled) {
+		t.Fatalf("got %v, want context.Canceled", err)
+	}
+	if n := server.requestCount(); n != 0 {
+		t.Fatalf("sent %d requests, want 0", n)
+	}
+}
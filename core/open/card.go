@@ -0,0 +1,76 @@
+package open
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CardSignParams 卡券扩展字段签名参数, Timestamp和NonceStr为空时自动生成
+type CardSignParams struct {
+	CardId    string
+	Code      string
+	OpenId    string
+	Timestamp int64
+	NonceStr  string
+}
+
+// CardExt 卡券扩展字段cardExt, 需JSON编码后传给wx.addCard
+type CardExt struct {
+	Code      string `json:"code,omitempty"`
+	OpenId    string `json:"openid,omitempty"`
+	Timestamp string `json:"timestamp"`
+	NonceStr  string `json:"nonce_str"`
+	Signature string `json:"signature"`
+}
+
+// GetWxCardTicket 获取授权方的卡券api_ticket, 过期时刷新
+func (self *AuthorizerClient) GetWxCardTicket() (string, error) {
+	return self.getTicket("wx_card", WxCardTicketCacheKeyPrefix+self.AuthorizerAppId)
+}
+
+// CardSign 生成卡券扩展字段及签名
+func (self *AuthorizerClient) CardSign(params CardSignParams) (*CardExt, error) {
+	if params.CardId == "" {
+		return nil, errors.New("card_id不能为空")
+	}
+	if params.Timestamp == 0 {
+		params.Timestamp = time.Now().Unix()
+	}
+	if params.NonceStr == "" {
+		nonce, err := randomNonce()
+		if err != nil {
+			return nil, err
+		}
+		params.NonceStr = nonce
+	}
+	ticket, err := self.GetWxCardTicket()
+	if err != nil {
+		return nil, err
+	}
+	timestamp := strconv.FormatInt(params.Timestamp, 10)
+	return &CardExt{
+		Code:      params.Code,
+		OpenId:    params.OpenId,
+		Timestamp: timestamp,
+		NonceStr:  params.NonceStr,
+		Signature: cardSignature(ticket, timestamp, params.NonceStr, params.CardId, params.Code, params.OpenId),
+	}, nil
+}
+
+// cardSignature 将非空参数按字典序排序后拼接计算sha1
+func cardSignature(values ...string) string {
+	parts := make([]string, 0, len(values))
+	for _, value := range values {
+		if value != "" {
+			parts = append(parts, value)
+		}
+	}
+	sort.Strings(parts)
+	sum := sha1.Sum([]byte(strings.Join(parts, "")))
+	return hex.EncodeToString(sum[:])
+}
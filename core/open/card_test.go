@@ -0,0 +1,68 @@
+package open
+
+import "testing"
+
+const testWxCardTicket = "ojZ8YtyVyr30HheH3CM73y7h4jJE"
+
+func TestCardSignatureGolden(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		values []string
+		want   string
+	}{
+		{
+			name:   "without code and openid",
+			values: []string{testWxCardTicket, "1404896688", "jonyqin", "pjZ8Yt1XGILfi-FUsewpnnolGgZk", "", ""},
+			want:   "b2bf675f1383b47ba888a95901766be98f9f3e67",
+		},
+		{
+			name:   "with code and openid",
+			values: []string{testWxCardTicket, "1404896688", "jonyqin", "pjZ8Yt1XGILfi-FUsewpnnolGgZk", "12345678", "oQz7mt5dF1n9iSlhZY4pnqT_ZY48"},
+			want:   "09a2d71de9aa5add43b5dbf47f5256f42c691bdc",
+		},
+	} {
+		if got := cardSignature(tc.values...); got != tc.want {
+			t.Errorf("%s: got %s, want %s", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestCardSign(t *testing.T) {
+	server := newTestServer(t)
+	server.respond("/cgi-bin/ticket/getticket", `{"errcode":0,"errmsg":"ok","ticket":"`+testWxCardTicket+`","expires_in":7200}`)
+	client, _ := newTestClient(t, server)
+	authorizer := client.Authorizer(testAuthorizerAppId)
+
+	ext, err := authorizer.CardSign(CardSignParams{CardId: "pjZ8Yt1XGILfi-FUsewpnnolGgZk", Timestamp: 1404896688, NonceStr: "jonyqin"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := CardExt{Timestamp: "1404896688", NonceStr: "jonyqin", Signature: "b2bf675f1383b47ba888a95901766be98f9f3e67"}
+	if *ext != want {
+		t.Fatalf("card ext: got %+v, want %+v", ext, want)
+	}
+	if query := server.lastRequest(t).RawQuery; query != "access_token="+testAuthorizerToken+"&type=wx_card" {
+		t.Fatalf("query: got %s", query)
+	}
+	if !client.Cache.Exists(WxCardTicketCacheKeyPrefix + testAuthorizerAppId) {
+		t.Fatal("ticket was not cached under the wx_card key")
+	}
+	if client.Cache.Exists(JsapiTicketCacheKeyPrefix + testAuthorizerAppId) {
+		t.Fatal("wx_card ticket must not be cached under the jsapi key")
+	}
+
+	ext, err = authorizer.CardSign(CardSignParams{CardId: "pjZ8Yt1XGILfi-FUsewpnnolGgZk", Code: "12345678", OpenId: "oQz7mt5dF1n9iSlhZY4pnqT_ZY48"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ext.NonceStr == "" || ext.Timestamp == "" || ext.Code != "12345678" || ext.OpenId != "oQz7mt5dF1n9iSlhZY4pnqT_ZY48" {
+		t.Fatalf("card ext: got %+v", ext)
+	}
+	if n := server.requestCount(); n != 1 {
+		t.Fatalf("sent %d requests, want 1", n)
+	}
+
+	if _, err := authorizer.CardSign(CardSignParams{}); err == nil {
+		t.Fatal("empty card_id: expected validation error")
+	}
+}
@@ -1,10 +1,12 @@
 package open
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"github.com/mrwangjinjin/go-wechat/core"
+	"github.com/mrwangjinjin/go-wechat/internal/singleflight"
 	"github.com/mrwangjinjin/go-wechat/pkg/util"
 	"log"
 	"net/http"
@@ -17,8 +19,42 @@ const (
 	ComponentTokenCacheKeyPrefix    = "CACHE_COMPONENT@@"
 	AuthorizerTokenCacheKeyPrefix   = "CACHE_AUTHORIZER_TOKEN@@"
 	MpAuthorizerTokenCacheKeyPrefix = "CACHE_AUTHORIZER_TOKEN_MP@@"
+	ComponentTokenLockKeyPrefix     = "CACHE_COMPONENT_LOCK@@"
+	AuthorizerRefreshTokenKeyPrefix = "CACHE_AUTHORIZER_REFRESH_TOKEN@@"
+	JsapiTicketCacheKeyPrefix       = "CACHE_JSAPI_TICKET@@"
+	WxCardTicketCacheKeyPrefix      = "CACHE_WX_CARD_TICKET@@"
+	AuthorizerFuncInfoKeyPrefix     = "CACHE_AUTHORIZER_FUNC_INFO@@"
+	WxaCodeCacheKeyPrefix           = "CACHE_WXACODE@@"
 )
 
+// componentTokenLockLease 刷新component_access_token的锁租约(秒), 持有者异常退出时锁在租约到期后释放
+const componentTokenLockLease = 10
+
+var (
+	// componentTokenLockWait 未取得锁时等待其它实例写入缓存的最长时间
+	componentTokenLockWait = 2 * time.Second
+	// componentTokenLockPoll 等待期间检查缓存的间隔
+	componentTokenLockPoll = 100 * time.Millisecond
+)
+
+const (
+	// tokenAhead 令牌提前过期的时间(秒), 预留网络和时钟误差
+	tokenAhead = 600
+	// defaultTokenTTL 响应缺少expires_in时令牌的缓存时间(秒)
+	defaultTokenTTL = 7200 - tokenAhead
+)
+
+// tokenTTL 根据响应中的expires_in(秒)计算令牌的缓存时间, 缺失或过短时使用defaultTokenTTL
+func tokenTTL(expiresIn interface{}) int64 {
+	if value, ok := expiresIn.(float64); ok && int64(value) > tokenAhead {
+		return int64(value) - tokenAhead
+	}
+	return defaultTokenTTL
+}
+
+// ErrComponentTokenRefreshing 其它实例正在刷新component_access_token且未在等待时间内完成, 可稍后重试
+var ErrComponentTokenRefreshing = errors.New("component_access_token正在由其它实例刷新, 请稍后重试")
+
 type Client struct {
 	Http      *core.HttpClient
 	Endpoint  *core.Endpoint
@@ -27,19 +63,91 @@ type Client struct {
 	AppSecret string
 	Token     string
 	AesKey    string
+	// Logger 日志, 为nil时使用core.DefaultLogger
+	Logger core.Logger
+	// ErrorReporter 缓存写入失败等内部错误的上报函数, 可为nil
+	ErrorReporter core.ErrorReporter
+	// DryRun 为true时上传代码、提交审核和发布只校验并记录请求, 返回模拟的成功结果
+	DryRun bool
+	// wxaCodeCacheTTL 小程序码缓存时间, 为0时不缓存
+	wxaCodeCacheTTL time.Duration
+	// flight 合并同一令牌/票据的并发刷新
+	flight singleflight.Group
+	// httpClient WithHTTPClient提供的http.Client
+	httpClient *http.Client
+	// timeout WithTimeout设置的单次请求超时时间
+	timeout time.Duration
+}
+
+// ClientOption NewClient的可选配置
+type ClientOption func(*Client)
+
+// WithHTTPClient 使用调用方提供的http.Client发起请求, 便于共享连接池和自定义Transport
+// User-Agent、限流和WithTimeout仍由SDK在其之上处理, 不会修改传入的http.Client; SDK不做自动重试
+func WithHTTPClient(client *http.Client) ClientOption {
+	return func(self *Client) {
+		self.httpClient = client
+	}
+}
+
+// WithHttpClient 同WithHTTPClient
+//
+// Deprecated: 使用WithHTTPClient
+func WithHttpClient(client *http.Client) ClientOption {
+	return WithHTTPClient(client)
+}
+
+// WithTimeout 设置单次请求(含读取响应体)的超时时间, 通过请求的context实现, 可与WithHTTPClient同时使用
+func WithTimeout(timeout time.Duration) ClientOption {
+	return func(self *Client) {
+		self.timeout = timeout
+	}
+}
+
+// WithWxaCodeCache 缓存授权方生成的小程序码, 参数完全相同的请求在ttl内直接返回缓存的图片
+// 仅适用于相同参数始终对应同一个码的场景
+func WithWxaCodeCache(ttl time.Duration) ClientOption {
+	return func(self *Client) {
+		self.wxaCodeCacheTTL = ttl
+	}
 }
 
 // NewClient
-func NewClient(clientConfig *core.ClientConfig, cache core.Cache) *Client {
-	return &Client{
-		Http:      core.NewHttpClient(),
-		Cache:     cache,
-		Endpoint:  core.NewEndpoint(clientConfig.BaseUrl),
-		AppId:     clientConfig.AppId,
-		AppSecret: clientConfig.AppSecret,
-		Token:     clientConfig.Token,
-		AesKey:    clientConfig.AesKey,
+func NewClient(clientConfig *core.ClientConfig, cache core.Cache, opts ...ClientOption) *Client {
+	httpClient := core.NewHttpClient()
+	httpClient.SetUserAgent(clientConfig.UserAgent)
+	httpClient.SetRateLimiter(clientConfig.RateLimiter)
+	httpClient.SetMaxResponseBytes(clientConfig.MaxResponseBytes)
+	client := &Client{
+		Http:          httpClient,
+		Cache:         cache,
+		Endpoint:      core.NewEndpoint(clientConfig.BaseUrl),
+		AppId:         clientConfig.AppId,
+		AppSecret:     clientConfig.AppSecret,
+		Token:         clientConfig.Token,
+		AesKey:        clientConfig.AesKey,
+		Logger:        clientConfig.Logger,
+		ErrorReporter: clientConfig.ErrorReporter,
+		DryRun:        clientConfig.DryRun,
+	}
+	for _, opt := range opts {
+		opt(client)
+	}
+	client.applyHttpOptions()
+	return client
+}
+
+// applyHttpOptions 应用WithHTTPClient和WithTimeout, 传入的http.Client自带更短的Timeout时
+// WithTimeout不会生效, 记录警告而不是静默忽略
+func (self *Client) applyHttpOptions() {
+	if self.httpClient != nil {
+		self.Http.SetHttpClient(self.httpClient)
+		if self.timeout > 0 && self.httpClient.Timeout > 0 && self.httpClient.Timeout < self.timeout {
+			self.logger().Printf("[WARN] WithTimeout(%v)长于WithHTTPClient提供的http.Client.Timeout(%v), 请求将在%v后超时",
+				self.timeout, self.httpClient.Timeout, self.httpClient.Timeout)
+		}
 	}
+	self.Http.SetTimeout(self.timeout)
 }
 
 // GetAuthUrl 获取授权页网址
@@ -48,36 +156,42 @@ func (self *Client) GetAuthUrl(redirectUri string, authType uint8) string {
 	if err != nil {
 		return ""
 	}
-	return fmt.Sprintf("https://mp.weixin.qq.com/cgi-bin/componentloginpage?component_appid=%s&pre_auth_code=%s&redirect_uri=%s&auth_type=%d",
+	return self.Endpoint.ComponentLoginPage(
 		url.QueryEscape(self.AppId),
 		url.QueryEscape(preAuthCode),
 		url.QueryEscape(redirectUri),
 		authType)
 }
 
-// GetToken
+// GetToken 获取授权方令牌, 缓存的令牌已过期时使用刷新令牌刷新
 func (self *Client) GetToken(authorizerAppId string) (map[string]interface{}, error) {
-	resp, err := self.Cache.Get(AuthorizerTokenCacheKeyPrefix + authorizerAppId)
-	if err != nil {
-		log.Println(err)
+	if token := self.cachedAuthorizerToken(authorizerAppId); authorizerTokenValid(token) {
+		return token, nil
+	}
+	if _, err := self.EnsureAuthorizerToken(authorizerAppId); err != nil {
 		return nil, err
 	}
-	return util.JsonUnmarshal(string(resp)), nil
+	token := self.cachedAuthorizerToken(authorizerAppId)
+	if token == nil {
+		return nil, errors.New("获取授权方令牌失败")
+	}
+	return token, nil
 }
 
 // RefreshToken
 func (self *Client) RefreshToken(authorizerAppId, refreshToken string) (map[string]interface{}, error) {
-	dst, err := json.Marshal(map[string]interface{}{
+	data := map[string]interface{}{
 		"component_appid":          self.AppId,
 		"authorizer_appid":         authorizerAppId,
 		"authorizer_refresh_token": refreshToken,
-	})
+	}
 	token, err := self.ApiComponentToken()
 	if err != nil {
 		log.Println(err)
 		return nil, err
 	}
-	status, body, err := self.Http.Post(self.Endpoint.ApiAuthorizerToken(token), "application/json", dst)
+	rawUrl := self.Endpoint.ApiAuthorizerToken(token)
+	status, body, err := self.Http.PostJSON(context.Background(), rawUrl, data)
 	if err != nil {
 		log.Println(err)
 		return nil, err
@@ -85,41 +199,78 @@ func (self *Client) RefreshToken(authorizerAppId, refreshToken string) (map[stri
 	if status != http.StatusOK {
 		return nil, errors.New("网络错误")
 	}
-	authorizerRefreshToken := util.JsonUnmarshalBytes(body)
-	_ = self.Cache.SetEx(AuthorizerTokenCacheKeyPrefix+authorizerAppId, map[string]interface{}{
+	authorizerRefreshToken, err := self.parseResponse(rawUrl, body)
+	if err != nil {
+		return nil, err
+	}
+	if err := responseError(authorizerRefreshToken); err != nil {
+		if errors.Is(err, ErrRefreshTokenInvalid) {
+			// 刷新令牌已失效, 清除缓存, 需重新授权
+			_ = self.Cache.Delete(AuthorizerTokenCacheKeyPrefix + authorizerAppId)
+			_ = self.Cache.Delete(AuthorizerRefreshTokenKeyPrefix + authorizerAppId)
+			_ = self.Cache.Delete(AuthorizerFuncInfoKeyPrefix + authorizerAppId)
+			return nil, ErrAuthorizerNotAuthorized
+		}
+		return nil, err
+	}
+	if _, err := requireString(authorizerRefreshToken, "authorizer_access_token"); err != nil {
+		return nil, err
+	}
+	ttl := tokenTTL(authorizerRefreshToken["expires_in"])
+	self.cacheSetEx(AuthorizerTokenCacheKeyPrefix+authorizerAppId, map[string]interface{}{
 		"authorizer_access_token":  authorizerRefreshToken["authorizer_access_token"],
 		"authorizer_refresh_token": authorizerRefreshToken["authorizer_refresh_token"],
-		"expires_in":               time.Now().Unix() + 6600,
-	}, 6600)
+		"expires_in":               time.Now().Unix() + ttl,
+	}, ttl)
+	self.saveRefreshToken(authorizerAppId, authorizerRefreshToken["authorizer_refresh_token"])
 	return authorizerRefreshToken, nil
 }
 
 // ApiCreatePreAuthCode 获取预授权码
 func (self *Client) ApiCreatePreAuthCode() (string, error) {
-	dst, err := json.Marshal(map[string]interface{}{
-		"component_appid": self.AppId,
-	})
-	token, err := self.ApiComponentToken()
+	preAuthCode, err := self.ApiCreatePreAuthCodeFull()
 	if err != nil {
-		log.Println(err)
 		return "", err
 	}
-	status, body, err := self.Http.Post(self.Endpoint.PreAuthCodoUrl(token), "application/json", dst)
+	return preAuthCode.PreAuthCode, nil
+}
+
+// PreAuthCode 预授权码, ExpiresIn为有效期(秒)
+type PreAuthCode struct {
+	PreAuthCode string `json:"pre_auth_code"`
+	ExpiresIn   int64  `json:"expires_in"`
+}
+
+// ExpiresAt 根据获取时间计算预授权码的过期时间
+func (self *PreAuthCode) ExpiresAt(issuedAt time.Time) time.Time {
+	return issuedAt.Add(time.Duration(self.ExpiresIn) * time.Second)
+}
+
+// ApiCreatePreAuthCodeFull 获取预授权码及其有效期
+// 预授权码在授权完成后即失效, 每个授权链接都需要新的预授权码, 因此不缓存,
+// 调用方可根据ExpiresAt判断已生成的授权链接是否过期
+func (self *Client) ApiCreatePreAuthCodeFull() (*PreAuthCode, error) {
+	token, err := self.ApiComponentToken()
 	if err != nil {
-		log.Println(err)
-		return "", err
+		self.logger().Printf("获取预授权码失败: %v", err)
+		return nil, err
 	}
-	if status != http.StatusOK {
-		return "", errors.New("网络错误")
+	var preAuthCode PreAuthCode
+	err = self.postJSON(self.Endpoint.PreAuthCodoUrl(token), map[string]interface{}{
+		"component_appid": self.AppId,
+	}, &preAuthCode)
+	if err != nil {
+		return nil, err
 	}
-	resp := util.JsonUnmarshalBytes(body)
-
-	return resp["pre_auth_code"].(string), nil
+	if preAuthCode.PreAuthCode == "" {
+		return nil, errors.New("响应缺少pre_auth_code")
+	}
+	return &preAuthCode, nil
 }
 
 // ApiQueryAuth 使用授权码换取公众号或小程序的接口调用凭据和授权信息
 func (self *Client) ApiQueryAuth(code string) (map[string]interface{}, error) {
-	authorizerToken, err := self.getRawApiQueryAuth(code)
+	_, authorizerToken, err := self.getRawApiQueryAuth(code)
 	if err != nil {
 		log.Println(err)
 		return authorizerToken, err
@@ -127,46 +278,74 @@ func (self *Client) ApiQueryAuth(code string) (map[string]interface{}, error) {
 	return authorizerToken, nil
 }
 
-// ApiQueryAuth 使用授权码换取公众号或小程序的接口调用凭据和授权信息
-func (self *Client) getRawApiQueryAuth(code string) (map[string]interface{}, error) {
-	dst, err := json.Marshal(map[string]interface{}{
+// QueryAuth 使用授权码换取接口调用凭据, 返回包含授权方appid和权限集列表的授权信息
+func (self *Client) QueryAuth(code string) (*QueryAuthResult, error) {
+	result, _, err := self.getRawApiQueryAuth(code)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// getRawApiQueryAuth 使用授权码换取接口调用凭据并写入缓存
+func (self *Client) getRawApiQueryAuth(code string) (*QueryAuthResult, map[string]interface{}, error) {
+	data := map[string]interface{}{
 		"component_appid":    self.AppId,
 		"authorization_code": code,
-	})
+	}
 	token, err := self.ApiComponentToken()
 	if err != nil {
 		log.Println(err)
-		return nil, err
+		return nil, nil, err
 	}
-	status, body, err := self.Http.Post(self.Endpoint.ApiQueryAuth(token), "application/json", dst)
+	rawUrl := self.Endpoint.ApiQueryAuth(token)
+	status, body, err := self.Http.PostJSON(context.Background(), rawUrl, data)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	if status != http.StatusOK {
-		return nil, errors.New("网络错误")
+		return nil, nil, errors.New("网络错误")
 	}
-	authorizerToken := util.JsonUnmarshalBytes(body)
-	authorzationInfo := authorizerToken["authorization_info"].(map[string]interface{})
-	authorzationInfo["expires_in"] = time.Now().Unix() + 6600
-	err = self.Cache.SetEx(AuthorizerTokenCacheKeyPrefix+authorzationInfo["authorizer_appid"].(string), authorzationInfo, 6600)
+	authorizerToken, err := self.parseResponse(rawUrl, body)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
-	return authorzationInfo, nil
+	authorzationInfo, err := requireMap(authorizerToken, "authorization_info")
+	if err != nil {
+		return nil, nil, err
+	}
+	authorizerAppId, err := requireString(authorzationInfo, "authorizer_appid")
+	if err != nil {
+		return nil, nil, err
+	}
+	var resp struct {
+		AuthorizationInfo rawQueryAuthResult `json:"authorization_info"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, nil, err
+	}
+	result := resp.AuthorizationInfo.result()
+	ttl := tokenTTL(authorzationInfo["expires_in"])
+	authorzationInfo["expires_in"] = time.Now().Unix() + ttl
+	self.cacheSetEx(AuthorizerTokenCacheKeyPrefix+authorizerAppId, authorzationInfo, ttl)
+	self.saveRefreshToken(authorizerAppId, authorzationInfo["authorizer_refresh_token"])
+	self.saveFuncInfo(authorizerAppId, result.FuncInfo)
+	return result, authorzationInfo, nil
 }
 
 // ApiAuthorizerInfo 获取授权方的帐号基本信息
 func (self *Client) ApiAuthorizerInfo(authorizerAppId string) (map[string]interface{}, error) {
-	dst, err := json.Marshal(map[string]interface{}{
+	data := map[string]interface{}{
 		"component_appid":  self.AppId,
 		"authorizer_appid": authorizerAppId,
-	})
+	}
 	token, err := self.ApiComponentToken()
 	if err != nil {
 		log.Println(err)
 		return nil, err
 	}
-	status, body, err := self.Http.Post(self.Endpoint.ApiAuthorizerInfo(token), "application/json", dst)
+	rawUrl := self.Endpoint.ApiAuthorizerInfo(token)
+	status, body, err := self.Http.PostJSON(context.Background(), rawUrl, data)
 	if err != nil {
 		log.Println(err)
 		return nil, err
@@ -174,124 +353,207 @@ func (self *Client) ApiAuthorizerInfo(authorizerAppId string) (map[string]interf
 	if status != http.StatusOK {
 		return nil, errors.New("网络错误")
 	}
-	authorizerToken := util.JsonUnmarshalBytes(body)
-	authorizerInfo := authorizerToken["authorizer_info"].(map[string]interface{})
-	return authorizerInfo, nil
+	authorizerToken, err := self.parseResponse(rawUrl, body)
+	if err != nil {
+		return nil, err
+	}
+	return requireMap(authorizerToken, "authorizer_info")
 }
 
 // ApiComponentToken 获取第三方平台component_access_token
 func (self *Client) ApiComponentToken() (string, error) {
-	exist := self.Cache.Exists(ComponentTokenCacheKeyPrefix + self.AppId)
-	if !exist {
-		componentToken, err := self.getRawApiComponentToken()
+	componentToken := self.cachedComponentToken()
+	if componentToken == nil {
+		var err error
+		componentToken, err = self.refreshComponentToken()
 		if err != nil {
 			log.Println(err)
 			return "", err
 		}
-		if _, ok := componentToken["component_access_token"]; !ok {
-			return "", errors.New("获取组件Token失败")
-		}
-		return componentToken["component_access_token"].(string), nil
+	}
+	accessToken, ok := componentToken["component_access_token"].(string)
+	if !ok {
+		return "", errors.New("获取组件Token失败")
+	}
+	return accessToken, nil
+}
+
+// RefreshComponentToken 跳过缓存强制刷新component_access_token并覆盖缓存,
+// 同一时刻的多次强制刷新只请求一次
+func (self *Client) RefreshComponentToken() (string, error) {
+	value, err, _ := self.flight.Do("force@@"+ComponentTokenCacheKeyPrefix+self.AppId, func() (interface{}, error) {
+		return self.getRawApiComponentToken()
+	})
+	if err != nil {
+		return "", err
+	}
+	return requireString(value.(map[string]interface{}), "component_access_token")
+}
+
+// cachedComponentToken 读取缓存中未过期的component_access_token, 不存在或已过期时返回nil
+func (self *Client) cachedComponentToken() map[string]interface{} {
+	if !self.Cache.Exists(ComponentTokenCacheKeyPrefix + self.AppId) {
+		return nil
 	}
 	resp, err := self.Cache.Get(ComponentTokenCacheKeyPrefix + self.AppId)
 	if err != nil {
 		log.Println(err)
-		return "", err
+		return nil
 	}
 	componentToken := util.JsonUnmarshal(resp)
 	if componentToken == nil {
-		return "", err
+		return nil
+	}
+	expiresIn, ok := componentToken["expires_in"].(float64)
+	if !ok || time.Now().Unix() > int64(expiresIn) {
+		return nil
+	}
+	return componentToken
+}
+
+// refreshComponentToken 刷新component_access_token, 进程内的并发刷新只执行一次
+func (self *Client) refreshComponentToken() (map[string]interface{}, error) {
+	value, err, _ := self.flight.Do(ComponentTokenCacheKeyPrefix+self.AppId, func() (interface{}, error) {
+		return self.refreshComponentTokenLocked()
+	})
+	if err != nil {
+		return nil, err
 	}
-	if time.Now().Unix() > int64(componentToken["expires_in"].(float64)) {
-		componentToken, err := self.getRawApiComponentToken()
+	return value.(map[string]interface{}), nil
+}
+
+// refreshComponentTokenLocked 取得租约锁后刷新component_access_token, 刷新结束(无论成败)即释放锁;
+// 其它实例持有锁时最多等待componentTokenLockWait, 期间锁被释放但缓存仍无令牌(持有者刷新失败)时重新争抢,
+// 超时返回ErrComponentTokenRefreshing
+func (self *Client) refreshComponentTokenLocked() (map[string]interface{}, error) {
+	lockKey := ComponentTokenLockKeyPrefix + self.AppId
+	deadline := time.Now().Add(componentTokenLockWait)
+	for {
+		acquired, err := self.Cache.SetNX(lockKey, time.Now().Unix(), componentTokenLockLease)
 		if err != nil {
-			log.Println(err)
-			return "", err
+			// 缓存不可用时无法加锁, 直接刷新
+			return self.getRawApiComponentToken()
+		}
+		if acquired {
+			defer func() {
+				_ = self.Cache.Delete(lockKey)
+			}()
+			return self.getRawApiComponentToken()
+		}
+		for self.Cache.Exists(lockKey) {
+			if !time.Now().Before(deadline) {
+				return nil, ErrComponentTokenRefreshing
+			}
+			time.Sleep(componentTokenLockPoll)
+			if componentToken := self.cachedComponentToken(); componentToken != nil {
+				return componentToken, nil
+			}
+		}
+		if componentToken := self.cachedComponentToken(); componentToken != nil {
+			return componentToken, nil
+		}
+		if !time.Now().Before(deadline) {
+			return nil, ErrComponentTokenRefreshing
 		}
-		return componentToken["component_access_token"].(string), nil
 	}
-	return componentToken["component_access_token"].(string), nil
+}
+
+// cacheSetEx 写入缓存, 失败时记录日志并上报, 调用方仍使用刚获取的值
+func (self *Client) cacheSetEx(key string, val interface{}, expires int64) {
+	self.reportCacheError(key, self.Cache.SetEx(key, val, expires))
+}
+
+// logger 返回配置的日志, 未配置(如直接构造Client)时使用core.DefaultLogger
+func (self *Client) logger() core.Logger {
+	if self.Logger != nil {
+		return self.Logger
+	}
+	return core.DefaultLogger
+}
+
+func (self *Client) reportCacheError(key string, err error) {
+	if err == nil {
+		return
+	}
+	err = fmt.Errorf("写入缓存%s失败: %w", key, err)
+	self.logger().Printf("%v", err)
+	if self.ErrorReporter != nil {
+		self.ErrorReporter(err)
+	}
 }
 
 // getRawApiComponentToken 获取第三方平台component_access_token
 func (self *Client) getRawApiComponentToken() (map[string]interface{}, error) {
-	dst, err := json.Marshal(map[string]interface{}{
+	data := map[string]interface{}{
 		"component_appid":         self.AppId,
 		"component_appsecret":     self.AppSecret,
 		"component_verify_ticket": self.getComponentTicket(),
-	})
-	status, body, err := self.Http.Post(self.Endpoint.ComponentAccessTokenUrl(), "application/json", dst)
+	}
+	rawUrl := self.Endpoint.ComponentAccessTokenUrl()
+	status, body, err := self.Http.PostJSON(context.Background(), rawUrl, data)
 	if err != nil {
 		log.Println(err)
 		return nil, err
 	}
 	if status != http.StatusOK {
-		log.Print(err)
+		return nil, errors.New("网络错误")
+	}
+	componentToken, err := self.parseResponse(rawUrl, body)
+	if err != nil {
+		return nil, err
+	}
+	if err := responseError(componentToken); err != nil {
 		return nil, err
 	}
-	componentToken := util.JsonUnmarshalBytes(body)
-	componentToken["expires_in"] = time.Now().Unix() + 6600
-	_ = self.Cache.SetEx(ComponentTokenCacheKeyPrefix+self.AppId, componentToken, 6600)
+	ttl := tokenTTL(componentToken["expires_in"])
+	componentToken["expires_in"] = time.Now().Unix() + ttl
+	self.cacheSetEx(ComponentTokenCacheKeyPrefix+self.AppId, componentToken, ttl)
 	return componentToken, nil
 }
 
 // getComponentTicket 获取component_verify_ticket
 func (self *Client) getComponentTicket() (ticket string) {
-	exist := self.Cache.Exists(ComponentTicketCacheKeyPrefix + self.AppId)
-	if !exist {
-		return ""
-	}
-	resp, _ := self.Cache.Get(ComponentTicketCacheKeyPrefix + self.AppId)
-	log.Println(ticket)
-	log.Println(resp)
-	componentVerifyTicket := util.JsonUnmarshal(resp)
+	componentVerifyTicket := self.cachedComponentTicket()
 	if componentVerifyTicket == nil {
 		return ""
 	}
-	return string(componentVerifyTicket["component_verify_ticket"].(string))
+	ticket, _ = componentVerifyTicket["component_verify_ticket"].(string)
+	return ticket
 }
 
-// FastRegisterWeapp 快速注册小程序
-func (self *Client) FastRegisterWeapp(data map[string]interface{}) error {
-	dst, err := json.Marshal(data)
-	token, err := self.ApiComponentToken()
-	if err != nil {
-		return err
+// cachedComponentTicket 读取缓存中的ticket记录, 包含component_verify_ticket和接收时间received_at
+func (self *Client) cachedComponentTicket() map[string]interface{} {
+	if !self.Cache.Exists(ComponentTicketCacheKeyPrefix + self.AppId) {
+		return nil
 	}
-	status, body, err := self.Http.Post(self.Endpoint.FastRegisterWeapp(token), "application/json", dst)
+	resp, err := self.Cache.Get(ComponentTicketCacheKeyPrefix + self.AppId)
 	if err != nil {
-		return err
-	}
-	if status != http.StatusOK {
-		return errors.New("网络错误")
+		return nil
 	}
-	resp := util.JsonUnmarshalBytes(body)
-	log.Println(resp)
-	if int(resp["errcode"].(float64)) != 0 {
-		return errors.New("注册失败:" + resp["errmsg"].(string))
-	}
-
-	return nil
+	return util.JsonUnmarshal(resp)
 }
 
 // FastRegisterWeappSearch 快速注册小程序结果查询
 func (self *Client) FastRegisterWeappSearch(data map[string]interface{}) error {
-	dst, err := json.Marshal(data)
 	token, err := self.ApiComponentToken()
 	if err != nil {
 		return err
 	}
-	status, body, err := self.Http.Post(self.Endpoint.FastRegisterWeappSearch(token), "application/json", dst)
+	rawUrl := self.Endpoint.FastRegisterWeappSearch(token)
+	status, body, err := self.Http.PostJSON(context.Background(), rawUrl, data)
 	if err != nil {
 		return err
 	}
 	if status != http.StatusOK {
 		return errors.New("网络错误")
 	}
-	resp := util.JsonUnmarshalBytes(body)
+	resp, err := self.parseResponse(rawUrl, body)
+	if err != nil {
+		return err
+	}
 	log.Println(resp)
-	if int(resp["errcode"].(float64)) != 0 {
-		return errors.New("注册失败:" + resp["errmsg"].(string))
+	if err := responseError(resp); err != nil {
+		return err
 	}
 
 	return nil
@@ -299,10 +561,11 @@ func (self *Client) FastRegisterWeappSearch(data map[string]interface{}) error {
 
 // BindTester 绑定体验者账号
 func (self *Client) BindTester(authorizerAccessToken, wechatId string) error {
-	dst, err := json.Marshal(map[string]interface{}{
+	data := map[string]interface{}{
 		"wechatid": wechatId,
-	})
-	status, body, err := self.Http.Post(self.Endpoint.BindTester(authorizerAccessToken), "application/json", dst)
+	}
+	rawUrl := self.Endpoint.BindTester(authorizerAccessToken)
+	status, body, err := self.Http.PostJSON(context.Background(), rawUrl, data)
 	if err != nil {
 		log.Println(err)
 		return err
@@ -310,37 +573,44 @@ func (self *Client) BindTester(authorizerAccessToken, wechatId string) error {
 	if status != http.StatusOK {
 		return errors.New("网络错误")
 	}
-	resp := util.JsonUnmarshalBytes(body)
-	if int(resp["errcode"].(float64)) != 0 {
-		return errors.New("操作失败:" + resp["errmsg"].(string))
+	resp, err := self.parseResponse(rawUrl, body)
+	if err != nil {
+		return err
+	}
+	if err := responseError(resp); err != nil {
+		return err
 	}
 	return nil
 }
 
 // UnbindTester 解除绑定体验者账号
 func (self *Client) UnbindTester(authorizerAccessToken, wechatId string) error {
-	dst, err := json.Marshal(map[string]interface{}{
+	data := map[string]interface{}{
 		"wechatid": wechatId,
-	})
-	status, body, err := self.Http.Post(self.Endpoint.UnbindTester(authorizerAccessToken), "application/json", dst)
+	}
+	rawUrl := self.Endpoint.UnbindTester(authorizerAccessToken)
+	status, body, err := self.Http.PostJSON(context.Background(), rawUrl, data)
 	if err != nil {
 		return err
 	}
 	if status != http.StatusOK {
 		return errors.New("网络错误")
 	}
-	resp := util.JsonUnmarshalBytes(body)
+	resp, err := self.parseResponse(rawUrl, body)
+	if err != nil {
+		return err
+	}
 	log.Println(resp)
-	if int(resp["errcode"].(float64)) != 0 {
-		return errors.New("操作失败:" + resp["errmsg"].(string))
+	if err := responseError(resp); err != nil {
+		return err
 	}
 	return nil
 }
 
 // ModifyDomain 修改小程序服务器域名
 func (self *Client) ModifyDomain(authorizerAccessToken string, data map[string]interface{}) error {
-	dst, err := json.Marshal(data)
-	status, body, err := self.Http.Post(self.Endpoint.ModifyDomain(authorizerAccessToken), "application/json", dst)
+	rawUrl := self.Endpoint.ModifyDomain(authorizerAccessToken)
+	status, body, err := self.Http.PostJSON(context.Background(), rawUrl, data)
 	if err != nil {
 		log.Println(err)
 		return err
@@ -348,56 +618,58 @@ func (self *Client) ModifyDomain(authorizerAccessToken string, data map[string]i
 	if status != http.StatusOK {
 		return errors.New("网络错误")
 	}
-	resp := util.JsonUnmarshalBytes(body)
+	resp, err := self.parseResponse(rawUrl, body)
+	if err != nil {
+		return err
+	}
 	log.Println(resp)
-	if int(resp["errcode"].(float64)) != 0 {
-		return errors.New("操作失败:" + resp["errmsg"].(string))
+	if err := responseError(resp); err != nil {
+		return err
 	}
 	return nil
 }
 
 // CommitCode 上传小程序代码
 func (self *Client) CommitCode(authorizerAccessToken string, data map[string]interface{}) error {
-	dst, err := json.Marshal(data)
-	status, body, err := self.Http.Post(self.Endpoint.CommitCode(authorizerAccessToken), "application/json", dst)
-	if err != nil {
-		log.Println(err)
-		return err
-	}
-	if status != http.StatusOK {
-		return errors.New("网络错误")
+	_, err := self.CommitCodeWithResponse(authorizerAccessToken, data)
+	return err
+}
+
+// CommitCodeWithResponse 上传小程序代码, 返回微信的完整响应
+func (self *Client) CommitCodeWithResponse(authorizerAccessToken string, data map[string]interface{}) (*CodeResponse, error) {
+	if ok, err := self.dryRun(self.Endpoint.CommitCode(authorizerAccessToken), data); ok {
+		return &CodeResponse{ErrMsg: "ok"}, err
 	}
-	resp := util.JsonUnmarshalBytes(body)
-	log.Println(resp)
-	if int(resp["errcode"].(float64)) != 0 {
-		return errors.New("操作失败:" + resp["errmsg"].(string))
+	var resp CodeResponse
+	if err := self.postJSON(self.Endpoint.CommitCode(authorizerAccessToken), data, &resp); err != nil {
+		return nil, err
 	}
-	return nil
+	return &resp, nil
 }
 
 // SubmitAudit 提交审核
+//
+// Deprecated: 使用SubmitAuditWithResponse
 func (self *Client) SubmitAudit(authorizerAccessToken string, data map[string]interface{}) error {
-	dst, err := json.Marshal(data)
-	status, body, err := self.Http.Post(self.Endpoint.SubmitAudit(authorizerAccessToken), "application/json", dst)
+	_, err := self.SubmitAuditWithResponse(authorizerAccessToken, SubmitAuditRequest{Extra: data})
+	return err
+}
+
+// SubmitAuditWithResult 提交审核, 返回审核编号auditid
+//
+// Deprecated: 使用SubmitAuditWithResponse, data中的字段可通过SubmitAuditRequest.Extra提交
+func (self *Client) SubmitAuditWithResult(authorizerAccessToken string, data map[string]interface{}) (int64, error) {
+	resp, err := self.SubmitAuditWithResponse(authorizerAccessToken, SubmitAuditRequest{Extra: data})
 	if err != nil {
-		log.Println(err)
-		return err
+		return 0, err
 	}
-	if status != http.StatusOK {
-		return errors.New("网络错误")
-	}
-	resp := util.JsonUnmarshalBytes(body)
-	log.Println(resp)
-	if int(resp["errcode"].(float64)) != 0 {
-		return errors.New("操作失败:" + resp["errmsg"].(string))
-	}
-	return nil
+	return resp.AuditId, nil
 }
 
 // UndoCodeAudit 审核撤回
 func (self *Client) UndoCodeAudit(authorizerAccessToken string, data map[string]interface{}) error {
-	dst, err := json.Marshal(data)
-	status, body, err := self.Http.Post(self.Endpoint.SubmitAudit(authorizerAccessToken), "application/json", dst)
+	rawUrl := self.Endpoint.UndoCodeAudit(authorizerAccessToken)
+	status, body, err := self.Http.PostJSON(context.Background(), rawUrl, data)
 	if err != nil {
 		log.Println(err)
 		return err
@@ -405,55 +677,57 @@ func (self *Client) UndoCodeAudit(authorizerAccessToken string, data map[string]
 	if status != http.StatusOK {
 		return errors.New("网络错误")
 	}
-	resp := util.JsonUnmarshalBytes(body)
+	resp, err := self.parseResponse(rawUrl, body)
+	if err != nil {
+		return err
+	}
 	log.Println(resp)
-	if int(resp["errcode"].(float64)) != 0 {
-		return errors.New("操作失败:" + resp["errmsg"].(string))
+	if err := responseError(resp); err != nil {
+		return err
 	}
 	return nil
 }
 
 // Release 小程序发布
 func (self *Client) Release(authorizerAccessToken string, data map[string]interface{}) error {
-	dst, err := json.Marshal(data)
-	status, body, err := self.Http.Post(self.Endpoint.Release(authorizerAccessToken), "application/json", dst)
-	if err != nil {
-		log.Println(err)
-		return err
+	_, err := self.ReleaseWithResponse(authorizerAccessToken, data)
+	return err
+}
+
+// ReleaseWithResponse 小程序发布, 返回微信的完整响应
+func (self *Client) ReleaseWithResponse(authorizerAccessToken string, data map[string]interface{}) (*CodeResponse, error) {
+	if data == nil {
+		data = map[string]interface{}{}
 	}
-	if status != http.StatusOK {
-		return errors.New("网络错误")
+	if ok, err := self.dryRun(self.Endpoint.Release(authorizerAccessToken), data); ok {
+		return &CodeResponse{ErrMsg: "ok"}, err
 	}
-	resp := util.JsonUnmarshalBytes(body)
-	log.Println(resp)
-	if int(resp["errcode"].(float64)) != 0 {
-		return errors.New("操作失败:" + resp["errmsg"].(string))
+	var resp CodeResponse
+	if err := self.postJSON(self.Endpoint.Release(authorizerAccessToken), data, &resp); err != nil {
+		return nil, err
 	}
-	return nil
+	return &resp, nil
 }
 
 // GetWxaCode 小程序码
 func (self *Client) GetWxaCode(authorizerAccessToken string, data map[string]interface{}) ([]byte, error) {
-	dst, err := json.Marshal(data)
-	status, body, err := self.Http.Post(self.Endpoint.GetWxaCode(authorizerAccessToken), "application/json", dst)
-	if err != nil {
-		log.Println(err)
-		return nil, err
-	}
-	if status != http.StatusOK {
-		return nil, errors.New("网络错误")
-	}
-	resp := util.JsonUnmarshalBytes(body)
-	log.Println(resp)
-	if _, ok := resp["errcode"]; ok {
-		return nil, errors.New("操作失败:" + resp["errmsg"].(string))
+	return self.GetWxaCodeContext(context.Background(), authorizerAccessToken, data)
+}
+
+// GetWxaCodeContext 小程序码, ctx取消时中止请求
+func (self *Client) GetWxaCodeContext(ctx context.Context, authorizerAccessToken string, data map[string]interface{}) ([]byte, error) {
+	if envVersion, ok := data["env_version"].(string); ok {
+		if err := checkEnvVersion(envVersion); err != nil {
+			return nil, err
+		}
 	}
-	return body, nil
+	return self.postBinary(ctx, self.Endpoint.GetWxaCode(authorizerAccessToken), data)
 }
 
 // GetLastAuditStatus 获取小程序最后一次审核状态
 func (self *Client) GetLastAuditStatus(authorizerAccessToken string) (map[string]interface{}, error) {
-	status, body, err := self.Http.Get(self.Endpoint.GetLastAuditStatus(authorizerAccessToken))
+	rawUrl := self.Endpoint.GetLastAuditStatus(authorizerAccessToken)
+	status, body, err := self.Http.Get(rawUrl)
 	if err != nil {
 		log.Println(err)
 		return nil, err
@@ -461,10 +735,13 @@ func (self *Client) GetLastAuditStatus(authorizerAccessToken string) (map[string
 	if status != http.StatusOK {
 		return nil, errors.New("网络错误")
 	}
-	resp := util.JsonUnmarshalBytes(body)
+	resp, err := self.parseResponse(rawUrl, body)
+	if err != nil {
+		return nil, err
+	}
 	log.Println(resp)
-	if int(resp["errcode"].(float64)) != 0 {
-		return nil, errors.New("操作失败:" + resp["errmsg"].(string))
+	if err := responseError(resp); err != nil {
+		return nil, err
 	}
 	return resp, nil
 }
@@ -476,7 +753,8 @@ func (self *Client) GetTemplateList() (map[string]interface{}, error) {
 		log.Println(err)
 		return nil, err
 	}
-	status, body, err := self.Http.Get(self.Endpoint.GetTemplateList(token))
+	rawUrl := self.Endpoint.GetTemplateList(token)
+	status, body, err := self.Http.Get(rawUrl)
 	if err != nil {
 		log.Println(err)
 		return nil, err
@@ -484,27 +762,34 @@ func (self *Client) GetTemplateList() (map[string]interface{}, error) {
 	if status != http.StatusOK {
 		return nil, errors.New("网络错误")
 	}
-	resp := util.JsonUnmarshalBytes(body)
+	resp, err := self.parseResponse(rawUrl, body)
+	if err != nil {
+		return nil, err
+	}
 	log.Println(resp)
-	if int(resp["errcode"].(float64)) != 0 {
-		return nil, errors.New("操作失败:" + resp["errmsg"].(string))
+	if err := responseError(resp); err != nil {
+		return nil, err
 	}
 	return resp, nil
 }
 
 // GetPage 获取已上传的代码的页面列表
 func (self *Client) GetPage(authorizerAccessToken string) (map[string]interface{}, error) {
-	status, body, err := self.Http.Get(self.Endpoint.GetPage(authorizerAccessToken))
+	rawUrl := self.Endpoint.GetPage(authorizerAccessToken)
+	status, body, err := self.Http.Get(rawUrl)
 	if err != nil {
 		return nil, err
 	}
 	if status != http.StatusOK {
 		return nil, errors.New("网络错误")
 	}
-	resp := util.JsonUnmarshalBytes(body)
+	resp, err := self.parseResponse(rawUrl, body)
+	if err != nil {
+		return nil, err
+	}
 	log.Println(resp)
-	if int(resp["errcode"].(float64)) != 0 {
-		return nil, errors.New("操作失败:" + resp["errmsg"].(string))
+	if err := responseError(resp); err != nil {
+		return nil, err
 	}
 	return resp, nil
 }
@@ -516,7 +801,8 @@ func (self *Client) MpLogin(authorizerAppId, code string) (map[string]interface{
 		log.Println(err)
 		return nil, err
 	}
-	status, body, err := self.Http.Get(self.Endpoint.JsCode2Session(authorizerAppId, code, self.AppId, token))
+	rawUrl := self.Endpoint.JsCode2Session(authorizerAppId, code, self.AppId, token)
+	status, body, err := self.Http.Get(rawUrl)
 	if err != nil {
 		log.Println(err)
 		return nil, err
@@ -524,87 +810,58 @@ func (self *Client) MpLogin(authorizerAppId, code string) (map[string]interface{
 	if status != http.StatusOK {
 		return nil, errors.New("网络错误")
 	}
-	resp := util.JsonUnmarshalBytes(body)
-	log.Println(resp)
-	if _, ok := resp["errcode"]; ok {
-		return nil, errors.New("操作失败:" + resp["errmsg"].(string))
+	resp, err := self.parseResponse(rawUrl, body)
+	if err != nil {
+		return nil, err
+	}
+	if err := responseError(resp); err != nil {
+		return nil, err
 	}
 	return resp, nil
 }
 
 // GetQrCode 小程序体验码
 func (self *Client) GetQrCode(authorizerAccessToken, path string) ([]byte, error) {
-	status, body, err := self.Http.Get(self.Endpoint.GetQrCode(authorizerAccessToken, path))
-	if err != nil {
-		return nil, err
-	}
-	if status != http.StatusOK {
-		return nil, errors.New("网络错误")
-	}
-	resp := util.JsonUnmarshalBytes(body)
-	log.Println(resp)
-	if _, ok := resp["errcode"]; ok {
-		return nil, errors.New("操作失败:" + resp["errmsg"].(string))
-	}
-	return body, nil
+	return self.getBinary(context.Background(), self.Endpoint.GetQrCode(authorizerAccessToken, url.QueryEscape(path)))
 }
 
 // GetQrCode 小程序体验码
 func (self *Client) GetQrCodeWithoutPath(authorizerAccessToken string) ([]byte, error) {
-	status, body, err := self.Http.Get(self.Endpoint.GetQrCodeWithoutPath(authorizerAccessToken))
-	if err != nil {
-		return nil, err
-	}
-	if status != http.StatusOK {
-		return nil, errors.New("网络错误")
-	}
-	resp := util.JsonUnmarshalBytes(body)
-	log.Println(resp)
-	if _, ok := resp["errcode"]; ok {
-		return nil, errors.New("操作失败:" + resp["errmsg"].(string))
-	}
-	return body, nil
+	return self.getBinary(context.Background(), self.Endpoint.GetQrCodeWithoutPath(authorizerAccessToken))
 }
 
 // GetWxaQrCode 生成带参数小程序码
 func (self *Client) GetWxaQrCode(authorizerAccessToken, path string, width int) ([]byte, error) {
-	dst, err := json.Marshal(map[string]interface{}{
+	return self.postBinary(context.Background(), self.Endpoint.CreateWxaQrCode(authorizerAccessToken), map[string]interface{}{
 		"path":  path,
 		"width": width,
 	})
-	status, body, err := self.Http.Post(self.Endpoint.CreateWxaQrCode(authorizerAccessToken), "application/json", dst)
-	if err != nil {
-		return nil, err
-	}
-	if status != http.StatusOK {
-		return nil, errors.New("网络错误")
-	}
-	resp := util.JsonUnmarshalBytes(body)
-	log.Println(resp)
-	if _, ok := resp["errcode"]; ok {
-		return nil, errors.New("操作失败:" + resp["errmsg"].(string))
-	}
-	return body, nil
 }
 
 // MemberAuth 获取小程序所有已绑定的体验者列表
 func (self *Client) MemberAuth(authorizerAccessToken string) (map[string]interface{}, error) {
-	status, body, err := self.Http.Get(self.Endpoint.MemberAuth(authorizerAccessToken))
+	rawUrl := self.Endpoint.MemberAuth(authorizerAccessToken)
+	status, body, err := self.Http.Get(rawUrl)
 	if err != nil {
 		return nil, err
 	}
 	if status != http.StatusOK {
 		return nil, errors.New("网络错误")
 	}
-	resp := util.JsonUnmarshalBytes(body)
+	resp, err := self.parseResponse(rawUrl, body)
+	if err != nil {
+		return nil, err
+	}
 	log.Println(resp)
-	if _, ok := resp["errcode"]; ok {
-		return nil, errors.New("操作失败:" + resp["errmsg"].(string))
+	if err := responseError(resp); err != nil {
+		return nil, err
 	}
 	return resp, nil
 }
 
-// OAuth2Authorize 获取服务号授权网址
+// OAuth2Authorize 获取服务号授权网址, scope和state为占位值
+//
+// Deprecated: 使用BuildComponentOAuthUrl
 func (self *Client) OAuth2Authorize(authorizerApppId, redirectUrl string) string {
 	return self.Endpoint.OAuth2Authorize(authorizerApppId, url.QueryEscape(redirectUrl), self.AppId)
 }
@@ -616,17 +873,21 @@ func (self *Client) OAuth2AccessToken(authorizerApppId, code string) (map[string
 		return nil, err
 	}
 
-	status, body, err := self.Http.Get(self.Endpoint.OAuth2AccessToken(authorizerApppId, code, self.AppId, token))
+	rawUrl := self.Endpoint.OAuth2AccessToken(authorizerApppId, code, self.AppId, token)
+	status, body, err := self.Http.Get(rawUrl)
 	if err != nil {
 		return nil, err
 	}
 	if status != http.StatusOK {
 		return nil, errors.New("网络错误")
 	}
-	resp := util.JsonUnmarshalBytes(body)
+	resp, err := self.parseResponse(rawUrl, body)
+	if err != nil {
+		return nil, err
+	}
 	log.Println(resp)
-	if _, ok := resp["errcode"]; ok {
-		return nil, errors.New("操作失败:" + resp["errmsg"].(string))
+	if err := responseError(resp); err != nil {
+		return nil, err
 	}
 	return resp, nil
 }
@@ -637,39 +898,47 @@ func (self *Client) OAuth2RefreshToken(authorizerAppId, refreshToken string) (ma
 	if err != nil {
 		return nil, err
 	}
-	status, body, err := self.Http.Get(self.Endpoint.OAuth2RefreshToken(authorizerAppId, self.AppId, token, refreshToken))
+	rawUrl := self.Endpoint.OAuth2RefreshToken(authorizerAppId, self.AppId, token, refreshToken)
+	status, body, err := self.Http.Get(rawUrl)
 	if err != nil {
 		return nil, err
 	}
 	if status != http.StatusOK {
 		return nil, errors.New("网络错误")
 	}
-	authorizerRefreshToken := util.JsonUnmarshalBytes(body)
-	_ = self.Cache.SetEx(MpAuthorizerTokenCacheKeyPrefix+authorizerAppId, map[string]interface{}{
+	authorizerRefreshToken, err := self.parseResponse(rawUrl, body)
+	if err != nil {
+		return nil, err
+	}
+	if err := responseError(authorizerRefreshToken); err != nil {
+		return nil, err
+	}
+	ttl := tokenTTL(authorizerRefreshToken["expires_in"])
+	self.cacheSetEx(MpAuthorizerTokenCacheKeyPrefix+authorizerAppId, map[string]interface{}{
 		"authorizer_mp_access_token":  authorizerRefreshToken["authorizer_access_token"],
 		"authorizer_mp_refresh_token": authorizerRefreshToken["authorizer_refresh_token"],
-		"expires_in":                  time.Now().Unix() + 6600,
-	}, 6600)
+		"expires_in":                  time.Now().Unix() + ttl,
+	}, ttl)
 	return authorizerRefreshToken, nil
 }
 
 // CustomService
 func (self *Client) CustomService(authorizerAccessToken string, data map[string]interface{}) error {
-	dst, err := json.Marshal(data)
-	if err != nil {
-		return err
-	}
-	status, body, err := self.Http.Post(self.Endpoint.CustomService(authorizerAccessToken), "", dst)
+	rawUrl := self.Endpoint.CustomService(authorizerAccessToken)
+	status, body, err := self.Http.PostJSON(context.Background(), rawUrl, data)
 	if err != nil {
 		return err
 	}
 	if status != http.StatusOK {
 		return errors.New("网络错误")
 	}
-	resp := util.JsonUnmarshalBytes(body)
+	resp, err := self.parseResponse(rawUrl, body)
+	if err != nil {
+		return err
+	}
 	log.Println(resp)
-	if _, ok := resp["errcode"]; ok {
-		return errors.New("操作失败:" + resp["errmsg"].(string))
+	if err := responseError(resp); err != nil {
+		return err
 	}
 	return nil
 }
@@ -0,0 +1,352 @@
+package open
+
+import (
+	"context"
+	"errors"
+	"github.com/mrwangjinjin/go-wechat/core"
+	"github.com/mrwangjinjin/go-wechat/core/cache/lru"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// expireComponentToken 删除缓存中的component_access_token, 使下次调用需要刷新
+func expireComponentToken(client *Client) {
+	_ = client.Cache.Delete(ComponentTokenCacheKeyPrefix + testAppId)
+}
+
+func shortenComponentTokenLockWait(t *testing.T, wait time.Duration) {
+	oldWait, oldPoll := componentTokenLockWait, componentTokenLockPoll
+	componentTokenLockWait, componentTokenLockPoll = wait, 10*time.Millisecond
+	t.Cleanup(func() {
+		componentTokenLockWait, componentTokenLockPoll = oldWait, oldPoll
+	})
+}
+
+func TestComponentTokenLockReleasedAfterRefresh(t *testing.T) {
+	server := newTestServer(t)
+	server.respond("/cgi-bin/component/api_component_token", `{"component_access_token":"NEW_TOKEN","expires_in":7200}`)
+	client, _ := newTestClient(t, server)
+	expireComponentToken(client)
+
+	token, err := client.ApiComponentToken()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token != "NEW_TOKEN" {
+		t.Fatalf("got %s, want NEW_TOKEN", token)
+	}
+	if client.Cache.Exists(ComponentTokenLockKeyPrefix + testAppId) {
+		t.Fatal("lock not released after successful refresh")
+	}
+}
+
+func TestComponentTokenLockReleasedAfterFailure(t *testing.T) {
+	server := newTestServer(t)
+	server.respond("/cgi-bin/component/api_component_token", `{"errcode":61004,"errmsg":"access clientip is not registered"}`)
+	client, _ := newTestClient(t, server)
+	expireComponentToken(client)
+
+	if _, err := client.ApiComponentToken(); err == nil {
+		t.Fatal("expected refresh error")
+	}
+	if client.Cache.Exists(ComponentTokenLockKeyPrefix + testAppId) {
+		t.Fatal("lock not released after failed refresh")
+	}
+}
+
+func TestComponentTokenWaitsForLockHolder(t *testing.T) {
+	shortenComponentTokenLockWait(t, time.Second)
+	server := newTestServer(t)
+	client, _ := newTestClient(t, server)
+	expireComponentToken(client)
+	// 模拟其它实例持有锁, 并在稍后写入令牌
+	_, _ = client.Cache.SetNX(ComponentTokenLockKeyPrefix+testAppId, 1, componentTokenLockLease)
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		_ = client.Cache.SetEx(ComponentTokenCacheKeyPrefix+testAppId, map[string]interface{}{
+			"component_access_token": "OTHER_TOKEN",
+			"expires_in":             time.Now().Unix() + 3600,
+		}, 3600)
+	}()
+
+	token, err := client.ApiComponentToken()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token != "OTHER_TOKEN" {
+		t.Fatalf("got %s, want OTHER_TOKEN", token)
+	}
+	if n := server.requestCount(); n != 0 {
+		t.Fatalf("waiter sent %d requests", n)
+	}
+}
+
+func TestComponentTokenRefreshesWhenHolderFails(t *testing.T) {
+	shortenComponentTokenLockWait(t, time.Second)
+	server := newTestServer(t)
+	server.respond("/cgi-bin/component/api_component_token", `{"component_access_token":"NEW_TOKEN","expires_in":7200}`)
+	client, _ := newTestClient(t, server)
+	expireComponentToken(client)
+	// 模拟其它实例刷新失败后释放锁
+	_, _ = client.Cache.SetNX(ComponentTokenLockKeyPrefix+testAppId, 1, componentTokenLockLease)
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		_ = client.Cache.Delete(ComponentTokenLockKeyPrefix + testAppId)
+	}()
+
+	start := time.Now()
+	token, err := client.ApiComponentToken()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token != "NEW_TOKEN" {
+		t.Fatalf("got %s, want NEW_TOKEN", token)
+	}
+	if elapsed := time.Since(start); elapsed >= time.Second {
+		t.Fatalf("waited %v for a released lock", elapsed)
+	}
+}
+
+func TestComponentTokenWaitIsBounded(t *testing.T) {
+	shortenComponentTokenLockWait(t, 100*time.Millisecond)
+	server := newTestServer(t)
+	client, _ := newTestClient(t, server)
+	expireComponentToken(client)
+	_, _ = client.Cache.SetNX(ComponentTokenLockKeyPrefix+testAppId, 1, componentTokenLockLease)
+
+	start := time.Now()
+	if _, err := client.ApiComponentToken(); !errors.Is(err, ErrComponentTokenRefreshing) {
+		t.Fatalf("got %v, want ErrComponentTokenRefreshing", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("waited %v, want about 100ms", elapsed)
+	}
+}
+
+func TestComponentTokenUsesResponseExpiry(t *testing.T) {
+	server := newTestServer(t)
+	server.respond("/cgi-bin/component/api_component_token", `{"component_access_token":"NEW_TOKEN","expires_in":3600}`)
+	client, _ := newTestClient(t, server)
+	expireComponentToken(client)
+
+	if _, err := client.ApiComponentToken(); err != nil {
+		t.Fatal(err)
+	}
+	expiresAt, _ := client.cachedComponentToken()["expires_in"].(float64)
+	want := time.Now().Unix() + 3600 - tokenAhead
+	if diff := int64(expiresAt) - want; diff < -1 || diff > 1 {
+		t.Fatalf("expires_in: got %d, want about %d", int64(expiresAt), want)
+	}
+}
+
+func TestTokenTTL(t *testing.T) {
+	for _, tc := range []struct {
+		expiresIn interface{}
+		want      int64
+	}{
+		{float64(7200), 7200 - tokenAhead},
+		{float64(3600), 3600 - tokenAhead},
+		{float64(300), defaultTokenTTL},
+		{nil, defaultTokenTTL},
+		{"7200", defaultTokenTTL},
+	} {
+		if got := tokenTTL(tc.expiresIn); got != tc.want {
+			t.Errorf("tokenTTL(%v): got %d, want %d", tc.expiresIn, got, tc.want)
+		}
+	}
+}
+
+func TestApiCreatePreAuthCodeFull(t *testing.T) {
+	server := newTestServer(t)
+	server.respond("/cgi-bin/component/api_create_preauthcode", `{"pre_auth_code":"PRE_AUTH_CODE","expires_in":1800}`)
+	client, _ := newTestClient(t, server)
+
+	code, err := client.ApiCreatePreAuthCodeFull()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if code.PreAuthCode != "PRE_AUTH_CODE" || code.ExpiresIn != 1800 {
+		t.Fatalf("unexpected pre-auth code: %+v", code)
+	}
+	issuedAt := time.Unix(1000, 0)
+	if got := code.ExpiresAt(issuedAt); !got.Equal(time.Unix(2800, 0)) {
+		t.Fatalf("ExpiresAt: got %v", got)
+	}
+	if body := decodeBody(t, server.lastRequest(t)); body["component_appid"] != testAppId {
+		t.Fatalf("unexpected body: %v", body)
+	}
+}
+
+// countingTransport 记录经过的请求数
+type countingTransport struct {
+	count int32
+}
+
+func (self *countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	atomic.AddInt32(&self.count, 1)
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestWithHTTPClientUsesCustomTransport(t *testing.T) {
+	server := newTestServer(t)
+	transport := &countingTransport{}
+	logger := &testLogger{}
+	client := NewClient(&core.ClientConfig{AppId: testAppId, BaseUrl: server.URL, Logger: logger},
+		lru.NewLRUCache(0), WithHTTPClient(&http.Client{Transport: transport}))
+
+	if err := client.Release(testAuthorizerToken, nil); err != nil {
+		t.Fatal(err)
+	}
+	if n := atomic.LoadInt32(&transport.count); n != 1 {
+		t.Fatalf("custom transport saw %d requests, want 1", n)
+	}
+	if logged := logger.String(); logged != "" {
+		t.Fatalf("unexpected log: %s", logged)
+	}
+}
+
+func TestWithTimeoutConflictIsLogged(t *testing.T) {
+	logger := &testLogger{}
+	NewClient(&core.ClientConfig{AppId: testAppId, Logger: logger}, lru.NewLRUCache(0),
+		WithHTTPClient(&http.Client{Timeout: time.Second}), WithTimeout(5*time.Second))
+	if logged := logger.String(); !strings.Contains(logged, "[WARN] WithTimeout(5s)") {
+		t.Fatalf("conflict not logged, got: %q", logged)
+	}
+
+	logger = &testLogger{}
+	NewClient(&core.ClientConfig{AppId: testAppId, Logger: logger}, lru.NewLRUCache(0),
+		WithHTTPClient(&http.Client{Timeout: 10 * time.Second}), WithTimeout(5*time.Second))
+	if logged := logger.String(); logged != "" {
+		t.Fatalf("shorter WithTimeout must not warn, got: %q", logged)
+	}
+}
+
+func TestWithTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(300 * time.Millisecond):
+		}
+	}))
+	defer server.Close()
+	client := NewClient(&core.ClientConfig{AppId: testAppId, BaseUrl: server.URL, Logger: &testLogger{}},
+		lru.NewLRUCache(0), WithTimeout(50*time.Millisecond))
+
+	start := time.Now()
+	err := client.Release(testAuthorizerToken, nil)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("got %v, want context.DeadlineExceeded", err)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Fatalf("request took %v, want about 50ms", elapsed)
+	}
+}
+
+// failingSetExCache SetEx总是失败, 其余操作使用内存缓存
+type failingSetExCache struct {
+	core.Cache
+}
+
+func (self *failingSetExCache) SetEx(key string, val interface{}, expires int64) error {
+	return errors.New("redis: connection refused")
+}
+
+func TestTokenReturnedWhenCacheWriteFails(t *testing.T) {
+	server := newTestServer(t)
+	server.respond("/cgi-bin/component/api_component_token", `{"component_access_token":"NEW_TOKEN","expires_in":7200}`)
+	logger := &testLogger{}
+	var reported []error
+	client := NewClient(&core.ClientConfig{
+		AppId:         testAppId,
+		BaseUrl:       server.URL,
+		Logger:        logger,
+		ErrorReporter: func(err error) { reported = append(reported, err) },
+	}, &failingSetExCache{Cache: lru.NewLRUCache(0)})
+
+	token, err := client.ApiComponentToken()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token != "NEW_TOKEN" {
+		t.Fatalf("got %s, want NEW_TOKEN", token)
+	}
+	if len(reported) != 1 || !strings.Contains(reported[0].Error(), ComponentTokenCacheKeyPrefix+testAppId) {
+		t.Fatalf("reported %v, want one component token write error", reported)
+	}
+	if logged := logger.String(); !strings.Contains(logged, "connection refused") {
+		t.Fatalf("cache error not logged, got: %q", logged)
+	}
+}
+
+func TestRefreshComponentTokenBypassesCache(t *testing.T) {
+	server := newTestServer(t)
+	server.respond("/cgi-bin/component/api_component_token", `{"component_access_token":"NEW_TOKEN","expires_in":7200}`)
+	client, _ := newTestClient(t, server)
+
+	token, err := client.RefreshComponentToken()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token != "NEW_TOKEN" {
+		t.Fatalf("got %s, want NEW_TOKEN", token)
+	}
+	if n := server.requestCount(); n != 1 {
+		t.Fatalf("got %d requests, want 1 despite a valid cached token", n)
+	}
+	if token, _ := client.ApiComponentToken(); token != "NEW_TOKEN" {
+		t.Fatalf("cache not overwritten, got %s", token)
+	}
+}
+
+func TestRefreshComponentTokenSingleflight(t *testing.T) {
+	server := newTestServer(t)
+	server.respond("/cgi-bin/component/api_component_token", `{"component_access_token":"NEW_TOKEN","expires_in":7200}`)
+	client, _ := newTestClient(t, server)
+
+	// 普通刷新进行中时强制刷新不等待其结果
+	started, release := make(chan struct{}), make(chan struct{})
+	go func() {
+		_, _, _ = client.flight.Do(ComponentTokenCacheKeyPrefix+testAppId, func() (interface{}, error) {
+			close(started)
+			<-release
+			return map[string]interface{}{"component_access_token": "STALE_TOKEN"}, nil
+		})
+	}()
+	<-started
+	token, err := client.RefreshComponentToken()
+	close(release)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token != "NEW_TOKEN" {
+		t.Fatalf("got %s, want NEW_TOKEN from its own request", token)
+	}
+
+	// 并发的强制刷新共享同一次请求
+	forcing, releaseForced := make(chan struct{}), make(chan struct{})
+	go func() {
+		_, _, _ = client.flight.Do("force@@"+ComponentTokenCacheKeyPrefix+testAppId, func() (interface{}, error) {
+			close(forcing)
+			<-releaseForced
+			return map[string]interface{}{"component_access_token": "SHARED_TOKEN"}, nil
+		})
+	}()
+	<-forcing
+	done := make(chan string)
+	go func() {
+		token, _ := client.RefreshComponentToken()
+		done <- token
+	}()
+	time.Sleep(50 * time.Millisecond)
+	close(releaseForced)
+	if token := <-done; token != "SHARED_TOKEN" {
+		t.Fatalf("got %s, want the in-flight forced refresh result", token)
+	}
+	if n := server.requestCount(); n != 1 {
+		t.Fatalf("got %d requests, want 1", n)
+	}
+}
@@ -0,0 +1,171 @@
+package open
+
+import "errors"
+
+// commentPageMax 获取评论列表单页最大条数
+const commentPageMax = 50
+
+// 评论类型
+const (
+	CommentTypeAll      = 0 // 普通评论和精选评论
+	CommentTypeNormal   = 1 // 普通评论
+	CommentTypeSelected = 2 // 精选评论
+)
+
+// CommentReply 作者回复
+type CommentReply struct {
+	Content    string `json:"content"`
+	CreateTime int64  `json:"create_time"`
+}
+
+// Comment 图文消息评论, CommentType: 0普通评论 1精选评论
+type Comment struct {
+	UserCommentId int64         `json:"user_comment_id"`
+	OpenId        string        `json:"openid"`
+	CreateTime    int64         `json:"create_time"`
+	Content       string        `json:"content"`
+	CommentType   int           `json:"comment_type"`
+	Reply         *CommentReply `json:"reply,omitempty"`
+}
+
+// CommentList 评论列表
+type CommentList struct {
+	Total   int       `json:"total"`
+	Comment []Comment `json:"comment"`
+}
+
+// commentRequest 评论管理接口的公共调用, msgDataId为群发返回的msg_data_id, index为多图文中的第几篇(从0开始)
+func (self *AuthorizerClient) commentRequest(endpoint func(string) string, msgDataId, index int, extra map[string]interface{}, result interface{}) error {
+	if msgDataId <= 0 || index < 0 {
+		return errors.New("msg_data_id或index不正确")
+	}
+	token, err := self.AccessToken()
+	if err != nil {
+		return err
+	}
+	data := map[string]interface{}{
+		"msg_data_id": msgDataId,
+		"index":       index,
+	}
+	for k, v := range extra {
+		data[k] = v
+	}
+	return self.client.postJSON(endpoint(token), data, result)
+}
+
+// OpenComment 打开已群发文章评论
+func (self *AuthorizerClient) OpenComment(msgDataId, index int) error {
+	return self.commentRequest(self.client.Endpoint.OpenComment, msgDataId, index, nil, nil)
+}
+
+// CloseComment 关闭已群发文章评论
+func (self *AuthorizerClient) CloseComment(msgDataId, index int) error {
+	return self.commentRequest(self.client.Endpoint.CloseComment, msgDataId, index, nil, nil)
+}
+
+// ListComments 查看指定文章的评论数据, count最大50, commentType取值见CommentType*
+func (self *AuthorizerClient) ListComments(msgDataId, index, begin, count, commentType int) (*CommentList, error) {
+	if begin < 0 || count <= 0 || count > commentPageMax {
+		return nil, errors.New("count取值范围为1-50")
+	}
+	if commentType < CommentTypeAll || commentType > CommentTypeSelected {
+		return nil, errors.New("type取值范围为0-2")
+	}
+	var list CommentList
+	err := self.commentRequest(self.client.Endpoint.ListComments, msgDataId, index, map[string]interface{}{
+		"begin": begin,
+		"count": count,
+		"type":  commentType,
+	}, &list)
+	if err != nil {
+		return nil, err
+	}
+	return &list, nil
+}
+
+// MarkElectComment 将评论标记精选
+func (self *AuthorizerClient) MarkElectComment(msgDataId, index int, userCommentId int64) error {
+	return self.commentRequest(self.client.Endpoint.MarkElectComment, msgDataId, index, map[string]interface{}{
+		"user_comment_id": userCommentId,
+	}, nil)
+}
+
+// UnmarkElectComment 将评论取消精选
+func (self *AuthorizerClient) UnmarkElectComment(msgDataId, index int, userCommentId int64) error {
+	return self.commentRequest(self.client.Endpoint.UnmarkElectComment, msgDataId, index, map[string]interface{}{
+		"user_comment_id": userCommentId,
+	}, nil)
+}
+
+// DeleteComment 删除评论
+func (self *AuthorizerClient) DeleteComment(msgDataId, index int, userCommentId int64) error {
+	return self.commentRequest(self.client.Endpoint.DeleteComment, msgDataId, index, map[string]interface{}{
+		"user_comment_id": userCommentId,
+	}, nil)
+}
+
+// ReplyComment 回复评论
+func (self *AuthorizerClient) ReplyComment(msgDataId, index int, userCommentId int64, content string) error {
+	if content == "" {
+		return errors.New("回复内容不能为空")
+	}
+	return self.commentRequest(self.client.Endpoint.ReplyComment, msgDataId, index, map[string]interface{}{
+		"user_comment_id": userCommentId,
+		"content":         content,
+	}, nil)
+}
+
+// DeleteCommentReply 删除回复
+func (self *AuthorizerClient) DeleteCommentReply(msgDataId, index int, userCommentId int64) error {
+	return self.commentRequest(self.client.Endpoint.DeleteCommentReply, msgDataId, index, map[string]interface{}{
+		"user_comment_id": userCommentId,
+	}, nil)
+}
+
+// CommentIterator 文章评论迭代器, 自动翻页
+type CommentIterator struct {
+	client      *AuthorizerClient
+	msgDataId   int
+	index       int
+	commentType int
+	begin       int
+	total       int
+	buf         []Comment
+	done        bool
+}
+
+// Comments 创建文章评论迭代器, 单图文消息的index为0
+func (self *AuthorizerClient) Comments(msgDataId, index, commentType int) *CommentIterator {
+	return &CommentIterator{
+		client:      self,
+		msgDataId:   msgDataId,
+		index:       index,
+		commentType: commentType,
+		total:       -1,
+	}
+}
+
+// Next 返回下一条评论, 遍历结束时第二个返回值为false
+func (self *CommentIterator) Next() (Comment, bool, error) {
+	if len(self.buf) == 0 {
+		if self.done || (self.total >= 0 && self.begin >= self.total) {
+			return Comment{}, false, nil
+		}
+		list, err := self.client.ListComments(self.msgDataId, self.index, self.begin, commentPageMax, self.commentType)
+		if err != nil {
+			return Comment{}, false, err
+		}
+		self.total = list.Total
+		self.begin += len(list.Comment)
+		self.buf = list.Comment
+		if len(list.Comment) < commentPageMax {
+			self.done = true
+		}
+		if len(self.buf) == 0 {
+			return Comment{}, false, nil
+		}
+	}
+	comment := self.buf[0]
+	self.buf = self.buf[1:]
+	return comment, true, nil
+}
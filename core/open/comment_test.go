@@ -0,0 +1,82 @@
+package open
+
+import (
+	"fmt"
+	"testing"
+)
+
+// commentListPage 生成第begin条起共count条评论的列表响应
+func commentListPage(total, begin, count int) string {
+	comments := pageItems(begin, count, func(i int) string {
+		return fmt.Sprintf(`{"user_comment_id":%d,"openid":"OPENID","create_time":1653000000,"content":"评论%d","comment_type":0}`, i+1, i)
+	})
+	return fmt.Sprintf(`{"errcode":0,"errmsg":"ok","total":%d,"comment":[%s]}`, total, comments)
+}
+
+func TestCommentIterator(t *testing.T) {
+	server := newTestServer(t)
+	server.respondSequence("/cgi-bin/comment/list",
+		commentListPage(60, 0, commentPageMax),
+		commentListPage(60, commentPageMax, 10),
+	)
+	client, _ := newTestClient(t, server)
+
+	var comments []Comment
+	drain(t, client.Authorizer(testAuthorizerAppId).Comments(2247483647, 1, CommentTypeNormal), &comments)
+	if len(comments) != 60 || comments[0].UserCommentId != 1 || comments[59].UserCommentId != 60 || comments[59].Content != "评论59" {
+		t.Fatalf("got %d comments", len(comments))
+	}
+	bodies := server.requestBodies(t, "/cgi-bin/comment/list")
+	if len(bodies) != 2 {
+		t.Fatalf("got %d requests, want 2", len(bodies))
+	}
+	want := map[string]interface{}{"msg_data_id": float64(2247483647), "index": float64(1), "begin": float64(commentPageMax), "count": float64(commentPageMax), "type": float64(CommentTypeNormal)}
+	for key, value := range want {
+		if bodies[1][key] != value {
+			t.Errorf("%s: got %v, want %v", key, bodies[1][key], value)
+		}
+	}
+}
+
+func TestCommentIteratorElectedWithReply(t *testing.T) {
+	server := newTestServer(t)
+	server.respond("/cgi-bin/comment/list", `{"errcode":0,"errmsg":"ok","total":2,"comment":[`+
+		`{"user_comment_id":1,"openid":"OPENID_1","create_time":1653000000,"content":"写得好","comment_type":1,"reply":{"content":"谢谢支持","create_time":1653000600}},`+
+		`{"user_comment_id":2,"openid":"OPENID_2","create_time":1653000100,"content":"学习了","comment_type":1}]}`)
+	client, _ := newTestClient(t, server)
+
+	var comments []Comment
+	drain(t, client.Authorizer(testAuthorizerAppId).Comments(2247483647, 0, CommentTypeSelected), &comments)
+	if len(comments) != 2 {
+		t.Fatalf("got %d comments, want 2", len(comments))
+	}
+	replied := comments[0]
+	if replied.CommentType != 1 || replied.Reply == nil || replied.Reply.Content != "谢谢支持" || replied.Reply.CreateTime != 1653000600 {
+		t.Fatalf("elected comment with reply: got %+v (reply %+v)", replied, replied.Reply)
+	}
+	if comments[1].CommentType != 1 || comments[1].Reply != nil {
+		t.Fatalf("elected comment without reply: got %+v", comments[1])
+	}
+	if body := decodeBody(t, server.lastRequest(t)); body["type"] != float64(CommentTypeSelected) {
+		t.Fatalf("type: got %v, want %d", body["type"], CommentTypeSelected)
+	}
+}
+
+func TestCommentIteratorValidation(t *testing.T) {
+	server := newTestServer(t)
+	client, _ := newTestClient(t, server)
+	authorizer := client.Authorizer(testAuthorizerAppId)
+
+	for name, iterator := range map[string]*CommentIterator{
+		"msg_data_id": authorizer.Comments(0, 0, CommentTypeAll),
+		"index":       authorizer.Comments(1, -1, CommentTypeAll),
+		"type":        authorizer.Comments(1, 0, 3),
+	} {
+		if _, ok, err := iterator.Next(); ok || err == nil {
+			t.Errorf("%s: got (%v, %v), want error", name, ok, err)
+		}
+	}
+	if n := server.requestCount(); n != 0 {
+		t.Fatalf("invalid requests sent %d requests", n)
+	}
+}
@@ -0,0 +1,89 @@
+package open
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// ExtConfig 第三方自定义配置ext_json
+type ExtConfig struct {
+	ExtEnable                      bool                   `json:"extEnable"`
+	ExtAppId                       string                 `json:"extAppid"`
+	DirectCommit                   bool                   `json:"directCommit,omitempty"`
+	Ext                            map[string]interface{} `json:"ext,omitempty"`
+	ExtPages                       map[string]interface{} `json:"extPages,omitempty"`
+	Pages                          []string               `json:"pages,omitempty"`
+	Window                         map[string]interface{} `json:"window,omitempty"`
+	TabBar                         map[string]interface{} `json:"tabBar,omitempty"`
+	NetworkTimeout                 map[string]interface{} `json:"networkTimeout,omitempty"`
+	NavigateToMiniProgramAppIdList []string               `json:"navigateToMiniProgramAppIdList,omitempty"`
+	RequiredPrivateInfos           []string               `json:"requiredPrivateInfos,omitempty"`
+}
+
+// CommitCodeRequest 上传代码请求, RawExtJSON不为空时直接作为ext_json提交, 忽略ExtJSON
+type CommitCodeRequest struct {
+	TemplateId  int64
+	ExtJSON     ExtConfig
+	RawExtJSON  string
+	UserVersion string
+	UserDesc    string
+}
+
+// extJSON 生成字符串形式的ext_json, extAppid为空时使用授权方appid
+func (self *CommitCodeRequest) extJSON(authorizerAppId string) (string, error) {
+	if self.RawExtJSON != "" {
+		if !json.Valid([]byte(self.RawExtJSON)) {
+			return "", errors.New("ext_json不是有效的JSON")
+		}
+		return self.RawExtJSON, nil
+	}
+	ext := self.ExtJSON
+	if ext.ExtAppId == "" {
+		ext.ExtAppId = authorizerAppId
+	}
+	if ext.ExtAppId != authorizerAppId {
+		return "", fmt.Errorf("extAppid(%s)与授权方appid(%s)不一致", ext.ExtAppId, authorizerAppId)
+	}
+	ext.ExtEnable = true
+	body, err := json.Marshal(ext)
+	if err != nil {
+		return "", err
+	}
+	return string(body), nil
+}
+
+// CommitCode 使用授权方令牌上传小程序代码
+func (self *AuthorizerClient) CommitCode(req CommitCodeRequest) error {
+	_, err := self.CommitCodeWithResponse(req)
+	return err
+}
+
+// CommitCodeWithResponse 使用授权方令牌上传小程序代码, 返回微信的完整响应
+func (self *AuthorizerClient) CommitCodeWithResponse(req CommitCodeRequest) (*CodeResponse, error) {
+	if req.TemplateId <= 0 {
+		return nil, errors.New("template_id不能为空")
+	}
+	if req.UserVersion == "" || req.UserDesc == "" {
+		return nil, errors.New("user_version和user_desc不能为空")
+	}
+	extJSON, err := req.extJSON(self.AuthorizerAppId)
+	if err != nil {
+		return nil, err
+	}
+	data := map[string]interface{}{
+		"template_id":  req.TemplateId,
+		"ext_json":     extJSON,
+		"user_version": req.UserVersion,
+		"user_desc":    req.UserDesc,
+	}
+	// 试运行时不获取令牌, 避免触发刷新请求
+	if ok, err := self.client.dryRun(self.client.Endpoint.CommitCode(dryRunAccessToken), data); ok {
+		return &CodeResponse{ErrMsg: "ok"}, err
+	}
+	token, err := self.AccessToken()
+	if err != nil {
+		return nil, err
+	}
+	return self.client.CommitCodeWithResponse(token, data)
+}
@@ -0,0 +1,116 @@
+package open
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCommitCodeDryRunMakesNoRequest(t *testing.T) {
+	server := newTestServer(t)
+	client, logger := newTestClient(t, server)
+	client.DryRun = true
+	// 令牌已过期且需要刷新, 试运行时也不应发起刷新请求
+	_ = client.Cache.Delete(AuthorizerTokenCacheKeyPrefix + testAuthorizerAppId)
+	client.saveRefreshToken(testAuthorizerAppId, "REFRESH_TOKEN")
+
+	resp, err := client.Authorizer(testAuthorizerAppId).CommitCodeWithResponse(CommitCodeRequest{
+		TemplateId:  1,
+		UserVersion: "1.0.0",
+		UserDesc:    "test",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.ErrMsg != "ok" {
+		t.Fatalf("ErrMsg: got %q, want ok", resp.ErrMsg)
+	}
+	if n := server.requestCount(); n != 0 {
+		t.Fatalf("dry run sent %d requests", n)
+	}
+	logged := logger.String()
+	if !strings.Contains(logged, "dry run: POST") || !strings.Contains(logged, `"template_id":1`) {
+		t.Fatalf("dry run log missing request, got:\n%s", logged)
+	}
+}
+
+func TestCommitCodeRequestBody(t *testing.T) {
+	server := newTestServer(t)
+	client, _ := newTestClient(t, server)
+
+	err := client.Authorizer(testAuthorizerAppId).CommitCode(CommitCodeRequest{
+		TemplateId: 3,
+		ExtJSON: ExtConfig{
+			DirectCommit:         true,
+			Ext:                  map[string]interface{}{"name": "demo"},
+			ExtPages:             map[string]interface{}{"pages/index/index": map[string]interface{}{"navigationBarTitleText": "首页"}},
+			Window:               map[string]interface{}{"navigationBarBackgroundColor": "#ffffff"},
+			RequiredPrivateInfos: []string{"getLocation"},
+		},
+		UserVersion: "1.0.1",
+		UserDesc:    "desc",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	req := server.lastRequest(t)
+	if req.Path != "/wxa/commit" {
+		t.Fatalf("path: got %s, want /wxa/commit", req.Path)
+	}
+	if got := req.Query["access_token"]; len(got) != 1 || got[0] != testAuthorizerToken {
+		t.Fatalf("access_token: got %v", got)
+	}
+	want := `{"ext_json":"{\"extEnable\":true,\"extAppid\":\"wx_authorizer\",\"directCommit\":true,\"ext\":{\"name\":\"demo\"},\"extPages\":{\"pages/index/index\":{\"navigationBarTitleText\":\"首页\"}},\"window\":{\"navigationBarBackgroundColor\":\"#ffffff\"},\"requiredPrivateInfos\":[\"getLocation\"]}","template_id":3,"user_desc":"desc","user_version":"1.0.1"}`
+	if string(req.Body) != want {
+		t.Fatalf("body:\n got %s\nwant %s", req.Body, want)
+	}
+}
+
+func TestCommitCodeRawExtJSON(t *testing.T) {
+	server := newTestServer(t)
+	client, _ := newTestClient(t, server)
+	authorizer := client.Authorizer(testAuthorizerAppId)
+	raw := `{"extEnable":true,"extAppid":"wx_authorizer","ext":{"custom":1}}`
+
+	err := authorizer.CommitCode(CommitCodeRequest{
+		TemplateId:  3,
+		ExtJSON:     ExtConfig{ExtAppId: "wx_other"},
+		RawExtJSON:  raw,
+		UserVersion: "1.0.1",
+		UserDesc:    "desc",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if body := decodeBody(t, server.lastRequest(t)); body["ext_json"] != raw {
+		t.Fatalf("ext_json: got %v, want %s", body["ext_json"], raw)
+	}
+	err = authorizer.CommitCode(CommitCodeRequest{
+		TemplateId:  3,
+		RawExtJSON:  `{"extEnable":`,
+		UserVersion: "1.0.1",
+		UserDesc:    "desc",
+	})
+	if err == nil {
+		t.Fatal("expected error for invalid raw ext_json")
+	}
+	if n := server.requestCount(); n != 1 {
+		t.Fatalf("got %d requests, want 1", n)
+	}
+}
+
+func TestCommitCodeRejectsMismatchedExtAppId(t *testing.T) {
+	server := newTestServer(t)
+	client, _ := newTestClient(t, server)
+	err := client.Authorizer(testAuthorizerAppId).CommitCode(CommitCodeRequest{
+		TemplateId:  3,
+		ExtJSON:     ExtConfig{ExtAppId: "wx_other"},
+		UserVersion: "1.0.1",
+		UserDesc:    "desc",
+	})
+	if err == nil {
+		t.Fatal("expected error for mismatched extAppid")
+	}
+	if n := server.requestCount(); n != 0 {
+		t.Fatalf("invalid request sent %d requests", n)
+	}
+}
@@ -0,0 +1,260 @@
+package open
+
+import (
+	"errors"
+	"time"
+)
+
+const datacubeDateLayout = "20060102"
+
+// VisitTrend 访问趋势
+type VisitTrend struct {
+	RefDate         string  `json:"ref_date"`
+	SessionCnt      int64   `json:"session_cnt"`
+	VisitPv         int64   `json:"visit_pv"`
+	VisitUv         int64   `json:"visit_uv"`
+	VisitUvNew      int64   `json:"visit_uv_new"`
+	StayTimeUv      float64 `json:"stay_time_uv"`
+	StayTimeSession float64 `json:"stay_time_session"`
+	VisitDepth      float64 `json:"visit_depth"`
+}
+
+// truncateDay 取date所在日期的零点
+func truncateDay(date time.Time) time.Time {
+	return time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location())
+}
+
+// dailyRange 日维度查询范围, 开始和结束为同一天且只能查询今天之前的数据
+func dailyRange(date time.Time) (string, string, error) {
+	day := truncateDay(date)
+	if !day.Before(truncateDay(time.Now().In(date.Location()))) {
+		return "", "", errors.New("只能查询今天之前的数据")
+	}
+	return day.Format(datacubeDateLayout), day.Format(datacubeDateLayout), nil
+}
+
+// weeklyRange 周维度查询范围, 开始为周一, 结束为周日且该周已结束
+func weeklyRange(weekStart time.Time) (string, string, error) {
+	begin := truncateDay(weekStart)
+	if begin.Weekday() != time.Monday {
+		return "", "", errors.New("周维度开始日期必须为周一")
+	}
+	end := begin.AddDate(0, 0, 6)
+	if !end.Before(truncateDay(time.Now().In(weekStart.Location()))) {
+		return "", "", errors.New("只能查询已结束的自然周")
+	}
+	return begin.Format(datacubeDateLayout), end.Format(datacubeDateLayout), nil
+}
+
+// monthlyRange 月维度查询范围, 开始为月初, 结束为月末且该月已结束
+func monthlyRange(month time.Time) (string, string, error) {
+	begin := time.Date(month.Year(), month.Month(), 1, 0, 0, 0, 0, month.Location())
+	if !truncateDay(month).Equal(begin) {
+		return "", "", errors.New("月维度开始日期必须为当月1日")
+	}
+	end := begin.AddDate(0, 1, -1)
+	if !end.Before(truncateDay(time.Now().In(month.Location()))) {
+		return "", "", errors.New("只能查询已结束的自然月")
+	}
+	return begin.Format(datacubeDateLayout), end.Format(datacubeDateLayout), nil
+}
+
+// datacube 按日期范围查询数据分析接口
+func (self *AuthorizerClient) datacube(endpoint func(string) string, beginDate, endDate string, result interface{}) error {
+	token, err := self.AccessToken()
+	if err != nil {
+		return err
+	}
+	return self.client.postJSON(endpoint(token), map[string]interface{}{
+		"begin_date": beginDate,
+		"end_date":   endDate,
+	}, result)
+}
+
+// getVisitTrend 查询访问趋势
+func (self *AuthorizerClient) getVisitTrend(endpoint func(string) string, beginDate, endDate string) ([]VisitTrend, error) {
+	var resp struct {
+		List []VisitTrend `json:"list"`
+	}
+	if err := self.datacube(endpoint, beginDate, endDate, &resp); err != nil {
+		return nil, err
+	}
+	return resp.List, nil
+}
+
+// GetDailyVisitTrend 获取用户访问小程序数据日趋势
+func (self *AuthorizerClient) GetDailyVisitTrend(date time.Time) ([]VisitTrend, error) {
+	beginDate, endDate, err := dailyRange(date)
+	if err != nil {
+		return nil, err
+	}
+	return self.getVisitTrend(self.client.Endpoint.DailyVisitTrend, beginDate, endDate)
+}
+
+// GetWeeklyVisitTrend 获取用户访问小程序数据周趋势, weekStart为周一
+func (self *AuthorizerClient) GetWeeklyVisitTrend(weekStart time.Time) ([]VisitTrend, error) {
+	beginDate, endDate, err := weeklyRange(weekStart)
+	if err != nil {
+		return nil, err
+	}
+	return self.getVisitTrend(self.client.Endpoint.WeeklyVisitTrend, beginDate, endDate)
+}
+
+// GetMonthlyVisitTrend 获取用户访问小程序数据月趋势, month为当月1日
+func (self *AuthorizerClient) GetMonthlyVisitTrend(month time.Time) ([]VisitTrend, error) {
+	beginDate, endDate, err := monthlyRange(month)
+	if err != nil {
+		return nil, err
+	}
+	return self.getVisitTrend(self.client.Endpoint.MonthlyVisitTrend, beginDate, endDate)
+}
+
+// DatacubeItem 数据分析键值项
+type DatacubeItem struct {
+	Key   int   `json:"key"`
+	Value int64 `json:"value"`
+}
+
+// RetainInfo 访问留存, Key为距ref_date的天/周/月数
+type RetainInfo struct {
+	RefDate    string         `json:"ref_date"`
+	VisitUvNew []DatacubeItem `json:"visit_uv_new"`
+	VisitUv    []DatacubeItem `json:"visit_uv"`
+}
+
+// VisitDistributionIndex 访问分布的单项指标
+type VisitDistributionIndex struct {
+	Index    string         `json:"index"`
+	ItemList []DatacubeItem `json:"item_list"`
+}
+
+// VisitDistribution 访问分布
+type VisitDistribution struct {
+	RefDate string                   `json:"ref_date"`
+	List    []VisitDistributionIndex `json:"list"`
+}
+
+// VisitPageItem 页面访问数据
+type VisitPageItem struct {
+	PagePath       string  `json:"page_path"`
+	PageVisitPv    int64   `json:"page_visit_pv"`
+	PageVisitUv    int64   `json:"page_visit_uv"`
+	PageStaytimePv float64 `json:"page_staytime_pv"`
+	EntrypagePv    int64   `json:"entrypage_pv"`
+	ExitpagePv     int64   `json:"exitpage_pv"`
+	PageSharePv    int64   `json:"page_share_pv"`
+	PageShareUv    int64   `json:"page_share_uv"`
+}
+
+// VisitPage 访问页面
+type VisitPage struct {
+	RefDate string          `json:"ref_date"`
+	List    []VisitPageItem `json:"list"`
+}
+
+// PortraitItem 用户画像分布项
+type PortraitItem struct {
+	Id    int    `json:"id"`
+	Name  string `json:"name"`
+	Value int64  `json:"value"`
+}
+
+// PortraitDistribution 用户画像分布
+type PortraitDistribution struct {
+	Index     int            `json:"index"`
+	Province  []PortraitItem `json:"province"`
+	City      []PortraitItem `json:"city"`
+	Genders   []PortraitItem `json:"genders"`
+	Platforms []PortraitItem `json:"platforms"`
+	Devices   []PortraitItem `json:"devices"`
+	Ages      []PortraitItem `json:"ages"`
+}
+
+// UserPortrait 用户画像
+type UserPortrait struct {
+	RefDate    string               `json:"ref_date"`
+	VisitUvNew PortraitDistribution `json:"visit_uv_new"`
+	VisitUv    PortraitDistribution `json:"visit_uv"`
+}
+
+// GetDailyRetain 获取用户访问小程序日留存
+func (self *AuthorizerClient) GetDailyRetain(date time.Time) (*RetainInfo, error) {
+	beginDate, endDate, err := dailyRange(date)
+	if err != nil {
+		return nil, err
+	}
+	var resp RetainInfo
+	if err := self.datacube(self.client.Endpoint.DailyRetainInfo, beginDate, endDate, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// GetWeeklyRetain 获取用户访问小程序周留存, weekStart为周一
+func (self *AuthorizerClient) GetWeeklyRetain(weekStart time.Time) (*RetainInfo, error) {
+	beginDate, endDate, err := weeklyRange(weekStart)
+	if err != nil {
+		return nil, err
+	}
+	var resp RetainInfo
+	if err := self.datacube(self.client.Endpoint.WeeklyRetainInfo, beginDate, endDate, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// GetMonthlyRetain 获取用户访问小程序月留存, month为当月1日
+func (self *AuthorizerClient) GetMonthlyRetain(month time.Time) (*RetainInfo, error) {
+	beginDate, endDate, err := monthlyRange(month)
+	if err != nil {
+		return nil, err
+	}
+	var resp RetainInfo
+	if err := self.datacube(self.client.Endpoint.MonthlyRetainInfo, beginDate, endDate, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// GetVisitDistribution 获取用户小程序访问分布数据
+func (self *AuthorizerClient) GetVisitDistribution(date time.Time) (*VisitDistribution, error) {
+	beginDate, endDate, err := dailyRange(date)
+	if err != nil {
+		return nil, err
+	}
+	var resp VisitDistribution
+	if err := self.datacube(self.client.Endpoint.VisitDistribution, beginDate, endDate, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// GetVisitPage 获取访问页面数据
+func (self *AuthorizerClient) GetVisitPage(date time.Time) (*VisitPage, error) {
+	beginDate, endDate, err := dailyRange(date)
+	if err != nil {
+		return nil, err
+	}
+	var resp VisitPage
+	if err := self.datacube(self.client.Endpoint.VisitPage, beginDate, endDate, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// GetUserPortrait 获取小程序用户画像, 统计截至endDate的最近days天(1/7/30)
+func (self *AuthorizerClient) GetUserPortrait(endDate time.Time, days int) (*UserPortrait, error) {
+	if days != 1 && days != 7 && days != 30 {
+		return nil, errors.New("用户画像只支持最近1天、7天或30天")
+	}
+	_, end, err := dailyRange(endDate)
+	if err != nil {
+		return nil, err
+	}
+	begin := truncateDay(endDate).AddDate(0, 0, 1-days).Format(datacubeDateLayout)
+	var resp UserPortrait
+	if err := self.datacube(self.client.Endpoint.UserPortrait, begin, end, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
@@ -0,0 +1,221 @@
+package open
+
+import (
+	"testing"
+	"time"
+)
+
+// datacubeDate 返回本地时区的日期
+func datacubeDate(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 15, 4, 5, 0, time.Local)
+}
+
+// assertDateRange 校验最后一次请求的begin_date和end_date
+func assertDateRange(t *testing.T, server *testServer, path, beginDate, endDate string) {
+	t.Helper()
+	req := server.lastRequest(t)
+	if req.Path != path {
+		t.Fatalf("path: got %s, want %s", req.Path, path)
+	}
+	if got := req.Query["access_token"]; len(got) != 1 || got[0] != testAuthorizerToken {
+		t.Fatalf("access_token: got %v", got)
+	}
+	want := `{"begin_date":"` + beginDate + `","end_date":"` + endDate + `"}`
+	if string(req.Body) != want {
+		t.Fatalf("body:\n got %s\nwant %s", req.Body, want)
+	}
+}
+
+func TestGetDailyVisitTrend(t *testing.T) {
+	server := newTestServer(t)
+	server.respond("/datacube/getweanalysisappiddailyvisittrend", `{"list":[{"ref_date":"20240110","session_cnt":142549,"visit_pv":472351,"visit_uv":55500,"visit_uv_new":5464,"stay_time_session":0,"visit_depth":1.9838}]}`)
+	client, _ := newTestClient(t, server)
+
+	list, err := client.Authorizer(testAuthorizerAppId).GetDailyVisitTrend(datacubeDate(2024, time.January, 10))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertDateRange(t, server, "/datacube/getweanalysisappiddailyvisittrend", "20240110", "20240110")
+	want := VisitTrend{RefDate: "20240110", SessionCnt: 142549, VisitPv: 472351, VisitUv: 55500, VisitUvNew: 5464, VisitDepth: 1.9838}
+	if len(list) != 1 || list[0] != want {
+		t.Fatalf("list: got %+v", list)
+	}
+}
+
+func TestGetWeeklyVisitTrend(t *testing.T) {
+	server := newTestServer(t)
+	server.respond("/datacube/getweanalysisappidweeklyvisittrend", `{"list":[{"ref_date":"20240108-20240114","session_cnt":986780,"visit_pv":3251840,"visit_uv":189405,"visit_uv_new":45592,"stay_time_session":54.5346,"visit_depth":1.9735}]}`)
+	client, _ := newTestClient(t, server)
+
+	list, err := client.Authorizer(testAuthorizerAppId).GetWeeklyVisitTrend(datacubeDate(2024, time.January, 8))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertDateRange(t, server, "/datacube/getweanalysisappidweeklyvisittrend", "20240108", "20240114")
+	if len(list) != 1 || list[0].SessionCnt != 986780 || list[0].StayTimeSession != 54.5346 {
+		t.Fatalf("list: got %+v", list)
+	}
+}
+
+func TestGetMonthlyVisitTrend(t *testing.T) {
+	server := newTestServer(t)
+	server.respond("/datacube/getweanalysisappidmonthlyvisittrend", `{"list":[{"ref_date":"202402","session_cnt":126513,"visit_pv":426113,"visit_uv":48659,"visit_uv_new":6726,"stay_time_session":56.4112,"visit_depth":2.0189}]}`)
+	client, _ := newTestClient(t, server)
+
+	list, err := client.Authorizer(testAuthorizerAppId).GetMonthlyVisitTrend(time.Date(2024, time.February, 1, 0, 0, 0, 0, time.Local))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertDateRange(t, server, "/datacube/getweanalysisappidmonthlyvisittrend", "20240201", "20240229")
+	if len(list) != 1 || list[0].RefDate != "202402" || list[0].VisitUv != 48659 {
+		t.Fatalf("list: got %+v", list)
+	}
+}
+
+func TestVisitTrendRangeValidation(t *testing.T) {
+	server := newTestServer(t)
+	client, _ := newTestClient(t, server)
+	authorizer := client.Authorizer(testAuthorizerAppId)
+	now := time.Now()
+
+	if _, err := authorizer.GetDailyVisitTrend(now); err == nil {
+		t.Error("today: expected validation error")
+	}
+	if _, err := authorizer.GetWeeklyVisitTrend(datacubeDate(2024, time.January, 10)); err == nil {
+		t.Error("week starting on Wednesday: expected validation error")
+	}
+	thisMonday := truncateDay(now).AddDate(0, 0, -((int(now.Weekday()) + 6) % 7))
+	if _, err := authorizer.GetWeeklyVisitTrend(thisMonday); err == nil {
+		t.Error("current week: expected validation error")
+	}
+	if _, err := authorizer.GetMonthlyVisitTrend(datacubeDate(2024, time.February, 2)); err == nil {
+		t.Error("month not starting on the 1st: expected validation error")
+	}
+	if _, err := authorizer.GetMonthlyVisitTrend(time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())); err == nil {
+		t.Error("current month: expected validation error")
+	}
+	if n := server.requestCount(); n != 0 {
+		t.Fatalf("invalid ranges sent %d requests", n)
+	}
+}
+
+func TestGetRetain(t *testing.T) {
+	server := newTestServer(t)
+	fixture := `{"ref_date":"20240110","visit_uv_new":[{"key":0,"value":5464}],"visit_uv":[{"key":0,"value":55500},{"key":1,"value":9080}]}`
+	server.respond("/datacube/getweanalysisappiddailyretaininfo", fixture)
+	server.respond("/datacube/getweanalysisappidweeklyretaininfo", fixture)
+	server.respond("/datacube/getweanalysisappidmonthlyretaininfo", fixture)
+	client, _ := newTestClient(t, server)
+	authorizer := client.Authorizer(testAuthorizerAppId)
+
+	for _, tc := range []struct {
+		call               func() (*RetainInfo, error)
+		path               string
+		beginDate, endDate string
+	}{
+		{func() (*RetainInfo, error) { return authorizer.GetDailyRetain(datacubeDate(2024, time.January, 10)) },
+			"/datacube/getweanalysisappiddailyretaininfo", "20240110", "20240110"},
+		{func() (*RetainInfo, error) { return authorizer.GetWeeklyRetain(datacubeDate(2024, time.January, 8)) },
+			"/datacube/getweanalysisappidweeklyretaininfo", "20240108", "20240114"},
+		{func() (*RetainInfo, error) { return authorizer.GetMonthlyRetain(datacubeDate(2024, time.January, 1)) },
+			"/datacube/getweanalysisappidmonthlyretaininfo", "20240101", "20240131"},
+	} {
+		info, err := tc.call()
+		if err != nil {
+			t.Fatalf("%s: %v", tc.path, err)
+		}
+		assertDateRange(t, server, tc.path, tc.beginDate, tc.endDate)
+		if info.RefDate != "20240110" || len(info.VisitUvNew) != 1 || info.VisitUvNew[0] != (DatacubeItem{Key: 0, Value: 5464}) ||
+			len(info.VisitUv) != 2 || info.VisitUv[1] != (DatacubeItem{Key: 1, Value: 9080}) {
+			t.Fatalf("%s: got %+v", tc.path, info)
+		}
+	}
+}
+
+func TestGetVisitDistribution(t *testing.T) {
+	server := newTestServer(t)
+	server.respond("/datacube/getweanalysisappidvisitdistribution", `{"ref_date":"20240110","list":[{"index":"access_source_session_cnt","item_list":[{"key":10,"value":5},{"key":8,"value":687}]},{"index":"access_staytime_info","item_list":[{"key":1,"value":3}]}]}`)
+	client, _ := newTestClient(t, server)
+
+	distribution, err := client.Authorizer(testAuthorizerAppId).GetVisitDistribution(datacubeDate(2024, time.January, 10))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertDateRange(t, server, "/datacube/getweanalysisappidvisitdistribution", "20240110", "20240110")
+	if distribution.RefDate != "20240110" || len(distribution.List) != 2 {
+		t.Fatalf("distribution: got %+v", distribution)
+	}
+	source := distribution.List[0]
+	if source.Index != "access_source_session_cnt" || len(source.ItemList) != 2 || source.ItemList[1] != (DatacubeItem{Key: 8, Value: 687}) {
+		t.Fatalf("access source: got %+v", source)
+	}
+}
+
+func TestGetVisitPage(t *testing.T) {
+	server := newTestServer(t)
+	server.respond("/datacube/getweanalysisappidvisitpage", `{"ref_date":"20240110","list":[{"page_path":"pages/main/main.html","page_visit_pv":213429,"page_visit_uv":55423,"page_staytime_pv":8.139198,"entrypage_pv":117922,"exitpage_pv":61304,"page_share_pv":180,"page_share_uv":166}]}`)
+	client, _ := newTestClient(t, server)
+
+	page, err := client.Authorizer(testAuthorizerAppId).GetVisitPage(datacubeDate(2024, time.January, 10))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertDateRange(t, server, "/datacube/getweanalysisappidvisitpage", "20240110", "20240110")
+	want := VisitPageItem{
+		PagePath:       "pages/main/main.html",
+		PageVisitPv:    213429,
+		PageVisitUv:    55423,
+		PageStaytimePv: 8.139198,
+		EntrypagePv:    117922,
+		ExitpagePv:     61304,
+		PageSharePv:    180,
+		PageShareUv:    166,
+	}
+	if page.RefDate != "20240110" || len(page.List) != 1 || page.List[0] != want {
+		t.Fatalf("page: got %+v", page)
+	}
+}
+
+func TestGetUserPortrait(t *testing.T) {
+	server := newTestServer(t)
+	server.respond("/datacube/getweanalysisappiduserportrait", `{"ref_date":"20240104-20240110","visit_uv_new":{"index":0,"province":[{"id":31,"name":"广东省","value":215}],"city":[{"id":3102,"name":"广州","value":78}],"genders":[{"id":1,"name":"男","value":2146}],"platforms":[{"id":1,"name":"iPhone","value":27642}],"devices":[{"name":"OPPO R9","value":61}],"ages":[{"id":1,"name":"17岁以下","value":151}]},"visit_uv":{"index":0,"province":[{"id":31,"name":"广东省","value":1341}],"city":[],"genders":[],"platforms":[],"devices":[],"ages":[]}}`)
+	client, _ := newTestClient(t, server)
+
+	portrait, err := client.Authorizer(testAuthorizerAppId).GetUserPortrait(datacubeDate(2024, time.January, 10), 7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertDateRange(t, server, "/datacube/getweanalysisappiduserportrait", "20240104", "20240110")
+	newUsers := portrait.VisitUvNew
+	if portrait.RefDate != "20240104-20240110" ||
+		len(newUsers.Province) != 1 || newUsers.Province[0] != (PortraitItem{Id: 31, Name: "广东省", Value: 215}) ||
+		len(newUsers.City) != 1 || newUsers.City[0].Name != "广州" ||
+		len(newUsers.Genders) != 1 || newUsers.Genders[0].Value != 2146 ||
+		len(newUsers.Platforms) != 1 || newUsers.Platforms[0].Name != "iPhone" ||
+		len(newUsers.Devices) != 1 || newUsers.Devices[0] != (PortraitItem{Name: "OPPO R9", Value: 61}) ||
+		len(newUsers.Ages) != 1 || newUsers.Ages[0].Name != "17岁以下" {
+		t.Fatalf("visit_uv_new: got %+v", newUsers)
+	}
+	if len(portrait.VisitUv.Province) != 1 || portrait.VisitUv.Province[0].Value != 1341 {
+		t.Fatalf("visit_uv: got %+v", portrait.VisitUv)
+	}
+}
+
+func TestGetUserPortraitValidation(t *testing.T) {
+	server := newTestServer(t)
+	client, _ := newTestClient(t, server)
+	authorizer := client.Authorizer(testAuthorizerAppId)
+
+	if _, err := authorizer.GetUserPortrait(datacubeDate(2024, time.January, 10), 3); err == nil {
+		t.Error("3 days: expected validation error")
+	}
+	if _, err := authorizer.GetUserPortrait(time.Now(), 1); err == nil {
+		t.Error("today: expected validation error")
+	}
+	if _, err := authorizer.GetWeeklyRetain(datacubeDate(2024, time.January, 9)); err == nil {
+		t.Error("retain week starting on Tuesday: expected validation error")
+	}
+	if n := server.requestCount(); n != 0 {
+		t.Fatalf("invalid ranges sent %d requests", n)
+	}
+}
@@ -0,0 +1,100 @@
+package open
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+)
+
+// 服务器域名操作
+const (
+	DomainActionAdd    = "add"
+	DomainActionDelete = "delete"
+	DomainActionSet    = "set"
+)
+
+// ServerDomains 小程序服务器域名, Invalid*为微信无法解析的域名
+type ServerDomains struct {
+	RequestDomain          []string `json:"requestdomain"`
+	WsRequestDomain        []string `json:"wsrequestdomain"`
+	UploadDomain           []string `json:"uploaddomain"`
+	DownloadDomain         []string `json:"downloaddomain"`
+	UdpDomain              []string `json:"udpdomain"`
+	TcpDomain              []string `json:"tcpdomain"`
+	InvalidRequestDomain   []string `json:"invalid_requestdomain"`
+	InvalidWsRequestDomain []string `json:"invalid_wsrequestdomain"`
+	InvalidUploadDomain    []string `json:"invalid_uploaddomain"`
+	InvalidDownloadDomain  []string `json:"invalid_downloaddomain"`
+	InvalidUdpDomain       []string `json:"invalid_udpdomain"`
+	InvalidTcpDomain       []string `json:"invalid_tcpdomain"`
+}
+
+// HasInvalid 是否存在无法解析的域名
+func (self *ServerDomains) HasInvalid() bool {
+	return len(self.InvalidRequestDomain)+len(self.InvalidWsRequestDomain)+len(self.InvalidUploadDomain)+
+		len(self.InvalidDownloadDomain)+len(self.InvalidUdpDomain)+len(self.InvalidTcpDomain) > 0
+}
+
+// GetServerDomains 获取小程序当前配置的服务器域名
+func (self *AuthorizerClient) GetServerDomains() (*ServerDomains, error) {
+	token, err := self.AccessToken()
+	if err != nil {
+		return nil, err
+	}
+	var domains ServerDomains
+	err = self.client.postJSON(self.client.Endpoint.ModifyDomain(token), map[string]interface{}{
+		"action": "get",
+	}, &domains)
+	if err != nil {
+		return nil, err
+	}
+	return &domains, nil
+}
+
+// checkDomains 校验域名为指定协议的主机名, 不能包含路径、查询参数或结尾的/
+func checkDomains(field, scheme string, domains []string) error {
+	for _, domain := range domains {
+		u, err := url.Parse(domain)
+		if err != nil || u.Scheme != scheme || u.Host == "" || u.Path != "" || u.RawQuery != "" || u.Fragment != "" || u.User != nil {
+			return fmt.Errorf("%s中的域名%q格式错误, 应为%s://域名", field, domain, scheme)
+		}
+	}
+	return nil
+}
+
+// SetServerDomain 修改小程序服务器域名, action取值为add/delete/set, 提交前校验域名格式
+func (self *AuthorizerClient) SetServerDomain(action string, domains ServerDomains) error {
+	if action != DomainActionAdd && action != DomainActionDelete && action != DomainActionSet {
+		return errors.New("action取值为add/delete/set")
+	}
+	checks := []struct {
+		field   string
+		scheme  string
+		domains []string
+	}{
+		{"requestdomain", "https", domains.RequestDomain},
+		{"wsrequestdomain", "wss", domains.WsRequestDomain},
+		{"uploaddomain", "https", domains.UploadDomain},
+		{"downloaddomain", "https", domains.DownloadDomain},
+		{"udpdomain", "udp", domains.UdpDomain},
+		{"tcpdomain", "tcp", domains.TcpDomain},
+	}
+	for _, check := range checks {
+		if err := checkDomains(check.field, check.scheme, check.domains); err != nil {
+			return err
+		}
+	}
+	token, err := self.AccessToken()
+	if err != nil {
+		return err
+	}
+	data := map[string]interface{}{
+		"action": action,
+	}
+	for _, check := range checks {
+		if len(check.domains) > 0 {
+			data[check.field] = check.domains
+		}
+	}
+	return self.client.postJSON(self.client.Endpoint.ModifyDomain(token), data, nil)
+}
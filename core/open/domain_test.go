@@ -0,0 +1,102 @@
+package open
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestGetServerDomains(t *testing.T) {
+	server := newTestServer(t)
+	server.respond("/wxa/modify_domain", `{"errcode":0,"errmsg":"ok","requestdomain":["https://www.qq.com"],"wsrequestdomain":["wss://www.qq.com"],"uploaddomain":["https://www.qq.com"],"downloaddomain":["https://www.qq.com"],"udpdomain":[],"tcpdomain":[],"invalid_requestdomain":["https://invalid.example.com"],"invalid_wsrequestdomain":[],"invalid_uploaddomain":[],"invalid_downloaddomain":["https://dead.example.com"],"invalid_udpdomain":[],"invalid_tcpdomain":[]}`)
+	client, _ := newTestClient(t, server)
+
+	domains, err := client.Authorizer(testAuthorizerAppId).GetServerDomains()
+	if err != nil {
+		t.Fatal(err)
+	}
+	req := server.lastRequest(t)
+	if got := req.Query["access_token"]; len(got) != 1 || got[0] != testAuthorizerToken {
+		t.Fatalf("access_token: got %v", got)
+	}
+	if string(req.Body) != `{"action":"get"}` {
+		t.Fatalf("body: got %s", req.Body)
+	}
+	if !reflect.DeepEqual(domains.RequestDomain, []string{"https://www.qq.com"}) || !reflect.DeepEqual(domains.WsRequestDomain, []string{"wss://www.qq.com"}) ||
+		len(domains.UploadDomain) != 1 || len(domains.DownloadDomain) != 1 {
+		t.Fatalf("domains: got %+v", domains)
+	}
+	if !reflect.DeepEqual(domains.InvalidRequestDomain, []string{"https://invalid.example.com"}) || !reflect.DeepEqual(domains.InvalidDownloadDomain, []string{"https://dead.example.com"}) {
+		t.Fatalf("invalid domains: got %+v", domains)
+	}
+	if !domains.HasInvalid() {
+		t.Fatal("HasInvalid: got false, want true")
+	}
+}
+
+func TestGetServerDomainsAllValid(t *testing.T) {
+	server := newTestServer(t)
+	server.respond("/wxa/modify_domain", `{"errcode":0,"errmsg":"ok","requestdomain":["https://www.qq.com"],"wsrequestdomain":[],"uploaddomain":[],"downloaddomain":[]}`)
+	client, _ := newTestClient(t, server)
+
+	domains, err := client.Authorizer(testAuthorizerAppId).GetServerDomains()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if domains.HasInvalid() {
+		t.Fatalf("HasInvalid: got true for %+v", domains)
+	}
+}
+
+func TestSetServerDomain(t *testing.T) {
+	server := newTestServer(t)
+	client, _ := newTestClient(t, server)
+
+	err := client.Authorizer(testAuthorizerAppId).SetServerDomain(DomainActionAdd, ServerDomains{
+		RequestDomain:   []string{"https://api.example.com", "https://api.example.com:8443"},
+		WsRequestDomain: []string{"wss://ws.example.com"},
+		UdpDomain:       []string{"udp://udp.example.com"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"action":"add","requestdomain":["https://api.example.com","https://api.example.com:8443"],"udpdomain":["udp://udp.example.com"],"wsrequestdomain":["wss://ws.example.com"]}`
+	if body := string(server.lastRequest(t).Body); body != want {
+		t.Fatalf("body:\n got %s\nwant %s", body, want)
+	}
+}
+
+func TestSetServerDomainRejectsMalformed(t *testing.T) {
+	server := newTestServer(t)
+	client, _ := newTestClient(t, server)
+	authorizer := client.Authorizer(testAuthorizerAppId)
+
+	for _, tc := range []struct {
+		name    string
+		domains ServerDomains
+		domain  string
+	}{
+		{"missing scheme", ServerDomains{RequestDomain: []string{"api.example.com"}}, "api.example.com"},
+		{"http scheme", ServerDomains{RequestDomain: []string{"http://api.example.com"}}, "http://api.example.com"},
+		{"trailing slash", ServerDomains{UploadDomain: []string{"https://up.example.com/"}}, "https://up.example.com/"},
+		{"path", ServerDomains{DownloadDomain: []string{"https://dl.example.com/files"}}, "https://dl.example.com/files"},
+		{"query", ServerDomains{RequestDomain: []string{"https://api.example.com?a=1"}}, "https://api.example.com?a=1"},
+		{"https for websocket", ServerDomains{WsRequestDomain: []string{"https://ws.example.com"}}, "https://ws.example.com"},
+		{"userinfo", ServerDomains{RequestDomain: []string{"https://user@api.example.com"}}, "https://user@api.example.com"},
+	} {
+		err := authorizer.SetServerDomain(DomainActionSet, tc.domains)
+		if err == nil {
+			t.Errorf("%s: expected validation error", tc.name)
+			continue
+		}
+		if !strings.Contains(err.Error(), tc.domain) {
+			t.Errorf("%s: error %q does not name %s", tc.name, err, tc.domain)
+		}
+	}
+	if err := authorizer.SetServerDomain("get", ServerDomains{}); err == nil {
+		t.Error("get action: expected validation error")
+	}
+	if n := server.requestCount(); n != 0 {
+		t.Fatalf("invalid domains sent %d requests", n)
+	}
+}
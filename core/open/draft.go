@@ -0,0 +1,197 @@
+package open
+
+import (
+	"errors"
+	"fmt"
+)
+
+// draftPageMax 批量获取草稿单次最大条数
+const draftPageMax = 20
+
+// DraftItem 草稿列表项
+type DraftItem struct {
+	MediaId    string `json:"media_id"`
+	UpdateTime int64  `json:"update_time"`
+	Content    struct {
+		NewsItem []Article `json:"news_item"`
+	} `json:"content"`
+}
+
+// DraftList 草稿列表
+type DraftList struct {
+	TotalCount int         `json:"total_count"`
+	ItemCount  int         `json:"item_count"`
+	Item       []DraftItem `json:"item"`
+}
+
+func checkArticle(i int, article Article) error {
+	if article.Title == "" || article.ThumbMediaId == "" || article.Content == "" {
+		return fmt.Errorf("第%d篇文章缺少title、thumb_media_id或content", i+1)
+	}
+	return nil
+}
+
+// AddDraft 新建草稿, 返回草稿的media_id
+func (self *AuthorizerClient) AddDraft(articles []Article) (string, error) {
+	if len(articles) == 0 {
+		return "", errors.New("草稿至少包含一篇文章")
+	}
+	for i, article := range articles {
+		if err := checkArticle(i, article); err != nil {
+			return "", err
+		}
+	}
+	token, err := self.AccessToken()
+	if err != nil {
+		return "", err
+	}
+	var resp struct {
+		MediaId string `json:"media_id"`
+	}
+	err = self.client.postJSON(self.client.Endpoint.AddDraft(token), map[string]interface{}{
+		"articles": articles,
+	}, &resp)
+	if err != nil {
+		return "", err
+	}
+	return resp.MediaId, nil
+}
+
+// GetDraft 获取草稿中的文章
+func (self *AuthorizerClient) GetDraft(mediaId string) ([]Article, error) {
+	if mediaId == "" {
+		return nil, errors.New("media_id不能为空")
+	}
+	token, err := self.AccessToken()
+	if err != nil {
+		return nil, err
+	}
+	var resp struct {
+		NewsItem []Article `json:"news_item"`
+	}
+	err = self.client.postJSON(self.client.Endpoint.GetDraft(token), map[string]interface{}{
+		"media_id": mediaId,
+	}, &resp)
+	if err != nil {
+		return nil, err
+	}
+	return resp.NewsItem, nil
+}
+
+// DeleteDraft 删除草稿
+func (self *AuthorizerClient) DeleteDraft(mediaId string) error {
+	if mediaId == "" {
+		return errors.New("media_id不能为空")
+	}
+	token, err := self.AccessToken()
+	if err != nil {
+		return err
+	}
+	return self.client.postJSON(self.client.Endpoint.DeleteDraft(token), map[string]interface{}{
+		"media_id": mediaId,
+	}, nil)
+}
+
+// UpdateDraft 修改草稿中的一篇文章, index为文章在草稿中的位置, 第一篇为0
+func (self *AuthorizerClient) UpdateDraft(mediaId string, index int, article Article) error {
+	if mediaId == "" {
+		return errors.New("media_id不能为空")
+	}
+	if index < 0 {
+		return errors.New("index不能小于0")
+	}
+	if err := checkArticle(index, article); err != nil {
+		return err
+	}
+	token, err := self.AccessToken()
+	if err != nil {
+		return err
+	}
+	return self.client.postJSON(self.client.Endpoint.UpdateDraft(token), map[string]interface{}{
+		"media_id": mediaId,
+		"index":    index,
+		"articles": article,
+	}, nil)
+}
+
+// GetDraftCount 获取草稿总数
+func (self *AuthorizerClient) GetDraftCount() (int, error) {
+	token, err := self.AccessToken()
+	if err != nil {
+		return 0, err
+	}
+	var resp struct {
+		TotalCount int `json:"total_count"`
+	}
+	if err := self.client.getJSON(self.client.Endpoint.GetDraftCount(token), &resp); err != nil {
+		return 0, err
+	}
+	return resp.TotalCount, nil
+}
+
+// BatchGetDraft 分页获取草稿列表, count取值范围为1-20, noContent为true时不返回文章content字段
+func (self *AuthorizerClient) BatchGetDraft(offset, count int, noContent bool) (*DraftList, error) {
+	if offset < 0 || count <= 0 || count > draftPageMax {
+		return nil, errors.New("count取值范围为1-20")
+	}
+	token, err := self.AccessToken()
+	if err != nil {
+		return nil, err
+	}
+	data := map[string]interface{}{
+		"offset": offset,
+		"count":  count,
+	}
+	if noContent {
+		data["no_content"] = 1
+	}
+	var list DraftList
+	if err := self.client.postJSON(self.client.Endpoint.BatchGetDraft(token), data, &list); err != nil {
+		return nil, err
+	}
+	return &list, nil
+}
+
+// DraftIterator 草稿迭代器, 自动翻页
+type DraftIterator struct {
+	client    *AuthorizerClient
+	noContent bool
+	offset    int
+	total     int
+	buf       []DraftItem
+	done      bool
+}
+
+// Drafts 创建草稿迭代器
+func (self *AuthorizerClient) Drafts(noContent bool) *DraftIterator {
+	return &DraftIterator{
+		client:    self,
+		noContent: noContent,
+		total:     -1,
+	}
+}
+
+// Next 返回下一个草稿, 遍历结束时第二个返回值为false
+func (self *DraftIterator) Next() (DraftItem, bool, error) {
+	if len(self.buf) == 0 {
+		if self.done || (self.total >= 0 && self.offset >= self.total) {
+			return DraftItem{}, false, nil
+		}
+		list, err := self.client.BatchGetDraft(self.offset, draftPageMax, self.noContent)
+		if err != nil {
+			return DraftItem{}, false, err
+		}
+		self.total = list.TotalCount
+		self.offset += len(list.Item)
+		self.buf = list.Item
+		if len(list.Item) < draftPageMax {
+			self.done = true
+		}
+		if len(self.buf) == 0 {
+			return DraftItem{}, false, nil
+		}
+	}
+	item := self.buf[0]
+	self.buf = self.buf[1:]
+	return item, true, nil
+}
@@ -0,0 +1,58 @@
+package open
+
+import (
+	"fmt"
+	"testing"
+)
+
+// draftListPage 生成第offset个起共count个草稿的列表响应
+func draftListPage(total, offset, count int) string {
+	items := pageItems(offset, count, func(i int) string {
+		return fmt.Sprintf(`{"media_id":"MEDIA_%d","update_time":1653000000,"content":{"news_item":[{"title":"标题%d"}]}}`, i, i)
+	})
+	return fmt.Sprintf(`{"total_count":%d,"item_count":%d,"item":[%s]}`, total, count, items)
+}
+
+func TestDraftIterator(t *testing.T) {
+	server := newTestServer(t)
+	server.respondSequence("/cgi-bin/draft/batchget",
+		draftListPage(25, 0, draftPageMax),
+		draftListPage(25, draftPageMax, 5),
+	)
+	client, _ := newTestClient(t, server)
+
+	var items []DraftItem
+	drain(t, client.Authorizer(testAuthorizerAppId).Drafts(true), &items)
+	if len(items) != 25 || items[0].MediaId != "MEDIA_0" || items[24].MediaId != "MEDIA_24" {
+		t.Fatalf("got %+v", items)
+	}
+	bodies := server.requestBodies(t, "/cgi-bin/draft/batchget")
+	if len(bodies) != 2 || bodies[1]["offset"] != float64(draftPageMax) || bodies[1]["count"] != float64(draftPageMax) || bodies[1]["no_content"] != float64(1) {
+		t.Fatalf("unexpected requests: %v", bodies)
+	}
+}
+
+func TestDraftIteratorStopsAtTotal(t *testing.T) {
+	server := newTestServer(t)
+	server.respond("/cgi-bin/draft/batchget", draftListPage(draftPageMax, 0, draftPageMax))
+	client, _ := newTestClient(t, server)
+
+	var items []DraftItem
+	drain(t, client.Authorizer(testAuthorizerAppId).Drafts(false), &items)
+	if len(items) != draftPageMax || server.requestCount() != 1 {
+		t.Fatalf("got %d drafts in %d requests, want %d in 1", len(items), server.requestCount(), draftPageMax)
+	}
+	if _, ok := decodeBody(t, server.lastRequest(t))["no_content"]; ok {
+		t.Fatal("no_content must be omitted when false")
+	}
+}
+
+func TestDraftIteratorError(t *testing.T) {
+	server := newTestServer(t)
+	server.respond("/cgi-bin/draft/batchget", `{"errcode":48001,"errmsg":"api unauthorized"}`)
+	client, _ := newTestClient(t, server)
+
+	if _, ok, err := client.Authorizer(testAuthorizerAppId).Drafts(false).Next(); ok || err == nil {
+		t.Fatalf("got (%v, %v), want error", ok, err)
+	}
+}
@@ -0,0 +1,77 @@
+package open
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/url"
+	"time"
+)
+
+// userEncryptKeyMaxVersions 同一用户最多同时有效的加密key版本数
+const userEncryptKeyMaxVersions = 3
+
+// UserEncryptKey 用户加密key, ExpireIn为秒
+type UserEncryptKey struct {
+	EncryptKey string `json:"encrypt_key"`
+	Version    int    `json:"version"`
+	ExpireIn   int64  `json:"expire_in"`
+	Iv         string `json:"iv"`
+	CreateTime int64  `json:"create_time"`
+}
+
+// ExpiresAt 过期时间
+func (self *UserEncryptKey) ExpiresAt() time.Time {
+	return time.Unix(self.CreateTime+self.ExpireIn, 0)
+}
+
+// Expired 是否已过期
+func (self *UserEncryptKey) Expired() bool {
+	return time.Now().After(self.ExpiresAt())
+}
+
+// UserEncryptKeys 用户最近的加密key, 最多3个版本, 按版本从新到旧排列
+type UserEncryptKeys struct {
+	KeyInfoList []UserEncryptKey `json:"key_info_list"`
+}
+
+// Version 按版本号查找未过期的加密key
+func (self *UserEncryptKeys) Version(version int) (*UserEncryptKey, bool) {
+	for i := range self.KeyInfoList {
+		key := &self.KeyInfoList[i]
+		if key.Version == version && !key.Expired() {
+			return key, true
+		}
+	}
+	return nil, false
+}
+
+// sessionKeySignature 用session_key对空字符串做HMAC-SHA256签名
+func sessionKeySignature(sessionKey string) string {
+	mac := hmac.New(sha256.New, []byte(sessionKey))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// GetUserEncryptKeys 获取用户加密key, 签名由sessionKey计算
+func (self *AuthorizerClient) GetUserEncryptKeys(openId, sessionKey string) (*UserEncryptKeys, error) {
+	if openId == "" || sessionKey == "" {
+		return nil, errors.New("openid和session_key不能为空")
+	}
+	token, err := self.AccessToken()
+	if err != nil {
+		return nil, err
+	}
+	values := url.Values{}
+	values.Set("openid", openId)
+	values.Set("signature", sessionKeySignature(sessionKey))
+	values.Set("sig_method", "hmac_sha256")
+	var keys UserEncryptKeys
+	if err := self.client.postJSON(self.client.Endpoint.GetUserEncryptKey(token, values.Encode()), map[string]interface{}{}, &keys); err != nil {
+		return nil, err
+	}
+	if len(keys.KeyInfoList) > userEncryptKeyMaxVersions {
+		keys.KeyInfoList = keys.KeyInfoList[:userEncryptKeyMaxVersions]
+	}
+	return &keys, nil
+}
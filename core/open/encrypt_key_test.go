@@ -0,0 +1,90 @@
+package open
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+// testSessionKey 固定的session_key, 签名为对空字符串的HMAC-SHA256
+const (
+	testSessionKey          = "HyVFkGl5F5OQWJZZaNzBBg=="
+	testSessionKeySignature = "252b75c92698025afe925b29cca5517fdf9ee67aae072cf3225ebf3a53783058"
+)
+
+func TestSessionKeySignatureKnownVector(t *testing.T) {
+	if got := sessionKeySignature(testSessionKey); got != testSessionKeySignature {
+		t.Fatalf("signature: got %s, want %s", got, testSessionKeySignature)
+	}
+}
+
+func TestGetUserEncryptKeys(t *testing.T) {
+	server := newTestServer(t)
+	now := time.Now().Unix()
+	server.respond("/wxa/business/getuserencryptkey", fmt.Sprintf(`{"errcode":0,"errmsg":"ok","key_info_list":[`+
+		`{"encrypt_key":"VI6BpyrK9XH4i4AIGe86tg==","version":10,"expire_in":3597,"iv":"6003f73ec441c386","create_time":%d},`+
+		`{"encrypt_key":"aoUGAHltcliiL9f23oTKHA==","version":9,"expire_in":3597,"iv":"7996656384218dbb","create_time":%d}]}`, now, now-7200))
+	client, _ := newTestClient(t, server)
+
+	keys, err := client.Authorizer(testAuthorizerAppId).GetUserEncryptKeys("OPENID", testSessionKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req := server.lastRequest(t)
+	for field, want := range map[string]string{
+		"access_token": testAuthorizerToken,
+		"openid":       "OPENID",
+		"signature":    testSessionKeySignature,
+		"sig_method":   "hmac_sha256",
+	} {
+		if got := req.Query[field]; len(got) != 1 || got[0] != want {
+			t.Errorf("%s: got %v, want %s", field, got, want)
+		}
+	}
+	if len(keys.KeyInfoList) != 2 {
+		t.Fatalf("keys: got %+v", keys)
+	}
+	want := UserEncryptKey{EncryptKey: "VI6BpyrK9XH4i4AIGe86tg==", Version: 10, ExpireIn: 3597, Iv: "6003f73ec441c386", CreateTime: now}
+	if keys.KeyInfoList[0] != want {
+		t.Fatalf("key: got %+v, want %+v", keys.KeyInfoList[0], want)
+	}
+	if key, ok := keys.Version(10); !ok || key.EncryptKey != "VI6BpyrK9XH4i4AIGe86tg==" {
+		t.Fatalf("version 10: got (%+v, %v)", key, ok)
+	}
+	if _, ok := keys.Version(9); ok {
+		t.Fatal("expired version 9 must not be returned")
+	}
+	if _, ok := keys.Version(8); ok {
+		t.Fatal("unknown version 8 must not be returned")
+	}
+}
+
+func TestGetUserEncryptKeysKeepsLatestVersions(t *testing.T) {
+	server := newTestServer(t)
+	server.respond("/wxa/business/getuserencryptkey", `{"errcode":0,"errmsg":"ok","key_info_list":[{"version":4},{"version":3},{"version":2},{"version":1}]}`)
+	client, _ := newTestClient(t, server)
+
+	keys, err := client.Authorizer(testAuthorizerAppId).GetUserEncryptKeys("OPENID", testSessionKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(keys.KeyInfoList) != 3 || keys.KeyInfoList[2].Version != 2 {
+		t.Fatalf("keys: got %+v, want the latest 3 versions", keys.KeyInfoList)
+	}
+}
+
+func TestGetUserEncryptKeysValidation(t *testing.T) {
+	server := newTestServer(t)
+	client, _ := newTestClient(t, server)
+	authorizer := client.Authorizer(testAuthorizerAppId)
+
+	if _, err := authorizer.GetUserEncryptKeys("", testSessionKey); err == nil {
+		t.Error("missing openid: expected validation error")
+	}
+	if _, err := authorizer.GetUserEncryptKeys("OPENID", ""); err == nil {
+		t.Error("missing session_key: expected validation error")
+	}
+	if n := server.requestCount(); n != 0 {
+		t.Fatalf("invalid requests sent %d requests", n)
+	}
+}
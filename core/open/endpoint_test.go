@@ -0,0 +1,203 @@
+package open
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+// expireAuthorizerToken 删除缓存中的authorizer_access_token, 仅保留刷新令牌
+func expireAuthorizerToken(client *Client) {
+	_ = client.Cache.Delete(AuthorizerTokenCacheKeyPrefix + testAuthorizerAppId)
+	client.saveRefreshToken(testAuthorizerAppId, "REFRESH_TOKEN")
+}
+
+// TestClientEndpoints 逐个调用Client的接口方法, 检查请求的路径与微信文档一致, 防止复制粘贴时用错Endpoint
+func TestClientEndpoints(t *testing.T) {
+	token := testAuthorizerToken
+	items := []AuditItem{{Address: "pages/index/index"}}
+	for _, tc := range []struct {
+		name  string
+		setup func(client *Client)
+		call  func(client *Client)
+		path  string
+		query map[string]string
+	}{
+		// UndoCodeAudit曾误用SubmitAudit的Endpoint
+		{name: "UndoCodeAudit", call: func(c *Client) { _ = c.UndoCodeAudit(token, nil) }, path: "/wxa/undocodeaudit"},
+		// GetQrCode曾丢失path参数
+		{name: "GetQrCode", call: func(c *Client) { _, _ = c.GetQrCode(token, "pages/index?id=1") }, path: "/wxa/get_qrcode", query: map[string]string{"path": "pages/index?id=1"}},
+		{name: "GetQrCodeWithoutPath", call: func(c *Client) { _, _ = c.GetQrCodeWithoutPath(token) }, path: "/wxa/get_qrcode"},
+
+		// 令牌与授权
+		{name: "ApiComponentToken", setup: expireComponentToken, call: func(c *Client) { _, _ = c.ApiComponentToken() }, path: "/cgi-bin/component/api_component_token"},
+		{name: "RefreshComponentToken", call: func(c *Client) { _, _ = c.RefreshComponentToken() }, path: "/cgi-bin/component/api_component_token"},
+		{name: "GetStableToken", call: func(c *Client) { _, _ = c.GetStableToken("wx_mp", "secret", false) }, path: "/cgi-bin/stable_token"},
+		{name: "ApiCreatePreAuthCode", call: func(c *Client) { _, _ = c.ApiCreatePreAuthCode() }, path: "/cgi-bin/component/api_create_preauthcode", query: map[string]string{"component_access_token": testComponentToken}},
+		{name: "ApiCreatePreAuthCodeFull", call: func(c *Client) { _, _ = c.ApiCreatePreAuthCodeFull() }, path: "/cgi-bin/component/api_create_preauthcode"},
+		{name: "GetAuthUrl", call: func(c *Client) { _ = c.GetAuthUrl("https://example.com", 3) }, path: "/cgi-bin/component/api_create_preauthcode"},
+		{name: "ApiQueryAuth", call: func(c *Client) { _, _ = c.ApiQueryAuth("AUTH_CODE") }, path: "/cgi-bin/component/api_query_auth"},
+		{name: "QueryAuth", call: func(c *Client) { _, _ = c.QueryAuth("AUTH_CODE") }, path: "/cgi-bin/component/api_query_auth"},
+		{name: "ApiAuthorizerInfo", call: func(c *Client) { _, _ = c.ApiAuthorizerInfo(testAuthorizerAppId) }, path: "/cgi-bin/component/api_get_authorizer_info"},
+		{name: "HasPermission", call: func(c *Client) { _, _ = c.HasPermission(testAuthorizerAppId, 17) }, path: "/cgi-bin/component/api_get_authorizer_info"},
+		{name: "GetToken", setup: expireAuthorizerToken, call: func(c *Client) { _, _ = c.GetToken(testAuthorizerAppId) }, path: "/cgi-bin/component/api_authorizer_token"},
+		{name: "EnsureAuthorizerToken", setup: expireAuthorizerToken, call: func(c *Client) { _, _ = c.EnsureAuthorizerToken(testAuthorizerAppId) }, path: "/cgi-bin/component/api_authorizer_token"},
+		{name: "Bootstrap", call: func(c *Client) { _, _ = c.Bootstrap(context.Background(), "https://example.com", 3) }, path: "/cgi-bin/component/api_create_preauthcode"},
+		{name: "RefreshToken", call: func(c *Client) { _, _ = c.RefreshToken(testAuthorizerAppId, "REFRESH_TOKEN") }, path: "/cgi-bin/component/api_authorizer_token"},
+		{name: "GetAuthorizerList", call: func(c *Client) { _, _ = c.GetAuthorizerList(0, 100) }, path: "/cgi-bin/component/api_get_authorizer_list"},
+		{name: "GetAuthorizerListContext", call: func(c *Client) { _, _ = c.GetAuthorizerListContext(context.Background(), 0, 100) }, path: "/cgi-bin/component/api_get_authorizer_list"},
+		{name: "GetAuthorizerOption", call: func(c *Client) { _, _ = c.GetAuthorizerOption(testAuthorizerAppId, "voice_recognize") }, path: "/cgi-bin/component/api_get_authorizer_option"},
+		{name: "SetAuthorizerOption", call: func(c *Client) { _ = c.SetAuthorizerOption(testAuthorizerAppId, "voice_recognize", "1") }, path: "/cgi-bin/component/api_set_authorizer_option"},
+
+		// 快速注册
+		{name: "FastRegisterWeapp", call: func(c *Client) {
+			_ = c.FastRegisterWeapp(FastRegisterRequest{Name: "n", Code: "c", CodeType: 1, LegalPersonaWechat: "w", LegalPersonaName: "l"})
+		}, path: "/cgi-bin/component/fastregisterweapp", query: map[string]string{"action": "create"}},
+		{name: "FastRegisterWeappRaw", call: func(c *Client) { _ = c.FastRegisterWeappRaw(map[string]interface{}{"name": "n"}) }, path: "/cgi-bin/component/fastregisterweapp", query: map[string]string{"action": "create"}},
+		{name: "QueryFastRegisterWeapp", call: func(c *Client) { _ = c.QueryFastRegisterWeapp("n", "w", "l") }, path: "/cgi-bin/component/fastregisterweapp", query: map[string]string{"action": "search"}},
+		{name: "FastRegisterWeappSearch", call: func(c *Client) { _ = c.FastRegisterWeappSearch(map[string]interface{}{"name": "n"}) }, path: "/cgi-bin/component/fastregisterweapp", query: map[string]string{"action": "search"}},
+		{name: "FastRegisterBetaWeapp", call: func(c *Client) { _ = c.FastRegisterBetaWeapp("n", "OPENID") }, path: "/wxa/component/fastregisterbetaweapp"},
+		{name: "FastRegisterPersonalWeapp", call: func(c *Client) {
+			_, _, _ = c.FastRegisterPersonalWeapp(PersonalRegisterRequest{IdName: "n", WxUser: "w"})
+		}, path: "/wxa/component/fastregisterpersonalweapp", query: map[string]string{"action": "create"}},
+		{name: "QueryPersonalRegisterTask", call: func(c *Client) { _, _ = c.QueryPersonalRegisterTask("TASK") }, path: "/wxa/component/fastregisterpersonalweapp", query: map[string]string{"action": "query"}},
+
+		// 代码管理
+		{name: "BindTester", call: func(c *Client) { _ = c.BindTester(token, "wechatid") }, path: "/wxa/bind_tester"},
+		{name: "UnbindTester", call: func(c *Client) { _ = c.UnbindTester(token, "wechatid") }, path: "/wxa/unbind_tester"},
+		{name: "ModifyDomain", call: func(c *Client) { _ = c.ModifyDomain(token, map[string]interface{}{"action": "get"}) }, path: "/wxa/modify_domain"},
+		{name: "CommitCode", call: func(c *Client) { _ = c.CommitCode(token, nil) }, path: "/wxa/commit"},
+		{name: "CommitCodeWithResponse", call: func(c *Client) { _, _ = c.CommitCodeWithResponse(token, nil) }, path: "/wxa/commit"},
+		{name: "SubmitAudit", call: func(c *Client) { _ = c.SubmitAudit(token, nil) }, path: "/wxa/submit_audit"},
+		{name: "SubmitAuditWithResult", call: func(c *Client) { _, _ = c.SubmitAuditWithResult(token, nil) }, path: "/wxa/submit_audit"},
+		{name: "SubmitAuditWithRequest", call: func(c *Client) { _, _ = c.SubmitAuditWithRequest(token, SubmitAuditRequest{}) }, path: "/wxa/submit_audit"},
+		{name: "SubmitAuditWithResponse", call: func(c *Client) { _, _ = c.SubmitAuditWithResponse(token, SubmitAuditRequest{}) }, path: "/wxa/submit_audit"},
+		{name: "SubmitAuditTyped", call: func(c *Client) { _, _ = c.SubmitAuditTyped(token, items, nil, "", "", "") }, path: "/wxa/submit_audit"},
+		{name: "GetLastAuditStatus", call: func(c *Client) { _, _ = c.GetLastAuditStatus(token) }, path: "/wxa/get_latest_auditstatus"},
+		{name: "Release", call: func(c *Client) { _ = c.Release(token, nil) }, path: "/wxa/release"},
+		{name: "ReleaseWithResponse", call: func(c *Client) { _, _ = c.ReleaseWithResponse(token, nil) }, path: "/wxa/release"},
+		{name: "GetTemplateList", call: func(c *Client) { _, _ = c.GetTemplateList() }, path: "/wxa/gettemplatelist"},
+		{name: "GetPage", call: func(c *Client) { _, _ = c.GetPage(token) }, path: "/wxa/get_page"},
+		{name: "MemberAuth", call: func(c *Client) { _, _ = c.MemberAuth(token) }, path: "/wxa/memberauth"},
+
+		// 小程序码
+		{name: "GetWxaCode", call: func(c *Client) { _, _ = c.GetWxaCode(token, map[string]interface{}{"path": "pages/index"}) }, path: "/wxa/getwxacode"},
+		{name: "GetWxaCodeContext", call: func(c *Client) {
+			_, _ = c.GetWxaCodeContext(context.Background(), token, map[string]interface{}{"path": "pages/index"})
+		}, path: "/wxa/getwxacode"},
+		{name: "GetWxaCodeImage", call: func(c *Client) { _, _, _ = c.GetWxaCodeImage(token, map[string]interface{}{"path": "pages/index"}) }, path: "/wxa/getwxacode"},
+		{name: "GetWxaCodeUnlimit", call: func(c *Client) { _, _ = c.GetWxaCodeUnlimit(token, WxaCodeUnlimitOptions{Scene: "a=1"}) }, path: "/wxa/getwxacodeunlimit"},
+		{name: "GetWxaCodeUnlimitContext", call: func(c *Client) {
+			_, _ = c.GetWxaCodeUnlimitContext(context.Background(), token, WxaCodeUnlimitOptions{Scene: "a=1"})
+		}, path: "/wxa/getwxacodeunlimit"},
+		{name: "BatchGetWxaCodeUnlimit", call: func(c *Client) {
+			_, _ = c.Authorizer(testAuthorizerAppId).BatchGetWxaCodeUnlimit(context.Background(), []WxaCodeUnlimitOptions{{Scene: "a=1"}}, 1)
+		}, path: "/wxa/getwxacodeunlimit"},
+		{name: "GetWxaQrCode", call: func(c *Client) { _, _ = c.GetWxaQrCode(token, "pages/index", 430) }, path: "/cgi-bin/wxaapp/createwxaqrcode"},
+
+		// 登录、网页授权与用户信息
+		{name: "MpLogin", call: func(c *Client) { _, _ = c.MpLogin(testAuthorizerAppId, "JSCODE") }, path: "/sns/component/jscode2session"},
+		{name: "Code2Session", call: func(c *Client) { _, _ = c.Code2Session(testAuthorizerAppId, "JSCODE") }, path: "/sns/component/jscode2session"},
+		{name: "OAuth2AccessToken", call: func(c *Client) { _, _ = c.OAuth2AccessToken(testAuthorizerAppId, "CODE") }, path: "/sns/oauth2/component/access_token"},
+		{name: "OAuth2RefreshToken", call: func(c *Client) { _, _ = c.OAuth2RefreshToken(testAuthorizerAppId, "REFRESH_TOKEN") }, path: "/sns/oauth2/component/refresh_token"},
+		{name: "ExchangeOAuthCode", call: func(c *Client) { _, _ = c.ExchangeOAuthCode(testAuthorizerAppId, "CODE") }, path: "/sns/oauth2/component/access_token"},
+		{name: "RefreshOAuthToken", call: func(c *Client) { _, _ = c.RefreshOAuthToken(testAuthorizerAppId, "REFRESH_TOKEN") }, path: "/sns/oauth2/component/refresh_token"},
+		{name: "GetOAuthUserInfo", call: func(c *Client) { _, _ = c.GetOAuthUserInfo("OAUTH_TOKEN", "OPENID", "zh_CN") }, path: "/sns/userinfo"},
+		{name: "GetUserPhoneNumber", call: func(c *Client) { _, _ = c.GetUserPhoneNumber(testAuthorizerAppId, "CODE") }, path: "/wxa/business/getuserphonenumber"},
+
+		// 客服消息、内容安全与订单页
+		{name: "CustomService", call: func(c *Client) { _ = c.CustomService(token, map[string]interface{}{"touser": "OPENID"}) }, path: "/cgi-bin/message/custom/send"},
+		{name: "MsgSecCheck", call: func(c *Client) {
+			_, _ = c.MsgSecCheck(testAuthorizerAppId, MsgSecCheckRequest{Content: "text", Scene: SecSceneProfile, OpenId: "OPENID"})
+		}, path: "/wxa/msg_sec_check"},
+		{name: "MediaCheckAsync", call: func(c *Client) {
+			_, _ = c.MediaCheckAsync(testAuthorizerAppId, MediaCheckRequest{MediaUrl: "https://example.com/a.png", MediaType: MediaTypeImage, OpenId: "OPENID", Scene: SecSceneProfile})
+		}, path: "/wxa/media_check_async"},
+		{name: "GetOrderPathInfo", call: func(c *Client) { _, _ = c.GetOrderPathInfo(OrderPathInfoLatest) }, path: "/wxaapi/wxamptrade/get_order_path_info"},
+		{name: "ApplyOrderPath", call: func(c *Client) {
+			_, _ = c.ApplyOrderPath(OrderPathApplication{Path: "pages/order", AppIdList: []string{testAuthorizerAppId}})
+		}, path: "/wxaapi/wxamptrade/apply_order_path_info"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			server := newTestServer(t)
+			client, _ := newTestClient(t, server)
+			_ = client.Cache.SetEx(ComponentTicketCacheKeyPrefix+testAppId, map[string]interface{}{
+				"component_verify_ticket": "TICKET",
+			}, 3600)
+			if tc.setup != nil {
+				tc.setup(client)
+			}
+			tc.call(client)
+			req := server.lastRequest(t)
+			if req.Path != tc.path {
+				t.Fatalf("path: got %s, want %s", req.Path, tc.path)
+			}
+			for key, value := range tc.query {
+				if got := req.Query[key]; len(got) != 1 || got[0] != value {
+					t.Errorf("query %s: got %v, want %s", key, got, value)
+				}
+			}
+		})
+	}
+}
+
+// TestClientPageUrls 检查只生成链接不发请求的方法
+func TestClientPageUrls(t *testing.T) {
+	server := newTestServer(t)
+	server.respond("/cgi-bin/component/api_create_preauthcode", `{"pre_auth_code":"PRE_AUTH_CODE","expires_in":1800}`)
+	client, _ := newTestClient(t, server)
+	oauthUrl, err := client.BuildComponentOAuthUrl(testAuthorizerAppId, "https://example.com/cb", ScopeSnsapiBase, "STATE")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for name, tc := range map[string]struct {
+		rawUrl string
+		prefix string
+		query  map[string]string
+	}{
+		"GetAuthUrl": {
+			rawUrl: client.GetAuthUrl("https://example.com/cb?a=1", 3),
+			prefix: "https://mp.weixin.qq.com/cgi-bin/componentloginpage?",
+			query: map[string]string{
+				"component_appid": testAppId,
+				"pre_auth_code":   "PRE_AUTH_CODE",
+				"redirect_uri":    "https://example.com/cb?a=1",
+				"auth_type":       "3",
+			},
+		},
+		"BuildComponentOAuthUrl": {
+			rawUrl: oauthUrl,
+			prefix: "https://open.weixin.qq.com/connect/oauth2/authorize?",
+			query: map[string]string{
+				"appid":           testAuthorizerAppId,
+				"redirect_uri":    "https://example.com/cb",
+				"scope":           ScopeSnsapiBase,
+				"state":           "STATE",
+				"component_appid": testAppId,
+			},
+		},
+		// OAuth2Authorize曾使用BaseUrl作为授权页域名
+		"OAuth2Authorize": {
+			rawUrl: client.OAuth2Authorize(testAuthorizerAppId, "https://example.com/cb"),
+			prefix: "https://open.weixin.qq.com/connect/oauth2/authorize?",
+			query:  map[string]string{"appid": testAuthorizerAppId, "component_appid": testAppId},
+		},
+	} {
+		if !strings.HasPrefix(tc.rawUrl, tc.prefix) {
+			t.Errorf("%s: got %s, want prefix %s", name, tc.rawUrl, tc.prefix)
+			continue
+		}
+		parsed, err := url.Parse(tc.rawUrl)
+		if err != nil {
+			t.Errorf("%s: %v", name, err)
+			continue
+		}
+		for key, value := range tc.query {
+			if got := parsed.Query().Get(key); got != value {
+				t.Errorf("%s: query %s got %q, want %q", name, key, got, value)
+			}
+		}
+	}
+}
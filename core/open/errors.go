@@ -0,0 +1,65 @@
+package open
+
+import "fmt"
+
+// Error 微信接口返回的错误
+type Error struct {
+	ErrCode int64  `json:"errcode"`
+	ErrMsg  string `json:"errmsg"`
+}
+
+func (self *Error) Error() string {
+	return fmt.Sprintf("操作失败:%s(%d)", self.ErrMsg, self.ErrCode)
+}
+
+// Is 按errcode判断是否为同一错误, 用于errors.Is
+func (self *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	if !ok {
+		return false
+	}
+	return t.ErrCode == self.ErrCode
+}
+
+var (
+	// ErrInvalidOpenId 不合法的openid
+	ErrInvalidOpenId = &Error{ErrCode: 40003, ErrMsg: "invalid openid"}
+	// ErrInvalidCode 无效的js_code
+	ErrInvalidCode = &Error{ErrCode: 40029, ErrMsg: "invalid code"}
+	// ErrInvalidSupportVersion 最低基础库版本不合法或低于允许的版本
+	ErrInvalidSupportVersion = &Error{ErrCode: 40097, ErrMsg: "invalid args"}
+	// ErrRequireSubscribe 用户未关注公众号
+	ErrRequireSubscribe = &Error{ErrCode: 43004, ErrMsg: "require subscribe"}
+	// ErrUserRefused 用户拒绝接收消息
+	ErrUserRefused = &Error{ErrCode: 43101, ErrMsg: "user refuse to accept the msg"}
+	// ErrApiDailyQuota 接口调用超过每日限额
+	ErrApiDailyQuota = &Error{ErrCode: 45009, ErrMsg: "reach max api daily quota limit"}
+	// ErrFrequencyLimit 调用频率超限
+	ErrFrequencyLimit = &Error{ErrCode: 45011, ErrMsg: "api minute-quota reach limit"}
+	// ErrResponseOutOfTime 超出回复时间窗口
+	ErrResponseOutOfTime = &Error{ErrCode: 45015, ErrMsg: "response out of time limit or subscription is canceled"}
+	// ErrOutOfResponseCount 超出客服消息下发条数限制(用户消息后48小时内最多20条)
+	ErrOutOfResponseCount = &Error{ErrCode: 45047, ErrMsg: "out of response count limit"}
+	// ErrTagInUse 标签下粉丝数超过10万, 不允许直接删除
+	ErrTagInUse = &Error{ErrCode: 45057, ErrMsg: "can't delete the tag that has too many fans"}
+	// ErrSystemTag 不能修改或删除系统默认保留的标签
+	ErrSystemTag = &Error{ErrCode: 45058, ErrMsg: "can't modify sys tag"}
+	// ErrDataFormat 模板参数不准确
+	ErrDataFormat = &Error{ErrCode: 47003, ErrMsg: "argument invalid"}
+	// ErrApiUnauthorized 接口未授权或未开通
+	ErrApiUnauthorized = &Error{ErrCode: 48001, ErrMsg: "api unauthorized"}
+	// ErrNameInvalid 名称格式不合法
+	ErrNameInvalid = &Error{ErrCode: 53010, ErrMsg: "invalid nickname"}
+	// ErrNameOccupied 名称与已有小程序重复
+	ErrNameOccupied = &Error{ErrCode: 53013, ErrMsg: "nickname is occupied"}
+	// ErrRefreshTokenInvalid 刷新令牌无效
+	ErrRefreshTokenInvalid = &Error{ErrCode: 61023, ErrMsg: "refresh_token is invalid"}
+	// ErrNoUnionId 用户未关注或未绑定开放平台, 无法获取unionid
+	ErrNoUnionId = &Error{ErrCode: 89002, ErrMsg: "open not exists"}
+	// ErrLinkPending 已向小程序管理员发送关联邀请, 等待确认
+	ErrLinkPending = &Error{ErrCode: 89010, ErrMsg: "link message has sent"}
+	// ErrAlreadyLinked 公众号已关联该小程序
+	ErrAlreadyLinked = &Error{ErrCode: 89015, ErrMsg: "has linked once"}
+	// ErrNfcModelNotRegistered NFC设备型号未注册
+	ErrNfcModelNotRegistered = &Error{ErrCode: 9800001, ErrMsg: "model_id not registered"}
+)
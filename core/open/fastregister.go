@@ -0,0 +1,74 @@
+package open
+
+import "errors"
+
+// FastRegisterRequest 快速注册企业小程序的企业信息, CodeType: 1统一社会信用代码 2组织机构代码 3营业执照注册号
+type FastRegisterRequest struct {
+	Name               string `json:"name"`
+	Code               string `json:"code"`
+	CodeType           int    `json:"code_type"`
+	LegalPersonaWechat string `json:"legal_persona_wechat"`
+	LegalPersonaName   string `json:"legal_persona_name"`
+	ComponentPhone     string `json:"component_phone,omitempty"`
+}
+
+// FastRegisterOptions 同FastRegisterRequest
+//
+// Deprecated: 使用FastRegisterRequest
+type FastRegisterOptions = FastRegisterRequest
+
+func (self *FastRegisterRequest) validate() error {
+	if self.Name == "" || self.Code == "" || self.LegalPersonaWechat == "" || self.LegalPersonaName == "" {
+		return errors.New("企业名称、企业代码、法人微信号和法人姓名不能为空")
+	}
+	if self.CodeType < 1 || self.CodeType > 3 {
+		return errors.New("code_type取值范围为1-3")
+	}
+	return nil
+}
+
+// FastRegisterWeapp 快速注册企业小程序, 结果通过notify_third_fasteregister事件推送
+func (self *Client) FastRegisterWeapp(req FastRegisterRequest) error {
+	if err := req.validate(); err != nil {
+		return err
+	}
+	token, err := self.ApiComponentToken()
+	if err != nil {
+		return err
+	}
+	return self.postJSON(self.Endpoint.FastRegisterWeapp(token), req, nil)
+}
+
+// FastRegisterWeappSubmit 同FastRegisterWeapp
+//
+// Deprecated: 使用FastRegisterWeapp
+func (self *Client) FastRegisterWeappSubmit(opts FastRegisterOptions) error {
+	return self.FastRegisterWeapp(opts)
+}
+
+// FastRegisterWeappRaw 使用原始参数快速注册企业小程序, 即原先接收map的FastRegisterWeapp
+//
+// Deprecated: 使用FastRegisterWeapp, 提交前会在本地校验必填字段
+func (self *Client) FastRegisterWeappRaw(data map[string]interface{}) error {
+	token, err := self.ApiComponentToken()
+	if err != nil {
+		return err
+	}
+	return self.postJSON(self.Endpoint.FastRegisterWeapp(token), data, nil)
+}
+
+// QueryFastRegisterWeapp 查询快速注册任务, 任务仍在进行中时返回nil
+func (self *Client) QueryFastRegisterWeapp(name, legalPersonaWechat, legalPersonaName string) error {
+	if name == "" || legalPersonaWechat == "" || legalPersonaName == "" {
+		return errors.New("企业名称、法人微信号和法人姓名不能为空")
+	}
+	token, err := self.ApiComponentToken()
+	if err != nil {
+		return err
+	}
+	return self.postJSON(self.Endpoint.FastRegisterWeappSearch(token), map[string]interface{}{
+		"name":                 name,
+		"legal_persona_wechat": legalPersonaWechat,
+		"legal_persona_name":   legalPersonaName,
+	}, nil)
+}
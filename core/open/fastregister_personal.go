@@ -0,0 +1,72 @@
+package open
+
+import "errors"
+
+// PersonalRegisterStatus 个人主体小程序注册任务状态
+type PersonalRegisterStatus int
+
+const (
+	PersonalRegisterSuccess       PersonalRegisterStatus = 0 // 注册成功
+	PersonalRegisterPendingVerify PersonalRegisterStatus = 1 // 等待用户确认及人脸核身
+	PersonalRegisterRejected      PersonalRegisterStatus = 2 // 用户拒绝
+	PersonalRegisterExpired       PersonalRegisterStatus = 3 // 任务已过期(24小时内未完成)
+	PersonalRegisterFailed        PersonalRegisterStatus = 4 // 注册失败
+)
+
+// IsPending 是否仍在等待用户完成人脸核身, 此时应继续轮询而不是视为失败
+func (self PersonalRegisterStatus) IsPending() bool {
+	return self == PersonalRegisterPendingVerify
+}
+
+// PersonalRegisterRequest 个人主体小程序注册请求
+type PersonalRegisterRequest struct {
+	IdName         string `json:"idname"`
+	WxUser         string `json:"wxuser"`
+	ComponentPhone string `json:"component_phone,omitempty"`
+}
+
+// PersonalRegisterTask 个人主体小程序注册任务
+type PersonalRegisterTask struct {
+	TaskId       string                 `json:"taskid"`
+	AuthorizeUrl string                 `json:"authorize_url"`
+	Status       PersonalRegisterStatus `json:"status"`
+	StatusMsg    string                 `json:"status_msg"`
+}
+
+// FastRegisterPersonalWeapp 快速注册个人主体小程序, 返回任务id和用户确认链接
+func (self *Client) FastRegisterPersonalWeapp(req PersonalRegisterRequest) (string, string, error) {
+	if req.IdName == "" || req.WxUser == "" {
+		return "", "", errors.New("个人用户名字和微信号不能为空")
+	}
+	token, err := self.ApiComponentToken()
+	if err != nil {
+		return "", "", err
+	}
+	var task PersonalRegisterTask
+	if err := self.postJSON(self.Endpoint.FastRegisterPersonalWeapp(token, "create"), req, &task); err != nil {
+		return "", "", err
+	}
+	return task.TaskId, task.AuthorizeUrl, nil
+}
+
+// QueryPersonalRegisterTask 查询个人主体小程序注册任务状态
+func (self *Client) QueryPersonalRegisterTask(taskId string) (*PersonalRegisterTask, error) {
+	if taskId == "" {
+		return nil, errors.New("taskid不能为空")
+	}
+	token, err := self.ApiComponentToken()
+	if err != nil {
+		return nil, err
+	}
+	var task PersonalRegisterTask
+	err = self.postJSON(self.Endpoint.FastRegisterPersonalWeapp(token, "query"), map[string]interface{}{
+		"taskid": taskId,
+	}, &task)
+	if err != nil {
+		return nil, err
+	}
+	if task.TaskId == "" {
+		task.TaskId = taskId
+	}
+	return &task, nil
+}
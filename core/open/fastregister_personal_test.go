@@ -0,0 +1,94 @@
+package open
+
+import "testing"
+
+func TestFastRegisterPersonalWeappCreate(t *testing.T) {
+	server := newTestServer(t)
+	server.respond("/wxa/component/fastregisterpersonalweapp", `{"errcode":0,"errmsg":"ok","taskid":"TASK_ID","authorize_url":"https://mp.weixin.qq.com/wxopen/authorize?taskid=TASK_ID"}`)
+	client, _ := newTestClient(t, server)
+
+	taskId, authorizeUrl, err := client.FastRegisterPersonalWeapp(PersonalRegisterRequest{
+		IdName:         "张三",
+		WxUser:         "zhangsan",
+		ComponentPhone: "13800138000",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if taskId != "TASK_ID" || authorizeUrl != "https://mp.weixin.qq.com/wxopen/authorize?taskid=TASK_ID" {
+		t.Fatalf("got (%s, %s)", taskId, authorizeUrl)
+	}
+	req := server.lastRequest(t)
+	if req.RawQuery != "action=create&component_access_token="+testComponentToken {
+		t.Fatalf("query: got %s", req.RawQuery)
+	}
+	if string(req.Body) != `{"idname":"张三","wxuser":"zhangsan","component_phone":"13800138000"}` {
+		t.Fatalf("body: got %s", req.Body)
+	}
+}
+
+func TestFastRegisterPersonalWeappOmitsPhone(t *testing.T) {
+	server := newTestServer(t)
+	server.respond("/wxa/component/fastregisterpersonalweapp", `{"errcode":0,"errmsg":"ok","taskid":"TASK_ID","authorize_url":"https://example.com"}`)
+	client, _ := newTestClient(t, server)
+
+	if _, _, err := client.FastRegisterPersonalWeapp(PersonalRegisterRequest{IdName: "张三", WxUser: "zhangsan"}); err != nil {
+		t.Fatal(err)
+	}
+	if body := string(server.lastRequest(t).Body); body != `{"idname":"张三","wxuser":"zhangsan"}` {
+		t.Fatalf("body: got %s", body)
+	}
+}
+
+func TestQueryPersonalRegisterTask(t *testing.T) {
+	for _, tc := range []struct {
+		fixture     string
+		wantStatus  PersonalRegisterStatus
+		wantPending bool
+	}{
+		{`{"errcode":0,"errmsg":"ok","taskid":"TASK_ID","authorize_url":"https://example.com","status":1,"status_msg":"等待用户确认"}`, PersonalRegisterPendingVerify, true},
+		{`{"errcode":0,"errmsg":"ok","status":0}`, PersonalRegisterSuccess, false},
+		{`{"errcode":0,"errmsg":"ok","status":2,"status_msg":"用户拒绝"}`, PersonalRegisterRejected, false},
+		{`{"errcode":0,"errmsg":"ok","status":4,"status_msg":"注册失败"}`, PersonalRegisterFailed, false},
+	} {
+		server := newTestServer(t)
+		server.respond("/wxa/component/fastregisterpersonalweapp", tc.fixture)
+		client, _ := newTestClient(t, server)
+
+		task, err := client.QueryPersonalRegisterTask("TASK_ID")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if task.Status != tc.wantStatus || task.Status.IsPending() != tc.wantPending {
+			t.Errorf("%s: got status %d pending %v", tc.fixture, task.Status, task.Status.IsPending())
+		}
+		if task.TaskId != "TASK_ID" {
+			t.Errorf("%s: taskid: got %q, want TASK_ID", tc.fixture, task.TaskId)
+		}
+		req := server.lastRequest(t)
+		if req.RawQuery != "action=query&component_access_token="+testComponentToken {
+			t.Fatalf("query: got %s", req.RawQuery)
+		}
+		if string(req.Body) != `{"taskid":"TASK_ID"}` {
+			t.Fatalf("body: got %s", req.Body)
+		}
+	}
+}
+
+func TestFastRegisterPersonalValidation(t *testing.T) {
+	server := newTestServer(t)
+	client, _ := newTestClient(t, server)
+
+	if _, _, err := client.FastRegisterPersonalWeapp(PersonalRegisterRequest{WxUser: "zhangsan"}); err == nil {
+		t.Error("missing idname: expected validation error")
+	}
+	if _, _, err := client.FastRegisterPersonalWeapp(PersonalRegisterRequest{IdName: "张三"}); err == nil {
+		t.Error("missing wxuser: expected validation error")
+	}
+	if _, err := client.QueryPersonalRegisterTask(""); err == nil {
+		t.Error("missing taskid: expected validation error")
+	}
+	if n := server.requestCount(); n != 0 {
+		t.Fatalf("invalid requests sent %d requests", n)
+	}
+}
@@ -0,0 +1,94 @@
+package open
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFastRegisterWeappCreateBody(t *testing.T) {
+	server := newTestServer(t)
+	client, _ := newTestClient(t, server)
+
+	err := client.FastRegisterWeapp(FastRegisterRequest{
+		Name:               "腾讯计算机系统有限公司",
+		Code:               "91440300708461136T",
+		CodeType:           1,
+		LegalPersonaWechat: "legal_wechat",
+		LegalPersonaName:   "张三",
+		ComponentPhone:     "13800138000",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	req := server.lastRequest(t)
+	if req.Path != "/cgi-bin/component/fastregisterweapp" || req.Query["action"][0] != "create" {
+		t.Fatalf("unexpected request: %s %v", req.Path, req.Query)
+	}
+	want := map[string]interface{}{
+		"name":                 "腾讯计算机系统有限公司",
+		"code":                 "91440300708461136T",
+		"code_type":            float64(1),
+		"legal_persona_wechat": "legal_wechat",
+		"legal_persona_name":   "张三",
+		"component_phone":      "13800138000",
+	}
+	if body := decodeBody(t, req); !reflect.DeepEqual(body, want) {
+		t.Fatalf("body: got %v, want %v", body, want)
+	}
+}
+
+func TestFastRegisterWeappSearchBody(t *testing.T) {
+	server := newTestServer(t)
+	client, _ := newTestClient(t, server)
+
+	if err := client.QueryFastRegisterWeapp("name", "legal_wechat", "张三"); err != nil {
+		t.Fatal(err)
+	}
+	req := server.lastRequest(t)
+	if req.Query["action"][0] != "search" {
+		t.Fatalf("action: got %v, want search", req.Query["action"])
+	}
+	want := map[string]interface{}{
+		"name":                 "name",
+		"legal_persona_wechat": "legal_wechat",
+		"legal_persona_name":   "张三",
+	}
+	if body := decodeBody(t, req); !reflect.DeepEqual(body, want) {
+		t.Fatalf("body: got %v, want %v", body, want)
+	}
+}
+
+func TestFastRegisterWeappValidation(t *testing.T) {
+	server := newTestServer(t)
+	client, _ := newTestClient(t, server)
+	valid := FastRegisterRequest{Name: "n", Code: "c", CodeType: 1, LegalPersonaWechat: "w", LegalPersonaName: "p"}
+
+	for name, mutate := range map[string]func(req *FastRegisterRequest){
+		"missing name":      func(req *FastRegisterRequest) { req.Name = "" },
+		"missing code":      func(req *FastRegisterRequest) { req.Code = "" },
+		"missing wechat":    func(req *FastRegisterRequest) { req.LegalPersonaWechat = "" },
+		"missing legal":     func(req *FastRegisterRequest) { req.LegalPersonaName = "" },
+		"code_type too low": func(req *FastRegisterRequest) { req.CodeType = 0 },
+		"code_type too big": func(req *FastRegisterRequest) { req.CodeType = 4 },
+	} {
+		req := valid
+		mutate(&req)
+		if err := client.FastRegisterWeapp(req); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+	if n := server.requestCount(); n != 0 {
+		t.Fatalf("invalid requests sent %d requests", n)
+	}
+}
+
+func TestFastRegisterWeappRawErrcode(t *testing.T) {
+	server := newTestServer(t)
+	server.respond("/cgi-bin/component/fastregisterweapp", `{"errcode":89249,"errmsg":"task running"}`)
+	client, _ := newTestClient(t, server)
+
+	err := client.FastRegisterWeappRaw(map[string]interface{}{"name": "n"})
+	if apiErr, ok := err.(*Error); !ok || apiErr.ErrCode != 89249 {
+		t.Fatalf("got %v, want *Error with errcode 89249", err)
+	}
+}
@@ -0,0 +1,117 @@
+package open
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// JSErrorQuery js错误查询条件
+type JSErrorQuery struct {
+	ErrMsgKeyword string
+	Type          int
+	ClientVersion string
+	Start         time.Time
+	End           time.Time
+	Offset        int
+	Limit         int
+}
+
+// JSError js错误聚合记录
+type JSError struct {
+	Time            int64  `json:"time"`
+	AppVersion      string `json:"app_version"`
+	ClientVersion   string `json:"client_version"`
+	ErrMsgMd5       string `json:"errmsg_md5"`
+	ErrMsg          string `json:"errmsg"`
+	ErrStack        string `json:"errstack"`
+	VersionErrorCnt int64  `json:"version_error_cnt"`
+	TotalErrorCnt   int64  `json:"total_error_cnt"`
+}
+
+// JSErrorResult js错误查询结果
+type JSErrorResult struct {
+	Results []JSError `json:"results"`
+	Total   int64     `json:"total"`
+}
+
+// FeedbackRecord 用户反馈
+type FeedbackRecord struct {
+	RecordId   int64    `json:"record_id"`
+	CreateTime int64    `json:"create_time"`
+	Content    string   `json:"content"`
+	Phone      string   `json:"phone"`
+	OpenId     string   `json:"openid"`
+	Nickname   string   `json:"nickname"`
+	HeadUrl    string   `json:"head_url"`
+	Type       int      `json:"type"`
+	MediaIds   []string `json:"mediaIds"`
+	SystemInfo string   `json:"systemInfo"`
+}
+
+// FeedbackList 用户反馈列表
+type FeedbackList struct {
+	List     []FeedbackRecord `json:"list"`
+	TotalNum int64            `json:"total_num"`
+}
+
+// SearchJSErrors 查询js错误详情
+func (self *AuthorizerClient) SearchJSErrors(query JSErrorQuery) (*JSErrorResult, error) {
+	if !query.Start.Before(query.End) {
+		return nil, errors.New("开始时间必须早于结束时间")
+	}
+	if query.Limit <= 0 {
+		query.Limit = 10
+	}
+	token, err := self.AccessToken()
+	if err != nil {
+		return nil, err
+	}
+	var result JSErrorResult
+	err = self.client.postJSON(self.client.Endpoint.JSErrSearch(token), map[string]interface{}{
+		"errmsg_keyword": query.ErrMsgKeyword,
+		"type":           query.Type,
+		"client_version": query.ClientVersion,
+		"start_time":     query.Start.Unix(),
+		"end_time":       query.End.Unix(),
+		"start":          query.Offset,
+		"limit":          query.Limit,
+	}, &result)
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// ListFeedback 获取用户反馈列表, typeFilter为0时不按类型过滤
+func (self *AuthorizerClient) ListFeedback(page, num, typeFilter int) (*FeedbackList, error) {
+	if page <= 0 || num <= 0 {
+		return nil, errors.New("page和num必须大于0")
+	}
+	token, err := self.AccessToken()
+	if err != nil {
+		return nil, err
+	}
+	values := url.Values{}
+	values.Set("page", strconv.Itoa(page))
+	values.Set("num", strconv.Itoa(num))
+	if typeFilter != 0 {
+		values.Set("type", strconv.Itoa(typeFilter))
+	}
+	var list FeedbackList
+	if err := self.client.getJSON(self.client.Endpoint.FeedbackList(token, values.Encode()), &list); err != nil {
+		return nil, err
+	}
+	return &list, nil
+}
+
+// GetFeedbackMedia 获取用户反馈携带的图片
+func (self *AuthorizerClient) GetFeedbackMedia(recordId int64, mediaId string) ([]byte, error) {
+	token, err := self.AccessToken()
+	if err != nil {
+		return nil, err
+	}
+	return self.client.getBinary(context.Background(), self.client.Endpoint.GetFeedbackMedia(token, recordId, url.QueryEscape(mediaId)))
+}
@@ -0,0 +1,120 @@
+package open
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestSearchJSErrors(t *testing.T) {
+	server := newTestServer(t)
+	server.respond("/wxaapi/log/jserr_search", `{"errcode":0,"errmsg":"ok","results":[{"time":1704902400,"app_version":"1.0.3","client_version":"8.0.44","errmsg_md5":"f2fb4f8cd638466ad0e7607b01b7d0ca","errmsg":"Cannot read property 'foo' of undefined","errstack":"TypeError: Cannot read property 'foo' of undefined\n    at pages/index/index.js:12:5","version_error_cnt":3,"total_error_cnt":17}],"total":1}`)
+	client, _ := newTestClient(t, server)
+
+	result, err := client.Authorizer(testAuthorizerAppId).SearchJSErrors(JSErrorQuery{
+		ErrMsgKeyword: "foo",
+		Type:          1,
+		ClientVersion: "8.0.44",
+		Start:         time.Unix(1704816000, 0),
+		End:           time.Unix(1704988800, 0),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	req := server.lastRequest(t)
+	if got := req.Query["access_token"]; len(got) != 1 || got[0] != testAuthorizerToken {
+		t.Fatalf("access_token: got %v", got)
+	}
+	want := `{"client_version":"8.0.44","end_time":1704988800,"errmsg_keyword":"foo","limit":10,"start":0,"start_time":1704816000,"type":1}`
+	if string(req.Body) != want {
+		t.Fatalf("body:\n got %s\nwant %s", req.Body, want)
+	}
+	if result.Total != 1 || len(result.Results) != 1 {
+		t.Fatalf("result: got %+v", result)
+	}
+	jsErr := result.Results[0]
+	if jsErr.ErrMsgMd5 != "f2fb4f8cd638466ad0e7607b01b7d0ca" || jsErr.AppVersion != "1.0.3" || jsErr.VersionErrorCnt != 3 || jsErr.TotalErrorCnt != 17 ||
+		jsErr.ErrStack != "TypeError: Cannot read property 'foo' of undefined\n    at pages/index/index.js:12:5" {
+		t.Fatalf("js error: got %+v", jsErr)
+	}
+}
+
+func TestSearchJSErrorsValidation(t *testing.T) {
+	server := newTestServer(t)
+	client, _ := newTestClient(t, server)
+
+	now := time.Now()
+	if _, err := client.Authorizer(testAuthorizerAppId).SearchJSErrors(JSErrorQuery{Start: now, End: now.Add(-time.Hour)}); err == nil {
+		t.Fatal("reversed range: expected validation error")
+	}
+	if n := server.requestCount(); n != 0 {
+		t.Fatalf("invalid query sent %d requests", n)
+	}
+}
+
+func TestListFeedback(t *testing.T) {
+	server := newTestServer(t)
+	server.respond("/wxaapi/feedback/list", `{"errcode":0,"errmsg":"ok","list":[{"record_id":237,"create_time":1704902400,"content":"页面打不开","phone":"13800000000","openid":"OPENID","nickname":"张三","head_url":"https://example.com/head.png","type":1,"mediaIds":["MEDIA_1","MEDIA_2"],"systemInfo":"{\"model\":\"iPhone 15\"}"}],"total_num":1}`)
+	client, _ := newTestClient(t, server)
+	authorizer := client.Authorizer(testAuthorizerAppId)
+
+	list, err := authorizer.ListFeedback(1, 10, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if query := server.lastRequest(t).RawQuery; query != "access_token="+testAuthorizerToken+"&num=10&page=1&type=1" {
+		t.Fatalf("query: got %s", query)
+	}
+	if list.TotalNum != 1 || len(list.List) != 1 {
+		t.Fatalf("list: got %+v", list)
+	}
+	record := list.List[0]
+	if record.RecordId != 237 || record.OpenId != "OPENID" || record.Type != 1 || len(record.MediaIds) != 2 || record.MediaIds[1] != "MEDIA_2" ||
+		record.SystemInfo != `{"model":"iPhone 15"}` {
+		t.Fatalf("record: got %+v", record)
+	}
+
+	if _, err := authorizer.ListFeedback(2, 10, 0); err != nil {
+		t.Fatal(err)
+	}
+	if query := server.lastRequest(t).RawQuery; query != "access_token="+testAuthorizerToken+"&num=10&page=2" {
+		t.Fatalf("query without type: got %s", query)
+	}
+
+	if _, err := authorizer.ListFeedback(0, 10, 0); err == nil {
+		t.Fatal("page 0: expected validation error")
+	}
+	if n := server.requestCount(); n != 2 {
+		t.Fatalf("sent %d requests, want 2", n)
+	}
+}
+
+func TestGetFeedbackMedia(t *testing.T) {
+	server := newTestServer(t)
+	server.respondBinary("/cgi-bin/media/getfeedbackmedia", "image/png", testPNG(t))
+	client, _ := newTestClient(t, server)
+
+	data, err := client.Authorizer(testAuthorizerAppId).GetFeedbackMedia(237, "MEDIA/1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertTestPNG(t, data)
+	if query := server.lastRequest(t).RawQuery; query != "access_token="+testAuthorizerToken+"&record_id=237&media_id=MEDIA%2F1" {
+		t.Fatalf("query: got %s", query)
+	}
+}
+
+func TestGetFeedbackMediaJSONError(t *testing.T) {
+	server := newTestServer(t)
+	server.respond("/cgi-bin/media/getfeedbackmedia", `{"errcode":40007,"errmsg":"invalid media_id"}`)
+	client, _ := newTestClient(t, server)
+
+	data, err := client.Authorizer(testAuthorizerAppId).GetFeedbackMedia(237, "BAD_MEDIA")
+	var apiErr *Error
+	if !errors.As(err, &apiErr) || apiErr.ErrCode != 40007 {
+		t.Fatalf("got %v, want errcode 40007", err)
+	}
+	if data != nil {
+		t.Fatalf("data: got %d bytes, want nil", len(data))
+	}
+}
@@ -0,0 +1,213 @@
+package open
+
+import (
+	"encoding/json"
+	"errors"
+)
+
+// publishPageMax 批量获取已发布文章单次最大条数
+const publishPageMax = 20
+
+// PublishStatus 发布状态
+type PublishStatus int
+
+const (
+	PublishSuccess        PublishStatus = 0 // 成功
+	PublishPending        PublishStatus = 1 // 发布中
+	PublishOriginalFailed PublishStatus = 2 // 原创失败
+	PublishFailed         PublishStatus = 3 // 常规失败
+	PublishAuditRejected  PublishStatus = 4 // 平台审核不通过
+	PublishDeletedByUser  PublishStatus = 5 // 成功后用户删除所有文章
+	PublishBannedBySystem PublishStatus = 6 // 成功后系统封禁所有文章
+)
+
+// IsPending 是否仍在发布中, 此时应继续轮询
+func (self PublishStatus) IsPending() bool {
+	return self == PublishPending
+}
+
+// PublishArticleItem 发布成功的文章
+type PublishArticleItem struct {
+	Idx        int    `json:"idx"`
+	ArticleUrl string `json:"article_url"`
+}
+
+// PublishResult 发布任务状态, 发布成功时ArticleId及ArticleDetail有效, FailIdx为失败的文章序号(从1开始)
+type PublishResult struct {
+	PublishId     json.Number   `json:"publish_id"`
+	PublishStatus PublishStatus `json:"publish_status"`
+	ArticleId     string        `json:"article_id"`
+	ArticleDetail struct {
+		Count int                  `json:"count"`
+		Item  []PublishArticleItem `json:"item"`
+	} `json:"article_detail"`
+	FailIdx []int `json:"fail_idx"`
+}
+
+// PublishedArticle 已发布文章, IsDeleted表示文章是否已被删除
+type PublishedArticle struct {
+	Article
+	IsDeleted bool `json:"is_deleted"`
+}
+
+// PublishedItem 已发布文章列表项
+type PublishedItem struct {
+	ArticleId  string `json:"article_id"`
+	UpdateTime int64  `json:"update_time"`
+	Content    struct {
+		NewsItem []PublishedArticle `json:"news_item"`
+	} `json:"content"`
+}
+
+// PublishedList 已发布文章列表
+type PublishedList struct {
+	TotalCount int             `json:"total_count"`
+	ItemCount  int             `json:"item_count"`
+	Item       []PublishedItem `json:"item"`
+}
+
+// PublishDraft 发布草稿, 返回发布任务id, 发布结果通过PUBLISHJOBFINISH事件推送或GetPublishStatus轮询
+func (self *AuthorizerClient) PublishDraft(mediaId string) (string, error) {
+	if mediaId == "" {
+		return "", errors.New("media_id不能为空")
+	}
+	token, err := self.AccessToken()
+	if err != nil {
+		return "", err
+	}
+	var resp struct {
+		PublishId json.Number `json:"publish_id"`
+	}
+	err = self.client.postJSON(self.client.Endpoint.SubmitPublish(token), map[string]interface{}{
+		"media_id": mediaId,
+	}, &resp)
+	if err != nil {
+		return "", err
+	}
+	return resp.PublishId.String(), nil
+}
+
+// GetPublishStatus 查询发布任务状态
+func (self *AuthorizerClient) GetPublishStatus(publishId string) (*PublishResult, error) {
+	if publishId == "" {
+		return nil, errors.New("publish_id不能为空")
+	}
+	token, err := self.AccessToken()
+	if err != nil {
+		return nil, err
+	}
+	var result PublishResult
+	err = self.client.postJSON(self.client.Endpoint.GetPublishStatus(token), map[string]interface{}{
+		"publish_id": publishId,
+	}, &result)
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// DeletePublishedArticle 删除已发布的文章, index为要删除的文章序号(从1开始), 为0时删除全部文章
+func (self *AuthorizerClient) DeletePublishedArticle(articleId string, index int) error {
+	if articleId == "" {
+		return errors.New("article_id不能为空")
+	}
+	if index < 0 {
+		return errors.New("index不能小于0")
+	}
+	token, err := self.AccessToken()
+	if err != nil {
+		return err
+	}
+	return self.client.postJSON(self.client.Endpoint.DeletePublished(token), map[string]interface{}{
+		"article_id": articleId,
+		"index":      index,
+	}, nil)
+}
+
+// GetPublishedArticle 获取已发布的图文信息
+func (self *AuthorizerClient) GetPublishedArticle(articleId string) ([]PublishedArticle, error) {
+	if articleId == "" {
+		return nil, errors.New("article_id不能为空")
+	}
+	token, err := self.AccessToken()
+	if err != nil {
+		return nil, err
+	}
+	var resp struct {
+		NewsItem []PublishedArticle `json:"news_item"`
+	}
+	err = self.client.postJSON(self.client.Endpoint.GetPublishedArticle(token), map[string]interface{}{
+		"article_id": articleId,
+	}, &resp)
+	if err != nil {
+		return nil, err
+	}
+	return resp.NewsItem, nil
+}
+
+// BatchGetPublished 分页获取已发布文章列表, count取值范围为1-20, noContent为true时不返回文章content字段
+func (self *AuthorizerClient) BatchGetPublished(offset, count int, noContent bool) (*PublishedList, error) {
+	if offset < 0 || count <= 0 || count > publishPageMax {
+		return nil, errors.New("count取值范围为1-20")
+	}
+	token, err := self.AccessToken()
+	if err != nil {
+		return nil, err
+	}
+	data := map[string]interface{}{
+		"offset": offset,
+		"count":  count,
+	}
+	if noContent {
+		data["no_content"] = 1
+	}
+	var list PublishedList
+	if err := self.client.postJSON(self.client.Endpoint.BatchGetPublished(token), data, &list); err != nil {
+		return nil, err
+	}
+	return &list, nil
+}
+
+// PublishedIterator 已发布文章迭代器, 自动翻页
+type PublishedIterator struct {
+	client    *AuthorizerClient
+	noContent bool
+	offset    int
+	total     int
+	buf       []PublishedItem
+	done      bool
+}
+
+// Published 创建已发布文章迭代器
+func (self *AuthorizerClient) Published(noContent bool) *PublishedIterator {
+	return &PublishedIterator{
+		client:    self,
+		noContent: noContent,
+		total:     -1,
+	}
+}
+
+// Next 返回下一条已发布文章, 遍历结束时第二个返回值为false
+func (self *PublishedIterator) Next() (PublishedItem, bool, error) {
+	if len(self.buf) == 0 {
+		if self.done || (self.total >= 0 && self.offset >= self.total) {
+			return PublishedItem{}, false, nil
+		}
+		list, err := self.client.BatchGetPublished(self.offset, publishPageMax, self.noContent)
+		if err != nil {
+			return PublishedItem{}, false, err
+		}
+		self.total = list.TotalCount
+		self.offset += len(list.Item)
+		self.buf = list.Item
+		if len(list.Item) < publishPageMax {
+			self.done = true
+		}
+		if len(self.buf) == 0 {
+			return PublishedItem{}, false, nil
+		}
+	}
+	item := self.buf[0]
+	self.buf = self.buf[1:]
+	return item, true, nil
+}
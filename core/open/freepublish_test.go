@@ -0,0 +1,49 @@
+package open
+
+import (
+	"fmt"
+	"testing"
+)
+
+// publishedListPage 生成第offset个起共count篇已发布文章的列表响应
+func publishedListPage(total, offset, count int) string {
+	items := pageItems(offset, count, func(i int) string {
+		return fmt.Sprintf(`{"article_id":"ARTICLE_%d","update_time":1653000000,"content":{"news_item":[{"title":"标题%d","url":"https://mp.weixin.qq.com/s/%d","is_deleted":false}]}}`, i, i, i)
+	})
+	return fmt.Sprintf(`{"total_count":%d,"item_count":%d,"item":[%s]}`, total, count, items)
+}
+
+func TestPublishedIterator(t *testing.T) {
+	server := newTestServer(t)
+	server.respondSequence("/cgi-bin/freepublish/batchget",
+		publishedListPage(21, 0, publishPageMax),
+		publishedListPage(21, publishPageMax, 1),
+	)
+	client, _ := newTestClient(t, server)
+
+	var items []PublishedItem
+	drain(t, client.Authorizer(testAuthorizerAppId).Published(false), &items)
+	if len(items) != 21 || items[20].ArticleId != "ARTICLE_20" || items[20].Content.NewsItem[0].Title != "标题20" {
+		t.Fatalf("got %+v", items)
+	}
+	bodies := server.requestBodies(t, "/cgi-bin/freepublish/batchget")
+	if len(bodies) != 2 || bodies[0]["offset"] != float64(0) || bodies[1]["offset"] != float64(publishPageMax) {
+		t.Fatalf("unexpected requests: %v", bodies)
+	}
+}
+
+func TestPublishedIteratorEmpty(t *testing.T) {
+	server := newTestServer(t)
+	server.respond("/cgi-bin/freepublish/batchget", `{"total_count":0,"item_count":0,"item":[]}`)
+	client, _ := newTestClient(t, server)
+
+	iterator := client.Authorizer(testAuthorizerAppId).Published(true)
+	for i := 0; i < 2; i++ {
+		if _, ok, err := iterator.Next(); ok || err != nil {
+			t.Fatalf("got (%v, %v), want end of iteration", ok, err)
+		}
+	}
+	if n := server.requestCount(); n != 1 {
+		t.Fatalf("got %d requests, want 1", n)
+	}
+}
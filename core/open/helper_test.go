@@ -0,0 +1,293 @@
+package open
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/mrwangjinjin/go-wechat/core"
+	"github.com/mrwangjinjin/go-wechat/core/cache/lru"
+	"image"
+	"image/color"
+	"image/png"
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+const (
+	testAppId           = "wx_component"
+	testAuthorizerAppId = "wx_authorizer"
+	testComponentToken  = "COMPONENT_TOKEN"
+	testAuthorizerToken = "AUTHORIZER_TOKEN"
+)
+
+// testPixel testPNG中唯一像素的颜色
+var testPixel = color.NRGBA{R: 0x07, G: 0xc1, B: 0x60, A: 0xff}
+
+// testPNG 返回用image/png编码的1x1图片, 用作小程序码响应
+func testPNG(t *testing.T) []byte {
+	t.Helper()
+	img := image.NewNRGBA(image.Rect(0, 0, 1, 1))
+	img.SetNRGBA(0, 0, testPixel)
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// assertTestPNG 校验data可解码为testPNG生成的图片
+func assertTestPNG(t *testing.T, data []byte) {
+	t.Helper()
+	img, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("decode png: %v", err)
+	}
+	assertTestImage(t, img)
+}
+
+// assertTestImage 校验img为testPNG生成的1x1图片
+func assertTestImage(t *testing.T, img image.Image) {
+	t.Helper()
+	if bounds := img.Bounds(); bounds.Dx() != 1 || bounds.Dy() != 1 {
+		t.Fatalf("image bounds = %v, want 1x1", bounds)
+	}
+	if got := color.NRGBAModel.Convert(img.At(0, 0)); got != testPixel {
+		t.Fatalf("pixel = %v, want %v", got, testPixel)
+	}
+}
+
+// testLogger 记录所有日志行, 并发安全
+type testLogger struct {
+	mu    sync.Mutex
+	lines []string
+}
+
+func (self *testLogger) Printf(format string, v ...interface{}) {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+	self.lines = append(self.lines, fmt.Sprintf(format, v...))
+}
+
+func (self *testLogger) String() string {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+	return strings.Join(self.lines, "\n")
+}
+
+// recordedRequest 测试服务收到的请求
+type recordedRequest struct {
+	Method   string
+	Path     string
+	Query    map[string][]string
+	RawQuery string
+	Header   http.Header
+	Body     []byte
+}
+
+// testResponse 预置的响应
+type testResponse struct {
+	contentType string
+	body        []byte
+}
+
+// testServer 记录收到的请求, 按路径返回预置响应, 未预置的路径返回{"errcode":0,"errmsg":"ok"}
+type testServer struct {
+	*httptest.Server
+	mu        sync.Mutex
+	requests  []recordedRequest
+	responses map[string]testResponse
+	// queued 按顺序返回的响应, 用完后使用responses
+	queued map[string][]testResponse
+}
+
+func newTestServer(t *testing.T) *testServer {
+	server := &testServer{responses: map[string]testResponse{}, queued: map[string][]testResponse{}}
+	server.Server = httptest.NewServer(http.HandlerFunc(server.serve))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func (self *testServer) serve(w http.ResponseWriter, r *http.Request) {
+	body, _ := ioutil.ReadAll(r.Body)
+	self.mu.Lock()
+	self.requests = append(self.requests, recordedRequest{
+		Method:   r.Method,
+		Path:     r.URL.Path,
+		Query:    r.URL.Query(),
+		RawQuery: r.URL.RawQuery,
+		Header:   r.Header,
+		Body:     body,
+	})
+	response, ok := self.responses[r.URL.Path]
+	if queued := self.queued[r.URL.Path]; len(queued) > 0 {
+		response, ok = queued[0], true
+		self.queued[r.URL.Path] = queued[1:]
+	}
+	self.mu.Unlock()
+	if !ok {
+		response = testResponse{contentType: "application/json", body: []byte(`{"errcode":0,"errmsg":"ok"}`)}
+	}
+	w.Header().Set("Content-Type", response.contentType)
+	_, _ = w.Write(response.body)
+}
+
+// respond 设置path的JSON响应体
+func (self *testServer) respond(path, body string) {
+	self.respondBinary(path, "application/json", []byte(body))
+}
+
+// respondSequence 设置path依次返回的JSON响应体, 用于翻页接口
+func (self *testServer) respondSequence(path string, bodies ...string) {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+	for _, body := range bodies {
+		self.queued[path] = append(self.queued[path], testResponse{contentType: "application/json", body: []byte(body)})
+	}
+}
+
+// respondBinary 设置path的响应类型和响应体
+func (self *testServer) respondBinary(path, contentType string, body []byte) {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+	self.responses[path] = testResponse{contentType: contentType, body: body}
+}
+
+// lastRequest 返回最后一次请求, 没有请求时测试失败
+func (self *testServer) lastRequest(t *testing.T) recordedRequest {
+	t.Helper()
+	self.mu.Lock()
+	defer self.mu.Unlock()
+	if len(self.requests) == 0 {
+		t.Fatal("no request received")
+	}
+	return self.requests[len(self.requests)-1]
+}
+
+// requestCount 返回收到的请求数
+func (self *testServer) requestCount() int {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+	return len(self.requests)
+}
+
+// newTestClient 创建请求发往测试服务的Client, 缓存中预置有效的component_access_token和authorizer_access_token
+func newTestClient(t *testing.T, server *testServer) (*Client, *testLogger) {
+	logger := &testLogger{}
+	client := NewClient(&core.ClientConfig{
+		AppId:     testAppId,
+		AppSecret: "secret",
+		BaseUrl:   server.URL,
+		Logger:    logger,
+	}, lru.NewLRUCache(0))
+	expiresIn := time.Now().Unix() + 3600
+	_ = client.Cache.SetEx(ComponentTokenCacheKeyPrefix+testAppId, map[string]interface{}{
+		"component_access_token": testComponentToken,
+		"expires_in":             expiresIn,
+	}, 3600)
+	_ = client.Cache.SetEx(AuthorizerTokenCacheKeyPrefix+testAuthorizerAppId, map[string]interface{}{
+		"authorizer_access_token":  testAuthorizerToken,
+		"authorizer_refresh_token": "REFRESH_TOKEN",
+		"expires_in":               expiresIn,
+	}, 3600)
+	return client, logger
+}
+
+// requestBodies 返回path收到的所有请求体
+func (self *testServer) requestBodies(t *testing.T, path string) []map[string]interface{} {
+	t.Helper()
+	self.mu.Lock()
+	requests := append([]recordedRequest(nil), self.requests...)
+	self.mu.Unlock()
+	var bodies []map[string]interface{}
+	for _, req := range requests {
+		if req.Path == path {
+			bodies = append(bodies, decodeBody(t, req))
+		}
+	}
+	return bodies
+}
+
+// decodeBody 将请求体解析为map, 失败时测试失败
+func decodeBody(t *testing.T, req recordedRequest) map[string]interface{} {
+	t.Helper()
+	var body map[string]interface{}
+	if err := json.Unmarshal(req.Body, &body); err != nil {
+		t.Fatalf("request body is not JSON: %v, body: %s", err, req.Body)
+	}
+	return body
+}
+
+// multipartForm 将multipart/form-data请求体解析为表单, 失败时测试失败
+func multipartForm(t *testing.T, req recordedRequest) *multipart.Form {
+	t.Helper()
+	mediaType, params, err := mime.ParseMediaType(req.Header.Get("Content-Type"))
+	if err != nil || mediaType != "multipart/form-data" {
+		t.Fatalf("Content-Type: got %q, want multipart/form-data", req.Header.Get("Content-Type"))
+	}
+	form, err := multipart.NewReader(bytes.NewReader(req.Body), params["boundary"]).ReadForm(1 << 20)
+	if err != nil {
+		t.Fatalf("read multipart form: %v", err)
+	}
+	t.Cleanup(func() { _ = form.RemoveAll() })
+	return form
+}
+
+// multipartFile 返回表单中field字段的文件名和内容, 字段不存在时测试失败
+func multipartFile(t *testing.T, form *multipart.Form, field string) (string, []byte) {
+	t.Helper()
+	files := form.File[field]
+	if len(files) != 1 {
+		t.Fatalf("multipart field %s: got %d files, want 1", field, len(files))
+	}
+	file, err := files[0].Open()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	data, err := ioutil.ReadAll(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return files[0].Filename, data
+}
+
+// maxDrainItems drain最多读取的元素数, 防止迭代器不结束时测试挂起
+const maxDrainItems = 10000
+
+// drain 反复调用iterator的Next() (T, bool, error)直到迭代结束, 将元素追加到items(*[]T), 出错时测试失败
+func drain(t *testing.T, iterator interface{}, items interface{}) {
+	t.Helper()
+	next := reflect.ValueOf(iterator).MethodByName("Next")
+	if !next.IsValid() {
+		t.Fatalf("%T没有Next方法", iterator)
+	}
+	slice := reflect.ValueOf(items).Elem()
+	for i := 0; i < maxDrainItems; i++ {
+		out := next.Call(nil)
+		if err, _ := out[2].Interface().(error); err != nil {
+			t.Fatal(err)
+		}
+		if !out[1].Bool() {
+			return
+		}
+		slice.Set(reflect.Append(slice, out[0]))
+	}
+	t.Fatalf("%T超过%d个元素仍未结束", iterator, maxDrainItems)
+}
+
+// pageItems 用item生成第offset个起共count个元素并以逗号连接, 用于构造翻页接口的响应
+func pageItems(offset, count int, item func(i int) string) string {
+	items := make([]string, 0, count)
+	for i := offset; i < offset+count; i++ {
+		items = append(items, item(i))
+	}
+	return strings.Join(items, ",")
+}
@@ -0,0 +1,263 @@
+package open
+
+import (
+	"errors"
+	"io"
+	"strconv"
+)
+
+// 备案状态
+const (
+	IcpStatusNone             = 0 // 未备案
+	IcpStatusAuditing         = 2 // 平台审核中
+	IcpStatusRejected         = 3 // 平台审核驳回
+	IcpStatusProvince         = 4 // 管局审核中
+	IcpStatusProvinceRejected = 5 // 管局审核驳回
+	IcpStatusFinished         = 6 // 已备案
+)
+
+// 取消备案类型
+const (
+	IcpCancelSubject = 1 // 注销主体
+	IcpCancelApp     = 2 // 注销小程序
+	IcpCancelService = 3 // 取消接入
+)
+
+// IcpClient 小程序备案接口
+type IcpClient struct {
+	authorizer *AuthorizerClient
+}
+
+// Icp 小程序备案
+func (self *AuthorizerClient) Icp() *IcpClient {
+	return &IcpClient{authorizer: self}
+}
+
+// IcpAuditData 备案驳回信息
+type IcpAuditData struct {
+	KeyName string `json:"key_name"`
+	Error   string `json:"error"`
+	Suggest string `json:"suggest"`
+}
+
+// IcpEntranceInfo 备案入口信息
+type IcpEntranceInfo struct {
+	Status    int            `json:"status"`
+	IsCombine bool           `json:"is_combine"`
+	AuditData []IcpAuditData `json:"audit_data"`
+}
+
+// IcpVerifyTask 人脸核身任务
+type IcpVerifyTask struct {
+	TaskId    string `json:"task_id"`
+	VerifyUrl string `json:"verify_url"`
+}
+
+// IcpVerifyTaskStatus 人脸核身任务状态
+type IcpVerifyTaskStatus struct {
+	IsFinish   bool `json:"is_finish"`
+	FaceStatus int  `json:"face_status"`
+}
+
+// IcpSubjectBaseInfo 备案主体基本信息
+type IcpSubjectBaseInfo struct {
+	Type     int    `json:"type"`
+	Name     string `json:"name"`
+	Province string `json:"province"`
+	City     string `json:"city"`
+	District string `json:"district"`
+	Address  string `json:"address"`
+	Comment  string `json:"comment,omitempty"`
+}
+
+// IcpPersonalInfo 个人主体信息
+type IcpPersonalInfo struct {
+	ResidencePermit string `json:"residence_permit,omitempty"`
+}
+
+// IcpOrganizeInfo 单位主体信息, 证件照片为UploadMedia返回的media_id
+type IcpOrganizeInfo struct {
+	CertificateType    int    `json:"certificate_type"`
+	CertificateNumber  string `json:"certificate_number"`
+	CertificateAddress string `json:"certificate_address"`
+	CertificatePhoto   string `json:"certificate_photo,omitempty"`
+}
+
+// IcpPrincipalInfo 负责人信息, 证件照片为UploadMedia返回的media_id
+type IcpPrincipalInfo struct {
+	Name                         string `json:"name"`
+	Mobile                       string `json:"mobile"`
+	Email                        string `json:"email"`
+	EmergencyContact             string `json:"emergency_contact,omitempty"`
+	EmergencyMobile              string `json:"emergency_mobile,omitempty"`
+	CertificateType              int    `json:"certificate_type"`
+	CertificateNumber            string `json:"certificate_number"`
+	CertificateValidityDateStart string `json:"certificate_validity_date_start"`
+	CertificateValidityDateEnd   string `json:"certificate_validity_date_end"`
+	CertificatePhotoFront        string `json:"certificate_photo_front"`
+	CertificatePhotoBack         string `json:"certificate_photo_back"`
+	AuthorizationLetter          string `json:"authorization_letter,omitempty"`
+	VerifyTaskId                 string `json:"verify_task_id"`
+}
+
+// IcpLegalPersonInfo 法人信息
+type IcpLegalPersonInfo struct {
+	Name              string `json:"name"`
+	CertificateNumber string `json:"certificate_number"`
+}
+
+// IcpSubject 备案主体信息
+type IcpSubject struct {
+	BaseInfo        IcpSubjectBaseInfo  `json:"base_info"`
+	PersonalInfo    *IcpPersonalInfo    `json:"personal_info,omitempty"`
+	OrganizeInfo    *IcpOrganizeInfo    `json:"organize_info,omitempty"`
+	PrincipalInfo   IcpPrincipalInfo    `json:"principal_info"`
+	LegalPersonInfo *IcpLegalPersonInfo `json:"legal_person_info,omitempty"`
+}
+
+// IcpNrlxDetail 前置审批项
+type IcpNrlxDetail struct {
+	Type  int    `json:"type"`
+	Media string `json:"media,omitempty"`
+}
+
+// IcpAppletBaseInfo 小程序基本信息
+type IcpAppletBaseInfo struct {
+	ServiceContentTypes []int           `json:"service_content_types"`
+	NrlxDetails         []IcpNrlxDetail `json:"nrlx_details,omitempty"`
+	Comment             string          `json:"comment,omitempty"`
+}
+
+// IcpApplets 小程序备案信息
+type IcpApplets struct {
+	BaseInfo      IcpAppletBaseInfo `json:"base_info"`
+	PrincipalInfo IcpPrincipalInfo  `json:"principal_info"`
+}
+
+// IcpMaterials 备案补充材料, 均为UploadMedia返回的media_id
+type IcpMaterials struct {
+	CommitmentLetter                []string `json:"commitment_letter,omitempty"`
+	BusinessNameChangeLetter        []string `json:"business_name_change_letter,omitempty"`
+	PartyBuildingConfirmationLetter []string `json:"party_building_confirmation_letter,omitempty"`
+	HoldingConfirmationLetter       []string `json:"holding_confirmation_letter,omitempty"`
+	PrincipalAuthorizationLetter    []string `json:"principal_authorization_letter,omitempty"`
+	LegalPersonAuthorizationLetter  []string `json:"legal_person_authorization_letter,omitempty"`
+	OtherMaterials                  []string `json:"other_materials,omitempty"`
+}
+
+// IcpApplication 备案申请
+type IcpApplication struct {
+	IcpSubject   *IcpSubject   `json:"icp_subject"`
+	IcpApplets   *IcpApplets   `json:"icp_applets"`
+	IcpMaterials *IcpMaterials `json:"icp_materials,omitempty"`
+}
+
+// GetEntranceInfo 查询备案入口信息
+func (self *IcpClient) GetEntranceInfo() (*IcpEntranceInfo, error) {
+	token, err := self.authorizer.AccessToken()
+	if err != nil {
+		return nil, err
+	}
+	var resp struct {
+		Info IcpEntranceInfo `json:"info"`
+	}
+	if err := self.authorizer.client.getJSON(self.authorizer.client.Endpoint.QueryIcpEntranceInfo(token), &resp); err != nil {
+		return nil, err
+	}
+	return &resp.Info, nil
+}
+
+// GetApplicationStatus 查询备案审核状态, 驳回时返回驳回信息
+func (self *IcpClient) GetApplicationStatus() (int, []IcpAuditData, error) {
+	info, err := self.GetEntranceInfo()
+	if err != nil {
+		return 0, nil, err
+	}
+	return info.Status, info.AuditData, nil
+}
+
+// CreateVerifyTask 发起人脸核身任务, alongWithAuth为true时与授权同时进行
+func (self *IcpClient) CreateVerifyTask(alongWithAuth bool) (*IcpVerifyTask, error) {
+	token, err := self.authorizer.AccessToken()
+	if err != nil {
+		return nil, err
+	}
+	var task IcpVerifyTask
+	err = self.authorizer.client.postJSON(self.authorizer.client.Endpoint.CreateIcpVerifyTask(token), map[string]interface{}{
+		"along_with_auth": alongWithAuth,
+	}, &task)
+	if err != nil {
+		return nil, err
+	}
+	return &task, nil
+}
+
+// QueryVerifyTask 查询人脸核身任务状态
+func (self *IcpClient) QueryVerifyTask(taskId string) (*IcpVerifyTaskStatus, error) {
+	if taskId == "" {
+		return nil, errors.New("task_id不能为空")
+	}
+	token, err := self.authorizer.AccessToken()
+	if err != nil {
+		return nil, err
+	}
+	var status IcpVerifyTaskStatus
+	err = self.authorizer.client.postJSON(self.authorizer.client.Endpoint.QueryIcpVerifyTask(token), map[string]interface{}{
+		"task_id": taskId,
+	}, &status)
+	if err != nil {
+		return nil, err
+	}
+	return &status, nil
+}
+
+// UploadMedia 上传备案材料, mediaType为image/video, 返回media_id
+func (self *IcpClient) UploadMedia(mediaType string, certificateType int, icpOrderField string, r io.Reader, filename string) (string, error) {
+	if mediaType == "" || filename == "" {
+		return "", errors.New("素材类型和文件名不能为空")
+	}
+	token, err := self.authorizer.AccessToken()
+	if err != nil {
+		return "", err
+	}
+	fields := map[string]string{
+		"type":            mediaType,
+		"icp_order_field": icpOrderField,
+	}
+	if certificateType > 0 {
+		fields["certificate_type"] = strconv.Itoa(certificateType)
+	}
+	var resp struct {
+		MediaId string `json:"media_id"`
+	}
+	if err := self.authorizer.client.postMultipart(self.authorizer.client.Endpoint.UploadIcpMedia(token), "media", filename, r, fields, &resp); err != nil {
+		return "", err
+	}
+	return resp.MediaId, nil
+}
+
+// SubmitApplication 提交备案申请
+func (self *IcpClient) SubmitApplication(req IcpApplication) error {
+	if req.IcpSubject == nil || req.IcpApplets == nil {
+		return errors.New("备案主体和小程序信息不能为空")
+	}
+	token, err := self.authorizer.AccessToken()
+	if err != nil {
+		return err
+	}
+	return self.authorizer.client.postJSON(self.authorizer.client.Endpoint.ApplyIcpFiling(token), req, nil)
+}
+
+// CancelApplication 撤回备案申请
+func (self *IcpClient) CancelApplication(cancelType int) error {
+	if cancelType < IcpCancelSubject || cancelType > IcpCancelService {
+		return errors.New("cancel_type取值范围为1-3")
+	}
+	token, err := self.authorizer.AccessToken()
+	if err != nil {
+		return err
+	}
+	return self.authorizer.client.postJSON(self.authorizer.client.Endpoint.CancelApplyIcpFiling(token), map[string]interface{}{
+		"cancel_type": cancelType,
+	}, nil)
+}
@@ -0,0 +1,228 @@
+package open
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestIcpGetEntranceInfo(t *testing.T) {
+	server := newTestServer(t)
+	server.respond("/wxa/icp/query_icp_entrance_info", `{"errcode":0,"errmsg":"ok","info":{"status":3,"is_combine":false,"audit_data":[{"key_name":"icp_subject.principal_info.certificate_photo_front","error":"证件照片模糊","suggest":"请上传清晰的证件照片"}]}}`)
+	client, _ := newTestClient(t, server)
+	icp := client.Authorizer(testAuthorizerAppId).Icp()
+
+	info, err := icp.GetEntranceInfo()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := server.lastRequest(t).Query["access_token"]; len(got) != 1 || got[0] != testAuthorizerToken {
+		t.Fatalf("access_token: got %v", got)
+	}
+	want := IcpAuditData{KeyName: "icp_subject.principal_info.certificate_photo_front", Error: "证件照片模糊", Suggest: "请上传清晰的证件照片"}
+	if info.Status != IcpStatusRejected || info.IsCombine || len(info.AuditData) != 1 || info.AuditData[0] != want {
+		t.Fatalf("info: got %+v", info)
+	}
+
+	status, auditData, err := icp.GetApplicationStatus()
+	if err != nil || status != IcpStatusRejected || len(auditData) != 1 {
+		t.Fatalf("got (%d, %+v, %v), want rejected status", status, auditData, err)
+	}
+}
+
+func TestIcpVerifyTask(t *testing.T) {
+	server := newTestServer(t)
+	server.respond("/wxa/icp/create_icp_verifytask", `{"errcode":0,"errmsg":"ok","task_id":"R5PqRPNb6GmG3i0rqd4pTg","verify_url":"https://example.com/verify"}`)
+	server.respond("/wxa/icp/query_icp_verifytask", `{"errcode":0,"errmsg":"ok","is_finish":true,"face_status":2}`)
+	client, _ := newTestClient(t, server)
+	icp := client.Authorizer(testAuthorizerAppId).Icp()
+
+	task, err := icp.CreateVerifyTask(true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *task != (IcpVerifyTask{TaskId: "R5PqRPNb6GmG3i0rqd4pTg", VerifyUrl: "https://example.com/verify"}) {
+		t.Fatalf("task: got %+v", task)
+	}
+	if body := string(server.lastRequest(t).Body); body != `{"along_with_auth":true}` {
+		t.Fatalf("create body: got %s", body)
+	}
+
+	status, err := icp.QueryVerifyTask(task.TaskId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !status.IsFinish || status.FaceStatus != 2 {
+		t.Fatalf("status: got %+v", status)
+	}
+	if body := string(server.lastRequest(t).Body); body != `{"task_id":"R5PqRPNb6GmG3i0rqd4pTg"}` {
+		t.Fatalf("query body: got %s", body)
+	}
+
+	if _, err := icp.QueryVerifyTask(""); err == nil {
+		t.Fatal("empty task_id: expected validation error")
+	}
+	if n := server.requestCount(); n != 2 {
+		t.Fatalf("sent %d requests, want 2", n)
+	}
+}
+
+func TestIcpUploadMedia(t *testing.T) {
+	server := newTestServer(t)
+	server.respond("/wxa/icp/upload_icp_media", `{"errcode":0,"errmsg":"ok","type":"image","media_id":"ICP_MEDIA_ID","created_at":1704902400}`)
+	client, _ := newTestClient(t, server)
+
+	mediaId, err := client.Authorizer(testAuthorizerAppId).Icp().UploadMedia("image", 2, "icp_subject.principal_info.certificate_photo_front", bytes.NewReader(testPNG(t)), "front.png")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mediaId != "ICP_MEDIA_ID" {
+		t.Fatalf("media_id: got %s", mediaId)
+	}
+	form := multipartForm(t, server.lastRequest(t))
+	for field, want := range map[string]string{
+		"type":             "image",
+		"certificate_type": "2",
+		"icp_order_field":  "icp_subject.principal_info.certificate_photo_front",
+	} {
+		if got := form.Value[field]; len(got) != 1 || got[0] != want {
+			t.Errorf("field %s: got %v, want %s", field, got, want)
+		}
+	}
+	filename, data := multipartFile(t, form, "media")
+	if filename != "front.png" {
+		t.Fatalf("filename: got %s", filename)
+	}
+	assertTestPNG(t, data)
+}
+
+func TestIcpUploadMediaOmitsCertificateType(t *testing.T) {
+	server := newTestServer(t)
+	server.respond("/wxa/icp/upload_icp_media", `{"errcode":0,"errmsg":"ok","media_id":"ICP_MEDIA_ID"}`)
+	client, _ := newTestClient(t, server)
+
+	if _, err := client.Authorizer(testAuthorizerAppId).Icp().UploadMedia("image", 0, "icp_materials.commitment_letter", bytes.NewReader(testPNG(t)), "letter.png"); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := multipartForm(t, server.lastRequest(t)).Value["certificate_type"]; ok {
+		t.Fatal("certificate_type must be omitted when zero")
+	}
+}
+
+func TestIcpSubmitApplication(t *testing.T) {
+	server := newTestServer(t)
+	client, _ := newTestClient(t, server)
+	principal := IcpPrincipalInfo{
+		Name:                         "张三",
+		Mobile:                       "13800000000",
+		Email:                        "zhangsan@example.com",
+		CertificateType:              2,
+		CertificateNumber:            "440105199001010000",
+		CertificateValidityDateStart: "20200101",
+		CertificateValidityDateEnd:   "20400101",
+		CertificatePhotoFront:        "FRONT_MEDIA",
+		CertificatePhotoBack:         "BACK_MEDIA",
+		VerifyTaskId:                 "TASK_ID",
+	}
+
+	err := client.Authorizer(testAuthorizerAppId).Icp().SubmitApplication(IcpApplication{
+		IcpSubject: &IcpSubject{
+			BaseInfo: IcpSubjectBaseInfo{
+				Type:     5,
+				Name:     "张三",
+				Province: "440000",
+				City:     "440100",
+				District: "440105",
+				Address:  "新港中路397号",
+			},
+			PrincipalInfo: principal,
+		},
+		IcpApplets: &IcpApplets{
+			BaseInfo:      IcpAppletBaseInfo{ServiceContentTypes: []int{1}},
+			PrincipalInfo: principal,
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	req := server.lastRequest(t)
+	if req.Path != "/wxa/icp/apply_icp_filing" {
+		t.Fatalf("path: got %s", req.Path)
+	}
+	principalJSON := `{"name":"张三","mobile":"13800000000","email":"zhangsan@example.com","certificate_type":2,"certificate_number":"440105199001010000","certificate_validity_date_start":"20200101","certificate_validity_date_end":"20400101","certificate_photo_front":"FRONT_MEDIA","certificate_photo_back":"BACK_MEDIA","verify_task_id":"TASK_ID"}`
+	want := `{"icp_subject":{"base_info":{"type":5,"name":"张三","province":"440000","city":"440100","district":"440105","address":"新港中路397号"},"principal_info":` + principalJSON + `},` +
+		`"icp_applets":{"base_info":{"service_content_types":[1]},"principal_info":` + principalJSON + `}}`
+	if string(req.Body) != want {
+		t.Fatalf("body:\n got %s\nwant %s", req.Body, want)
+	}
+}
+
+func TestIcpSubmitApplicationWithMaterials(t *testing.T) {
+	server := newTestServer(t)
+	client, _ := newTestClient(t, server)
+
+	err := client.Authorizer(testAuthorizerAppId).Icp().SubmitApplication(IcpApplication{
+		IcpSubject: &IcpSubject{
+			BaseInfo:        IcpSubjectBaseInfo{Type: 1},
+			OrganizeInfo:    &IcpOrganizeInfo{CertificateType: 1, CertificateNumber: "91440101MA00000000", CertificateAddress: "广州市", CertificatePhoto: "LICENSE_MEDIA"},
+			LegalPersonInfo: &IcpLegalPersonInfo{Name: "李四", CertificateNumber: "440105198001010000"},
+		},
+		IcpApplets: &IcpApplets{
+			BaseInfo: IcpAppletBaseInfo{ServiceContentTypes: []int{1, 2}, NrlxDetails: []IcpNrlxDetail{{Type: 3, Media: "NRLX_MEDIA"}}},
+		},
+		IcpMaterials: &IcpMaterials{CommitmentLetter: []string{"LETTER_MEDIA"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	body := decodeBody(t, server.lastRequest(t))
+	subject := body["icp_subject"].(map[string]interface{})
+	if _, ok := subject["personal_info"]; ok {
+		t.Fatal("personal_info must be omitted for organizations")
+	}
+	if organize := subject["organize_info"].(map[string]interface{}); organize["certificate_photo"] != "LICENSE_MEDIA" {
+		t.Fatalf("organize_info: got %v", organize)
+	}
+	if legal := subject["legal_person_info"].(map[string]interface{}); legal["name"] != "李四" {
+		t.Fatalf("legal_person_info: got %v", legal)
+	}
+	materials := body["icp_materials"].(map[string]interface{})
+	if len(materials) != 1 || materials["commitment_letter"].([]interface{})[0] != "LETTER_MEDIA" {
+		t.Fatalf("icp_materials: got %v, want only commitment_letter", materials)
+	}
+	nrlx := body["icp_applets"].(map[string]interface{})["base_info"].(map[string]interface{})["nrlx_details"].([]interface{})
+	if len(nrlx) != 1 || nrlx[0].(map[string]interface{})["media"] != "NRLX_MEDIA" {
+		t.Fatalf("nrlx_details: got %v", nrlx)
+	}
+}
+
+func TestIcpValidation(t *testing.T) {
+	server := newTestServer(t)
+	client, _ := newTestClient(t, server)
+	icp := client.Authorizer(testAuthorizerAppId).Icp()
+
+	if err := icp.SubmitApplication(IcpApplication{IcpApplets: &IcpApplets{}}); err == nil {
+		t.Error("missing subject: expected validation error")
+	}
+	if err := icp.SubmitApplication(IcpApplication{IcpSubject: &IcpSubject{}}); err == nil {
+		t.Error("missing applets: expected validation error")
+	}
+	if err := icp.CancelApplication(0); err == nil {
+		t.Error("cancel_type 0: expected validation error")
+	}
+	if err := icp.CancelApplication(4); err == nil {
+		t.Error("cancel_type 4: expected validation error")
+	}
+	if _, err := icp.UploadMedia("", 0, "", bytes.NewReader(nil), "a.png"); err == nil {
+		t.Error("missing media type: expected validation error")
+	}
+	if n := server.requestCount(); n != 0 {
+		t.Fatalf("invalid requests sent %d requests", n)
+	}
+
+	if err := icp.CancelApplication(IcpCancelApp); err != nil {
+		t.Fatal(err)
+	}
+	if body := string(server.lastRequest(t).Body); body != `{"cancel_type":2}` {
+		t.Fatalf("cancel body: got %s", body)
+	}
+}
@@ -0,0 +1,128 @@
+package open
+
+import (
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/mrwangjinjin/go-wechat/pkg/util"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ticketAhead 票据提前过期的时间(秒)
+const ticketAhead = 200
+
+// JsapiSignature wx.config所需的签名信息
+type JsapiSignature struct {
+	AppId     string `json:"appId"`
+	Timestamp int64  `json:"timestamp"`
+	NonceStr  string `json:"nonceStr"`
+	Signature string `json:"signature"`
+}
+
+// GetJsapiTicket 获取授权方的jsapi_ticket, 过期时刷新, 并发刷新只请求一次
+func (self *AuthorizerClient) GetJsapiTicket() (string, error) {
+	return self.getTicket("jsapi", JsapiTicketCacheKeyPrefix+self.AuthorizerAppId)
+}
+
+// getTicket 获取指定类型的票据, 缓存过期时刷新
+func (self *AuthorizerClient) getTicket(ticketType, key string) (string, error) {
+	if ticket := self.cachedTicket(key); ticket != "" {
+		return ticket, nil
+	}
+	value, err, _ := self.client.flight.Do(key, func() (interface{}, error) {
+		if ticket := self.cachedTicket(key); ticket != "" {
+			return ticket, nil
+		}
+		return self.refreshTicket(ticketType, key)
+	})
+	if err != nil {
+		return "", err
+	}
+	return value.(string), nil
+}
+
+func (self *AuthorizerClient) cachedTicket(key string) string {
+	if !self.client.Cache.Exists(key) {
+		return ""
+	}
+	resp, err := self.client.Cache.Get(key)
+	if err != nil {
+		return ""
+	}
+	cached := util.JsonUnmarshal(resp)
+	ticket, _ := cached["ticket"].(string)
+	expiresIn, _ := cached["expires_in"].(float64)
+	if ticket == "" || time.Now().Unix() >= int64(expiresIn) {
+		return ""
+	}
+	return ticket
+}
+
+func (self *AuthorizerClient) refreshTicket(ticketType, key string) (string, error) {
+	token, err := self.AccessToken()
+	if err != nil {
+		return "", err
+	}
+	var resp struct {
+		Ticket    string `json:"ticket"`
+		ExpiresIn int64  `json:"expires_in"`
+	}
+	if err := self.client.getJSON(self.client.Endpoint.GetTicket(token, ticketType), &resp); err != nil {
+		return "", err
+	}
+	if resp.Ticket == "" {
+		return "", errors.New("响应缺少ticket")
+	}
+	expires := resp.ExpiresIn - ticketAhead
+	if expires > 0 {
+		self.client.cacheSetEx(key, map[string]interface{}{
+			"ticket":     resp.Ticket,
+			"expires_in": time.Now().Unix() + expires,
+		}, expires)
+	}
+	return resp.Ticket, nil
+}
+
+// JsapiSign 生成wx.config签名, url为当前网页的完整地址, #及其后的部分会被去除
+func (self *AuthorizerClient) JsapiSign(url string) (*JsapiSignature, error) {
+	if i := strings.IndexByte(url, '#'); i >= 0 {
+		url = url[:i]
+	}
+	if url == "" {
+		return nil, errors.New("url不能为空")
+	}
+	ticket, err := self.GetJsapiTicket()
+	if err != nil {
+		return nil, err
+	}
+	nonce, err := randomNonce()
+	if err != nil {
+		return nil, err
+	}
+	signature := &JsapiSignature{
+		AppId:     self.AuthorizerAppId,
+		Timestamp: time.Now().Unix(),
+		NonceStr:  nonce,
+	}
+	signature.Signature = jsapiSignature(ticket, signature.NonceStr, signature.Timestamp, url)
+	return signature, nil
+}
+
+// jsapiSignature 按字典序拼接参数后计算sha1
+func jsapiSignature(ticket, nonceStr string, timestamp int64, url string) string {
+	plain := fmt.Sprintf("jsapi_ticket=%s&noncestr=%s&timestamp=%s&url=%s", ticket, nonceStr, strconv.FormatInt(timestamp, 10), url)
+	sum := sha1.Sum([]byte(plain))
+	return hex.EncodeToString(sum[:])
+}
+
+func randomNonce() (string, error) {
+	nonce := make([]byte, 8)
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(nonce), nil
+}
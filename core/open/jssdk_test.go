@@ -0,0 +1,106 @@
+package open
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+const testJsapiTicket = "sM4AOVdWfPE4DxkXGEs8VMCPGGVi4C3VM0P37wVUCFvkVAy_90u5h9nbSlYy3-Sl-HhTdfl2fzFy1AOcHKP7qg"
+
+func TestJsapiSignatureKnownVector(t *testing.T) {
+	// 微信JS-SDK文档附录中的签名示例
+	got := jsapiSignature(testJsapiTicket, "Wm3WZYTPz0wzccnW", 1414587457, "http://mp.weixin.qq.com?params=value")
+	if want := "0f9de62fce790f9a083d5c99e95740ceb90c27ed"; got != want {
+		t.Fatalf("signature: got %s, want %s", got, want)
+	}
+}
+
+func TestGetJsapiTicketCached(t *testing.T) {
+	server := newTestServer(t)
+	server.respond("/cgi-bin/ticket/getticket", `{"errcode":0,"errmsg":"ok","ticket":"`+testJsapiTicket+`","expires_in":7200}`)
+	client, _ := newTestClient(t, server)
+	authorizer := client.Authorizer(testAuthorizerAppId)
+
+	for i := 0; i < 2; i++ {
+		ticket, err := authorizer.GetJsapiTicket()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ticket != testJsapiTicket {
+			t.Fatalf("ticket: got %s", ticket)
+		}
+	}
+	if n := server.requestCount(); n != 1 {
+		t.Fatalf("sent %d requests, want 1", n)
+	}
+	req := server.lastRequest(t)
+	if req.Method != "GET" || req.RawQuery != "access_token="+testAuthorizerToken+"&type=jsapi" {
+		t.Fatalf("request: %s %s", req.Method, req.RawQuery)
+	}
+	if !client.Cache.Exists(JsapiTicketCacheKeyPrefix + testAuthorizerAppId) {
+		t.Fatal("ticket was not cached under the authorizer key")
+	}
+}
+
+func TestGetJsapiTicketExpired(t *testing.T) {
+	server := newTestServer(t)
+	server.respond("/cgi-bin/ticket/getticket", `{"errcode":0,"errmsg":"ok","ticket":"NEW_TICKET","expires_in":7200}`)
+	client, _ := newTestClient(t, server)
+	_ = client.Cache.SetEx(JsapiTicketCacheKeyPrefix+testAuthorizerAppId, map[string]interface{}{
+		"ticket":     "OLD_TICKET",
+		"expires_in": time.Now().Unix() - 1,
+	}, 3600)
+
+	ticket, err := client.Authorizer(testAuthorizerAppId).GetJsapiTicket()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ticket != "NEW_TICKET" {
+		t.Fatalf("ticket: got %s, want NEW_TICKET", ticket)
+	}
+}
+
+func TestGetJsapiTicketConcurrent(t *testing.T) {
+	server := newTestServer(t)
+	server.respond("/cgi-bin/ticket/getticket", `{"errcode":0,"errmsg":"ok","ticket":"`+testJsapiTicket+`","expires_in":7200}`)
+	client, _ := newTestClient(t, server)
+	authorizer := client.Authorizer(testAuthorizerAppId)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if ticket, err := authorizer.GetJsapiTicket(); err != nil || ticket != testJsapiTicket {
+				t.Errorf("got (%s, %v)", ticket, err)
+			}
+		}()
+	}
+	wg.Wait()
+	if n := server.requestCount(); n != 1 {
+		t.Fatalf("concurrent refresh sent %d requests, want 1", n)
+	}
+}
+
+func TestJsapiSign(t *testing.T) {
+	server := newTestServer(t)
+	server.respond("/cgi-bin/ticket/getticket", `{"errcode":0,"errmsg":"ok","ticket":"`+testJsapiTicket+`","expires_in":7200}`)
+	client, _ := newTestClient(t, server)
+	authorizer := client.Authorizer(testAuthorizerAppId)
+
+	signature, err := authorizer.JsapiSign("https://example.com/page?a=1#/detail?id=2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if signature.AppId != testAuthorizerAppId || signature.NonceStr == "" || signature.Timestamp == 0 {
+		t.Fatalf("signature: got %+v", signature)
+	}
+	if want := jsapiSignature(testJsapiTicket, signature.NonceStr, signature.Timestamp, "https://example.com/page?a=1"); signature.Signature != want {
+		t.Fatalf("signature must be computed without the fragment: got %s, want %s", signature.Signature, want)
+	}
+
+	if _, err := authorizer.JsapiSign("#/detail"); err == nil {
+		t.Fatal("fragment only: expected validation error")
+	}
+}
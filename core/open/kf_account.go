@@ -0,0 +1,122 @@
+package open
+
+import (
+	"errors"
+	"io"
+	"net/url"
+	"regexp"
+)
+
+// kfAccountPattern 客服账号格式: 最多10个字符的账号前缀@公众号微信号
+var kfAccountPattern = regexp.MustCompile(`^[0-9A-Za-z_]{1,10}@[0-9A-Za-z_-]+$`)
+
+// checkKfAccount 校验客服账号格式
+func checkKfAccount(kfAccount string) error {
+	if !kfAccountPattern.MatchString(kfAccount) {
+		return errors.New("kf_account格式应为账号前缀@公众号微信号, 前缀最多10个字母、数字或下划线")
+	}
+	return nil
+}
+
+// KfAccount 公众号客服账号, Status仅在线客服列表返回: 1为web在线
+type KfAccount struct {
+	KfAccount    string `json:"kf_account"`
+	KfNick       string `json:"kf_nick"`
+	KfId         string `json:"kf_id"`
+	KfHeadImgUrl string `json:"kf_headimgurl"`
+	Status       int    `json:"status"`
+	AcceptedCase int    `json:"accepted_case"`
+}
+
+// kfAccountRequest 添加或修改客服账号, kf_account格式为账号前缀@公众号微信号
+func (self *AuthorizerClient) kfAccountRequest(endpoint, kfAccount, nickname, password string) error {
+	if err := checkKfAccount(kfAccount); err != nil {
+		return err
+	}
+	if nickname == "" {
+		return errors.New("nickname不能为空")
+	}
+	data := map[string]interface{}{
+		"kf_account": kfAccount,
+		"nickname":   nickname,
+	}
+	if password != "" {
+		data["password"] = password
+	}
+	return self.client.postJSON(endpoint, data, nil)
+}
+
+// AddKfAccount 添加客服账号
+func (self *AuthorizerClient) AddKfAccount(kfAccount, nickname, password string) error {
+	token, err := self.AccessToken()
+	if err != nil {
+		return err
+	}
+	return self.kfAccountRequest(self.client.Endpoint.AddKfAccount(token), kfAccount, nickname, password)
+}
+
+// UpdateKfAccount 修改客服账号
+func (self *AuthorizerClient) UpdateKfAccount(kfAccount, nickname, password string) error {
+	token, err := self.AccessToken()
+	if err != nil {
+		return err
+	}
+	return self.kfAccountRequest(self.client.Endpoint.UpdateKfAccount(token), kfAccount, nickname, password)
+}
+
+// DeleteKfAccount 删除客服账号, kf_account通过查询参数传递
+func (self *AuthorizerClient) DeleteKfAccount(kfAccount string) error {
+	if err := checkKfAccount(kfAccount); err != nil {
+		return err
+	}
+	token, err := self.AccessToken()
+	if err != nil {
+		return err
+	}
+	return self.client.getJSON(self.client.Endpoint.DeleteKfAccount(token, url.QueryEscape(kfAccount)), nil)
+}
+
+// UploadKfHeadImg 上传客服头像, 建议使用640*640的jpg图片
+func (self *AuthorizerClient) UploadKfHeadImg(kfAccount string, r io.Reader, filename string) error {
+	if err := checkKfAccount(kfAccount); err != nil {
+		return err
+	}
+	if filename == "" {
+		return errors.New("文件名不能为空")
+	}
+	token, err := self.AccessToken()
+	if err != nil {
+		return err
+	}
+	return self.client.postMultipart(self.client.Endpoint.UploadKfHeadImg(token, url.QueryEscape(kfAccount)), "media", filename, r, nil, nil)
+}
+
+// GetKfList 获取所有客服账号
+func (self *AuthorizerClient) GetKfList() ([]KfAccount, error) {
+	token, err := self.AccessToken()
+	if err != nil {
+		return nil, err
+	}
+	var resp struct {
+		KfList []KfAccount `json:"kf_list"`
+	}
+	if err := self.client.getJSON(self.client.Endpoint.GetKfList(token), &resp); err != nil {
+		return nil, err
+	}
+	return resp.KfList, nil
+}
+
+// GetOnlineKfList 获取在线客服列表
+func (self *AuthorizerClient) GetOnlineKfList() ([]KfAccount, error) {
+	token, err := self.AccessToken()
+	if err != nil {
+		return nil, err
+	}
+	var resp struct {
+		KfOnlineList []KfAccount `json:"kf_online_list"`
+	}
+	if err := self.client.getJSON(self.client.Endpoint.GetOnlineKfList(token), &resp); err != nil {
+		return nil, err
+	}
+	return resp.KfOnlineList, nil
+}
@@ -0,0 +1,104 @@
+package open
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGetKfList(t *testing.T) {
+	server := newTestServer(t)
+	server.respond("/cgi-bin/customservice/getkflist", `{"kf_list":[{"kf_account":"test1@test","kf_nick":"ntest1","kf_id":"1001","kf_headimgurl":"http://mmbiz.qpic.cn/mmbiz/1"},{"kf_account":"test2@test","kf_nick":"ntest2","kf_id":"1002","kf_headimgurl":""}]}`)
+	client, _ := newTestClient(t, server)
+
+	accounts, err := client.Authorizer(testAuthorizerAppId).GetKfList()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []KfAccount{
+		{KfAccount: "test1@test", KfNick: "ntest1", KfId: "1001", KfHeadImgUrl: "http://mmbiz.qpic.cn/mmbiz/1"},
+		{KfAccount: "test2@test", KfNick: "ntest2", KfId: "1002"},
+	}
+	if len(accounts) != len(want) || accounts[0] != want[0] || accounts[1] != want[1] {
+		t.Fatalf("got %+v, want %+v", accounts, want)
+	}
+	if req := server.lastRequest(t); req.Method != "GET" || req.Query["access_token"][0] != testAuthorizerToken {
+		t.Fatalf("unexpected request: %s %v", req.Method, req.Query)
+	}
+}
+
+func TestGetOnlineKfList(t *testing.T) {
+	server := newTestServer(t)
+	server.respond("/cgi-bin/customservice/getonlinekflist", `{"kf_online_list":[{"kf_account":"test1@test","status":1,"kf_id":"1001","accepted_case":1}]}`)
+	client, _ := newTestClient(t, server)
+
+	accounts, err := client.Authorizer(testAuthorizerAppId).GetOnlineKfList()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(accounts) != 1 || accounts[0] != (KfAccount{KfAccount: "test1@test", KfId: "1001", Status: 1, AcceptedCase: 1}) {
+		t.Fatalf("got %+v", accounts)
+	}
+}
+
+func TestDeleteKfAccountUsesQuery(t *testing.T) {
+	server := newTestServer(t)
+	client, _ := newTestClient(t, server)
+
+	if err := client.Authorizer(testAuthorizerAppId).DeleteKfAccount("test1@gh_test"); err != nil {
+		t.Fatal(err)
+	}
+	req := server.lastRequest(t)
+	if req.Path != "/customservice/kfaccount/del" || req.Method != "GET" {
+		t.Fatalf("unexpected request: %s %s", req.Method, req.Path)
+	}
+	if len(req.Body) != 0 {
+		t.Fatalf("delete must not send a body, got %s", req.Body)
+	}
+	if !strings.Contains(req.RawQuery, "kf_account=test1%40gh_test") {
+		t.Fatalf("kf_account not escaped in query: %s", req.RawQuery)
+	}
+	if got := req.Query["kf_account"]; len(got) != 1 || got[0] != "test1@gh_test" {
+		t.Fatalf("kf_account: got %v", got)
+	}
+}
+
+func TestAddKfAccount(t *testing.T) {
+	server := newTestServer(t)
+	client, _ := newTestClient(t, server)
+	authorizer := client.Authorizer(testAuthorizerAppId)
+
+	if err := authorizer.AddKfAccount("test1@gh_test", "客服1", ""); err != nil {
+		t.Fatal(err)
+	}
+	req := server.lastRequest(t)
+	want := `{"kf_account":"test1@gh_test","nickname":"客服1"}`
+	if req.Path != "/customservice/kfaccount/add" || string(req.Body) != want {
+		t.Fatalf("got %s %s, want /customservice/kfaccount/add %s", req.Path, req.Body, want)
+	}
+	if err := authorizer.UpdateKfAccount("test1@gh_test", "客服1", "pswmd5"); err != nil {
+		t.Fatal(err)
+	}
+	req = server.lastRequest(t)
+	want = `{"kf_account":"test1@gh_test","nickname":"客服1","password":"pswmd5"}`
+	if req.Path != "/customservice/kfaccount/update" || string(req.Body) != want {
+		t.Fatalf("got %s %s, want /customservice/kfaccount/update %s", req.Path, req.Body, want)
+	}
+}
+
+func TestKfAccountFormat(t *testing.T) {
+	server := newTestServer(t)
+	client, _ := newTestClient(t, server)
+	authorizer := client.Authorizer(testAuthorizerAppId)
+
+	for _, kfAccount := range []string{"", "test1", "@gh_test", "test1@", "toolongprefix@gh_test", "te-st@gh_test", "test1@gh test"} {
+		if err := authorizer.AddKfAccount(kfAccount, "客服1", ""); err == nil {
+			t.Errorf("%q: expected format error", kfAccount)
+		}
+	}
+	if err := authorizer.AddKfAccount("test1@gh_test", "", ""); err == nil {
+		t.Error("expected error for empty nickname")
+	}
+	if n := server.requestCount(); n != 0 {
+		t.Fatalf("invalid requests sent %d requests", n)
+	}
+}
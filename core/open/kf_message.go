@@ -0,0 +1,231 @@
+package open
+
+import (
+	"errors"
+	"io"
+)
+
+// 客服消息类型
+const (
+	KfMsgTypeText            = "text"
+	KfMsgTypeImage           = "image"
+	KfMsgTypeVoice           = "voice"
+	KfMsgTypeVideo           = "video"
+	KfMsgTypeMusic           = "music"
+	KfMsgTypeNews            = "news"
+	KfMsgTypeWxCard          = "wxcard"
+	KfMsgTypeLink            = "link"
+	KfMsgTypeMiniProgramPage = "miniprogrampage"
+)
+
+// KfText 文本消息
+type KfText struct {
+	Content string `json:"content"`
+}
+
+// KfMedia 媒体消息
+type KfMedia struct {
+	MediaId string `json:"media_id"`
+}
+
+// KfVideo 视频消息
+type KfVideo struct {
+	MediaId      string `json:"media_id"`
+	ThumbMediaId string `json:"thumb_media_id"`
+	Title        string `json:"title,omitempty"`
+	Description  string `json:"description,omitempty"`
+}
+
+// KfMusic 音乐消息
+type KfMusic struct {
+	Title        string `json:"title,omitempty"`
+	Description  string `json:"description,omitempty"`
+	MusicUrl     string `json:"musicurl"`
+	HqMusicUrl   string `json:"hqmusicurl"`
+	ThumbMediaId string `json:"thumb_media_id"`
+}
+
+// KfArticle 图文消息(点击跳转到外链)的文章
+type KfArticle struct {
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	Url         string `json:"url"`
+	PicUrl      string `json:"picurl"`
+}
+
+// KfNews 图文消息, 目前仅支持一篇文章
+type KfNews struct {
+	Articles []KfArticle `json:"articles"`
+}
+
+// KfWxCard 卡券消息
+type KfWxCard struct {
+	CardId string `json:"card_id"`
+}
+
+// KfCustomService 指定发送消息的客服账号
+type KfCustomService struct {
+	KfAccount string `json:"kf_account"`
+}
+
+// KfLink 图文链接消息
+type KfLink struct {
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	Url         string `json:"url"`
+	ThumbUrl    string `json:"thumb_url"`
+}
+
+// KfMiniProgramPage 小程序卡片消息
+type KfMiniProgramPage struct {
+	Title        string `json:"title"`
+	AppId        string `json:"appid,omitempty"`
+	PagePath     string `json:"pagepath"`
+	ThumbMediaId string `json:"thumb_media_id"`
+}
+
+// KfMessage 客服消息, 使用NewKf*构造
+type KfMessage struct {
+	ToUser          string             `json:"touser"`
+	MsgType         string             `json:"msgtype"`
+	Text            *KfText            `json:"text,omitempty"`
+	Image           *KfMedia           `json:"image,omitempty"`
+	Voice           *KfMedia           `json:"voice,omitempty"`
+	Video           *KfVideo           `json:"video,omitempty"`
+	Music           *KfMusic           `json:"music,omitempty"`
+	News            *KfNews            `json:"news,omitempty"`
+	WxCard          *KfWxCard          `json:"wxcard,omitempty"`
+	Link            *KfLink            `json:"link,omitempty"`
+	MiniProgramPage *KfMiniProgramPage `json:"miniprogrampage,omitempty"`
+	CustomService   *KfCustomService   `json:"customservice,omitempty"`
+}
+
+// WithKfAccount 以指定客服账号的身份发送
+func (self *KfMessage) WithKfAccount(kfAccount string) *KfMessage {
+	self.CustomService = &KfCustomService{KfAccount: kfAccount}
+	return self
+}
+
+// NewKfText 文本客服消息
+func NewKfText(toUser, content string) *KfMessage {
+	return &KfMessage{
+		ToUser:  toUser,
+		MsgType: KfMsgTypeText,
+		Text:    &KfText{Content: content},
+	}
+}
+
+// NewKfImage 图片客服消息, mediaId通过UploadTempMedia获取
+func NewKfImage(toUser, mediaId string) *KfMessage {
+	return &KfMessage{
+		ToUser:  toUser,
+		MsgType: KfMsgTypeImage,
+		Image:   &KfMedia{MediaId: mediaId},
+	}
+}
+
+// NewKfVoice 语音客服消息
+func NewKfVoice(toUser, mediaId string) *KfMessage {
+	return &KfMessage{
+		ToUser:  toUser,
+		MsgType: KfMsgTypeVoice,
+		Voice:   &KfMedia{MediaId: mediaId},
+	}
+}
+
+// NewKfVideo 视频客服消息
+func NewKfVideo(toUser string, video KfVideo) *KfMessage {
+	return &KfMessage{
+		ToUser:  toUser,
+		MsgType: KfMsgTypeVideo,
+		Video:   &video,
+	}
+}
+
+// NewKfMusic 音乐客服消息
+func NewKfMusic(toUser string, music KfMusic) *KfMessage {
+	return &KfMessage{
+		ToUser:  toUser,
+		MsgType: KfMsgTypeMusic,
+		Music:   &music,
+	}
+}
+
+// NewKfNews 图文客服消息(点击跳转到外链)
+func NewKfNews(toUser string, article KfArticle) *KfMessage {
+	return &KfMessage{
+		ToUser:  toUser,
+		MsgType: KfMsgTypeNews,
+		News:    &KfNews{Articles: []KfArticle{article}},
+	}
+}
+
+// NewKfWxCard 卡券客服消息
+func NewKfWxCard(toUser, cardId string) *KfMessage {
+	return &KfMessage{
+		ToUser:  toUser,
+		MsgType: KfMsgTypeWxCard,
+		WxCard:  &KfWxCard{CardId: cardId},
+	}
+}
+
+// NewKfLink 图文链接客服消息
+func NewKfLink(toUser string, link KfLink) *KfMessage {
+	return &KfMessage{
+		ToUser:  toUser,
+		MsgType: KfMsgTypeLink,
+		Link:    &link,
+	}
+}
+
+// NewKfMiniProgramPage 小程序卡片客服消息
+func NewKfMiniProgramPage(toUser string, page KfMiniProgramPage) *KfMessage {
+	return &KfMessage{
+		ToUser:          toUser,
+		MsgType:         KfMsgTypeMiniProgramPage,
+		MiniProgramPage: &page,
+	}
+}
+
+// SendCustomerMessage 发送客服消息
+// 超出回复时间窗口时返回ErrResponseOutOfTime, 超出下发条数时返回ErrOutOfResponseCount, 均不应重试
+func (self *AuthorizerClient) SendCustomerMessage(msg *KfMessage) error {
+	if msg == nil || msg.ToUser == "" || msg.MsgType == "" {
+		return errors.New("客服消息缺少touser或msgtype")
+	}
+	token, err := self.AccessToken()
+	if err != nil {
+		return err
+	}
+	return self.client.postJSON(self.client.Endpoint.CustomService(token), msg, nil)
+}
+
+// SendKfMessage 同SendCustomerMessage
+func (self *AuthorizerClient) SendKfMessage(msg KfMessage) error {
+	return self.SendCustomerMessage(&msg)
+}
+
+// SetTyping 设置或取消对用户显示"正在输入"状态
+func (self *AuthorizerClient) SetTyping(openId string, typing bool) error {
+	if openId == "" {
+		return errors.New("openid不能为空")
+	}
+	token, err := self.AccessToken()
+	if err != nil {
+		return err
+	}
+	command := "CancelTyping"
+	if typing {
+		command = "Typing"
+	}
+	return self.client.postJSON(self.client.Endpoint.CustomTyping(token), map[string]interface{}{
+		"touser":  openId,
+		"command": command,
+	}, nil)
+}
+
+// UploadTempMedia 上传临时素材, mediaType为image/voice/video/thumb
+func (self *AuthorizerClient) UploadTempMedia(mediaType string, r io.Reader, filename string) (string, error) {
+	mediaId, _, err := self.UploadMedia(MediaType(mediaType), filename, r)
+	return mediaId, err
+}
@@ -0,0 +1,239 @@
+package open
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestSendCustomerMessageBodies(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		msg  *KfMessage
+		want string
+	}{
+		{
+			name: "text",
+			msg:  NewKfText("OPENID", "你好"),
+			want: `{"touser":"OPENID","msgtype":"text","text":{"content":"你好"}}`,
+		},
+		{
+			name: "image",
+			msg:  NewKfImage("OPENID", "MEDIA_ID"),
+			want: `{"touser":"OPENID","msgtype":"image","image":{"media_id":"MEDIA_ID"}}`,
+		},
+		{
+			name: "link",
+			msg: NewKfLink("OPENID", KfLink{
+				Title:       "Happy Day",
+				Description: "Is Really A Happy Day",
+				Url:         "https://example.com",
+				ThumbUrl:    "https://example.com/thumb.png",
+			}),
+			want: `{"touser":"OPENID","msgtype":"link","link":{"title":"Happy Day","description":"Is Really A Happy Day","url":"https://example.com","thumb_url":"https://example.com/thumb.png"}}`,
+		},
+		{
+			name: "miniprogrampage",
+			msg: NewKfMiniProgramPage("OPENID", KfMiniProgramPage{
+				Title:        "title",
+				PagePath:     "pages/index?foo=bar",
+				ThumbMediaId: "THUMB_MEDIA_ID",
+			}),
+			want: `{"touser":"OPENID","msgtype":"miniprogrampage","miniprogrampage":{"title":"title","pagepath":"pages/index?foo=bar","thumb_media_id":"THUMB_MEDIA_ID"}}`,
+		},
+		{
+			name: "kf account",
+			msg:  NewKfText("OPENID", "你好").WithKfAccount("test1@kftest"),
+			want: `{"touser":"OPENID","msgtype":"text","text":{"content":"你好"},"customservice":{"kf_account":"test1@kftest"}}`,
+		},
+	} {
+		server := newTestServer(t)
+		client, _ := newTestClient(t, server)
+
+		if err := client.Authorizer(testAuthorizerAppId).SendCustomerMessage(tc.msg); err != nil {
+			t.Fatalf("%s: %v", tc.name, err)
+		}
+		req := server.lastRequest(t)
+		if req.Path != "/cgi-bin/message/custom/send" {
+			t.Fatalf("%s: path: got %s", tc.name, req.Path)
+		}
+		if got := req.Query["access_token"]; len(got) != 1 || got[0] != testAuthorizerToken {
+			t.Fatalf("%s: access_token: got %v", tc.name, got)
+		}
+		if string(req.Body) != tc.want {
+			t.Errorf("%s: body:\n got %s\nwant %s", tc.name, req.Body, tc.want)
+		}
+	}
+}
+
+func TestSendCustomerMessageOutOfTimeWindow(t *testing.T) {
+	server := newTestServer(t)
+	server.respond("/cgi-bin/message/custom/send", `{"errcode":45015,"errmsg":"response out of time limit or subscription is canceled"}`)
+	client, _ := newTestClient(t, server)
+
+	err := client.Authorizer(testAuthorizerAppId).SendCustomerMessage(NewKfText("OPENID", "你好"))
+	if !errors.Is(err, ErrResponseOutOfTime) {
+		t.Fatalf("got %v, want ErrResponseOutOfTime", err)
+	}
+}
+
+func TestSendCustomerMessageValidation(t *testing.T) {
+	server := newTestServer(t)
+	client, _ := newTestClient(t, server)
+	authorizer := client.Authorizer(testAuthorizerAppId)
+
+	if err := authorizer.SendCustomerMessage(nil); err == nil {
+		t.Error("nil message: expected validation error")
+	}
+	if err := authorizer.SendCustomerMessage(NewKfText("", "你好")); err == nil {
+		t.Error("missing touser: expected validation error")
+	}
+	if n := server.requestCount(); n != 0 {
+		t.Fatalf("invalid requests sent %d requests", n)
+	}
+}
+
+func TestUploadTempMedia(t *testing.T) {
+	server := newTestServer(t)
+	server.respond("/cgi-bin/media/upload", `{"type":"image","media_id":"MEDIA_ID","created_at":1600000000}`)
+	client, _ := newTestClient(t, server)
+	image := testPNG(t)
+
+	mediaId, err := client.Authorizer(testAuthorizerAppId).UploadTempMedia("image", bytes.NewReader(image), "reply.png")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mediaId != "MEDIA_ID" {
+		t.Fatalf("media_id: got %s", mediaId)
+	}
+	req := server.lastRequest(t)
+	if req.RawQuery != "access_token="+testAuthorizerToken+"&type=image" {
+		t.Fatalf("query: got %s", req.RawQuery)
+	}
+	filename, data := multipartFile(t, multipartForm(t, req), "media")
+	if filename != "reply.png" {
+		t.Fatalf("filename: got %s", filename)
+	}
+	assertTestPNG(t, data)
+}
+
+func TestUploadTempMediaRejectsInvalidFile(t *testing.T) {
+	server := newTestServer(t)
+	client, _ := newTestClient(t, server)
+	authorizer := client.Authorizer(testAuthorizerAppId)
+
+	if _, err := authorizer.UploadTempMedia("image", bytes.NewReader(testPNG(t)), "reply.txt"); err == nil {
+		t.Error("unsupported extension: expected validation error")
+	}
+	if _, err := authorizer.UploadTempMedia("file", bytes.NewReader(testPNG(t)), "reply.png"); err == nil {
+		t.Error("unsupported media type: expected validation error")
+	}
+	if _, err := authorizer.UploadTempMedia("thumb", bytes.NewReader(make([]byte, 64<<10+1)), "thumb.jpg"); err == nil {
+		t.Error("oversized thumb: expected validation error")
+	}
+	if n := server.requestCount(); n != 0 {
+		t.Fatalf("invalid uploads sent %d requests", n)
+	}
+}
+
+func TestSendKfMessageOfficialAccountBodies(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		msg  *KfMessage
+		want string
+	}{
+		{
+			name: "voice",
+			msg:  NewKfVoice("OPENID", "MEDIA_ID"),
+			want: `{"touser":"OPENID","msgtype":"voice","voice":{"media_id":"MEDIA_ID"}}`,
+		},
+		{
+			name: "video",
+			msg:  NewKfVideo("OPENID", KfVideo{MediaId: "MEDIA_ID", ThumbMediaId: "THUMB_MEDIA_ID", Title: "标题"}),
+			want: `{"touser":"OPENID","msgtype":"video","video":{"media_id":"MEDIA_ID","thumb_media_id":"THUMB_MEDIA_ID","title":"标题"}}`,
+		},
+		{
+			name: "music",
+			msg: NewKfMusic("OPENID", KfMusic{
+				Title:        "MUSIC_TITLE",
+				MusicUrl:     "https://example.com/a.mp3",
+				HqMusicUrl:   "https://example.com/a_hq.mp3",
+				ThumbMediaId: "THUMB_MEDIA_ID",
+			}),
+			want: `{"touser":"OPENID","msgtype":"music","music":{"title":"MUSIC_TITLE","musicurl":"https://example.com/a.mp3","hqmusicurl":"https://example.com/a_hq.mp3","thumb_media_id":"THUMB_MEDIA_ID"}}`,
+		},
+		{
+			name: "news",
+			msg: NewKfNews("OPENID", KfArticle{
+				Title:       "Happy Day",
+				Description: "Is Really A Happy Day",
+				Url:         "https://example.com",
+				PicUrl:      "https://example.com/pic.png",
+			}),
+			want: `{"touser":"OPENID","msgtype":"news","news":{"articles":[{"title":"Happy Day","description":"Is Really A Happy Day","url":"https://example.com","picurl":"https://example.com/pic.png"}]}}`,
+		},
+		{
+			name: "wxcard",
+			msg:  NewKfWxCard("OPENID", "123dsdajkasd231jhksad"),
+			want: `{"touser":"OPENID","msgtype":"wxcard","wxcard":{"card_id":"123dsdajkasd231jhksad"}}`,
+		},
+		{
+			name: "miniprogrampage with appid",
+			msg:  NewKfMiniProgramPage("OPENID", KfMiniProgramPage{Title: "title", AppId: "wx_weapp", PagePath: "pages/index", ThumbMediaId: "THUMB_MEDIA_ID"}),
+			want: `{"touser":"OPENID","msgtype":"miniprogrampage","miniprogrampage":{"title":"title","appid":"wx_weapp","pagepath":"pages/index","thumb_media_id":"THUMB_MEDIA_ID"}}`,
+		},
+	} {
+		server := newTestServer(t)
+		client, _ := newTestClient(t, server)
+
+		if err := client.Authorizer(testAuthorizerAppId).SendKfMessage(*tc.msg); err != nil {
+			t.Fatalf("%s: %v", tc.name, err)
+		}
+		if body := string(server.lastRequest(t).Body); body != tc.want {
+			t.Errorf("%s: body:\n got %s\nwant %s", tc.name, body, tc.want)
+		}
+	}
+}
+
+func TestSendKfMessageOutOfResponseCount(t *testing.T) {
+	server := newTestServer(t)
+	server.respond("/cgi-bin/message/custom/send", `{"errcode":45047,"errmsg":"out of response count limit"}`)
+	client, _ := newTestClient(t, server)
+
+	err := client.Authorizer(testAuthorizerAppId).SendKfMessage(*NewKfText("OPENID", "你好"))
+	if !errors.Is(err, ErrOutOfResponseCount) {
+		t.Fatalf("got %v, want ErrOutOfResponseCount", err)
+	}
+}
+
+func TestSetTyping(t *testing.T) {
+	server := newTestServer(t)
+	client, _ := newTestClient(t, server)
+	authorizer := client.Authorizer(testAuthorizerAppId)
+
+	for _, tc := range []struct {
+		typing bool
+		want   string
+	}{
+		{typing: true, want: `{"command":"Typing","touser":"OPENID"}`},
+		{typing: false, want: `{"command":"CancelTyping","touser":"OPENID"}`},
+	} {
+		if err := authorizer.SetTyping("OPENID", tc.typing); err != nil {
+			t.Fatal(err)
+		}
+		req := server.lastRequest(t)
+		if req.Path != "/cgi-bin/message/custom/typing" {
+			t.Fatalf("path: got %s", req.Path)
+		}
+		if string(req.Body) != tc.want {
+			t.Errorf("typing=%v: body: got %s, want %s", tc.typing, req.Body, tc.want)
+		}
+	}
+
+	if err := authorizer.SetTyping("", true); err == nil {
+		t.Fatal("empty openid: expected validation error")
+	}
+	if n := server.requestCount(); n != 2 {
+		t.Fatalf("sent %d requests, want 2", n)
+	}
+}
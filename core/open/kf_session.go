@@ -0,0 +1,236 @@
+package open
+
+import (
+	"errors"
+	"net/url"
+	"time"
+)
+
+// kfMsgRecordMaxNumber 获取聊天记录单次最大条数
+const kfMsgRecordMaxNumber = 10000
+
+// KfSession 客服会话
+type KfSession struct {
+	KfAccount  string
+	OpenId     string
+	CreateTime time.Time
+}
+
+// KfWaitCase 未接入的会话
+type KfWaitCase struct {
+	OpenId     string
+	LatestTime time.Time
+}
+
+// KfMsgRecord 客服聊天记录, OperCode: 2002客服发送 2003客服接收
+type KfMsgRecord struct {
+	OpenId   string
+	OperCode int
+	Text     string
+	Time     time.Time
+	Worker   string
+}
+
+// kfSessionRequest 创建或关闭会话
+func (self *AuthorizerClient) kfSessionRequest(endpoint func(string) string, openId, kfAccount string) error {
+	if err := checkKfAccount(kfAccount); err != nil {
+		return err
+	}
+	if openId == "" {
+		return errors.New("openid不能为空")
+	}
+	token, err := self.AccessToken()
+	if err != nil {
+		return err
+	}
+	return self.client.postJSON(endpoint(token), map[string]interface{}{
+		"kf_account": kfAccount,
+		"openid":     openId,
+	}, nil)
+}
+
+// CreateKfSession 将openid对应的用户接入指定客服
+func (self *AuthorizerClient) CreateKfSession(openId, kfAccount string) error {
+	return self.kfSessionRequest(self.client.Endpoint.CreateKfSession, openId, kfAccount)
+}
+
+// CloseKfSession 关闭openid对应的用户与客服的会话, 参数顺序同CreateKfSession
+func (self *AuthorizerClient) CloseKfSession(openId, kfAccount string) error {
+	return self.kfSessionRequest(self.client.Endpoint.CloseKfSession, openId, kfAccount)
+}
+
+// GetKfSession 获取用户当前的会话, 用户未接入客服时KfAccount为空
+func (self *AuthorizerClient) GetKfSession(openId string) (*KfSession, error) {
+	if openId == "" {
+		return nil, errors.New("openid不能为空")
+	}
+	token, err := self.AccessToken()
+	if err != nil {
+		return nil, err
+	}
+	var resp struct {
+		KfAccount  string `json:"kf_account"`
+		CreateTime int64  `json:"createtime"`
+	}
+	if err := self.client.getJSON(self.client.Endpoint.GetKfSession(token, url.QueryEscape(openId)), &resp); err != nil {
+		return nil, err
+	}
+	session := &KfSession{KfAccount: resp.KfAccount, OpenId: openId}
+	if resp.CreateTime > 0 {
+		session.CreateTime = time.Unix(resp.CreateTime, 0)
+	}
+	return session, nil
+}
+
+// GetKfSessionList 获取客服的会话列表
+func (self *AuthorizerClient) GetKfSessionList(kfAccount string) ([]KfSession, error) {
+	if kfAccount == "" {
+		return nil, errors.New("kf_account不能为空")
+	}
+	token, err := self.AccessToken()
+	if err != nil {
+		return nil, err
+	}
+	var resp struct {
+		SessionList []struct {
+			OpenId     string `json:"openid"`
+			CreateTime int64  `json:"createtime"`
+		} `json:"sessionlist"`
+	}
+	if err := self.client.getJSON(self.client.Endpoint.GetKfSessionList(token, url.QueryEscape(kfAccount)), &resp); err != nil {
+		return nil, err
+	}
+	sessions := make([]KfSession, 0, len(resp.SessionList))
+	for _, item := range resp.SessionList {
+		sessions = append(sessions, KfSession{
+			KfAccount:  kfAccount,
+			OpenId:     item.OpenId,
+			CreateTime: time.Unix(item.CreateTime, 0),
+		})
+	}
+	return sessions, nil
+}
+
+// GetKfWaitCase 获取未接入的会话列表, 返回未接入会话总数及最早的至多100条
+func (self *AuthorizerClient) GetKfWaitCase() ([]KfWaitCase, int, error) {
+	token, err := self.AccessToken()
+	if err != nil {
+		return nil, 0, err
+	}
+	var resp struct {
+		Count        int `json:"count"`
+		WaitCaseList []struct {
+			OpenId     string `json:"openid"`
+			LatestTime int64  `json:"latest_time"`
+		} `json:"waitcaselist"`
+	}
+	if err := self.client.getJSON(self.client.Endpoint.GetKfWaitCase(token), &resp); err != nil {
+		return nil, 0, err
+	}
+	cases := make([]KfWaitCase, 0, len(resp.WaitCaseList))
+	for _, item := range resp.WaitCaseList {
+		cases = append(cases, KfWaitCase{
+			OpenId:     item.OpenId,
+			LatestTime: time.Unix(item.LatestTime, 0),
+		})
+	}
+	return cases, resp.Count, nil
+}
+
+// GetKfMsgList 获取聊天记录, 起止时间需在同一天内, number最大10000
+// 返回本页记录及下一页的msgid, 返回条数小于number时表示已取完
+func (self *AuthorizerClient) GetKfMsgList(start, end time.Time, msgId int64, number int) ([]KfMsgRecord, int64, error) {
+	if start.IsZero() || end.IsZero() || !end.After(start) {
+		return nil, 0, errors.New("起止时间不正确")
+	}
+	if end.Sub(start) > 24*time.Hour {
+		return nil, 0, errors.New("起止时间间隔不能超过24小时")
+	}
+	if number <= 0 || number > kfMsgRecordMaxNumber {
+		number = kfMsgRecordMaxNumber
+	}
+	token, err := self.AccessToken()
+	if err != nil {
+		return nil, 0, err
+	}
+	var resp struct {
+		RecordList []struct {
+			OpenId   string `json:"openid"`
+			OperCode int    `json:"opercode"`
+			Text     string `json:"text"`
+			Time     int64  `json:"time"`
+			Worker   string `json:"worker"`
+		} `json:"recordlist"`
+		Number int   `json:"number"`
+		MsgId  int64 `json:"msgid"`
+	}
+	err = self.client.postJSON(self.client.Endpoint.GetKfMsgList(token), map[string]interface{}{
+		"starttime": start.Unix(),
+		"endtime":   end.Unix(),
+		"msgid":     msgId,
+		"number":    number,
+	}, &resp)
+	if err != nil {
+		return nil, 0, err
+	}
+	records := make([]KfMsgRecord, 0, len(resp.RecordList))
+	for _, item := range resp.RecordList {
+		records = append(records, KfMsgRecord{
+			OpenId:   item.OpenId,
+			OperCode: item.OperCode,
+			Text:     item.Text,
+			Time:     time.Unix(item.Time, 0),
+			Worker:   item.Worker,
+		})
+	}
+	return records, resp.MsgId, nil
+}
+
+// MsgRecordIterator 客服聊天记录迭代器, 按msgid自动翻页直到结束时间
+type MsgRecordIterator struct {
+	client *AuthorizerClient
+	start  time.Time
+	end    time.Time
+	msgId  int64
+	number int
+	buf    []KfMsgRecord
+	done   bool
+}
+
+// MsgRecords 创建客服聊天记录迭代器, number为每页条数, 0表示使用最大值
+func (self *AuthorizerClient) MsgRecords(start, end time.Time, number int) *MsgRecordIterator {
+	if number <= 0 || number > kfMsgRecordMaxNumber {
+		number = kfMsgRecordMaxNumber
+	}
+	return &MsgRecordIterator{
+		client: self,
+		start:  start,
+		end:    end,
+		msgId:  1,
+		number: number,
+	}
+}
+
+// Next 返回下一条聊天记录, 没有更多记录时第二个返回值为false
+func (self *MsgRecordIterator) Next() (KfMsgRecord, bool, error) {
+	if len(self.buf) == 0 {
+		if self.done {
+			return KfMsgRecord{}, false, nil
+		}
+		records, next, err := self.client.GetKfMsgList(self.start, self.end, self.msgId, self.number)
+		if err != nil {
+			return KfMsgRecord{}, false, err
+		}
+		if len(records) < self.number || next == 0 || next == self.msgId {
+			self.done = true
+		}
+		self.msgId = next
+		self.buf = records
+		if len(self.buf) == 0 {
+			return KfMsgRecord{}, false, nil
+		}
+	}
+	record := self.buf[0]
+	self.buf = self.buf[1:]
+	return record, true, nil
+}
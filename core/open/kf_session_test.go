@@ -0,0 +1,76 @@
+package open
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMsgRecordIterator(t *testing.T) {
+	server := newTestServer(t)
+	server.respondSequence("/customservice/msgrecord/getmsglist",
+		`{"recordlist":[{"openid":"OPENID","opercode":2002,"text":"你好","time":1400563710,"worker":"test1@test"},{"openid":"OPENID","opercode":2003,"text":"您好","time":1400563731,"worker":"test1@test"}],"number":2,"msgid":20165267}`,
+		`{"recordlist":[{"openid":"OPENID","opercode":2002,"text":"再见","time":1400563800,"worker":"test1@test"}],"number":1,"msgid":20165268}`,
+	)
+	client, _ := newTestClient(t, server)
+	start := time.Unix(1400563700, 0)
+
+	var records []KfMsgRecord
+	drain(t, client.Authorizer(testAuthorizerAppId).MsgRecords(start, start.Add(time.Hour), 2), &records)
+	if len(records) != 3 || records[2].Text != "再见" || records[0].Worker != "test1@test" || records[0].OperCode != 2002 {
+		t.Fatalf("got %+v", records)
+	}
+	bodies := server.requestBodies(t, "/customservice/msgrecord/getmsglist")
+	if len(bodies) != 2 || bodies[0]["msgid"] != float64(1) || bodies[1]["msgid"] != float64(20165267) || bodies[1]["number"] != float64(2) {
+		t.Fatalf("unexpected requests: %v", bodies)
+	}
+	if bodies[0]["starttime"] != float64(1400563700) || bodies[0]["endtime"] != float64(1400567300) {
+		t.Fatalf("unexpected time range: %v", bodies[0])
+	}
+}
+
+func TestMsgRecordIteratorValidation(t *testing.T) {
+	server := newTestServer(t)
+	client, _ := newTestClient(t, server)
+	authorizer := client.Authorizer(testAuthorizerAppId)
+	start := time.Unix(1400563700, 0)
+
+	for name, iterator := range map[string]*MsgRecordIterator{
+		"zero time": authorizer.MsgRecords(time.Time{}, start, 0),
+		"end first": authorizer.MsgRecords(start, start.Add(-time.Hour), 0),
+		"over 24h":  authorizer.MsgRecords(start, start.Add(25*time.Hour), 0),
+	} {
+		if _, ok, err := iterator.Next(); ok || err == nil {
+			t.Errorf("%s: got (%v, %v), want error", name, ok, err)
+		}
+	}
+	if n := server.requestCount(); n != 0 {
+		t.Fatalf("invalid requests sent %d requests", n)
+	}
+}
+
+func TestKfSessionArgumentOrder(t *testing.T) {
+	server := newTestServer(t)
+	client, _ := newTestClient(t, server)
+	authorizer := client.Authorizer(testAuthorizerAppId)
+
+	if err := authorizer.CreateKfSession("OPENID", "test1@gh_test"); err != nil {
+		t.Fatal(err)
+	}
+	want := `{"kf_account":"test1@gh_test","openid":"OPENID"}`
+	if req := server.lastRequest(t); req.Path != "/customservice/kfsession/create" || string(req.Body) != want {
+		t.Fatalf("create: got %s %s, want %s", req.Path, req.Body, want)
+	}
+	if err := authorizer.CloseKfSession("OPENID", "test1@gh_test"); err != nil {
+		t.Fatal(err)
+	}
+	if req := server.lastRequest(t); req.Path != "/customservice/kfsession/close" || string(req.Body) != want {
+		t.Fatalf("close: got %s %s, want %s", req.Path, req.Body, want)
+	}
+	// openid与kf_account传反时kf_account格式校验失败, 不发送请求
+	if err := authorizer.CreateKfSession("test1@gh_test", "OPENID"); err == nil {
+		t.Fatal("expected error for swapped arguments")
+	}
+	if n := server.requestCount(); n != 2 {
+		t.Fatalf("got %d requests, want 2", n)
+	}
+}
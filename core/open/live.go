@@ -0,0 +1,108 @@
+package open
+
+import "errors"
+
+// liveInfoMaxLimit 直播间列表单次最大条数
+const liveInfoMaxLimit = 100
+
+// LiveGoods 直播间商品
+type LiveGoods struct {
+	GoodsId         int64  `json:"goods_id"`
+	CoverImg        string `json:"cover_img"`
+	Url             string `json:"url"`
+	Name            string `json:"name"`
+	Price           int64  `json:"price"`
+	Price2          int64  `json:"price2"`
+	PriceType       int    `json:"price_type"`
+	ThirdPartyAppId string `json:"third_party_appid"`
+}
+
+// RoomInfo 直播间信息, 价格单位为分
+type RoomInfo struct {
+	Name          string      `json:"name"`
+	RoomId        int64       `json:"roomid"`
+	CoverImg      string      `json:"cover_img"`
+	ShareImg      string      `json:"share_img"`
+	LiveStatus    int         `json:"live_status"`
+	StartTime     int64       `json:"start_time"`
+	EndTime       int64       `json:"end_time"`
+	AnchorName    string      `json:"anchor_name"`
+	Goods         []LiveGoods `json:"goods"`
+	LiveType      int         `json:"live_type"`
+	CloseLike     int         `json:"close_like"`
+	CloseGoods    int         `json:"close_goods"`
+	CloseComment  int         `json:"close_comment"`
+	CloseKf       int         `json:"close_kf"`
+	CloseReplay   int         `json:"close_replay"`
+	IsFeedsPublic int         `json:"is_feeds_public"`
+	CreaterOpenId string      `json:"creater_openid"`
+	FeedsImg      string      `json:"feeds_img"`
+}
+
+// LiveRooms 直播间列表
+type LiveRooms struct {
+	RoomInfo []RoomInfo `json:"room_info"`
+	Total    int        `json:"total"`
+}
+
+// ReplayInfo 直播回放片段
+type ReplayInfo struct {
+	CreateTime string `json:"create_time"`
+	ExpireTime string `json:"expire_time"`
+	MediaUrl   string `json:"media_url"`
+}
+
+// LiveReplay 直播回放列表
+type LiveReplay struct {
+	LiveReplay []ReplayInfo `json:"live_replay"`
+	Total      int          `json:"total"`
+}
+
+func checkLiveRange(start, limit int) error {
+	if start < 0 || limit <= 0 || limit > liveInfoMaxLimit {
+		return errors.New("start不能小于0, limit取值范围为1-100")
+	}
+	return nil
+}
+
+// GetLiveRooms 获取直播间列表
+func (self *AuthorizerClient) GetLiveRooms(start, limit int) (*LiveRooms, error) {
+	if err := checkLiveRange(start, limit); err != nil {
+		return nil, err
+	}
+	token, err := self.AccessToken()
+	if err != nil {
+		return nil, err
+	}
+	var rooms LiveRooms
+	err = self.client.postJSON(self.client.Endpoint.GetLiveInfo(token), map[string]interface{}{
+		"start": start,
+		"limit": limit,
+	}, &rooms)
+	if err != nil {
+		return nil, err
+	}
+	return &rooms, nil
+}
+
+// GetLiveReplay 获取直播间回放
+func (self *AuthorizerClient) GetLiveReplay(roomId, start, limit int) (*LiveReplay, error) {
+	if err := checkLiveRange(start, limit); err != nil {
+		return nil, err
+	}
+	token, err := self.AccessToken()
+	if err != nil {
+		return nil, err
+	}
+	var replay LiveReplay
+	err = self.client.postJSON(self.client.Endpoint.GetLiveInfo(token), map[string]interface{}{
+		"action":  "get_replay",
+		"room_id": roomId,
+		"start":   start,
+		"limit":   limit,
+	}, &replay)
+	if err != nil {
+		return nil, err
+	}
+	return &replay, nil
+}
@@ -0,0 +1,77 @@
+package open
+
+import "testing"
+
+func TestGetLiveRooms(t *testing.T) {
+	server := newTestServer(t)
+	server.respond("/wxa/business/getliveinfo", `{"errcode":0,"errmsg":"ok","room_info":[{"name":"直播房间名","roomid":1,"cover_img":"http://mmbiz.qpic.cn/cover","share_img":"http://mmbiz.qpic.cn/share","live_status":101,"start_time":1568128900,"end_time":1568131200,"anchor_name":"里斯","goods":[{"cover_img":"http://mmbiz.qpic.cn/goods","url":"pages/index/index.html","name":"茶杯","price":1889,"price2":0,"price_type":1,"goods_id":256,"third_party_appid":"wx_third"}],"live_type":0,"close_like":0,"close_goods":0,"close_comment":0,"close_kf":1,"close_replay":1,"is_feeds_public":1,"creater_openid":"CREATER_OPENID","feeds_img":"http://mmbiz.qpic.cn/feeds"}],"total":1}`)
+	client, _ := newTestClient(t, server)
+
+	rooms, err := client.Authorizer(testAuthorizerAppId).GetLiveRooms(0, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req := server.lastRequest(t)
+	if got := req.Query["access_token"]; len(got) != 1 || got[0] != testAuthorizerToken {
+		t.Fatalf("access_token: got %v", got)
+	}
+	if string(req.Body) != `{"limit":10,"start":0}` {
+		t.Fatalf("body: got %s", req.Body)
+	}
+	if rooms.Total != 1 || len(rooms.RoomInfo) != 1 {
+		t.Fatalf("rooms: got %+v", rooms)
+	}
+	room := rooms.RoomInfo[0]
+	if room.Name != "直播房间名" || room.RoomId != 1 || room.LiveStatus != 101 || room.StartTime != 1568128900 || room.EndTime != 1568131200 ||
+		room.AnchorName != "里斯" || room.CloseKf != 1 || room.CreaterOpenId != "CREATER_OPENID" {
+		t.Fatalf("room: got %+v", room)
+	}
+	want := LiveGoods{
+		GoodsId:         256,
+		CoverImg:        "http://mmbiz.qpic.cn/goods",
+		Url:             "pages/index/index.html",
+		Name:            "茶杯",
+		Price:           1889,
+		PriceType:       1,
+		ThirdPartyAppId: "wx_third",
+	}
+	if len(room.Goods) != 1 || room.Goods[0] != want {
+		t.Fatalf("goods: got %+v, want %+v", room.Goods, want)
+	}
+}
+
+func TestGetLiveReplay(t *testing.T) {
+	server := newTestServer(t)
+	server.respond("/wxa/business/getliveinfo", `{"errcode":0,"errmsg":"ok","live_replay":[{"expire_time":"2020-11-11T03:49:55Z","create_time":"2019-11-12T03:49:55Z","media_url":"http://test.vod2.myqcloud.com/replay.m3u8"}],"total":1}`)
+	client, _ := newTestClient(t, server)
+
+	replay, err := client.Authorizer(testAuthorizerAppId).GetLiveReplay(3, 0, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if body := string(server.lastRequest(t).Body); body != `{"action":"get_replay","limit":100,"room_id":3,"start":0}` {
+		t.Fatalf("body: got %s", body)
+	}
+	want := ReplayInfo{CreateTime: "2019-11-12T03:49:55Z", ExpireTime: "2020-11-11T03:49:55Z", MediaUrl: "http://test.vod2.myqcloud.com/replay.m3u8"}
+	if replay.Total != 1 || len(replay.LiveReplay) != 1 || replay.LiveReplay[0] != want {
+		t.Fatalf("replay: got %+v", replay)
+	}
+}
+
+func TestLiveRangeValidation(t *testing.T) {
+	server := newTestServer(t)
+	client, _ := newTestClient(t, server)
+	authorizer := client.Authorizer(testAuthorizerAppId)
+
+	for _, r := range [][2]int{{-1, 10}, {0, 0}, {0, 101}} {
+		if _, err := authorizer.GetLiveRooms(r[0], r[1]); err == nil {
+			t.Errorf("rooms start=%d limit=%d: expected validation error", r[0], r[1])
+		}
+		if _, err := authorizer.GetLiveReplay(1, r[0], r[1]); err == nil {
+			t.Errorf("replay start=%d limit=%d: expected validation error", r[0], r[1])
+		}
+	}
+	if n := server.requestCount(); n != 0 {
+		t.Fatalf("invalid ranges sent %d requests", n)
+	}
+}
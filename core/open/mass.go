@@ -0,0 +1,195 @@
+package open
+
+import (
+	"errors"
+	"fmt"
+)
+
+const (
+	// massMinOpenIds 按openid列表群发最少用户数
+	massMinOpenIds = 2
+	// massMaxOpenIds 按openid列表群发最多用户数
+	massMaxOpenIds = 10000
+)
+
+// 群发消息类型
+const (
+	MassMsgTypeMpNews  = "mpnews"
+	MassMsgTypeText    = "text"
+	MassMsgTypeImage   = "image"
+	MassMsgTypeVoice   = "voice"
+	MassMsgTypeMpVideo = "mpvideo"
+)
+
+// 群发状态
+const (
+	MassStatusSuccess = "SEND_SUCCESS"
+	MassStatusSending = "SENDING"
+	MassStatusFail    = "SEND_FAIL"
+	MassStatusDelete  = "DELETE"
+)
+
+// MassFilter 按标签群发的筛选条件, IsToAll为true时发送给全部用户
+type MassFilter struct {
+	IsToAll bool  `json:"is_to_all"`
+	TagId   int64 `json:"tag_id,omitempty"`
+}
+
+// MassMessage 群发消息, 使用NewMass*构造, 由发送方法填写发送对象
+// SendIgnoreReprint为true时图文被判定为转载也继续群发, ClientMsgId用于24小时内去重
+type MassMessage struct {
+	Filter            *MassFilter `json:"filter,omitempty"`
+	ToUser            interface{} `json:"touser,omitempty"`
+	MsgType           string      `json:"msgtype"`
+	MpNews            *KfMedia    `json:"mpnews,omitempty"`
+	Text              *KfText     `json:"text,omitempty"`
+	Image             *KfMedia    `json:"image,omitempty"`
+	Voice             *KfMedia    `json:"voice,omitempty"`
+	MpVideo           *KfMedia    `json:"mpvideo,omitempty"`
+	SendIgnoreReprint int         `json:"send_ignore_reprint,omitempty"`
+	ClientMsgId       string      `json:"clientmsgid,omitempty"`
+}
+
+// MassResult 群发结果, MsgDataId仅图文消息返回, 可用于评论管理
+type MassResult struct {
+	MsgId     int64 `json:"msg_id"`
+	MsgDataId int64 `json:"msg_data_id"`
+}
+
+// NewMassMpNews 图文群发消息, mediaId为草稿或永久图文素材的media_id
+func NewMassMpNews(mediaId string, sendIgnoreReprint bool) *MassMessage {
+	msg := &MassMessage{
+		MsgType: MassMsgTypeMpNews,
+		MpNews:  &KfMedia{MediaId: mediaId},
+	}
+	if sendIgnoreReprint {
+		msg.SendIgnoreReprint = 1
+	}
+	return msg
+}
+
+// NewMassText 文本群发消息
+func NewMassText(content string) *MassMessage {
+	return &MassMessage{
+		MsgType: MassMsgTypeText,
+		Text:    &KfText{Content: content},
+	}
+}
+
+// NewMassImage 图片群发消息
+func NewMassImage(mediaId string) *MassMessage {
+	return &MassMessage{
+		MsgType: MassMsgTypeImage,
+		Image:   &KfMedia{MediaId: mediaId},
+	}
+}
+
+// NewMassVoice 语音群发消息
+func NewMassVoice(mediaId string) *MassMessage {
+	return &MassMessage{
+		MsgType: MassMsgTypeVoice,
+		Voice:   &KfMedia{MediaId: mediaId},
+	}
+}
+
+// NewMassVideo 视频群发消息, mediaId为视频素材的media_id
+func NewMassVideo(mediaId string) *MassMessage {
+	return &MassMessage{
+		MsgType: MassMsgTypeMpVideo,
+		MpVideo: &KfMedia{MediaId: mediaId},
+	}
+}
+
+// WithClientMsgId 设置群发去重id, 24小时内相同clientmsgid的群发不会重复发送
+func (self *MassMessage) WithClientMsgId(clientMsgId string) *MassMessage {
+	self.ClientMsgId = clientMsgId
+	return self
+}
+
+func (self *AuthorizerClient) sendMass(endpoint func(string) string, msg MassMessage) (*MassResult, error) {
+	if msg.MsgType == "" {
+		return nil, errors.New("群发消息缺少msgtype")
+	}
+	token, err := self.AccessToken()
+	if err != nil {
+		return nil, err
+	}
+	var result MassResult
+	if err := self.client.postJSON(endpoint(token), msg, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// SendMassByTag 按标签群发
+func (self *AuthorizerClient) SendMassByTag(msg MassMessage, tagId int64) (*MassResult, error) {
+	msg.Filter = &MassFilter{TagId: tagId}
+	msg.ToUser = nil
+	return self.sendMass(self.client.Endpoint.MassSendAll, msg)
+}
+
+// SendMassToAll 群发给全部用户
+func (self *AuthorizerClient) SendMassToAll(msg MassMessage) (*MassResult, error) {
+	msg.Filter = &MassFilter{IsToAll: true}
+	msg.ToUser = nil
+	return self.sendMass(self.client.Endpoint.MassSendAll, msg)
+}
+
+// SendMassByOpenIds 按openid列表群发, 用户数为2-10000
+func (self *AuthorizerClient) SendMassByOpenIds(msg MassMessage, openIds []string) (*MassResult, error) {
+	if len(openIds) < massMinOpenIds || len(openIds) > massMaxOpenIds {
+		return nil, fmt.Errorf("openid列表长度为%d-%d", massMinOpenIds, massMaxOpenIds)
+	}
+	msg.Filter = nil
+	msg.ToUser = openIds
+	return self.sendMass(self.client.Endpoint.MassSend, msg)
+}
+
+// PreviewMass 预览群发消息, 发送给指定openid的用户
+func (self *AuthorizerClient) PreviewMass(msg MassMessage, openId string) error {
+	if openId == "" {
+		return errors.New("openid不能为空")
+	}
+	msg.Filter = nil
+	msg.ToUser = openId
+	msg.SendIgnoreReprint = 0
+	msg.ClientMsgId = ""
+	_, err := self.sendMass(self.client.Endpoint.MassPreview, msg)
+	return err
+}
+
+// GetMassStatus 查询群发消息发送状态, 取值见MassStatus*
+func (self *AuthorizerClient) GetMassStatus(msgId int64) (string, error) {
+	token, err := self.AccessToken()
+	if err != nil {
+		return "", err
+	}
+	var resp struct {
+		MsgStatus string `json:"msg_status"`
+	}
+	err = self.client.postJSON(self.client.Endpoint.MassGet(token), map[string]interface{}{
+		"msg_id": msgId,
+	}, &resp)
+	if err != nil {
+		return "", err
+	}
+	return resp.MsgStatus, nil
+}
+
+// DeleteMass 删除群发消息, articleIdx为要删除的文章序号(从1开始), 为0时删除全部文章
+func (self *AuthorizerClient) DeleteMass(msgId int64, articleIdx int) error {
+	if articleIdx < 0 {
+		return errors.New("article_idx不能小于0")
+	}
+	token, err := self.AccessToken()
+	if err != nil {
+		return err
+	}
+	data := map[string]interface{}{
+		"msg_id": msgId,
+	}
+	if articleIdx > 0 {
+		data["article_idx"] = articleIdx
+	}
+	return self.client.postJSON(self.client.Endpoint.MassDelete(token), data, nil)
+}
@@ -0,0 +1,148 @@
+package open
+
+import "testing"
+
+func TestSendMassRequestBody(t *testing.T) {
+	server := newTestServer(t)
+	server.respond("/cgi-bin/message/mass/sendall", `{"errcode":0,"errmsg":"send job submission success","msg_id":34182,"msg_data_id":206227730}`)
+	server.respond("/cgi-bin/message/mass/send", `{"errcode":0,"errmsg":"send job submission success","msg_id":34183}`)
+	client, _ := newTestClient(t, server)
+	authorizer := client.Authorizer(testAuthorizerAppId)
+	openIds := []string{"OPENID_1", "OPENID_2"}
+
+	for _, tc := range []struct {
+		name string
+		send func() (*MassResult, error)
+		path string
+		want string
+	}{
+		{
+			name: "mpnews by tag",
+			send: func() (*MassResult, error) { return authorizer.SendMassByTag(*NewMassMpNews("MEDIA_ID", true), 2) },
+			path: "/cgi-bin/message/mass/sendall",
+			want: `{"filter":{"is_to_all":false,"tag_id":2},"msgtype":"mpnews","mpnews":{"media_id":"MEDIA_ID"},"send_ignore_reprint":1}`,
+		},
+		{
+			name: "text to all",
+			send: func() (*MassResult, error) {
+				return authorizer.SendMassToAll(*NewMassText("CONTENT").WithClientMsgId("MSG_1"))
+			},
+			path: "/cgi-bin/message/mass/sendall",
+			want: `{"filter":{"is_to_all":true},"msgtype":"text","text":{"content":"CONTENT"},"clientmsgid":"MSG_1"}`,
+		},
+		{
+			name: "image by openids",
+			send: func() (*MassResult, error) { return authorizer.SendMassByOpenIds(*NewMassImage("MEDIA_ID"), openIds) },
+			path: "/cgi-bin/message/mass/send",
+			want: `{"touser":["OPENID_1","OPENID_2"],"msgtype":"image","image":{"media_id":"MEDIA_ID"}}`,
+		},
+		{
+			name: "voice by openids",
+			send: func() (*MassResult, error) { return authorizer.SendMassByOpenIds(*NewMassVoice("MEDIA_ID"), openIds) },
+			path: "/cgi-bin/message/mass/send",
+			want: `{"touser":["OPENID_1","OPENID_2"],"msgtype":"voice","voice":{"media_id":"MEDIA_ID"}}`,
+		},
+		{
+			name: "video by openids",
+			send: func() (*MassResult, error) { return authorizer.SendMassByOpenIds(*NewMassVideo("MEDIA_ID"), openIds) },
+			path: "/cgi-bin/message/mass/send",
+			want: `{"touser":["OPENID_1","OPENID_2"],"msgtype":"mpvideo","mpvideo":{"media_id":"MEDIA_ID"}}`,
+		},
+	} {
+		result, err := tc.send()
+		if err != nil {
+			t.Errorf("%s: %v", tc.name, err)
+			continue
+		}
+		if result.MsgId == 0 {
+			t.Errorf("%s: msg_id not decoded", tc.name)
+		}
+		req := server.lastRequest(t)
+		if req.Path != tc.path {
+			t.Errorf("%s: path %s, want %s", tc.name, req.Path, tc.path)
+		}
+		if string(req.Body) != tc.want {
+			t.Errorf("%s: body\n got %s\nwant %s", tc.name, req.Body, tc.want)
+		}
+	}
+}
+
+func TestSendMassResult(t *testing.T) {
+	server := newTestServer(t)
+	server.respond("/cgi-bin/message/mass/sendall", `{"errcode":0,"errmsg":"send job submission success","msg_id":34182,"msg_data_id":206227730}`)
+	client, _ := newTestClient(t, server)
+
+	result, err := client.Authorizer(testAuthorizerAppId).SendMassToAll(*NewMassMpNews("MEDIA_ID", false))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *result != (MassResult{MsgId: 34182, MsgDataId: 206227730}) {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+}
+
+func TestPreviewMassRequestBody(t *testing.T) {
+	server := newTestServer(t)
+	client, _ := newTestClient(t, server)
+
+	msg := NewMassMpNews("MEDIA_ID", true).WithClientMsgId("MSG_1")
+	if err := client.Authorizer(testAuthorizerAppId).PreviewMass(*msg, "OPENID"); err != nil {
+		t.Fatal(err)
+	}
+	req := server.lastRequest(t)
+	want := `{"touser":"OPENID","msgtype":"mpnews","mpnews":{"media_id":"MEDIA_ID"}}`
+	if req.Path != "/cgi-bin/message/mass/preview" || string(req.Body) != want {
+		t.Fatalf("got %s %s, want /cgi-bin/message/mass/preview %s", req.Path, req.Body, want)
+	}
+}
+
+func TestMassStatusAndDelete(t *testing.T) {
+	server := newTestServer(t)
+	server.respond("/cgi-bin/message/mass/get", `{"msg_id":201053012,"msg_status":"SEND_SUCCESS"}`)
+	client, _ := newTestClient(t, server)
+	authorizer := client.Authorizer(testAuthorizerAppId)
+
+	status, err := authorizer.GetMassStatus(201053012)
+	if err != nil || status != MassStatusSuccess {
+		t.Fatalf("got (%s, %v), want SEND_SUCCESS", status, err)
+	}
+	if body := string(server.lastRequest(t).Body); body != `{"msg_id":201053012}` {
+		t.Fatalf("get body: %s", body)
+	}
+	for articleIdx, want := range map[int]string{
+		0: `{"msg_id":201053012}`,
+		2: `{"article_idx":2,"msg_id":201053012}`,
+	} {
+		if err := authorizer.DeleteMass(201053012, articleIdx); err != nil {
+			t.Fatal(err)
+		}
+		if req := server.lastRequest(t); req.Path != "/cgi-bin/message/mass/delete" || string(req.Body) != want {
+			t.Fatalf("delete %d: got %s %s, want %s", articleIdx, req.Path, req.Body, want)
+		}
+	}
+}
+
+func TestMassValidation(t *testing.T) {
+	server := newTestServer(t)
+	client, _ := newTestClient(t, server)
+	authorizer := client.Authorizer(testAuthorizerAppId)
+
+	if _, err := authorizer.SendMassByOpenIds(*NewMassText("CONTENT"), []string{"OPENID_1"}); err == nil {
+		t.Error("expected error for a single openid")
+	}
+	if _, err := authorizer.SendMassByOpenIds(*NewMassText("CONTENT"), make([]string, massMaxOpenIds+1)); err == nil {
+		t.Error("expected error for too many openids")
+	}
+	if _, err := authorizer.SendMassToAll(MassMessage{}); err == nil {
+		t.Error("expected error for missing msgtype")
+	}
+	if err := authorizer.PreviewMass(*NewMassText("CONTENT"), ""); err == nil {
+		t.Error("expected error for missing openid")
+	}
+	if err := authorizer.DeleteMass(1, -1); err == nil {
+		t.Error("expected error for negative article_idx")
+	}
+	if n := server.requestCount(); n != 0 {
+		t.Fatalf("invalid requests sent %d requests", n)
+	}
+}
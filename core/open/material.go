@@ -0,0 +1,283 @@
+package open
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// materialPageMax 批量获取永久素材单次最大条数
+const materialPageMax = 20
+
+// Article 图文消息文章, 永久图文素材与草稿共用, Url和ThumbUrl仅在查询时返回
+type Article struct {
+	Title              string `json:"title"`
+	ThumbMediaId       string `json:"thumb_media_id"`
+	Author             string `json:"author,omitempty"`
+	Digest             string `json:"digest,omitempty"`
+	ShowCoverPic       int    `json:"show_cover_pic,omitempty"`
+	Content            string `json:"content"`
+	ContentSourceUrl   string `json:"content_source_url"`
+	NeedOpenComment    int    `json:"need_open_comment,omitempty"`
+	OnlyFansCanComment int    `json:"only_fans_can_comment,omitempty"`
+	Url                string `json:"url,omitempty"`
+	ThumbUrl           string `json:"thumb_url,omitempty"`
+}
+
+// VideoMaterial 视频素材信息
+type VideoMaterial struct {
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	DownUrl     string `json:"down_url"`
+}
+
+// Material 永久素材内容, 图文素材返回NewsItem, 视频素材返回Video, 其它素材写入调用方提供的io.Writer
+type Material struct {
+	ContentType string
+	NewsItem    []Article
+	Video       *VideoMaterial
+}
+
+// MaterialCount 永久素材总数
+type MaterialCount struct {
+	VoiceCount int `json:"voice_count"`
+	VideoCount int `json:"video_count"`
+	ImageCount int `json:"image_count"`
+	NewsCount  int `json:"news_count"`
+}
+
+// MaterialItem 永久素材列表项, 图文素材的文章在Content.NewsItem中
+type MaterialItem struct {
+	MediaId    string `json:"media_id"`
+	Name       string `json:"name"`
+	UpdateTime int64  `json:"update_time"`
+	Url        string `json:"url"`
+	Content    struct {
+		NewsItem []Article `json:"news_item"`
+	} `json:"content"`
+}
+
+// MaterialList 永久素材列表
+type MaterialList struct {
+	TotalCount int            `json:"total_count"`
+	ItemCount  int            `json:"item_count"`
+	Item       []MaterialItem `json:"item"`
+}
+
+func (self *AuthorizerClient) addMaterial(mediaType MediaType, filename string, r io.Reader, fields map[string]string) (string, string, error) {
+	if filename == "" {
+		return "", "", errors.New("文件名不能为空")
+	}
+	data, err := readMedia(mediaType, filename, r)
+	if err != nil {
+		return "", "", err
+	}
+	token, err := self.AccessToken()
+	if err != nil {
+		return "", "", err
+	}
+	var resp struct {
+		MediaId string `json:"media_id"`
+		Url     string `json:"url"`
+	}
+	err = self.client.postMultipart(self.client.Endpoint.AddMaterial(token, string(mediaType)), "media", filename, bytes.NewReader(data), fields, &resp)
+	if err != nil {
+		return "", "", err
+	}
+	return resp.MediaId, resp.Url, nil
+}
+
+// AddMaterial 上传图片、语音或缩略图永久素材, 返回media_id及图片素材的url, 视频素材使用AddVideoMaterial
+func (self *AuthorizerClient) AddMaterial(mediaType MediaType, filename string, r io.Reader) (string, string, error) {
+	if mediaType == MediaVideo {
+		return "", "", errors.New("视频素材请使用AddVideoMaterial上传")
+	}
+	return self.addMaterial(mediaType, filename, r, nil)
+}
+
+// AddVideoMaterial 上传视频永久素材, 返回media_id
+func (self *AuthorizerClient) AddVideoMaterial(filename string, r io.Reader, title, introduction string) (string, error) {
+	if title == "" {
+		return "", errors.New("视频素材标题不能为空")
+	}
+	description, err := json.Marshal(map[string]string{
+		"title":        title,
+		"introduction": introduction,
+	})
+	if err != nil {
+		return "", err
+	}
+	mediaId, _, err := self.addMaterial(MediaVideo, filename, r, map[string]string{
+		"description": string(description),
+	})
+	return mediaId, err
+}
+
+// AddNews 新增永久图文素材, 返回media_id
+func (self *AuthorizerClient) AddNews(articles []Article) (string, error) {
+	if len(articles) == 0 {
+		return "", errors.New("图文素材至少包含一篇文章")
+	}
+	for i, article := range articles {
+		if err := checkArticle(i, article); err != nil {
+			return "", err
+		}
+	}
+	token, err := self.AccessToken()
+	if err != nil {
+		return "", err
+	}
+	var resp struct {
+		MediaId string `json:"media_id"`
+	}
+	err = self.client.postJSON(self.client.Endpoint.AddNews(token), map[string]interface{}{
+		"articles": articles,
+	}, &resp)
+	if err != nil {
+		return "", err
+	}
+	return resp.MediaId, nil
+}
+
+// GetMaterial 获取永久素材, 图文和视频素材解析到返回值中, 其它素材的内容写入w
+func (self *AuthorizerClient) GetMaterial(mediaId string, w io.Writer) (*Material, error) {
+	if mediaId == "" {
+		return nil, errors.New("media_id不能为空")
+	}
+	token, err := self.AccessToken()
+	if err != nil {
+		return nil, err
+	}
+	materialUrl := self.client.Endpoint.GetMaterial(token)
+	status, header, body, err := self.client.Http.PostJSONWithHeader(context.Background(), materialUrl, map[string]interface{}{
+		"media_id": mediaId,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if status != http.StatusOK {
+		return nil, errors.New("网络错误")
+	}
+	material := &Material{ContentType: header.Get("Content-Type")}
+	if isJSONResponse(material.ContentType, body) {
+		var resp struct {
+			NewsItem []Article `json:"news_item"`
+			VideoMaterial
+		}
+		if err := decodeResponse(body, &resp); err != nil {
+			return nil, self.client.logApiError(materialUrl, err)
+		}
+		switch {
+		case resp.NewsItem != nil:
+			material.NewsItem = resp.NewsItem
+		case resp.DownUrl != "":
+			material.Video = &resp.VideoMaterial
+		default:
+			return nil, fmt.Errorf("无法识别的素材响应: %s", bodySnippet(body))
+		}
+		return material, nil
+	}
+	if _, err := w.Write(body); err != nil {
+		return nil, err
+	}
+	return material, nil
+}
+
+// DeleteMaterial 删除永久素材
+func (self *AuthorizerClient) DeleteMaterial(mediaId string) error {
+	if mediaId == "" {
+		return errors.New("media_id不能为空")
+	}
+	token, err := self.AccessToken()
+	if err != nil {
+		return err
+	}
+	return self.client.postJSON(self.client.Endpoint.DeleteMaterial(token), map[string]interface{}{
+		"media_id": mediaId,
+	}, nil)
+}
+
+// GetMaterialCount 获取永久素材总数
+func (self *AuthorizerClient) GetMaterialCount() (*MaterialCount, error) {
+	token, err := self.AccessToken()
+	if err != nil {
+		return nil, err
+	}
+	var count MaterialCount
+	if err := self.client.getJSON(self.client.Endpoint.GetMaterialCount(token), &count); err != nil {
+		return nil, err
+	}
+	return &count, nil
+}
+
+// BatchGetMaterial 分页获取永久素材列表, count取值范围为1-20
+func (self *AuthorizerClient) BatchGetMaterial(mediaType MediaType, offset, count int) (*MaterialList, error) {
+	if _, ok := mediaLimits[mediaType]; !ok && mediaType != MediaNews {
+		return nil, fmt.Errorf("不支持的素材类型:%s", mediaType)
+	}
+	if offset < 0 || count <= 0 || count > materialPageMax {
+		return nil, errors.New("count取值范围为1-20")
+	}
+	token, err := self.AccessToken()
+	if err != nil {
+		return nil, err
+	}
+	var list MaterialList
+	err = self.client.postJSON(self.client.Endpoint.BatchGetMaterial(token), map[string]interface{}{
+		"type":   mediaType,
+		"offset": offset,
+		"count":  count,
+	}, &list)
+	if err != nil {
+		return nil, err
+	}
+	return &list, nil
+}
+
+// MaterialIterator 永久素材迭代器, 自动翻页
+type MaterialIterator struct {
+	client    *AuthorizerClient
+	mediaType MediaType
+	offset    int
+	total     int
+	buf       []MaterialItem
+	done      bool
+}
+
+// Materials 创建指定类型的永久素材迭代器
+func (self *AuthorizerClient) Materials(mediaType MediaType) *MaterialIterator {
+	return &MaterialIterator{
+		client:    self,
+		mediaType: mediaType,
+		total:     -1,
+	}
+}
+
+// Next 返回下一个素材, 遍历结束时第二个返回值为false
+func (self *MaterialIterator) Next() (MaterialItem, bool, error) {
+	if len(self.buf) == 0 {
+		if self.done || (self.total >= 0 && self.offset >= self.total) {
+			return MaterialItem{}, false, nil
+		}
+		list, err := self.client.BatchGetMaterial(self.mediaType, self.offset, materialPageMax)
+		if err != nil {
+			return MaterialItem{}, false, err
+		}
+		self.total = list.TotalCount
+		self.offset += len(list.Item)
+		self.buf = list.Item
+		if len(list.Item) < materialPageMax {
+			self.done = true
+		}
+		if len(self.buf) == 0 {
+			return MaterialItem{}, false, nil
+		}
+	}
+	item := self.buf[0]
+	self.buf = self.buf[1:]
+	return item, true, nil
+}
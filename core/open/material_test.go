@@ -0,0 +1,46 @@
+package open
+
+import (
+	"fmt"
+	"testing"
+)
+
+// materialListPage 生成第offset个起共count个图片素材的列表响应
+func materialListPage(total, offset, count int) string {
+	items := pageItems(offset, count, func(i int) string {
+		return fmt.Sprintf(`{"media_id":"MEDIA_%d","name":"%d.jpg","update_time":1653000000,"url":"https://mmbiz.qpic.cn/%d"}`, i, i, i)
+	})
+	return fmt.Sprintf(`{"total_count":%d,"item_count":%d,"item":[%s]}`, total, count, items)
+}
+
+func TestMaterialIterator(t *testing.T) {
+	server := newTestServer(t)
+	server.respondSequence("/cgi-bin/material/batchget_material",
+		materialListPage(40, 0, materialPageMax),
+		materialListPage(40, materialPageMax, materialPageMax),
+	)
+	client, _ := newTestClient(t, server)
+
+	var items []MaterialItem
+	drain(t, client.Authorizer(testAuthorizerAppId).Materials(MediaImage), &items)
+	if len(items) != 40 || items[39].MediaId != "MEDIA_39" || items[39].Name != "39.jpg" {
+		t.Fatalf("got %+v", items)
+	}
+	// 已取满total_count时不再请求下一页
+	bodies := server.requestBodies(t, "/cgi-bin/material/batchget_material")
+	if len(bodies) != 2 || bodies[1]["type"] != "image" || bodies[1]["offset"] != float64(materialPageMax) {
+		t.Fatalf("unexpected requests: %v", bodies)
+	}
+}
+
+func TestMaterialIteratorRejectsUnknownType(t *testing.T) {
+	server := newTestServer(t)
+	client, _ := newTestClient(t, server)
+
+	if _, ok, err := client.Authorizer(testAuthorizerAppId).Materials(MediaType("file")).Next(); ok || err == nil {
+		t.Fatalf("got (%v, %v), want error", ok, err)
+	}
+	if n := server.requestCount(); n != 0 {
+		t.Fatalf("invalid request sent %d requests", n)
+	}
+}
@@ -0,0 +1,161 @@
+package open
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// MediaType 素材类型
+type MediaType string
+
+const (
+	MediaImage MediaType = "image"
+	MediaVoice MediaType = "voice"
+	MediaVideo MediaType = "video"
+	MediaThumb MediaType = "thumb"
+	// MediaNews 图文素材, 仅用于永久素材
+	MediaNews MediaType = "news"
+)
+
+// mediaLimit 临时素材的大小及格式限制
+type mediaLimit struct {
+	maxSize    int64
+	extensions []string
+}
+
+var mediaLimits = map[MediaType]mediaLimit{
+	MediaImage: {10 << 20, []string{".bmp", ".png", ".jpeg", ".jpg", ".gif"}},
+	MediaVoice: {2 << 20, []string{".amr", ".mp3"}},
+	MediaVideo: {10 << 20, []string{".mp4"}},
+	MediaThumb: {64 << 10, []string{".jpg", ".jpeg"}},
+}
+
+// articleImageLimit 图文消息内图片的大小及格式限制
+var articleImageLimit = mediaLimit{1 << 20, []string{".jpg", ".jpeg", ".png"}}
+
+// read 校验文件格式并读取内容, 超出大小限制时返回错误
+func (self mediaLimit) read(name, filename string, r io.Reader) ([]byte, error) {
+	ext := strings.ToLower(filepath.Ext(filename))
+	valid := false
+	for _, allowed := range self.extensions {
+		if ext == allowed {
+			valid = true
+			break
+		}
+	}
+	if !valid {
+		return nil, fmt.Errorf("%s仅支持%s格式", name, strings.Join(self.extensions, "/"))
+	}
+	data, err := ioutil.ReadAll(io.LimitReader(r, self.maxSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > self.maxSize {
+		return nil, fmt.Errorf("%s不能超过%d字节", name, self.maxSize)
+	}
+	return data, nil
+}
+
+// readMedia 按素材类型校验文件格式并读取内容
+func readMedia(mediaType MediaType, filename string, r io.Reader) ([]byte, error) {
+	limit, ok := mediaLimits[mediaType]
+	if !ok {
+		return nil, fmt.Errorf("不支持的素材类型:%s", mediaType)
+	}
+	return limit.read(string(mediaType)+"素材", filename, r)
+}
+
+// UploadMedia 上传临时素材, 有效期3天, 返回media_id及上传时间
+func (self *AuthorizerClient) UploadMedia(mediaType MediaType, filename string, r io.Reader) (string, time.Time, error) {
+	if filename == "" {
+		return "", time.Time{}, errors.New("文件名不能为空")
+	}
+	data, err := readMedia(mediaType, filename, r)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	token, err := self.AccessToken()
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	var resp struct {
+		MediaId   string `json:"media_id"`
+		CreatedAt int64  `json:"created_at"`
+	}
+	err = self.client.postMultipart(self.client.Endpoint.UploadMedia(token, string(mediaType)), "media", filename, bytes.NewReader(data), nil, &resp)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	return resp.MediaId, time.Unix(resp.CreatedAt, 0), nil
+}
+
+// GetMedia 获取临时素材并写入w, 返回素材的Content-Type
+// 视频素材不返回文件内容, 此时返回视频下载地址videoUrl, 不写入w
+func (self *AuthorizerClient) GetMedia(mediaId string, w io.Writer) (string, string, error) {
+	if mediaId == "" {
+		return "", "", errors.New("media_id不能为空")
+	}
+	token, err := self.AccessToken()
+	if err != nil {
+		return "", "", err
+	}
+	mediaUrl := self.client.Endpoint.GetMedia(token, url.QueryEscape(mediaId))
+	status, header, body, err := self.client.Http.GetWithHeader(context.Background(), mediaUrl)
+	if err != nil {
+		return "", "", err
+	}
+	if status != http.StatusOK {
+		return "", "", errors.New("网络错误")
+	}
+	contentType := header.Get("Content-Type")
+	if isJSONResponse(contentType, body) {
+		var resp struct {
+			VideoUrl string `json:"video_url"`
+		}
+		if err := decodeResponse(body, &resp); err != nil {
+			return "", "", self.client.logApiError(mediaUrl, err)
+		}
+		if resp.VideoUrl == "" {
+			return "", "", fmt.Errorf("响应缺少video_url: %s", bodySnippet(body))
+		}
+		return contentType, resp.VideoUrl, nil
+	}
+	if _, err := w.Write(body); err != nil {
+		return "", "", err
+	}
+	return contentType, "", nil
+}
+
+// UploadArticleImage 上传图文消息内的图片, 返回微信CDN地址, 不占用素材库配额, 仅支持1MB以内的jpg/png
+func (self *AuthorizerClient) UploadArticleImage(filename string, r io.Reader) (string, error) {
+	if filename == "" {
+		return "", errors.New("文件名不能为空")
+	}
+	data, err := articleImageLimit.read("图文消息图片", filename, r)
+	if err != nil {
+		return "", err
+	}
+	token, err := self.AccessToken()
+	if err != nil {
+		return "", err
+	}
+	var resp struct {
+		Url string `json:"url"`
+	}
+	if err := self.client.postMultipart(self.client.Endpoint.UploadArticleImage(token), "media", filename, bytes.NewReader(data), nil, &resp); err != nil {
+		return "", err
+	}
+	if resp.Url == "" {
+		return "", errors.New("响应缺少url")
+	}
+	return resp.Url, nil
+}
@@ -0,0 +1,158 @@
+package open
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestUploadMedia(t *testing.T) {
+	server := newTestServer(t)
+	server.respond("/cgi-bin/media/upload", `{"type":"voice","media_id":"VOICE_MEDIA_ID","created_at":1704902400}`)
+	client, _ := newTestClient(t, server)
+
+	mediaId, createdAt, err := client.Authorizer(testAuthorizerAppId).UploadMedia(MediaVoice, "hello.MP3", bytes.NewReader([]byte("ID3")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mediaId != "VOICE_MEDIA_ID" || !createdAt.Equal(time.Unix(1704902400, 0)) {
+		t.Fatalf("got (%s, %v)", mediaId, createdAt)
+	}
+	req := server.lastRequest(t)
+	if req.RawQuery != "access_token="+testAuthorizerToken+"&type=voice" {
+		t.Fatalf("query: got %s", req.RawQuery)
+	}
+	filename, data := multipartFile(t, multipartForm(t, req), "media")
+	if filename != "hello.MP3" || string(data) != "ID3" {
+		t.Fatalf("file: got (%s, %q)", filename, data)
+	}
+}
+
+func TestUploadMediaLimits(t *testing.T) {
+	server := newTestServer(t)
+	client, _ := newTestClient(t, server)
+	authorizer := client.Authorizer(testAuthorizerAppId)
+
+	for _, tc := range []struct {
+		name      string
+		mediaType MediaType
+		filename  string
+		size      int
+	}{
+		{"voice over 2MB", MediaVoice, "a.mp3", 2<<20 + 1},
+		{"video wrong extension", MediaVideo, "a.avi", 1},
+		{"thumb png", MediaThumb, "a.png", 1},
+		{"news is permanent only", MediaNews, "a.jpg", 1},
+		{"empty filename", MediaImage, "", 1},
+	} {
+		if _, _, err := authorizer.UploadMedia(tc.mediaType, tc.filename, bytes.NewReader(make([]byte, tc.size))); err == nil {
+			t.Errorf("%s: expected validation error", tc.name)
+		}
+	}
+	if n := server.requestCount(); n != 0 {
+		t.Fatalf("invalid uploads sent %d requests", n)
+	}
+}
+
+func TestGetMediaBinary(t *testing.T) {
+	server := newTestServer(t)
+	server.respondBinary("/cgi-bin/media/get", "image/png", testPNG(t))
+	client, _ := newTestClient(t, server)
+
+	var buf bytes.Buffer
+	contentType, videoUrl, err := client.Authorizer(testAuthorizerAppId).GetMedia("MEDIA/ID", &buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if contentType != "image/png" || videoUrl != "" {
+		t.Fatalf("got (%s, %s)", contentType, videoUrl)
+	}
+	assertTestPNG(t, buf.Bytes())
+	if query := server.lastRequest(t).RawQuery; query != "access_token="+testAuthorizerToken+"&media_id=MEDIA%2FID" {
+		t.Fatalf("query: got %s", query)
+	}
+}
+
+func TestGetMediaVideo(t *testing.T) {
+	server := newTestServer(t)
+	server.respond("/cgi-bin/media/get", `{"video_url":"http://example.com/video.mp4"}`)
+	client, _ := newTestClient(t, server)
+
+	var buf bytes.Buffer
+	_, videoUrl, err := client.Authorizer(testAuthorizerAppId).GetMedia("VIDEO_MEDIA_ID", &buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if videoUrl != "http://example.com/video.mp4" {
+		t.Fatalf("video_url: got %s", videoUrl)
+	}
+	if buf.Len() != 0 {
+		t.Fatalf("wrote %d bytes for a video response, want 0", buf.Len())
+	}
+}
+
+func TestGetMediaError(t *testing.T) {
+	server := newTestServer(t)
+	server.respond("/cgi-bin/media/get", `{"errcode":40007,"errmsg":"invalid media_id"}`)
+	client, _ := newTestClient(t, server)
+
+	var buf bytes.Buffer
+	_, _, err := client.Authorizer(testAuthorizerAppId).GetMedia("EXPIRED_MEDIA_ID", &buf)
+	var apiErr *Error
+	if !errors.As(err, &apiErr) || apiErr.ErrCode != 40007 {
+		t.Fatalf("got %v, want errcode 40007", err)
+	}
+	if buf.Len() != 0 {
+		t.Fatalf("wrote %d bytes for an error response, want 0", buf.Len())
+	}
+}
+
+func TestUploadArticleImage(t *testing.T) {
+	server := newTestServer(t)
+	server.respond("/cgi-bin/media/uploadimg", `{"url":"http://mmbiz.qpic.cn/mmbiz/gLO17UPS6FS2xsypf378iaNhWacZ1G1UplZYWEYfwvuU6Ont96b1roYsCNFwaRrSaKTPCUdBK9DgEHicsKwWCBRQ/0"}`)
+	client, _ := newTestClient(t, server)
+
+	imageUrl, err := client.Authorizer(testAuthorizerAppId).UploadArticleImage("cover.png", bytes.NewReader(testPNG(t)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if imageUrl != "http://mmbiz.qpic.cn/mmbiz/gLO17UPS6FS2xsypf378iaNhWacZ1G1UplZYWEYfwvuU6Ont96b1roYsCNFwaRrSaKTPCUdBK9DgEHicsKwWCBRQ/0" {
+		t.Fatalf("url: got %s", imageUrl)
+	}
+	req := server.lastRequest(t)
+	if req.RawQuery != "access_token="+testAuthorizerToken {
+		t.Fatalf("query: got %s", req.RawQuery)
+	}
+	filename, data := multipartFile(t, multipartForm(t, req), "media")
+	if filename != "cover.png" {
+		t.Fatalf("filename: got %s", filename)
+	}
+	assertTestPNG(t, data)
+}
+
+func TestUploadArticleImageMissingUrl(t *testing.T) {
+	server := newTestServer(t)
+	server.respond("/cgi-bin/media/uploadimg", `{"errcode":0,"errmsg":"ok"}`)
+	client, _ := newTestClient(t, server)
+
+	if _, err := client.Authorizer(testAuthorizerAppId).UploadArticleImage("cover.png", bytes.NewReader(testPNG(t))); err == nil {
+		t.Fatal("missing url: expected error")
+	}
+}
+
+func TestUploadArticleImageLimits(t *testing.T) {
+	server := newTestServer(t)
+	client, _ := newTestClient(t, server)
+	authorizer := client.Authorizer(testAuthorizerAppId)
+
+	if _, err := authorizer.UploadArticleImage("cover.gif", bytes.NewReader(testPNG(t))); err == nil {
+		t.Error("gif: expected validation error")
+	}
+	if _, err := authorizer.UploadArticleImage("cover.jpg", bytes.NewReader(make([]byte, 1<<20+1))); err == nil {
+		t.Error("over 1MB: expected validation error")
+	}
+	if n := server.requestCount(); n != 0 {
+		t.Fatalf("invalid uploads sent %d requests", n)
+	}
+}
@@ -0,0 +1,96 @@
+package open
+
+import (
+	"encoding/json"
+	"errors"
+)
+
+// MenuButton 自定义菜单按钮, 一级按钮可通过SubButton包含二级菜单
+type MenuButton struct {
+	Type      string       `json:"type,omitempty"`
+	Name      string       `json:"name"`
+	Key       string       `json:"key,omitempty"`
+	Url       string       `json:"url,omitempty"`
+	MediaId   string       `json:"media_id,omitempty"`
+	AppId     string       `json:"appid,omitempty"`
+	PagePath  string       `json:"pagepath,omitempty"`
+	ArticleId string       `json:"article_id,omitempty"`
+	SubButton []MenuButton `json:"sub_button,omitempty"`
+}
+
+// Menu 公众号自定义菜单
+type Menu struct {
+	Button []MenuButton `json:"button"`
+}
+
+// MatchRule 个性化菜单匹配规则, 至少需要一个非空字段
+// Sex、Country、Province、City、Language已被微信标记为废弃, 但接口仍然接受
+type MatchRule struct {
+	TagId              string `json:"tag_id,omitempty"`
+	ClientPlatformType string `json:"client_platform_type,omitempty"`
+	Sex                string `json:"sex,omitempty"`
+	Country            string `json:"country,omitempty"`
+	Province           string `json:"province,omitempty"`
+	City               string `json:"city,omitempty"`
+	Language           string `json:"language,omitempty"`
+}
+
+func (self *MatchRule) empty() bool {
+	return *self == MatchRule{}
+}
+
+// AddConditionalMenu 创建个性化菜单, 返回菜单id
+func (self *AuthorizerClient) AddConditionalMenu(menu Menu, rule MatchRule) (string, error) {
+	if len(menu.Button) == 0 {
+		return "", errors.New("菜单按钮不能为空")
+	}
+	if rule.empty() {
+		return "", errors.New("菜单匹配规则不能为空")
+	}
+	token, err := self.AccessToken()
+	if err != nil {
+		return "", err
+	}
+	// menuid有时返回字符串有时返回数字
+	var resp struct {
+		MenuId json.Number `json:"menuid"`
+	}
+	err = self.client.postJSON(self.client.Endpoint.AddConditionalMenu(token), map[string]interface{}{
+		"button":    menu.Button,
+		"matchrule": rule,
+	}, &resp)
+	if err != nil {
+		return "", err
+	}
+	return resp.MenuId.String(), nil
+}
+
+// DeleteConditionalMenu 删除个性化菜单
+func (self *AuthorizerClient) DeleteConditionalMenu(menuId string) error {
+	if menuId == "" {
+		return errors.New("menuid不能为空")
+	}
+	token, err := self.AccessToken()
+	if err != nil {
+		return err
+	}
+	return self.client.postJSON(self.client.Endpoint.DeleteConditionalMenu(token), map[string]interface{}{
+		"menuid": menuId,
+	}, nil)
+}
+
+// TryMatchMenu 测试个性化菜单匹配结果, userId可以是粉丝的openid或微信号
+func (self *AuthorizerClient) TryMatchMenu(userId string) (Menu, error) {
+	var menu Menu
+	if userId == "" {
+		return menu, errors.New("user_id不能为空")
+	}
+	token, err := self.AccessToken()
+	if err != nil {
+		return menu, err
+	}
+	err = self.client.postJSON(self.client.Endpoint.TryMatchMenu(token), map[string]interface{}{
+		"user_id": userId,
+	}, &menu)
+	return menu, err
+}
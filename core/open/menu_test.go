@@ -0,0 +1,116 @@
+package open
+
+import "testing"
+
+func TestAddConditionalMenu(t *testing.T) {
+	menu := Menu{Button: []MenuButton{
+		{Type: "click", Name: "今日歌曲", Key: "V1001_TODAY_MUSIC"},
+		{Name: "菜单", SubButton: []MenuButton{
+			{Type: "view", Name: "搜索", Url: "https://www.soso.com/"},
+			{Type: "miniprogram", Name: "小程序", Url: "https://example.com", AppId: "wx_weapp", PagePath: "pages/index"},
+		}},
+	}}
+	for _, tc := range []struct {
+		name    string
+		fixture string
+	}{
+		{name: "number", fixture: `{"menuid":208379533}`},
+		{name: "string", fixture: `{"menuid":"208379533"}`},
+	} {
+		server := newTestServer(t)
+		server.respond("/cgi-bin/menu/addconditional", tc.fixture)
+		client, _ := newTestClient(t, server)
+
+		menuId, err := client.Authorizer(testAuthorizerAppId).AddConditionalMenu(menu, MatchRule{TagId: "2", ClientPlatformType: "2"})
+		if err != nil {
+			t.Fatalf("%s: %v", tc.name, err)
+		}
+		if menuId != "208379533" {
+			t.Errorf("%s: menuid: got %q", tc.name, menuId)
+		}
+		req := server.lastRequest(t)
+		if got := req.Query["access_token"]; len(got) != 1 || got[0] != testAuthorizerToken {
+			t.Fatalf("%s: access_token: got %v", tc.name, got)
+		}
+		want := `{"button":[{"type":"click","name":"今日歌曲","key":"V1001_TODAY_MUSIC"},{"name":"菜单","sub_button":[{"type":"view","name":"搜索","url":"https://www.soso.com/"},{"type":"miniprogram","name":"小程序","url":"https://example.com","appid":"wx_weapp","pagepath":"pages/index"}]}],"matchrule":{"tag_id":"2","client_platform_type":"2"}}`
+		if string(req.Body) != want {
+			t.Fatalf("%s: body:\n got %s\nwant %s", tc.name, req.Body, want)
+		}
+	}
+}
+
+func TestAddConditionalMenuDeprecatedRule(t *testing.T) {
+	server := newTestServer(t)
+	server.respond("/cgi-bin/menu/addconditional", `{"menuid":1}`)
+	client, _ := newTestClient(t, server)
+
+	rule := MatchRule{Sex: "1", Country: "中国", Province: "广东", City: "广州", Language: "zh_CN"}
+	if _, err := client.Authorizer(testAuthorizerAppId).AddConditionalMenu(Menu{Button: []MenuButton{{Type: "click", Name: "A", Key: "A"}}}, rule); err != nil {
+		t.Fatal(err)
+	}
+	matchRule := decodeBody(t, server.lastRequest(t))["matchrule"].(map[string]interface{})
+	want := map[string]string{"sex": "1", "country": "中国", "province": "广东", "city": "广州", "language": "zh_CN"}
+	if len(matchRule) != len(want) {
+		t.Fatalf("matchrule: got %v", matchRule)
+	}
+	for key, value := range want {
+		if matchRule[key] != value {
+			t.Errorf("matchrule %s: got %v, want %s", key, matchRule[key], value)
+		}
+	}
+}
+
+func TestDeleteConditionalMenu(t *testing.T) {
+	server := newTestServer(t)
+	client, _ := newTestClient(t, server)
+
+	if err := client.Authorizer(testAuthorizerAppId).DeleteConditionalMenu("208379533"); err != nil {
+		t.Fatal(err)
+	}
+	req := server.lastRequest(t)
+	if req.Path != "/cgi-bin/menu/delconditional" {
+		t.Fatalf("path: got %s", req.Path)
+	}
+	if string(req.Body) != `{"menuid":"208379533"}` {
+		t.Fatalf("body: got %s", req.Body)
+	}
+}
+
+func TestTryMatchMenu(t *testing.T) {
+	server := newTestServer(t)
+	server.respond("/cgi-bin/menu/trymatch", `{"button":[{"type":"view","name":"tx","url":"http://www.qq.com/","sub_button":[]}]}`)
+	client, _ := newTestClient(t, server)
+
+	menu, err := client.Authorizer(testAuthorizerAppId).TryMatchMenu("weixin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(menu.Button) != 1 || menu.Button[0].Type != "view" || menu.Button[0].Url != "http://www.qq.com/" {
+		t.Fatalf("menu: got %+v", menu)
+	}
+	if body := string(server.lastRequest(t).Body); body != `{"user_id":"weixin"}` {
+		t.Fatalf("body: got %s", body)
+	}
+}
+
+func TestConditionalMenuValidation(t *testing.T) {
+	server := newTestServer(t)
+	client, _ := newTestClient(t, server)
+	authorizer := client.Authorizer(testAuthorizerAppId)
+
+	if _, err := authorizer.AddConditionalMenu(Menu{}, MatchRule{TagId: "2"}); err == nil {
+		t.Error("empty menu: expected validation error")
+	}
+	if _, err := authorizer.AddConditionalMenu(Menu{Button: []MenuButton{{Type: "click", Name: "A", Key: "A"}}}, MatchRule{}); err == nil {
+		t.Error("empty rule: expected validation error")
+	}
+	if err := authorizer.DeleteConditionalMenu(""); err == nil {
+		t.Error("empty menuid: expected validation error")
+	}
+	if _, err := authorizer.TryMatchMenu(""); err == nil {
+		t.Error("empty user_id: expected validation error")
+	}
+	if n := server.requestCount(); n != 0 {
+		t.Fatalf("invalid requests sent %d requests", n)
+	}
+}
@@ -0,0 +1,103 @@
+package open
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+const (
+	// monitorCloseTimeout Close等待后台任务退出的最长时间
+	monitorCloseTimeout = 5 * time.Second
+	// ticketStaleAfter 微信每10分钟推送一次ticket, 超过20分钟未收到新ticket时告警
+	ticketStaleAfter = 20 * time.Minute
+)
+
+// Monitor 后台检查component_verify_ticket是否缺失或停止推送, 并在component_access_token过期前提前刷新
+type Monitor struct {
+	client   *Client
+	interval time.Duration
+	mu       sync.Mutex
+	cancel   context.CancelFunc
+	done     chan struct{}
+}
+
+// NewMonitor 创建后台监控, interval为检查间隔, 小于等于0时默认为1分钟
+func (self *Client) NewMonitor(interval time.Duration) *Monitor {
+	if interval <= 0 {
+		interval = time.Minute
+	}
+	return &Monitor{
+		client:   self,
+		interval: interval,
+	}
+}
+
+// Start 启动后台检查, ctx取消或调用Close时退出, 重复调用无效
+func (self *Monitor) Start(ctx context.Context) {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+	if self.done != nil {
+		return
+	}
+	ctx, self.cancel = context.WithCancel(ctx)
+	self.done = make(chan struct{})
+	go self.run(ctx, self.done)
+}
+
+func (self *Monitor) run(ctx context.Context, done chan struct{}) {
+	defer close(done)
+	ticker := time.NewTicker(self.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			self.check()
+		}
+	}
+}
+
+// check 缺少ticket或ticket停止推送时记录日志, component_access_token将在两个检查间隔内过期时提前刷新
+func (self *Monitor) check() {
+	ticket := self.client.cachedComponentTicket()
+	if ticket == nil || ticket["component_verify_ticket"] == nil {
+		self.client.logger().Printf("[WARN] %v", ErrComponentTicketMissing)
+		return
+	}
+	if receivedAt, ok := ticket["received_at"].(float64); ok {
+		if age := time.Since(time.Unix(int64(receivedAt), 0)); age > ticketStaleAfter {
+			self.client.logger().Printf("[WARN] component_verify_ticket已%v未更新, 请确认授权事件接收URL可以访问", age.Truncate(time.Second))
+		}
+	}
+	if componentToken := self.client.cachedComponentToken(); componentToken != nil {
+		expiresAt, _ := componentToken["expires_in"].(float64)
+		if time.Until(time.Unix(int64(expiresAt), 0)) > 2*self.interval {
+			return
+		}
+	}
+	if _, err := self.client.refreshComponentToken(); err != nil {
+		self.client.logger().Printf("[WARN] 刷新component_access_token失败: %v", err)
+	}
+}
+
+// Close 停止后台检查并等待其退出, 超过5秒未退出时返回错误
+func (self *Monitor) Close() error {
+	self.mu.Lock()
+	cancel, done := self.cancel, self.done
+	self.mu.Unlock()
+	if done == nil {
+		return nil
+	}
+	cancel()
+	timer := time.NewTimer(monitorCloseTimeout)
+	defer timer.Stop()
+	select {
+	case <-done:
+		return nil
+	case <-timer.C:
+		return errors.New("等待后台监控退出超时")
+	}
+}
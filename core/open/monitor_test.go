@@ -0,0 +1,118 @@
+package open
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func seedComponentTicket(client *Client, receivedAt time.Time) {
+	_ = client.Cache.SetEx(ComponentTicketCacheKeyPrefix+testAppId, map[string]interface{}{
+		"component_verify_ticket": "TICKET",
+		"received_at":             receivedAt.Unix(),
+	}, 3600)
+}
+
+func TestMonitorStartClose(t *testing.T) {
+	server := newTestServer(t)
+	server.respond("/cgi-bin/component/api_component_token", `{"component_access_token":"NEW_TOKEN","expires_in":7200}`)
+	client, _ := newTestClient(t, server)
+	seedComponentTicket(client, time.Now())
+	monitor := client.NewMonitor(time.Millisecond)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	monitor.Start(ctx)
+	monitor.Start(ctx)
+	time.Sleep(20 * time.Millisecond)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := monitor.Close(); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestMonitorStopsWithContext(t *testing.T) {
+	server := newTestServer(t)
+	client, _ := newTestClient(t, server)
+	monitor := client.NewMonitor(time.Millisecond)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	monitor.Start(ctx)
+	cancel()
+	select {
+	case <-monitor.done:
+	case <-time.After(time.Second):
+		t.Fatal("monitor did not exit after ctx was cancelled")
+	}
+	if err := monitor.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMonitorWarnsMissingTicket(t *testing.T) {
+	server := newTestServer(t)
+	client, logger := newTestClient(t, server)
+
+	client.NewMonitor(time.Minute).check()
+	if logged := logger.String(); !strings.Contains(logged, ErrComponentTicketMissing.Error()) {
+		t.Fatalf("missing ticket not logged, got: %q", logged)
+	}
+}
+
+func TestMonitorWarnsStaleTicket(t *testing.T) {
+	server := newTestServer(t)
+	client, logger := newTestClient(t, server)
+	monitor := client.NewMonitor(time.Minute)
+
+	seedComponentTicket(client, time.Now().Add(-5*time.Minute))
+	monitor.check()
+	if logged := logger.String(); logged != "" {
+		t.Fatalf("fresh ticket logged: %q", logged)
+	}
+	seedComponentTicket(client, time.Now().Add(-30*time.Minute))
+	monitor.check()
+	if logged := logger.String(); !strings.Contains(logged, "未更新") {
+		t.Fatalf("stale ticket not logged, got: %q", logged)
+	}
+}
+
+func TestMonitorRefreshesTokenEarly(t *testing.T) {
+	server := newTestServer(t)
+	server.respond("/cgi-bin/component/api_component_token", `{"component_access_token":"NEW_TOKEN","expires_in":7200}`)
+	client, _ := newTestClient(t, server)
+	seedComponentTicket(client, time.Now())
+	monitor := client.NewMonitor(time.Minute)
+
+	// 令牌还有1小时过期, 无需刷新
+	monitor.check()
+	if n := server.requestCount(); n != 0 {
+		t.Fatalf("valid token caused %d requests", n)
+	}
+
+	// 令牌将在两个检查间隔内过期, 提前刷新
+	_ = client.Cache.SetEx(ComponentTokenCacheKeyPrefix+testAppId, map[string]interface{}{
+		"component_access_token": testComponentToken,
+		"expires_in":             time.Now().Add(90 * time.Second).Unix(),
+	}, 3600)
+	monitor.check()
+	token, err := client.ApiComponentToken()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token != "NEW_TOKEN" {
+		t.Fatalf("got %s, want NEW_TOKEN", token)
+	}
+	if body := decodeBody(t, server.lastRequest(t)); body["component_verify_ticket"] != "TICKET" {
+		t.Fatalf("unexpected refresh body: %v", body)
+	}
+}
@@ -0,0 +1,134 @@
+package open
+
+import "errors"
+
+// IndustryClass 行业分类
+type IndustryClass struct {
+	FirstClass  string `json:"first_class"`
+	SecondClass string `json:"second_class"`
+}
+
+// Industry 公众号设置的行业
+type Industry struct {
+	PrimaryIndustry   IndustryClass `json:"primary_industry"`
+	SecondaryIndustry IndustryClass `json:"secondary_industry"`
+}
+
+// PrivateTemplate 公众号模板
+type PrivateTemplate struct {
+	TemplateId      string `json:"template_id"`
+	Title           string `json:"title"`
+	PrimaryIndustry string `json:"primary_industry"`
+	DeputyIndustry  string `json:"deputy_industry"`
+	Content         string `json:"content"`
+	Example         string `json:"example"`
+}
+
+// SetIndustry 设置公众号所属行业
+func (self *AuthorizerClient) SetIndustry(id1, id2 string) error {
+	if id1 == "" || id2 == "" {
+		return errors.New("行业编号不能为空")
+	}
+	token, err := self.AccessToken()
+	if err != nil {
+		return err
+	}
+	return self.client.postJSON(self.client.Endpoint.SetIndustry(token), map[string]interface{}{
+		"industry_id1": id1,
+		"industry_id2": id2,
+	}, nil)
+}
+
+// GetIndustry 获取公众号设置的行业信息
+func (self *AuthorizerClient) GetIndustry() (*Industry, error) {
+	token, err := self.AccessToken()
+	if err != nil {
+		return nil, err
+	}
+	var industry Industry
+	if err := self.client.getJSON(self.client.Endpoint.GetIndustry(token), &industry); err != nil {
+		return nil, err
+	}
+	return &industry, nil
+}
+
+// AddTemplateFromLibrary 从模板库添加模板, 返回模板id
+func (self *AuthorizerClient) AddTemplateFromLibrary(templateIdShort string) (string, error) {
+	if templateIdShort == "" {
+		return "", errors.New("template_id_short不能为空")
+	}
+	token, err := self.AccessToken()
+	if err != nil {
+		return "", err
+	}
+	var resp struct {
+		TemplateId string `json:"template_id"`
+	}
+	err = self.client.postJSON(self.client.Endpoint.AddTemplateFromLibrary(token), map[string]interface{}{
+		"template_id_short": templateIdShort,
+	}, &resp)
+	if err != nil {
+		return "", err
+	}
+	return resp.TemplateId, nil
+}
+
+// GetAllPrivateTemplates 获取公众号已添加的模板列表
+func (self *AuthorizerClient) GetAllPrivateTemplates() ([]PrivateTemplate, error) {
+	token, err := self.AccessToken()
+	if err != nil {
+		return nil, err
+	}
+	var resp struct {
+		TemplateList []PrivateTemplate `json:"template_list"`
+	}
+	if err := self.client.getJSON(self.client.Endpoint.GetAllPrivateTemplates(token), &resp); err != nil {
+		return nil, err
+	}
+	return resp.TemplateList, nil
+}
+
+// DeletePrivateTemplate 删除公众号已添加的模板
+func (self *AuthorizerClient) DeletePrivateTemplate(templateId string) error {
+	if templateId == "" {
+		return errors.New("template_id不能为空")
+	}
+	token, err := self.AccessToken()
+	if err != nil {
+		return err
+	}
+	return self.client.postJSON(self.client.Endpoint.DeletePrivateTemplate(token), map[string]interface{}{
+		"template_id": templateId,
+	}, nil)
+}
+
+// TemplateMessage 公众号模板消息, Url和MiniProgram同时填写时优先跳转小程序
+type TemplateMessage struct {
+	ToUser      string                   `json:"touser"`
+	TemplateId  string                   `json:"template_id"`
+	Url         string                   `json:"url,omitempty"`
+	MiniProgram *TemplateMiniProgram     `json:"miniprogram,omitempty"`
+	ClientMsgId string                   `json:"client_msg_id,omitempty"`
+	Data        map[string]TemplateValue `json:"data"`
+}
+
+// SendTemplateMessage 发送公众号模板消息, 返回消息id, 发送结果通过TEMPLATESENDJOBFINISH事件推送
+func (self *AuthorizerClient) SendTemplateMessage(msg TemplateMessage) (int64, error) {
+	if msg.ToUser == "" || msg.TemplateId == "" {
+		return 0, errors.New("touser和template_id不能为空")
+	}
+	if msg.MiniProgram != nil && msg.MiniProgram.AppId == "" {
+		return 0, errors.New("miniprogram缺少appid")
+	}
+	token, err := self.AccessToken()
+	if err != nil {
+		return 0, err
+	}
+	var resp struct {
+		MsgId int64 `json:"msgid"`
+	}
+	if err := self.client.postJSON(self.client.Endpoint.SendTemplateMessage(token), msg, &resp); err != nil {
+		return 0, err
+	}
+	return resp.MsgId, nil
+}
@@ -0,0 +1,91 @@
+package open
+
+import "testing"
+
+func TestSendTemplateMessageRequestBody(t *testing.T) {
+	server := newTestServer(t)
+	server.respond("/cgi-bin/message/template/send", `{"errcode":0,"errmsg":"ok","msgid":200228332}`)
+	client, _ := newTestClient(t, server)
+
+	msgId, err := client.Authorizer(testAuthorizerAppId).SendTemplateMessage(TemplateMessage{
+		ToUser:      "OPENID",
+		TemplateId:  "TEMPLATE_ID",
+		Url:         "https://example.com",
+		MiniProgram: &TemplateMiniProgram{AppId: "wx_weapp", PagePath: "index?foo=bar"},
+		ClientMsgId: "MSG_1",
+		Data: map[string]TemplateValue{
+			"keyword1": {Value: "巧克力", Color: "#173177"},
+			"keyword2": {Value: "39.8元"},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if msgId != 200228332 {
+		t.Fatalf("msgid: got %d", msgId)
+	}
+	req := server.lastRequest(t)
+	if got := req.Query["access_token"]; len(got) != 1 || got[0] != testAuthorizerToken {
+		t.Fatalf("access_token: got %v", got)
+	}
+	want := `{"touser":"OPENID","template_id":"TEMPLATE_ID","url":"https://example.com","miniprogram":{"appid":"wx_weapp","pagepath":"index?foo=bar"},"client_msg_id":"MSG_1","data":{"keyword1":{"value":"巧克力","color":"#173177"},"keyword2":{"value":"39.8元"}}}`
+	if string(req.Body) != want {
+		t.Fatalf("body:\n got %s\nwant %s", req.Body, want)
+	}
+}
+
+func TestSendTemplateMessageValidation(t *testing.T) {
+	server := newTestServer(t)
+	client, _ := newTestClient(t, server)
+	authorizer := client.Authorizer(testAuthorizerAppId)
+
+	for name, msg := range map[string]TemplateMessage{
+		"missing touser":      {TemplateId: "TEMPLATE_ID"},
+		"missing template_id": {ToUser: "OPENID"},
+		"miniprogram appid":   {ToUser: "OPENID", TemplateId: "TEMPLATE_ID", MiniProgram: &TemplateMiniProgram{PagePath: "index"}},
+	} {
+		if _, err := authorizer.SendTemplateMessage(msg); err == nil {
+			t.Errorf("%s: expected validation error", name)
+		}
+	}
+	if n := server.requestCount(); n != 0 {
+		t.Fatalf("invalid requests sent %d requests", n)
+	}
+}
+
+func TestTemplateManagement(t *testing.T) {
+	server := newTestServer(t)
+	server.respond("/cgi-bin/template/get_industry", `{"primary_industry":{"first_class":"运输与仓储","second_class":"快递"},"secondary_industry":{"first_class":"IT科技","second_class":"互联网|电子商务"}}`)
+	server.respond("/cgi-bin/template/api_add_template", `{"errcode":0,"errmsg":"ok","template_id":"TEMPLATE_ID"}`)
+	server.respond("/cgi-bin/template/get_all_private_template", `{"template_list":[{"template_id":"TEMPLATE_ID","title":"领取奖金提醒","primary_industry":"IT科技","deputy_industry":"互联网|电子商务","content":"{{result.DATA}}","example":"您已提交领奖申请"}]}`)
+	client, _ := newTestClient(t, server)
+	authorizer := client.Authorizer(testAuthorizerAppId)
+
+	if err := authorizer.SetIndustry("1", "4"); err != nil {
+		t.Fatal(err)
+	}
+	if body := string(server.lastRequest(t).Body); body != `{"industry_id1":"1","industry_id2":"4"}` {
+		t.Fatalf("set industry body: %s", body)
+	}
+	industry, err := authorizer.GetIndustry()
+	if err != nil || industry.SecondaryIndustry.SecondClass != "互联网|电子商务" {
+		t.Fatalf("got (%+v, %v)", industry, err)
+	}
+	templateId, err := authorizer.AddTemplateFromLibrary("TM00015")
+	if err != nil || templateId != "TEMPLATE_ID" {
+		t.Fatalf("got (%s, %v), want TEMPLATE_ID", templateId, err)
+	}
+	if body := string(server.lastRequest(t).Body); body != `{"template_id_short":"TM00015"}` {
+		t.Fatalf("add template body: %s", body)
+	}
+	templates, err := authorizer.GetAllPrivateTemplates()
+	if err != nil || len(templates) != 1 || templates[0].Title != "领取奖金提醒" {
+		t.Fatalf("got (%+v, %v)", templates, err)
+	}
+	if err := authorizer.DeletePrivateTemplate("TEMPLATE_ID"); err != nil {
+		t.Fatal(err)
+	}
+	if req := server.lastRequest(t); req.Path != "/cgi-bin/template/del_private_template" || string(req.Body) != `{"template_id":"TEMPLATE_ID"}` {
+		t.Fatalf("delete: got %s %s", req.Path, req.Body)
+	}
+}
@@ -0,0 +1,100 @@
+package open
+
+import (
+	"errors"
+	"net/url"
+)
+
+// 网页授权作用域
+const (
+	ScopeSnsapiBase     = "snsapi_base"
+	ScopeSnsapiUserinfo = "snsapi_userinfo"
+)
+
+// OAuthToken 网页授权access_token
+type OAuthToken struct {
+	AccessToken  string `json:"access_token"`
+	ExpiresIn    int64  `json:"expires_in"`
+	RefreshToken string `json:"refresh_token"`
+	OpenId       string `json:"openid"`
+	Scope        string `json:"scope"`
+	UnionId      string `json:"unionid"`
+}
+
+// SnsUserInfo 网页授权用户信息
+type SnsUserInfo struct {
+	OpenId     string   `json:"openid"`
+	Nickname   string   `json:"nickname"`
+	Sex        int      `json:"sex"`
+	Province   string   `json:"province"`
+	City       string   `json:"city"`
+	Country    string   `json:"country"`
+	HeadImgUrl string   `json:"headimgurl"`
+	Privilege  []string `json:"privilege"`
+	UnionId    string   `json:"unionid"`
+}
+
+// BuildComponentOAuthUrl 代公众号发起网页授权的链接, 以#wechat_redirect结尾
+func (self *Client) BuildComponentOAuthUrl(authorizerAppId, redirectUri, scope, state string) (string, error) {
+	if scope != ScopeSnsapiBase && scope != ScopeSnsapiUserinfo {
+		return "", errors.New("scope取值为snsapi_base或snsapi_userinfo")
+	}
+	if authorizerAppId == "" || redirectUri == "" {
+		return "", errors.New("appid和redirect_uri不能为空")
+	}
+	// 参数顺序与微信文档一致, 部分客户端对顺序敏感, 不使用url.Values.Encode
+	query := "appid=" + url.QueryEscape(authorizerAppId) +
+		"&redirect_uri=" + url.QueryEscape(redirectUri) +
+		"&response_type=code" +
+		"&scope=" + scope +
+		"&state=" + url.QueryEscape(state) +
+		"&component_appid=" + url.QueryEscape(self.AppId)
+	return self.Endpoint.ComponentOAuth2Authorize(query), nil
+}
+
+// ExchangeOAuthCode 代公众号使用code换取网页授权access_token
+func (self *Client) ExchangeOAuthCode(authorizerAppId, code string) (*OAuthToken, error) {
+	if code == "" {
+		return nil, ErrInvalidCode
+	}
+	token, err := self.ApiComponentToken()
+	if err != nil {
+		return nil, err
+	}
+	var oauthToken OAuthToken
+	if err := self.getJSON(self.Endpoint.OAuth2AccessToken(url.QueryEscape(authorizerAppId), url.QueryEscape(code), self.AppId, token), &oauthToken); err != nil {
+		return nil, err
+	}
+	return &oauthToken, nil
+}
+
+// RefreshOAuthToken 代公众号刷新网页授权access_token
+func (self *Client) RefreshOAuthToken(authorizerAppId, refreshToken string) (*OAuthToken, error) {
+	if refreshToken == "" {
+		return nil, errors.New("refresh_token不能为空")
+	}
+	token, err := self.ApiComponentToken()
+	if err != nil {
+		return nil, err
+	}
+	var oauthToken OAuthToken
+	if err := self.getJSON(self.Endpoint.OAuth2RefreshToken(url.QueryEscape(authorizerAppId), self.AppId, token, url.QueryEscape(refreshToken)), &oauthToken); err != nil {
+		return nil, err
+	}
+	return &oauthToken, nil
+}
+
+// GetOAuthUserInfo 获取网页授权用户信息, 需要snsapi_userinfo作用域, lang为空时默认zh_CN
+func (self *Client) GetOAuthUserInfo(accessToken, openId, lang string) (*SnsUserInfo, error) {
+	if accessToken == "" || openId == "" {
+		return nil, errors.New("access_token和openid不能为空")
+	}
+	if lang == "" {
+		lang = "zh_CN"
+	}
+	var userInfo SnsUserInfo
+	if err := self.getJSON(self.Endpoint.SnsUserInfo(url.QueryEscape(accessToken), url.QueryEscape(openId), url.QueryEscape(lang)), &userInfo); err != nil {
+		return nil, err
+	}
+	return &userInfo, nil
+}
@@ -0,0 +1,116 @@
+package open
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestBuildComponentOAuthUrl(t *testing.T) {
+	server := newTestServer(t)
+	client, _ := newTestClient(t, server)
+
+	got, err := client.BuildComponentOAuthUrl(testAuthorizerAppId, "https://example.com/oauth?tenant=1", ScopeSnsapiUserinfo, "STATE 1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "https://open.weixin.qq.com/connect/oauth2/authorize?appid=wx_authorizer&redirect_uri=https%3A%2F%2Fexample.com%2Foauth%3Ftenant%3D1&response_type=code&scope=snsapi_userinfo&state=STATE+1&component_appid=wx_component#wechat_redirect"
+	if got != want {
+		t.Fatalf("url:\n got %s\nwant %s", got, want)
+	}
+
+	if _, err := client.BuildComponentOAuthUrl(testAuthorizerAppId, "https://example.com/oauth", "snsapi_login", ""); err == nil {
+		t.Error("unknown scope: expected validation error")
+	}
+	if _, err := client.BuildComponentOAuthUrl("", "https://example.com/oauth", ScopeSnsapiBase, ""); err == nil {
+		t.Error("empty appid: expected validation error")
+	}
+}
+
+func TestExchangeOAuthCode(t *testing.T) {
+	server := newTestServer(t)
+	server.respond("/sns/oauth2/component/access_token", `{"access_token":"OAUTH_TOKEN","expires_in":7200,"refresh_token":"REFRESH_TOKEN","openid":"OPENID","scope":"snsapi_userinfo","unionid":"UNIONID"}`)
+	client, _ := newTestClient(t, server)
+
+	token, err := client.ExchangeOAuthCode(testAuthorizerAppId, "CODE")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := OAuthToken{AccessToken: "OAUTH_TOKEN", ExpiresIn: 7200, RefreshToken: "REFRESH_TOKEN", OpenId: "OPENID", Scope: ScopeSnsapiUserinfo, UnionId: "UNIONID"}
+	if *token != want {
+		t.Fatalf("token: got %+v", token)
+	}
+	req := server.lastRequest(t)
+	if req.Method != "GET" {
+		t.Fatalf("method: got %s", req.Method)
+	}
+	wantQuery := "appid=wx_authorizer&code=CODE&grant_type=authorization_code&component_appid=wx_component&component_access_token=" + testComponentToken
+	if req.RawQuery != wantQuery {
+		t.Fatalf("query:\n got %s\nwant %s", req.RawQuery, wantQuery)
+	}
+
+	if _, err := client.ExchangeOAuthCode(testAuthorizerAppId, ""); !errors.Is(err, ErrInvalidCode) {
+		t.Fatalf("empty code: got %v, want ErrInvalidCode", err)
+	}
+	if n := server.requestCount(); n != 1 {
+		t.Fatalf("sent %d requests, want 1", n)
+	}
+}
+
+func TestExchangeOAuthCodeError(t *testing.T) {
+	server := newTestServer(t)
+	server.respond("/sns/oauth2/component/access_token", `{"errcode":40029,"errmsg":"invalid code"}`)
+	client, _ := newTestClient(t, server)
+
+	_, err := client.ExchangeOAuthCode(testAuthorizerAppId, "USED_CODE")
+	var apiErr *Error
+	if !errors.As(err, &apiErr) || apiErr.ErrCode != 40029 {
+		t.Fatalf("got %v, want errcode 40029", err)
+	}
+}
+
+func TestRefreshOAuthToken(t *testing.T) {
+	server := newTestServer(t)
+	server.respond("/sns/oauth2/component/refresh_token", `{"access_token":"NEW_OAUTH_TOKEN","expires_in":7200,"refresh_token":"REFRESH_TOKEN","openid":"OPENID","scope":"snsapi_base"}`)
+	client, _ := newTestClient(t, server)
+
+	token, err := client.RefreshOAuthToken(testAuthorizerAppId, "REFRESH_TOKEN")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token.AccessToken != "NEW_OAUTH_TOKEN" || token.Scope != ScopeSnsapiBase || token.OpenId != "OPENID" {
+		t.Fatalf("token: got %+v", token)
+	}
+	wantQuery := "appid=wx_authorizer&grant_type=refresh_token&component_appid=wx_component&component_access_token=" + testComponentToken + "&refresh_token=REFRESH_TOKEN"
+	if query := server.lastRequest(t).RawQuery; query != wantQuery {
+		t.Fatalf("query:\n got %s\nwant %s", query, wantQuery)
+	}
+
+	if _, err := client.RefreshOAuthToken(testAuthorizerAppId, ""); err == nil {
+		t.Fatal("empty refresh_token: expected validation error")
+	}
+}
+
+func TestGetOAuthUserInfo(t *testing.T) {
+	server := newTestServer(t)
+	server.respond("/sns/userinfo", `{"openid":"OPENID","nickname":"张三","sex":1,"province":"广东","city":"广州","country":"中国","headimgurl":"https://example.com/head.png","privilege":["chinaunicom"],"unionid":"UNIONID"}`)
+	client, _ := newTestClient(t, server)
+
+	userInfo, err := client.GetOAuthUserInfo("OAUTH_TOKEN", "OPENID", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if userInfo.Nickname != "张三" || userInfo.Sex != 1 || userInfo.City != "广州" || userInfo.HeadImgUrl != "https://example.com/head.png" ||
+		len(userInfo.Privilege) != 1 || userInfo.UnionId != "UNIONID" {
+		t.Fatalf("user info: got %+v", userInfo)
+	}
+	req := server.lastRequest(t)
+	for key, want := range map[string]string{"access_token": "OAUTH_TOKEN", "openid": "OPENID", "lang": "zh_CN"} {
+		if got := req.Query[key]; len(got) != 1 || got[0] != want {
+			t.Errorf("%s: got %v, want %s", key, got, want)
+		}
+	}
+
+	if _, err := client.GetOAuthUserInfo("", "OPENID", ""); err == nil {
+		t.Fatal("empty access_token: expected validation error")
+	}
+}
@@ -0,0 +1,122 @@
+package open
+
+import "errors"
+
+// orderPathMaxAppIds 单次批量申请的最大appid数
+const orderPathMaxAppIds = 100
+
+// 订单页path信息类型
+const (
+	OrderPathInfoLatest = 0 // 最新提交的信息
+	OrderPathInfoOnline = 1 // 线上生效的信息
+)
+
+// OrderPathStatus 订单页path审核状态
+type OrderPathStatus int
+
+const (
+	OrderPathStatusNone     OrderPathStatus = 0 // 未申请
+	OrderPathStatusAuditing OrderPathStatus = 1 // 审核中
+	OrderPathStatusApproved OrderPathStatus = 2 // 审核通过
+	OrderPathStatusRejected OrderPathStatus = 3 // 审核驳回
+)
+
+// OrderPathInfo 订单页path信息
+type OrderPathInfo struct {
+	Path        string          `json:"path"`
+	ImgList     []string        `json:"img_list"`
+	Video       string          `json:"video"`
+	TestAccount string          `json:"test_account"`
+	TestPwd     string          `json:"test_pwd"`
+	TestRemark  string          `json:"test_remark"`
+	Status      OrderPathStatus `json:"status"`
+	ApplyTime   int64           `json:"apply_time"`
+}
+
+// OrderPathApplication 批量申请订单页path, AppIdList为需要设置的授权方appid
+type OrderPathApplication struct {
+	Path        string   `json:"path"`
+	ImgList     []string `json:"img_list,omitempty"`
+	Video       string   `json:"video,omitempty"`
+	TestAccount string   `json:"test_account,omitempty"`
+	TestPwd     string   `json:"test_pwd,omitempty"`
+	TestRemark  string   `json:"test_remark,omitempty"`
+	AppIdList   []string `json:"appid_list"`
+}
+
+// OrderPathApplyResult 单个授权方的申请结果
+type OrderPathApplyResult struct {
+	AppId   string `json:"appid"`
+	ErrCode int64  `json:"errcode"`
+	ErrMsg  string `json:"errmsg"`
+}
+
+// Ok 该授权方是否提交成功
+func (self *OrderPathApplyResult) Ok() bool {
+	return self.ErrCode == 0
+}
+
+// GetOrderPathInfo 获取订单页path信息, infoType为OrderPathInfoLatest或OrderPathInfoOnline
+func (self *Client) GetOrderPathInfo(infoType int) (*OrderPathInfo, error) {
+	if infoType != OrderPathInfoLatest && infoType != OrderPathInfoOnline {
+		return nil, errors.New("info_type取值为0或1")
+	}
+	token, err := self.ApiComponentToken()
+	if err != nil {
+		return nil, err
+	}
+	var resp struct {
+		Msg OrderPathInfo `json:"msg"`
+	}
+	err = self.postJSON(self.Endpoint.GetOrderPathInfo(token), map[string]interface{}{
+		"info_type": infoType,
+	}, &resp)
+	if err != nil {
+		return nil, err
+	}
+	return &resp.Msg, nil
+}
+
+// ApplyOrderPath 第三方平台为多个授权方批量申请订单页path, 返回每个appid的提交结果,
+// 部分appid失败时不返回错误, 需逐个检查结果
+func (self *Client) ApplyOrderPath(req OrderPathApplication) ([]OrderPathApplyResult, error) {
+	if req.Path == "" {
+		return nil, errors.New("path不能为空")
+	}
+	if len(req.AppIdList) == 0 || len(req.AppIdList) > orderPathMaxAppIds {
+		return nil, errors.New("appid_list为1-100个")
+	}
+	seen := make(map[string]bool, len(req.AppIdList))
+	for _, appId := range req.AppIdList {
+		if appId == "" || seen[appId] {
+			return nil, errors.New("appid_list不能包含空值或重复的appid")
+		}
+		seen[appId] = true
+	}
+	token, err := self.ApiComponentToken()
+	if err != nil {
+		return nil, err
+	}
+	var resp struct {
+		FailAppIdList []OrderPathApplyResult `json:"fail_appid_list"`
+	}
+	err = self.postJSON(self.Endpoint.ApplyOrderPathInfo(token), map[string]interface{}{
+		"batch_req": req,
+	}, &resp)
+	if err != nil {
+		return nil, err
+	}
+	failed := make(map[string]OrderPathApplyResult, len(resp.FailAppIdList))
+	for _, result := range resp.FailAppIdList {
+		failed[result.AppId] = result
+	}
+	results := make([]OrderPathApplyResult, 0, len(req.AppIdList))
+	for _, appId := range req.AppIdList {
+		if result, ok := failed[appId]; ok {
+			results = append(results, result)
+			continue
+		}
+		results = append(results, OrderPathApplyResult{AppId: appId})
+	}
+	return results, nil
+}
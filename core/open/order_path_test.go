@@ -0,0 +1,89 @@
+package open
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestGetOrderPathInfo(t *testing.T) {
+	server := newTestServer(t)
+	server.respond("/wxaapi/wxamptrade/get_order_path_info", `{"errcode":0,"errmsg":"ok","msg":{"path":"pages/order/list","img_list":["http://mmbiz.qpic.cn/1.png","http://mmbiz.qpic.cn/2.png"],"video":"","test_account":"test","test_pwd":"pwd","test_remark":"","status":2,"apply_time":1704902400}}`)
+	client, _ := newTestClient(t, server)
+
+	info, err := client.GetOrderPathInfo(OrderPathInfoOnline)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req := server.lastRequest(t)
+	if got := req.Query["access_token"]; len(got) != 1 || got[0] != testComponentToken {
+		t.Fatalf("access_token: got %v, want component token", got)
+	}
+	if string(req.Body) != `{"info_type":1}` {
+		t.Fatalf("body: got %s", req.Body)
+	}
+	if info.Path != "pages/order/list" || len(info.ImgList) != 2 || info.TestAccount != "test" || info.Status != OrderPathStatusApproved || info.ApplyTime != 1704902400 {
+		t.Fatalf("info: got %+v", info)
+	}
+	if _, err := client.GetOrderPathInfo(2); err == nil {
+		t.Fatal("info_type 2: expected validation error")
+	}
+}
+
+func TestApplyOrderPathPartialFailure(t *testing.T) {
+	server := newTestServer(t)
+	server.respond("/wxaapi/wxamptrade/apply_order_path_info", `{"errcode":0,"errmsg":"ok","fail_appid_list":[{"appid":"wx_b","errcode":1,"errmsg":"该小程序不属于交易类目"}]}`)
+	client, _ := newTestClient(t, server)
+
+	results, err := client.ApplyOrderPath(OrderPathApplication{
+		Path:        "pages/order/list",
+		ImgList:     []string{"http://mmbiz.qpic.cn/1.png"},
+		TestAccount: "test",
+		TestPwd:     "pwd",
+		AppIdList:   []string{"wx_a", "wx_b", "wx_c"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"batch_req":{"path":"pages/order/list","img_list":["http://mmbiz.qpic.cn/1.png"],"test_account":"test","test_pwd":"pwd","appid_list":["wx_a","wx_b","wx_c"]}}`
+	if body := string(server.lastRequest(t).Body); body != want {
+		t.Fatalf("body:\n got %s\nwant %s", body, want)
+	}
+	if len(results) != 3 {
+		t.Fatalf("results: got %+v", results)
+	}
+	for i, appId := range []string{"wx_a", "wx_b", "wx_c"} {
+		if results[i].AppId != appId {
+			t.Fatalf("results[%d]: got %s, want %s in request order", i, results[i].AppId, appId)
+		}
+	}
+	if !results[0].Ok() || !results[2].Ok() {
+		t.Fatalf("wx_a and wx_c must succeed: %+v", results)
+	}
+	if results[1].Ok() || results[1].ErrCode != 1 || results[1].ErrMsg != "该小程序不属于交易类目" {
+		t.Fatalf("wx_b: got %+v, want failure", results[1])
+	}
+}
+
+func TestApplyOrderPathValidation(t *testing.T) {
+	server := newTestServer(t)
+	client, _ := newTestClient(t, server)
+	tooMany := make([]string, orderPathMaxAppIds+1)
+	for i := range tooMany {
+		tooMany[i] = fmt.Sprintf("wx_%d", i)
+	}
+
+	for name, req := range map[string]OrderPathApplication{
+		"missing path":    {AppIdList: []string{"wx_a"}},
+		"empty appids":    {Path: "pages/order/list"},
+		"too many appids": {Path: "pages/order/list", AppIdList: tooMany},
+		"empty appid":     {Path: "pages/order/list", AppIdList: []string{"wx_a", ""}},
+		"duplicate appid": {Path: "pages/order/list", AppIdList: []string{"wx_a", "wx_a"}},
+	} {
+		if _, err := client.ApplyOrderPath(req); err == nil {
+			t.Errorf("%s: expected validation error", name)
+		}
+	}
+	if n := server.requestCount(); n != 0 {
+		t.Fatalf("invalid requests sent %d requests", n)
+	}
+}
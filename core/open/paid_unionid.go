@@ -0,0 +1,55 @@
+package open
+
+import (
+	"errors"
+	"net/url"
+)
+
+// PaidUnionIdQuery 支付订单信息, TransactionId与MchId+OutTradeNo二选一
+type PaidUnionIdQuery struct {
+	TransactionId string
+	MchId         string
+	OutTradeNo    string
+}
+
+func (self *PaidUnionIdQuery) values(openId string) (url.Values, error) {
+	byTransaction := self.TransactionId != ""
+	byOutTradeNo := self.MchId != "" || self.OutTradeNo != ""
+	if byTransaction == byOutTradeNo {
+		return nil, errors.New("transaction_id与mch_id+out_trade_no必须且只能填写一种")
+	}
+	values := url.Values{}
+	values.Set("openid", openId)
+	if byTransaction {
+		values.Set("transaction_id", self.TransactionId)
+		return values, nil
+	}
+	if self.MchId == "" || self.OutTradeNo == "" {
+		return nil, errors.New("mch_id和out_trade_no必须同时填写")
+	}
+	values.Set("mch_id", self.MchId)
+	values.Set("out_trade_no", self.OutTradeNo)
+	return values, nil
+}
+
+// GetPaidUnionId 用户支付完成后获取unionid, 未绑定开放平台时返回ErrNoUnionId
+func (self *AuthorizerClient) GetPaidUnionId(openId string, opts PaidUnionIdQuery) (string, error) {
+	if openId == "" {
+		return "", errors.New("openid不能为空")
+	}
+	values, err := opts.values(openId)
+	if err != nil {
+		return "", err
+	}
+	token, err := self.AccessToken()
+	if err != nil {
+		return "", err
+	}
+	var resp struct {
+		UnionId string `json:"unionid"`
+	}
+	if err := self.client.getJSON(self.client.Endpoint.GetPaidUnionId(token, values.Encode()), &resp); err != nil {
+		return "", err
+	}
+	return resp.UnionId, nil
+}
@@ -0,0 +1,76 @@
+package open
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestGetPaidUnionIdByTransaction(t *testing.T) {
+	server := newTestServer(t)
+	server.respond("/wxa/getpaidunionid", `{"errcode":0,"errmsg":"ok","unionid":"UNIONID"}`)
+	client, _ := newTestClient(t, server)
+
+	unionId, err := client.Authorizer(testAuthorizerAppId).GetPaidUnionId("OPENID", PaidUnionIdQuery{TransactionId: "4200000000000000000"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if unionId != "UNIONID" {
+		t.Fatalf("unionid: got %s", unionId)
+	}
+	req := server.lastRequest(t)
+	if req.Method != "GET" {
+		t.Fatalf("method: got %s", req.Method)
+	}
+	if req.RawQuery != "access_token="+testAuthorizerToken+"&openid=OPENID&transaction_id=4200000000000000000" {
+		t.Fatalf("query: got %s", req.RawQuery)
+	}
+}
+
+func TestGetPaidUnionIdByOutTradeNo(t *testing.T) {
+	server := newTestServer(t)
+	server.respond("/wxa/getpaidunionid", `{"errcode":0,"errmsg":"ok","unionid":"UNIONID"}`)
+	client, _ := newTestClient(t, server)
+
+	unionId, err := client.Authorizer(testAuthorizerAppId).GetPaidUnionId("OPENID", PaidUnionIdQuery{MchId: "1900000001", OutTradeNo: "ORDER 1&2"})
+	if err != nil || unionId != "UNIONID" {
+		t.Fatalf("got (%s, %v), want UNIONID", unionId, err)
+	}
+	if query := server.lastRequest(t).RawQuery; query != "access_token="+testAuthorizerToken+"&mch_id=1900000001&openid=OPENID&out_trade_no=ORDER+1%262" {
+		t.Fatalf("query: got %s", query)
+	}
+}
+
+func TestGetPaidUnionIdNotBound(t *testing.T) {
+	server := newTestServer(t)
+	server.respond("/wxa/getpaidunionid", `{"errcode":89002,"errmsg":"open not exists"}`)
+	client, _ := newTestClient(t, server)
+
+	_, err := client.Authorizer(testAuthorizerAppId).GetPaidUnionId("OPENID", PaidUnionIdQuery{TransactionId: "4200000000000000000"})
+	if !errors.Is(err, ErrNoUnionId) {
+		t.Fatalf("got %v, want ErrNoUnionId", err)
+	}
+}
+
+func TestGetPaidUnionIdValidation(t *testing.T) {
+	server := newTestServer(t)
+	client, _ := newTestClient(t, server)
+	authorizer := client.Authorizer(testAuthorizerAppId)
+
+	for name, opts := range map[string]PaidUnionIdQuery{
+		"neither":             {},
+		"both":                {TransactionId: "4200000000000000000", MchId: "1900000001", OutTradeNo: "ORDER"},
+		"mch_id only":         {MchId: "1900000001"},
+		"out_trade_no only":   {OutTradeNo: "ORDER"},
+		"transaction with no": {TransactionId: "4200000000000000000", OutTradeNo: "ORDER"},
+	} {
+		if _, err := authorizer.GetPaidUnionId("OPENID", opts); err == nil {
+			t.Errorf("%s: expected validation error", name)
+		}
+	}
+	if _, err := authorizer.GetPaidUnionId("", PaidUnionIdQuery{TransactionId: "4200000000000000000"}); err == nil {
+		t.Error("missing openid: expected validation error")
+	}
+	if n := server.requestCount(); n != 0 {
+		t.Fatalf("invalid queries sent %d requests", n)
+	}
+}
@@ -0,0 +1,97 @@
+package open
+
+import (
+	"encoding/json"
+	"errors"
+	"strconv"
+	"time"
+)
+
+// 性能数据类型
+const (
+	PerformanceOpenRate      = 10016 // 打开率
+	PerformanceStartupCost   = 10017 // 启动各阶段耗时
+	PerformancePageSwitch    = 10021 // 页面切换耗时
+	PerformanceMemory        = 10022 // 运行内存指标
+	PerformanceMemoryWarning = 10023 // 内存异常
+)
+
+// KV 查询参数, 如网络类型networktype、机型device_level、设备平台device
+type KV struct {
+	Field string `json:"field"`
+	Value string `json:"value"`
+}
+
+// PerformanceField 单日数据
+type PerformanceField struct {
+	RefDate string `json:"refdate"`
+	Value   string `json:"value"`
+}
+
+// PerformanceLine 数据序列
+type PerformanceLine struct {
+	Fields []PerformanceField `json:"fields"`
+}
+
+// PerformanceTable 性能指标
+type PerformanceTable struct {
+	Id    string            `json:"id"`
+	Zh    string            `json:"zh"`
+	Lines []PerformanceLine `json:"lines"`
+}
+
+// GetPerformance 获取小程序性能数据
+func (self *AuthorizerClient) GetPerformance(module int, start, end time.Time, params []KV) ([]PerformanceTable, error) {
+	if !start.Before(end) {
+		return nil, errors.New("开始时间必须早于结束时间")
+	}
+	if params == nil {
+		params = []KV{}
+	}
+	token, err := self.AccessToken()
+	if err != nil {
+		return nil, err
+	}
+	var resp struct {
+		Data json.RawMessage `json:"data"`
+	}
+	err = self.client.postJSON(self.client.Endpoint.GetPerformance(token), map[string]interface{}{
+		"time": map[string]int64{
+			"begin_timestamp": start.Unix(),
+			"end_timestamp":   end.Unix(),
+		},
+		"module": strconv.Itoa(module),
+		"params": params,
+	}, &resp)
+	if err != nil {
+		return nil, err
+	}
+	return decodePerformanceData(resp.Data)
+}
+
+// decodePerformanceData data字段为JSON编码后的字符串, 需要二次解析; 兼容直接返回对象的情况
+func decodePerformanceData(data json.RawMessage) ([]PerformanceTable, error) {
+	if len(data) == 0 || string(data) == "null" {
+		return nil, nil
+	}
+	payload := []byte(data)
+	if data[0] == '"' {
+		var encoded string
+		if err := json.Unmarshal(data, &encoded); err != nil {
+			return nil, err
+		}
+		if encoded == "" {
+			return nil, nil
+		}
+		payload = []byte(encoded)
+	}
+	var performance struct {
+		Body struct {
+			Tables []PerformanceTable `json:"tables"`
+		} `json:"body"`
+	}
+	if err := json.Unmarshal(payload, &performance); err != nil {
+		return nil, err
+	}
+	return performance.Body.Tables, nil
+}
@@ -0,0 +1,93 @@
+package open
+
+import (
+	"testing"
+	"time"
+)
+
+// performanceFixture 接口返回的data字段, 为JSON编码后的字符串
+const performanceFixture = `{"errcode":0,"errmsg":"ok","data":"{\"body\":{\"tables\":[{\"id\":\"startup_total_cost\",\"lines\":[{\"fields\":[{\"refdate\":\"20240110\",\"value\":\"1532.29\"},{\"refdate\":\"20240111\",\"value\":\"1487.61\"}]}],\"zh\":\"启动总耗时\"}],\"count\":1}}"}`
+
+func TestGetPerformance(t *testing.T) {
+	server := newTestServer(t)
+	server.respond("/wxaapi/log/get_performance", performanceFixture)
+	client, _ := newTestClient(t, server)
+	start := time.Unix(1704816000, 0)
+	end := time.Unix(1704988800, 0)
+
+	tables, err := client.Authorizer(testAuthorizerAppId).GetPerformance(PerformanceStartupCost, start, end, []KV{
+		{Field: "networktype", Value: "wifi"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	req := server.lastRequest(t)
+	if got := req.Query["access_token"]; len(got) != 1 || got[0] != testAuthorizerToken {
+		t.Fatalf("access_token: got %v", got)
+	}
+	want := `{"module":"10017","params":[{"field":"networktype","value":"wifi"}],"time":{"begin_timestamp":1704816000,"end_timestamp":1704988800}}`
+	if string(req.Body) != want {
+		t.Fatalf("body:\n got %s\nwant %s", req.Body, want)
+	}
+	if len(tables) != 1 || tables[0].Id != "startup_total_cost" || tables[0].Zh != "启动总耗时" || len(tables[0].Lines) != 1 {
+		t.Fatalf("tables: got %+v", tables)
+	}
+	fields := tables[0].Lines[0].Fields
+	if len(fields) != 2 || fields[1] != (PerformanceField{RefDate: "20240111", Value: "1487.61"}) {
+		t.Fatalf("fields: got %+v", fields)
+	}
+}
+
+func TestGetPerformanceEmptyParams(t *testing.T) {
+	server := newTestServer(t)
+	server.respond("/wxaapi/log/get_performance", performanceFixture)
+	client, _ := newTestClient(t, server)
+
+	if _, err := client.Authorizer(testAuthorizerAppId).GetPerformance(PerformanceMemory, time.Unix(1704816000, 0), time.Unix(1704988800, 0), nil); err != nil {
+		t.Fatal(err)
+	}
+	if params, ok := decodeBody(t, server.lastRequest(t))["params"].([]interface{}); !ok || len(params) != 0 {
+		t.Fatalf("params: got %v, want []", params)
+	}
+}
+
+func TestDecodePerformanceData(t *testing.T) {
+	for name, data := range map[string]string{
+		"object":         `{"body":{"tables":[{"id":"memory","zh":"运行内存","lines":[]}]}}`,
+		"encoded string": `"{\"body\":{\"tables\":[{\"id\":\"memory\",\"zh\":\"运行内存\",\"lines\":[]}]}}"`,
+	} {
+		tables, err := decodePerformanceData([]byte(data))
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if len(tables) != 1 || tables[0].Id != "memory" {
+			t.Fatalf("%s: got %+v", name, tables)
+		}
+	}
+	for name, data := range map[string]string{
+		"missing":      ``,
+		"null":         `null`,
+		"empty string": `""`,
+	} {
+		tables, err := decodePerformanceData([]byte(data))
+		if err != nil || tables != nil {
+			t.Fatalf("%s: got (%+v, %v), want (nil, nil)", name, tables, err)
+		}
+	}
+	if _, err := decodePerformanceData([]byte(`"{not json"`)); err == nil {
+		t.Fatal("malformed payload: expected error")
+	}
+}
+
+func TestGetPerformanceValidation(t *testing.T) {
+	server := newTestServer(t)
+	client, _ := newTestClient(t, server)
+
+	now := time.Now()
+	if _, err := client.Authorizer(testAuthorizerAppId).GetPerformance(PerformanceOpenRate, now, now, nil); err == nil {
+		t.Fatal("empty range: expected validation error")
+	}
+	if n := server.requestCount(); n != 0 {
+		t.Fatalf("invalid range sent %d requests", n)
+	}
+}
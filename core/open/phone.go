@@ -0,0 +1,87 @@
+package open
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// ErrPhoneCodeExpired 获取手机号的code无效、已过期或已使用
+var ErrPhoneCodeExpired = errors.New("手机号code已过期或已使用")
+
+// PhoneCodeError 获取手机号时微信返回code无效(40029), errors.Is(err, ErrPhoneCodeExpired)为true,
+// 与Code2Session的ErrInvalidCode互不匹配, Err为微信返回的原始错误
+type PhoneCodeError struct {
+	Err *Error
+}
+
+func (self *PhoneCodeError) Error() string {
+	return fmt.Sprintf("%v: %v", ErrPhoneCodeExpired, self.Err)
+}
+
+// Is 用于errors.Is(err, ErrPhoneCodeExpired)
+func (self *PhoneCodeError) Is(target error) bool {
+	return target == ErrPhoneCodeExpired
+}
+
+// Watermark 数据水印
+type Watermark struct {
+	AppId     string `json:"appid"`
+	Timestamp int64  `json:"timestamp"`
+}
+
+// PhoneInfo 用户手机号信息
+type PhoneInfo struct {
+	PhoneNumber     string      `json:"phoneNumber"`
+	PurePhoneNumber string      `json:"purePhoneNumber"`
+	CountryCode     json.Number `json:"countryCode"`
+	Watermark       Watermark   `json:"watermark"`
+}
+
+// WatermarkMismatchError 数据水印中的appid与授权方不一致
+type WatermarkMismatchError struct {
+	AppId          string
+	WatermarkAppId string
+}
+
+func (self *WatermarkMismatchError) Error() string {
+	return fmt.Sprintf("数据水印appid不匹配: 期望%s, 实际%s", self.AppId, self.WatermarkAppId)
+}
+
+// GetUserPhoneNumber 使用getPhoneNumber返回的code获取用户手机号
+// code只能使用一次且有效期较短, 失效时返回*PhoneCodeError, 可用errors.Is(err, ErrPhoneCodeExpired)判断
+func (self *AuthorizerClient) GetUserPhoneNumber(code string) (*PhoneInfo, error) {
+	if code == "" {
+		return nil, errors.New("code不能为空")
+	}
+	token, err := self.AccessToken()
+	if err != nil {
+		return nil, err
+	}
+	var resp struct {
+		PhoneInfo PhoneInfo `json:"phone_info"`
+	}
+	err = self.client.postJSON(self.client.Endpoint.GetUserPhoneNumber(token), map[string]interface{}{
+		"code": code,
+	}, &resp)
+	var apiErr *Error
+	if errors.As(err, &apiErr) && apiErr.ErrCode == ErrInvalidCode.ErrCode {
+		return nil, &PhoneCodeError{Err: apiErr}
+	}
+	if err != nil {
+		return nil, err
+	}
+	if resp.PhoneInfo.Watermark.AppId != self.AuthorizerAppId {
+		return nil, &WatermarkMismatchError{
+			AppId:          self.AuthorizerAppId,
+			WatermarkAppId: resp.PhoneInfo.Watermark.AppId,
+		}
+	}
+	return &resp.PhoneInfo, nil
+}
+
+// GetUserPhoneNumber 使用getPhoneNumber返回的code获取指定授权方用户的手机号,
+// code失效时返回*PhoneCodeError, 可用errors.Is(err, ErrPhoneCodeExpired)判断
+func (self *Client) GetUserPhoneNumber(authorizerAppId, code string) (*PhoneInfo, error) {
+	return self.Authorizer(authorizerAppId).GetUserPhoneNumber(code)
+}
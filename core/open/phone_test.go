@@ -0,0 +1,99 @@
+package open
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestGetUserPhoneNumberExpiredCode(t *testing.T) {
+	server := newTestServer(t)
+	server.respond("/wxa/business/getuserphonenumber", `{"errcode":40029,"errmsg":"invalid code"}`)
+	client, _ := newTestClient(t, server)
+
+	_, err := client.Authorizer(testAuthorizerAppId).GetUserPhoneNumber("expired")
+	if !errors.Is(err, ErrPhoneCodeExpired) {
+		t.Fatalf("got %v, want ErrPhoneCodeExpired", err)
+	}
+	if errors.Is(err, ErrInvalidCode) {
+		t.Fatal("phone code error must not match ErrInvalidCode")
+	}
+	var codeErr *PhoneCodeError
+	if !errors.As(err, &codeErr) || codeErr.Err.ErrCode != 40029 {
+		t.Fatalf("got %#v, want *PhoneCodeError with errcode 40029", err)
+	}
+}
+
+func TestGetUserPhoneNumberOtherErrorsPassThrough(t *testing.T) {
+	server := newTestServer(t)
+	server.respond("/wxa/business/getuserphonenumber", `{"errcode":48001,"errmsg":"api unauthorized"}`)
+	client, _ := newTestClient(t, server)
+
+	_, err := client.Authorizer(testAuthorizerAppId).GetUserPhoneNumber("code")
+	if !errors.Is(err, ErrApiUnauthorized) || errors.Is(err, ErrPhoneCodeExpired) {
+		t.Fatalf("got %v, want ErrApiUnauthorized", err)
+	}
+}
+
+func TestGetUserPhoneNumberEmptyCode(t *testing.T) {
+	server := newTestServer(t)
+	client, _ := newTestClient(t, server)
+
+	if _, err := client.Authorizer(testAuthorizerAppId).GetUserPhoneNumber(""); err == nil {
+		t.Fatal("expected error for empty code")
+	}
+	if n := server.requestCount(); n != 0 {
+		t.Fatalf("empty code sent %d requests", n)
+	}
+}
+
+func TestClientGetUserPhoneNumberSampleResponse(t *testing.T) {
+	server := newTestServer(t)
+	server.respond("/wxa/business/getuserphonenumber", `{
+		"errcode": 0,
+		"errmsg": "ok",
+		"phone_info": {
+			"phoneNumber": "+86 13800138000",
+			"purePhoneNumber": "13800138000",
+			"countryCode": 86,
+			"watermark": {"timestamp": 1637744274, "appid": "wx_authorizer"}
+		}
+	}`)
+	client, _ := newTestClient(t, server)
+
+	info, err := client.GetUserPhoneNumber(testAuthorizerAppId, "code")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.PhoneNumber != "+86 13800138000" || info.PurePhoneNumber != "13800138000" || info.CountryCode.String() != "86" {
+		t.Fatalf("unexpected phone info: %+v", info)
+	}
+	if info.Watermark.Timestamp != 1637744274 {
+		t.Fatalf("watermark timestamp: got %d", info.Watermark.Timestamp)
+	}
+	req := server.lastRequest(t)
+	if req.Query["access_token"][0] != testAuthorizerToken || decodeBody(t, req)["code"] != "code" {
+		t.Fatalf("unexpected request: %+v", req)
+	}
+}
+
+func TestClientGetUserPhoneNumberWatermarkMismatch(t *testing.T) {
+	server := newTestServer(t)
+	server.respond("/wxa/business/getuserphonenumber", `{"errcode":0,"errmsg":"ok","phone_info":{"purePhoneNumber":"13800138000","watermark":{"appid":"wx_other"}}}`)
+	client, _ := newTestClient(t, server)
+
+	_, err := client.GetUserPhoneNumber(testAuthorizerAppId, "code")
+	var mismatch *WatermarkMismatchError
+	if !errors.As(err, &mismatch) || mismatch.WatermarkAppId != "wx_other" {
+		t.Fatalf("got %v, want *WatermarkMismatchError", err)
+	}
+}
+
+func TestClientGetUserPhoneNumberExpiredCode(t *testing.T) {
+	server := newTestServer(t)
+	server.respond("/wxa/business/getuserphonenumber", `{"errcode":40029,"errmsg":"invalid code"}`)
+	client, _ := newTestClient(t, server)
+
+	if _, err := client.GetUserPhoneNumber(testAuthorizerAppId, "code"); !errors.Is(err, ErrPhoneCodeExpired) {
+		t.Fatalf("got %v, want ErrPhoneCodeExpired", err)
+	}
+}
@@ -0,0 +1,85 @@
+package open
+
+import "errors"
+
+// PrivacyInterfaceStatus 隐私接口申请状态
+type PrivacyInterfaceStatus int
+
+const (
+	PrivacyInterfaceNotApplied   PrivacyInterfaceStatus = 1 // 待申请开通
+	PrivacyInterfaceNoPermission PrivacyInterfaceStatus = 2 // 无权限
+	PrivacyInterfaceApplying     PrivacyInterfaceStatus = 3 // 申请中
+	PrivacyInterfaceRejected     PrivacyInterfaceStatus = 4 // 申请失败
+	PrivacyInterfaceApproved     PrivacyInterfaceStatus = 5 // 已开通
+)
+
+func (self PrivacyInterfaceStatus) String() string {
+	switch self {
+	case PrivacyInterfaceNotApplied:
+		return "未申请"
+	case PrivacyInterfaceNoPermission:
+		return "无权限"
+	case PrivacyInterfaceApplying:
+		return "申请中"
+	case PrivacyInterfaceRejected:
+		return "驳回"
+	case PrivacyInterfaceApproved:
+		return "通过"
+	}
+	return "未知"
+}
+
+// PrivacyInterface 隐私接口信息
+type PrivacyInterface struct {
+	ApiName    string                 `json:"api_name"`
+	ApiChName  string                 `json:"api_ch_name"`
+	ApiDesc    string                 `json:"api_desc"`
+	ApiLink    string                 `json:"api_link"`
+	GroupName  string                 `json:"group_name"`
+	ApplyTime  int64                  `json:"apply_time"`
+	Status     PrivacyInterfaceStatus `json:"status"`
+	AuditId    int64                  `json:"audit_id"`
+	FailReason string                 `json:"fail_reason"`
+}
+
+// PrivacyInterfaceApplication 隐私接口申请, 图片和视频为UploadTempMedia返回的media_id
+type PrivacyInterfaceApplication struct {
+	ApiName   string   `json:"api_name"`
+	Content   string   `json:"content"`
+	UrlList   []string `json:"url_list,omitempty"`
+	PicList   []string `json:"pic_list,omitempty"`
+	VideoList []string `json:"video_list,omitempty"`
+}
+
+// GetPrivacyInterfaces 获取隐私接口列表及申请状态
+func (self *AuthorizerClient) GetPrivacyInterfaces() ([]PrivacyInterface, error) {
+	token, err := self.AccessToken()
+	if err != nil {
+		return nil, err
+	}
+	var resp struct {
+		InterfaceList []PrivacyInterface `json:"interface_list"`
+	}
+	if err := self.client.getJSON(self.client.Endpoint.GetPrivacyInterface(token), &resp); err != nil {
+		return nil, err
+	}
+	return resp.InterfaceList, nil
+}
+
+// ApplyPrivacyInterface 申请隐私接口, 返回审核单id
+func (self *AuthorizerClient) ApplyPrivacyInterface(req PrivacyInterfaceApplication) (int64, error) {
+	if req.ApiName == "" || req.Content == "" {
+		return 0, errors.New("接口英文名和申请原因不能为空")
+	}
+	token, err := self.AccessToken()
+	if err != nil {
+		return 0, err
+	}
+	var resp struct {
+		AuditId int64 `json:"audit_id"`
+	}
+	if err := self.client.postJSON(self.client.Endpoint.ApplyPrivacyInterface(token), req, &resp); err != nil {
+		return 0, err
+	}
+	return resp.AuditId, nil
+}
@@ -0,0 +1,99 @@
+package open
+
+import "testing"
+
+func TestGetPrivacyInterfaces(t *testing.T) {
+	server := newTestServer(t)
+	server.respond("/wxa/security/get_privacy_interface", `{"errcode":0,"errmsg":"ok","interface_list":[`+
+		`{"api_name":"wx.getLocation","api_ch_name":"获取当前的地理位置","api_desc":"获取当前的地理位置、速度","api_link":"https://developers.weixin.qq.com/getLocation","group_name":"地理位置","status":5,"apply_time":1620281006,"audit_id":420958012},`+
+		`{"api_name":"wx.chooseLocation","api_ch_name":"打开地图选择位置","status":4,"apply_time":1620281120,"audit_id":420958013,"fail_reason":"申请原因描述不清晰"},`+
+		`{"api_name":"wx.choosePoi","api_ch_name":"打开POI列表选择位置","status":3,"apply_time":1620281201,"audit_id":420958014},`+
+		`{"api_name":"wx.getFuzzyLocation","api_ch_name":"获取模糊地理位置","status":1}]}`)
+	client, _ := newTestClient(t, server)
+
+	interfaces, err := client.Authorizer(testAuthorizerAppId).GetPrivacyInterfaces()
+	if err != nil {
+		t.Fatal(err)
+	}
+	req := server.lastRequest(t)
+	if req.Method != "GET" {
+		t.Fatalf("method: got %s", req.Method)
+	}
+	if got := req.Query["access_token"]; len(got) != 1 || got[0] != testAuthorizerToken {
+		t.Fatalf("access_token: got %v", got)
+	}
+	if len(interfaces) != 4 {
+		t.Fatalf("interfaces: got %+v", interfaces)
+	}
+	approved := PrivacyInterface{
+		ApiName:   "wx.getLocation",
+		ApiChName: "获取当前的地理位置",
+		ApiDesc:   "获取当前的地理位置、速度",
+		ApiLink:   "https://developers.weixin.qq.com/getLocation",
+		GroupName: "地理位置",
+		ApplyTime: 1620281006,
+		Status:    PrivacyInterfaceApproved,
+		AuditId:   420958012,
+	}
+	if interfaces[0] != approved {
+		t.Fatalf("approved: got %+v", interfaces[0])
+	}
+	for i, want := range []struct {
+		status PrivacyInterfaceStatus
+		name   string
+	}{
+		{PrivacyInterfaceApproved, "通过"},
+		{PrivacyInterfaceRejected, "驳回"},
+		{PrivacyInterfaceApplying, "申请中"},
+		{PrivacyInterfaceNotApplied, "未申请"},
+	} {
+		if interfaces[i].Status != want.status || interfaces[i].Status.String() != want.name {
+			t.Errorf("%s: got status %d (%s), want %s", interfaces[i].ApiName, interfaces[i].Status, interfaces[i].Status, want.name)
+		}
+	}
+	if interfaces[1].FailReason != "申请原因描述不清晰" {
+		t.Fatalf("fail_reason: got %q", interfaces[1].FailReason)
+	}
+	if PrivacyInterfaceStatus(0).String() != "未知" {
+		t.Fatalf("unknown status: got %s", PrivacyInterfaceStatus(0))
+	}
+}
+
+func TestApplyPrivacyInterface(t *testing.T) {
+	server := newTestServer(t)
+	server.respond("/wxa/security/apply_privacy_interface", `{"errcode":0,"errmsg":"ok","audit_id":420958015}`)
+	client, _ := newTestClient(t, server)
+
+	auditId, err := client.Authorizer(testAuthorizerAppId).ApplyPrivacyInterface(PrivacyInterfaceApplication{
+		ApiName: "wx.chooseLocation",
+		Content: "用于门店自提时选择收货地址",
+		UrlList: []string{"https://example.com/demo.png"},
+		PicList: []string{"MEDIA_ID"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if auditId != 420958015 {
+		t.Fatalf("audit_id: got %d", auditId)
+	}
+	want := `{"api_name":"wx.chooseLocation","content":"用于门店自提时选择收货地址","url_list":["https://example.com/demo.png"],"pic_list":["MEDIA_ID"]}`
+	if body := string(server.lastRequest(t).Body); body != want {
+		t.Fatalf("body:\n got %s\nwant %s", body, want)
+	}
+}
+
+func TestApplyPrivacyInterfaceValidation(t *testing.T) {
+	server := newTestServer(t)
+	client, _ := newTestClient(t, server)
+	authorizer := client.Authorizer(testAuthorizerAppId)
+
+	if _, err := authorizer.ApplyPrivacyInterface(PrivacyInterfaceApplication{Content: "原因"}); err == nil {
+		t.Error("missing api_name: expected validation error")
+	}
+	if _, err := authorizer.ApplyPrivacyInterface(PrivacyInterfaceApplication{ApiName: "wx.getLocation"}); err == nil {
+		t.Error("missing content: expected validation error")
+	}
+	if n := server.requestCount(); n != 0 {
+		t.Fatalf("invalid requests sent %d requests", n)
+	}
+}
@@ -0,0 +1,133 @@
+package open
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/mrwangjinjin/go-wechat/core"
+	"io"
+	"net/url"
+	"time"
+)
+
+const (
+	// qrcodeMaxExpire 临时二维码最长有效期
+	qrcodeMaxExpire = 30 * 24 * time.Hour
+	// qrcodeMaxLimitSceneId 永久二维码scene_id最大值
+	qrcodeMaxLimitSceneId = 100000
+	// qrcodeMaxSceneStrLen 场景值字符串最大长度
+	qrcodeMaxSceneStrLen = 64
+)
+
+// 二维码类型
+const (
+	QrScene         = "QR_SCENE"
+	QrStrScene      = "QR_STR_SCENE"
+	QrLimitScene    = "QR_LIMIT_SCENE"
+	QrLimitStrScene = "QR_LIMIT_STR_SCENE"
+)
+
+// QrcodeTicket 带参数二维码, 永久二维码的ExpireSeconds为0
+type QrcodeTicket struct {
+	Ticket        string `json:"ticket"`
+	Url           string `json:"url"`
+	ExpireSeconds int64  `json:"expire_seconds"`
+}
+
+// qrcodeScene 根据场景值类型选择整型或字符串型二维码, 返回action_name及action_info
+func qrcodeScene(scene interface{}, permanent bool) (string, map[string]interface{}, error) {
+	var sceneId int64
+	switch v := scene.(type) {
+	case string:
+		if v == "" || len(v) > qrcodeMaxSceneStrLen {
+			return "", nil, errors.New("scene_str长度为1-64")
+		}
+		action := QrStrScene
+		if permanent {
+			action = QrLimitStrScene
+		}
+		return action, map[string]interface{}{"scene": map[string]interface{}{"scene_str": v}}, nil
+	case int:
+		sceneId = int64(v)
+	case int32:
+		sceneId = int64(v)
+	case int64:
+		sceneId = v
+	case uint32:
+		sceneId = int64(v)
+	default:
+		return "", nil, fmt.Errorf("不支持的场景值类型:%T", scene)
+	}
+	if permanent {
+		if sceneId < 1 || sceneId > qrcodeMaxLimitSceneId {
+			return "", nil, errors.New("永久二维码scene_id取值范围为1-100000")
+		}
+		return QrLimitScene, map[string]interface{}{"scene": map[string]interface{}{"scene_id": sceneId}}, nil
+	}
+	if sceneId <= 0 || sceneId > 1<<32-1 {
+		return "", nil, errors.New("临时二维码scene_id为32位非0整数")
+	}
+	return QrScene, map[string]interface{}{"scene": map[string]interface{}{"scene_id": sceneId}}, nil
+}
+
+func (self *AuthorizerClient) createQrcode(data map[string]interface{}) (*QrcodeTicket, error) {
+	token, err := self.AccessToken()
+	if err != nil {
+		return nil, err
+	}
+	var ticket QrcodeTicket
+	if err := self.client.postJSON(self.client.Endpoint.CreateQrcode(token), data, &ticket); err != nil {
+		return nil, err
+	}
+	return &ticket, nil
+}
+
+// CreateTempQrcode 创建临时二维码, scene为整数时生成QR_SCENE, 为字符串时生成QR_STR_SCENE
+// expire最长30天, 为0时使用微信默认的60秒
+func (self *AuthorizerClient) CreateTempQrcode(scene interface{}, expire time.Duration) (*QrcodeTicket, error) {
+	if expire < 0 || expire > qrcodeMaxExpire {
+		return nil, errors.New("临时二维码有效期最长30天")
+	}
+	action, info, err := qrcodeScene(scene, false)
+	if err != nil {
+		return nil, err
+	}
+	data := map[string]interface{}{
+		"action_name": action,
+		"action_info": info,
+	}
+	if expire > 0 {
+		data["expire_seconds"] = int64(expire / time.Second)
+	}
+	return self.createQrcode(data)
+}
+
+// CreatePermanentQrcode 创建永久二维码, scene为整数时生成QR_LIMIT_SCENE, 为字符串时生成QR_LIMIT_STR_SCENE
+func (self *AuthorizerClient) CreatePermanentQrcode(scene interface{}) (*QrcodeTicket, error) {
+	action, info, err := qrcodeScene(scene, true)
+	if err != nil {
+		return nil, err
+	}
+	return self.createQrcode(map[string]interface{}{
+		"action_name": action,
+		"action_info": info,
+	})
+}
+
+// ShowQrcodeUrl 通过ticket换取二维码图片的地址
+func ShowQrcodeUrl(ticket string) string {
+	return fmt.Sprintf("%s?ticket=%s", core.ShowQrcodeUrl, url.QueryEscape(ticket))
+}
+
+// DownloadQrcode 下载ticket对应的二维码图片并写入w
+func (self *AuthorizerClient) DownloadQrcode(ticket string, w io.Writer) error {
+	if ticket == "" {
+		return errors.New("ticket不能为空")
+	}
+	body, err := self.client.getBinary(context.Background(), self.client.Endpoint.ShowQrcode(url.QueryEscape(ticket)))
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(body)
+	return err
+}
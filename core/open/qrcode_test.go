@@ -0,0 +1,106 @@
+package open
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCreateQrcodeActions(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		permanent bool
+		scene     interface{}
+		expire    time.Duration
+		want      string
+	}{
+		{
+			name:   "QR_SCENE",
+			scene:  123,
+			expire: 7 * 24 * time.Hour,
+			want:   `{"action_info":{"scene":{"scene_id":123}},"action_name":"QR_SCENE","expire_seconds":604800}`,
+		},
+		{
+			name:  "QR_STR_SCENE",
+			scene: "campaign_2024",
+			want:  `{"action_info":{"scene":{"scene_str":"campaign_2024"}},"action_name":"QR_STR_SCENE"}`,
+		},
+		{
+			name:      "QR_LIMIT_SCENE",
+			permanent: true,
+			scene:     int64(100000),
+			want:      `{"action_info":{"scene":{"scene_id":100000}},"action_name":"QR_LIMIT_SCENE"}`,
+		},
+		{
+			name:      "QR_LIMIT_STR_SCENE",
+			permanent: true,
+			scene:     "channel_a",
+			want:      `{"action_info":{"scene":{"scene_str":"channel_a"}},"action_name":"QR_LIMIT_STR_SCENE"}`,
+		},
+	} {
+		server := newTestServer(t)
+		server.respond("/cgi-bin/qrcode/create", `{"ticket":"gQH47joAAAAAAAAAASxodHRwOi8vd2VpeGluLnFxLmNvbS9xL2taZ2Z3TVRtNzJXV1Brb3ZhYmJJAAIEZ23sUwMEmm3sUw==","expire_seconds":604800,"url":"http://weixin.qq.com/q/kZgfwMTm72WWPkovabbI"}`)
+		client, _ := newTestClient(t, server)
+		authorizer := client.Authorizer(testAuthorizerAppId)
+
+		var ticket *QrcodeTicket
+		var err error
+		if tc.permanent {
+			ticket, err = authorizer.CreatePermanentQrcode(tc.scene)
+		} else {
+			ticket, err = authorizer.CreateTempQrcode(tc.scene, tc.expire)
+		}
+		if err != nil {
+			t.Fatalf("%s: %v", tc.name, err)
+		}
+		if ticket.Url != "http://weixin.qq.com/q/kZgfwMTm72WWPkovabbI" || ticket.ExpireSeconds != 604800 || ticket.Ticket == "" {
+			t.Fatalf("%s: ticket: got %+v", tc.name, ticket)
+		}
+		req := server.lastRequest(t)
+		if got := req.Query["access_token"]; len(got) != 1 || got[0] != testAuthorizerToken {
+			t.Fatalf("%s: access_token: got %v", tc.name, got)
+		}
+		if string(req.Body) != tc.want {
+			t.Errorf("%s: body:\n got %s\nwant %s", tc.name, req.Body, tc.want)
+		}
+	}
+}
+
+func TestCreateQrcodeValidation(t *testing.T) {
+	server := newTestServer(t)
+	client, _ := newTestClient(t, server)
+	authorizer := client.Authorizer(testAuthorizerAppId)
+
+	longScene := strings.Repeat("a", 65)
+	for _, tc := range []struct {
+		name   string
+		scene  interface{}
+		expire time.Duration
+	}{
+		{"expire over 30 days", 1, 31 * 24 * time.Hour},
+		{"negative expire", 1, -time.Second},
+		{"zero scene_id", 0, time.Hour},
+		{"empty scene_str", "", time.Hour},
+		{"scene_str over 64 bytes", longScene, time.Hour},
+		{"unsupported scene type", 1.5, time.Hour},
+	} {
+		if _, err := authorizer.CreateTempQrcode(tc.scene, tc.expire); err == nil {
+			t.Errorf("temp %s: expected validation error", tc.name)
+		}
+	}
+	for _, scene := range []interface{}{0, 100001, longScene} {
+		if _, err := authorizer.CreatePermanentQrcode(scene); err == nil {
+			t.Errorf("permanent scene %v: expected validation error", scene)
+		}
+	}
+	if n := server.requestCount(); n != 0 {
+		t.Fatalf("invalid scenes sent %d requests", n)
+	}
+}
+
+func TestShowQrcodeUrl(t *testing.T) {
+	got := ShowQrcodeUrl("gQH47joAAAAAAAAAASxodHRwOi8v+/==")
+	if want := "https://mp.weixin.qq.com/cgi-bin/showqrcode?ticket=gQH47joAAAAAAAAAASxodHRwOi8v%2B%2F%3D%3D"; got != want {
+		t.Fatalf("url:\n got %s\nwant %s", got, want)
+	}
+}
@@ -0,0 +1,126 @@
+package open
+
+import "github.com/mrwangjinjin/go-wechat/pkg/util"
+
+// QueryAuthResult 使用授权码换取的授权信息, FuncInfo为授权给第三方平台的权限集id列表
+type QueryAuthResult struct {
+	AuthorizerAppId        string
+	AuthorizerAccessToken  string
+	ExpiresIn              int64
+	AuthorizerRefreshToken string
+	FuncInfo               []int
+}
+
+// HasFunc 是否授权了指定权限集
+func (self *QueryAuthResult) HasFunc(id int) bool {
+	for _, funcId := range self.FuncInfo {
+		if funcId == id {
+			return true
+		}
+	}
+	return false
+}
+
+// rawQueryAuthResult authorization_info的原始结构
+type rawQueryAuthResult struct {
+	AuthorizerAppId        string `json:"authorizer_appid"`
+	AuthorizerAccessToken  string `json:"authorizer_access_token"`
+	ExpiresIn              int64  `json:"expires_in"`
+	AuthorizerRefreshToken string `json:"authorizer_refresh_token"`
+	FuncInfo               []struct {
+		FuncScopeCategory struct {
+			Id int `json:"id"`
+		} `json:"funcscope_category"`
+	} `json:"func_info"`
+}
+
+func (self *rawQueryAuthResult) result() *QueryAuthResult {
+	result := &QueryAuthResult{
+		AuthorizerAppId:        self.AuthorizerAppId,
+		AuthorizerAccessToken:  self.AuthorizerAccessToken,
+		ExpiresIn:              self.ExpiresIn,
+		AuthorizerRefreshToken: self.AuthorizerRefreshToken,
+	}
+	for _, info := range self.FuncInfo {
+		result.FuncInfo = append(result.FuncInfo, info.FuncScopeCategory.Id)
+	}
+	return result
+}
+
+// saveFuncInfo 持久保存授权方的权限集列表, 不随authorizer_access_token刷新而丢失
+func (self *Client) saveFuncInfo(authorizerAppId string, funcInfo []int) {
+	if funcInfo == nil {
+		funcInfo = []int{}
+	}
+	key := AuthorizerFuncInfoKeyPrefix + authorizerAppId
+	self.reportCacheError(key, self.Cache.Set(key, map[string]interface{}{
+		"func_info": funcInfo,
+	}))
+}
+
+// HasPermission 授权方是否授权了指定权限集, 如18为小程序开发管理
+// 优先使用换取授权信息时缓存的权限集列表, 未缓存(如早于缓存功能的授权或缓存被淘汰)时
+// 通过api_get_authorizer_info查询并缓存
+func (self *Client) HasPermission(authorizerAppId string, permissionId int) (bool, error) {
+	funcInfo, ok := self.cachedFuncInfo(authorizerAppId)
+	if !ok {
+		var err error
+		funcInfo, err = self.fetchFuncInfo(authorizerAppId)
+		if err != nil {
+			return false, err
+		}
+	}
+	for _, id := range funcInfo {
+		if id == permissionId {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// cachedFuncInfo 读取缓存的权限集列表
+func (self *Client) cachedFuncInfo(authorizerAppId string) ([]int, bool) {
+	key := AuthorizerFuncInfoKeyPrefix + authorizerAppId
+	if !self.Cache.Exists(key) {
+		return nil, false
+	}
+	resp, err := self.Cache.Get(key)
+	if err != nil {
+		return nil, false
+	}
+	ids, ok := util.JsonUnmarshal(resp)["func_info"].([]interface{})
+	if !ok {
+		return nil, false
+	}
+	funcInfo := make([]int, 0, len(ids))
+	for _, id := range ids {
+		if value, ok := id.(float64); ok {
+			funcInfo = append(funcInfo, int(value))
+		}
+	}
+	return funcInfo, true
+}
+
+// fetchFuncInfo 通过获取授权方信息接口查询权限集列表并写入缓存
+func (self *Client) fetchFuncInfo(authorizerAppId string) ([]int, error) {
+	token, err := self.ApiComponentToken()
+	if err != nil {
+		return nil, err
+	}
+	var resp struct {
+		AuthorizationInfo rawQueryAuthResult `json:"authorization_info"`
+	}
+	err = self.postJSON(self.Endpoint.ApiAuthorizerInfo(token), map[string]interface{}{
+		"component_appid":  self.AppId,
+		"authorizer_appid": authorizerAppId,
+	}, &resp)
+	if err != nil {
+		return nil, err
+	}
+	if resp.AuthorizationInfo.AuthorizerAppId == "" {
+		return nil, ErrAuthorizerNotAuthorized
+	}
+	funcInfo := resp.AuthorizationInfo.result().FuncInfo
+	self.saveFuncInfo(authorizerAppId, funcInfo)
+	return funcInfo, nil
+}
@@ -0,0 +1,57 @@
+package open
+
+import "testing"
+
+const testAuthorizationInfo = `{
+	"authorizer_appid": "wx_authorizer",
+	"authorizer_access_token": "ACCESS_TOKEN",
+	"expires_in": 7200,
+	"authorizer_refresh_token": "REFRESH_TOKEN",
+	"func_info": [
+		{"funcscope_category": {"id": 17}},
+		{"funcscope_category": {"id": 18}}
+	]
+}`
+
+func TestQueryAuthCachesFuncInfo(t *testing.T) {
+	server := newTestServer(t)
+	server.respond("/cgi-bin/component/api_query_auth", `{"authorization_info":`+testAuthorizationInfo+`}`)
+	client, _ := newTestClient(t, server)
+
+	result, err := client.QueryAuth("AUTH_CODE")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.AuthorizerAppId != testAuthorizerAppId || !result.HasFunc(18) || result.HasFunc(1) {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+	count := server.requestCount()
+	ok, err := client.HasPermission(testAuthorizerAppId, 18)
+	if err != nil || !ok {
+		t.Fatalf("HasPermission(18): got (%v, %v), want (true, nil)", ok, err)
+	}
+	if server.requestCount() != count {
+		t.Fatal("HasPermission must use the cached func_info")
+	}
+}
+
+func TestHasPermissionFallsBackToAuthorizerInfo(t *testing.T) {
+	server := newTestServer(t)
+	server.respond("/cgi-bin/component/api_get_authorizer_info", `{"authorizer_info":{"nick_name":"demo"},"authorization_info":`+testAuthorizationInfo+`}`)
+	client, _ := newTestClient(t, server)
+
+	ok, err := client.HasPermission(testAuthorizerAppId, 18)
+	if err != nil || !ok {
+		t.Fatalf("HasPermission(18): got (%v, %v), want (true, nil)", ok, err)
+	}
+	if body := decodeBody(t, server.lastRequest(t)); body["authorizer_appid"] != testAuthorizerAppId {
+		t.Fatalf("unexpected body: %v", body)
+	}
+	ok, err = client.HasPermission(testAuthorizerAppId, 1)
+	if err != nil || ok {
+		t.Fatalf("HasPermission(1): got (%v, %v), want (false, nil)", ok, err)
+	}
+	if n := server.requestCount(); n != 1 {
+		t.Fatalf("got %d requests, want the fallback result to be cached", n)
+	}
+}
@@ -0,0 +1,233 @@
+package open
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/mrwangjinjin/go-wechat/core"
+	"github.com/mrwangjinjin/go-wechat/pkg/util"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// maxBodySnippet 错误信息中保留的响应体最大长度
+const maxBodySnippet = 200
+
+// postJSON 以JSON格式提交请求, 并将响应解析到result
+func (self *Client) postJSON(url string, data interface{}, result interface{}) error {
+	return self.postJSONContext(context.Background(), url, data, result)
+}
+
+// postJSONContext 以JSON格式提交请求, 并将响应解析到result, ctx取消时中止请求
+func (self *Client) postJSONContext(ctx context.Context, url string, data interface{}, result interface{}) error {
+	status, body, err := self.Http.PostJSON(ctx, url, data)
+	if err != nil {
+		return err
+	}
+	if status != http.StatusOK {
+		return errors.New("网络错误")
+	}
+	return self.logApiError(url, decodeResponse(body, result))
+}
+
+// getJSON 发起GET请求, 并将响应解析到result
+func (self *Client) getJSON(url string, result interface{}) error {
+	status, body, err := self.Http.Get(url)
+	if err != nil {
+		return err
+	}
+	if status != http.StatusOK {
+		return errors.New("网络错误")
+	}
+	return self.logApiError(url, decodeResponse(body, result))
+}
+
+// postMultipart 以multipart/form-data格式上传文件, 并将响应解析到result
+func (self *Client) postMultipart(url, fieldName, filename string, file io.Reader, fields map[string]string, result interface{}) error {
+	status, body, err := self.Http.PostMultipart(url, fieldName, filename, file, fields)
+	if err != nil {
+		return err
+	}
+	if status != http.StatusOK {
+		return errors.New("网络错误")
+	}
+	return self.logApiError(url, decodeResponse(body, result))
+}
+
+// postBinary 以JSON格式提交请求, 返回二进制响应(如图片), 响应为JSON错误时返回错误
+func (self *Client) postBinary(ctx context.Context, url string, data interface{}) ([]byte, error) {
+	status, header, body, err := self.Http.PostJSONWithHeader(ctx, url, data)
+	if err != nil {
+		return nil, err
+	}
+	if status != http.StatusOK {
+		return nil, errors.New("网络错误")
+	}
+	result, err := decodeBinaryResponse(header.Get("Content-Type"), body)
+	return result, self.logApiError(url, err)
+}
+
+// decodeBinaryResponse 二进制接口出错时返回JSON, 优先按Content-Type判断响应格式,
+// 图片类型直接返回, JSON类型解析errcode, 缺少Content-Type时才检查响应体
+func decodeBinaryResponse(contentType string, body []byte) ([]byte, error) {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	switch {
+	case strings.HasPrefix(mediaType, "image/"):
+		return body, nil
+	case isJSONResponse(contentType, body):
+		if err := decodeResponse(body, nil); err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("响应不是二进制内容: %s", bodySnippet(body))
+	}
+	return body, nil
+}
+
+// decodeResponse 检查errcode, 成功时将响应解析到result
+func decodeResponse(body []byte, result interface{}) error {
+	var apiErr Error
+	if err := json.Unmarshal(body, &apiErr); err != nil {
+		return fmt.Errorf("响应不是有效的JSON: %v, body: %s", err, bodySnippet(body))
+	}
+	if apiErr.ErrCode != 0 {
+		return &apiErr
+	}
+	if result == nil {
+		return nil
+	}
+	return json.Unmarshal(body, result)
+}
+
+// isJSONResponse 根据Content-Type判断响应是否为JSON, 缺少Content-Type时检查响应体
+func isJSONResponse(contentType string, body []byte) bool {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	return mediaType == core.ContentTypeJSON || mediaType == "text/plain" || (mediaType == "" && looksLikeJSON(body))
+}
+
+// looksLikeJSON 判断响应体是否为JSON对象
+func looksLikeJSON(body []byte) bool {
+	return bytes.HasPrefix(bytes.TrimSpace(body), []byte("{"))
+}
+
+// parseResponse 将响应解析为map, 响应不是JSON对象时返回包含响应片段的错误,
+// errcode非0时记录警告日志, 错误仍由调用方通过responseError等读取
+func (self *Client) parseResponse(rawUrl string, body []byte) (map[string]interface{}, error) {
+	if looksLikeJSON(body) {
+		if resp := util.JsonUnmarshalBytes(body); resp != nil {
+			_ = self.logApiError(rawUrl, responseError(resp))
+			return resp, nil
+		}
+	}
+	return nil, fmt.Errorf("响应不是有效的JSON, body: %s", bodySnippet(body))
+}
+
+// bodySnippet 截取响应体前maxBodySnippet字节, 用于错误信息
+func bodySnippet(body []byte) string {
+	body = bytes.TrimSpace(body)
+	if len(body) > maxBodySnippet {
+		return string(body[:maxBodySnippet]) + "..."
+	}
+	return string(body)
+}
+
+// getBinary 发起GET请求, 返回二进制响应(如图片), 响应为JSON错误时返回错误
+func (self *Client) getBinary(ctx context.Context, url string) ([]byte, error) {
+	status, header, body, err := self.Http.GetWithHeader(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+	if status != http.StatusOK {
+		return nil, errors.New("网络错误")
+	}
+	result, err := decodeBinaryResponse(header.Get("Content-Type"), body)
+	return result, self.logApiError(url, err)
+}
+
+// responseError 安全读取已解析响应中的errcode, 非0时返回*Error
+func responseError(resp map[string]interface{}) error {
+	if resp == nil {
+		return errors.New("响应格式错误")
+	}
+	code, _ := resp["errcode"].(float64)
+	if code == 0 {
+		return nil
+	}
+	msg, _ := resp["errmsg"].(string)
+	return &Error{ErrCode: int64(code), ErrMsg: msg}
+}
+
+// requireString 安全读取响应中的字符串字段, 缺失时优先返回响应中的错误
+func requireString(resp map[string]interface{}, key string) (string, error) {
+	if value, ok := resp[key].(string); ok && value != "" {
+		return value, nil
+	}
+	if err := responseError(resp); err != nil {
+		return "", err
+	}
+	return "", fmt.Errorf("响应缺少%s", key)
+}
+
+// requireMap 安全读取响应中的对象字段, 缺失时优先返回响应中的错误
+func requireMap(resp map[string]interface{}, key string) (map[string]interface{}, error) {
+	if value, ok := resp[key].(map[string]interface{}); ok {
+		return value, nil
+	}
+	if err := responseError(resp); err != nil {
+		return nil, err
+	}
+	return nil, fmt.Errorf("响应缺少%s", key)
+}
+
+// requireInt64 安全读取响应中的数值字段, 缺失时优先返回响应中的错误
+func requireInt64(resp map[string]interface{}, key string) (int64, error) {
+	if value, ok := resp[key].(float64); ok {
+		return int64(value), nil
+	}
+	if err := responseError(resp); err != nil {
+		return 0, err
+	}
+	return 0, fmt.Errorf("响应缺少%s", key)
+}
+
+// dryRunAccessToken 试运行时尚未获取令牌, 以此代替请求地址中的access_token
+const dryRunAccessToken = "***"
+
+// dryRun 试运行模式下序列化请求体并记录目标地址(隐去access_token), 返回true时调用方不应发送请求
+func (self *Client) dryRun(rawUrl string, data interface{}) (bool, error) {
+	if !self.DryRun {
+		return false, nil
+	}
+	body, err := json.Marshal(data)
+	if err != nil {
+		return true, err
+	}
+	if u, err := url.Parse(rawUrl); err == nil {
+		query := u.Query()
+		if query.Get("access_token") != "" {
+			query.Set("access_token", "***")
+			u.RawQuery = query.Encode()
+		}
+		rawUrl = u.String()
+	}
+	self.logger().Printf("dry run: POST %s %s", rawUrl, body)
+	return true, nil
+}
+
+// logApiError 以警告级别记录微信返回的errcode及接口路径(不含access_token), 原样返回err
+func (self *Client) logApiError(rawUrl string, err error) error {
+	var apiErr *Error
+	if !errors.As(err, &apiErr) {
+		return err
+	}
+	endpoint := rawUrl
+	if u, parseErr := url.Parse(rawUrl); parseErr == nil {
+		endpoint = u.Path
+	}
+	self.logger().Printf("[WARN] wechat api error endpoint=%s errcode=%d errmsg=%q", endpoint, apiErr.ErrCode, apiErr.ErrMsg)
+	return err
+}
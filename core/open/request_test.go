@@ -0,0 +1,99 @@
+package open
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestApiErrorIsLoggedAsWarning(t *testing.T) {
+	server := newTestServer(t)
+	server.respond("/wxa/undocodeaudit", `{"errcode":48001,"errmsg":"api unauthorized"}`)
+	server.respond("/wxa/release", `{"errcode":48001,"errmsg":"api unauthorized"}`)
+	client, logger := newTestClient(t, server)
+
+	// parseResponse路径
+	if err := client.UndoCodeAudit(testAuthorizerToken, nil); !errors.Is(err, ErrApiUnauthorized) {
+		t.Fatalf("UndoCodeAudit: got %v, want ErrApiUnauthorized", err)
+	}
+	// postJSON路径
+	if err := client.Release(testAuthorizerToken, nil); !errors.Is(err, ErrApiUnauthorized) {
+		t.Fatalf("Release: got %v, want ErrApiUnauthorized", err)
+	}
+
+	logged := logger.String()
+	for _, want := range []string{
+		`[WARN] wechat api error endpoint=/wxa/undocodeaudit errcode=48001 errmsg="api unauthorized"`,
+		`[WARN] wechat api error endpoint=/wxa/release errcode=48001 errmsg="api unauthorized"`,
+	} {
+		if !strings.Contains(logged, want) {
+			t.Errorf("log missing %q, got:\n%s", want, logged)
+		}
+	}
+	if strings.Contains(logged, testAuthorizerToken) {
+		t.Errorf("log leaks access_token:\n%s", logged)
+	}
+}
+
+func TestSuccessIsNotLogged(t *testing.T) {
+	server := newTestServer(t)
+	client, logger := newTestClient(t, server)
+	if err := client.Release(testAuthorizerToken, nil); err != nil {
+		t.Fatal(err)
+	}
+	if logged := logger.String(); strings.Contains(logged, "[WARN]") {
+		t.Errorf("unexpected warning: %s", logged)
+	}
+}
+
+func TestNilLoggerUsesDefault(t *testing.T) {
+	server := newTestServer(t)
+	server.respond("/wxa/release", `{"errcode":48001,"errmsg":"api unauthorized"}`)
+	client, _ := newTestClient(t, server)
+	client.Logger = nil
+	if err := client.Release(testAuthorizerToken, nil); !errors.Is(err, ErrApiUnauthorized) {
+		t.Fatalf("Release: got %v, want ErrApiUnauthorized", err)
+	}
+	client.DryRun = true
+	if err := client.Release(testAuthorizerToken, nil); err != nil {
+		t.Fatalf("dry run Release: %v", err)
+	}
+}
+
+func TestHTMLResponseWithStatusOK(t *testing.T) {
+	html := "<html><head><title>502 Bad Gateway</title></head><body>" + strings.Repeat("x", 300) + "</body></html>"
+	server := newTestServer(t)
+	server.respondBinary("/wxa/get_latest_auditstatus", "text/html", []byte(html))
+	server.respondBinary("/wxa/release", "text/html", []byte(html))
+	client, _ := newTestClient(t, server)
+
+	// parseResponse路径
+	resp, err := client.GetLastAuditStatus(testAuthorizerToken)
+	if err == nil || resp != nil {
+		t.Fatalf("GetLastAuditStatus: got (%v, %v), want an error", resp, err)
+	}
+	if !strings.Contains(err.Error(), "<title>502 Bad Gateway</title>") || !strings.HasSuffix(err.Error(), "...") {
+		t.Fatalf("GetLastAuditStatus: error lacks a truncated body snippet: %v", err)
+	}
+	// postJSON路径
+	if err := client.Release(testAuthorizerToken, nil); err == nil || !strings.Contains(err.Error(), "502 Bad Gateway") {
+		t.Fatalf("Release: got %v, want an error with the body snippet", err)
+	}
+}
+
+func TestDecodeResponseRejectsHTML(t *testing.T) {
+	var result struct {
+		Status int `json:"status"`
+	}
+	err := decodeResponse([]byte("<html>error</html>"), &result)
+	if err == nil || !strings.Contains(err.Error(), "<html>error</html>") {
+		t.Fatalf("got %v, want an error with the body", err)
+	}
+	var apiErr *Error
+	if errors.As(err, &apiErr) {
+		t.Fatalf("HTML body reported as wechat error %v", apiErr)
+	}
+	if err := decodeResponse([]byte(`{"errcode":0,"status":2}`), &result); err != nil || result.Status != 2 {
+		t.Fatalf("got (%+v, %v)", result, err)
+	}
+}
@@ -0,0 +1,53 @@
+package open
+
+import (
+	"errors"
+	"net"
+)
+
+// RiskScene 风控场景
+type RiskScene int
+
+const (
+	RiskSceneRegister  RiskScene = 0 // 注册
+	RiskSceneMarketing RiskScene = 1 // 营销作弊
+)
+
+// RiskRankRequest 用户安全等级查询, AppId为空时使用授权方appid
+type RiskRankRequest struct {
+	AppId        string    `json:"appid"`
+	OpenId       string    `json:"openid"`
+	Scene        RiskScene `json:"scene"`
+	MobileNo     string    `json:"mobile_no,omitempty"`
+	ClientIp     string    `json:"client_ip"`
+	EmailAddress string    `json:"email_address,omitempty"`
+	ExtendedInfo string    `json:"extended_info,omitempty"`
+	IsTest       bool      `json:"is_test,omitempty"`
+}
+
+// GetUserRiskRank 获取用户安全等级, 返回0-4, 等级越高风险越大; 未开通时返回ErrApiUnauthorized
+func (self *AuthorizerClient) GetUserRiskRank(req RiskRankRequest) (int, error) {
+	if req.AppId == "" {
+		req.AppId = self.AuthorizerAppId
+	}
+	if req.OpenId == "" {
+		return 0, errors.New("openid不能为空")
+	}
+	if req.Scene != RiskSceneRegister && req.Scene != RiskSceneMarketing {
+		return 0, errors.New("scene取值为0或1")
+	}
+	if net.ParseIP(req.ClientIp) == nil {
+		return 0, errors.New("client_ip不是合法的IP地址")
+	}
+	token, err := self.AccessToken()
+	if err != nil {
+		return 0, err
+	}
+	var resp struct {
+		RiskRank int `json:"risk_rank"`
+	}
+	if err := self.client.postJSON(self.client.Endpoint.GetUserRiskRank(token), req, &resp); err != nil {
+		return 0, err
+	}
+	return resp.RiskRank, nil
+}
@@ -0,0 +1,88 @@
+package open
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestGetUserRiskRank(t *testing.T) {
+	server := newTestServer(t)
+	server.respond("/wxa/getuserriskrank", `{"errcode":0,"errmsg":"getuserriskrank succ","risk_rank":3,"unoin_id":123456}`)
+	client, _ := newTestClient(t, server)
+
+	rank, err := client.Authorizer(testAuthorizerAppId).GetUserRiskRank(RiskRankRequest{
+		OpenId:       "OPENID",
+		Scene:        RiskSceneMarketing,
+		MobileNo:     "12345678",
+		ClientIp:     "203.0.113.7",
+		EmailAddress: "user@example.com",
+		ExtendedInfo: "coupon",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rank != 3 {
+		t.Fatalf("risk_rank: got %d, want 3", rank)
+	}
+	req := server.lastRequest(t)
+	if got := req.Query["access_token"]; len(got) != 1 || got[0] != testAuthorizerToken {
+		t.Fatalf("access_token: got %v", got)
+	}
+	want := `{"appid":"wx_authorizer","openid":"OPENID","scene":1,"mobile_no":"12345678","client_ip":"203.0.113.7","email_address":"user@example.com","extended_info":"coupon"}`
+	if string(req.Body) != want {
+		t.Fatalf("body:\n got %s\nwant %s", req.Body, want)
+	}
+}
+
+func TestGetUserRiskRankOmitsOptionalFields(t *testing.T) {
+	server := newTestServer(t)
+	server.respond("/wxa/getuserriskrank", `{"errcode":0,"errmsg":"ok","risk_rank":0}`)
+	client, _ := newTestClient(t, server)
+
+	rank, err := client.Authorizer(testAuthorizerAppId).GetUserRiskRank(RiskRankRequest{
+		AppId:    "wx_other",
+		OpenId:   "OPENID",
+		Scene:    RiskSceneRegister,
+		ClientIp: "2001:db8::1",
+		IsTest:   true,
+	})
+	if err != nil || rank != 0 {
+		t.Fatalf("got (%d, %v), want 0", rank, err)
+	}
+	want := `{"appid":"wx_other","openid":"OPENID","scene":0,"client_ip":"2001:db8::1","is_test":true}`
+	if body := string(server.lastRequest(t).Body); body != want {
+		t.Fatalf("body:\n got %s\nwant %s", body, want)
+	}
+}
+
+func TestGetUserRiskRankNotEnabled(t *testing.T) {
+	server := newTestServer(t)
+	server.respond("/wxa/getuserriskrank", `{"errcode":48001,"errmsg":"api unauthorized"}`)
+	client, _ := newTestClient(t, server)
+
+	_, err := client.Authorizer(testAuthorizerAppId).GetUserRiskRank(RiskRankRequest{OpenId: "OPENID", ClientIp: "203.0.113.7"})
+	if !errors.Is(err, ErrApiUnauthorized) {
+		t.Fatalf("got %v, want ErrApiUnauthorized", err)
+	}
+}
+
+func TestGetUserRiskRankValidation(t *testing.T) {
+	server := newTestServer(t)
+	client, _ := newTestClient(t, server)
+	authorizer := client.Authorizer(testAuthorizerAppId)
+
+	for name, req := range map[string]RiskRankRequest{
+		"missing openid": {ClientIp: "203.0.113.7"},
+		"unknown scene":  {OpenId: "OPENID", Scene: 2, ClientIp: "203.0.113.7"},
+		"missing ip":     {OpenId: "OPENID"},
+		"invalid ip":     {OpenId: "OPENID", ClientIp: "203.0.113.256"},
+		"hostname":       {OpenId: "OPENID", ClientIp: "example.com"},
+	} {
+		if _, err := authorizer.GetUserRiskRank(req); err == nil {
+			t.Errorf("%s: expected validation error", name)
+		}
+	}
+	if n := server.requestCount(); n != 0 {
+		t.Fatalf("invalid requests sent %d requests", n)
+	}
+}
@@ -0,0 +1,38 @@
+package open
+
+import "errors"
+
+// JumpWxa 跳转到的小程序页面
+type JumpWxa struct {
+	Path       string `json:"path,omitempty"`
+	Query      string `json:"query,omitempty"`
+	EnvVersion string `json:"env_version,omitempty"`
+}
+
+// GenerateNfcScheme 获取NFC的小程序scheme, 只能通过已注册的NFC设备打开; 设备型号未注册时返回ErrNfcModelNotRegistered
+func (self *AuthorizerClient) GenerateNfcScheme(jump JumpWxa, modelId, sn string) (string, error) {
+	if modelId == "" {
+		return "", errors.New("model_id不能为空")
+	}
+	if err := checkEnvVersion(jump.EnvVersion); err != nil {
+		return "", err
+	}
+	token, err := self.AccessToken()
+	if err != nil {
+		return "", err
+	}
+	data := map[string]interface{}{
+		"jump_wxa": jump,
+		"model_id": modelId,
+	}
+	if sn != "" {
+		data["sn"] = sn
+	}
+	var resp struct {
+		OpenLink string `json:"openlink"`
+	}
+	if err := self.client.postJSON(self.client.Endpoint.GenerateNfcScheme(token), data, &resp); err != nil {
+		return "", err
+	}
+	return resp.OpenLink, nil
+}
@@ -0,0 +1,72 @@
+package open
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestGenerateNfcScheme(t *testing.T) {
+	server := newTestServer(t)
+	server.respond("/wxa/generatenfcscheme", `{"errcode":0,"errmsg":"ok","openlink":"weixin://dl/business/?t=NFC_TICKET"}`)
+	client, _ := newTestClient(t, server)
+
+	openLink, err := client.Authorizer(testAuthorizerAppId).GenerateNfcScheme(JumpWxa{
+		Path:       "pages/device/index",
+		Query:      "id=1",
+		EnvVersion: "trial",
+	}, "MODEL_ID", "SN_0001")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if openLink != "weixin://dl/business/?t=NFC_TICKET" {
+		t.Fatalf("openlink: got %s", openLink)
+	}
+	req := server.lastRequest(t)
+	if got := req.Query["access_token"]; len(got) != 1 || got[0] != testAuthorizerToken {
+		t.Fatalf("access_token: got %v", got)
+	}
+	want := `{"jump_wxa":{"path":"pages/device/index","query":"id=1","env_version":"trial"},"model_id":"MODEL_ID","sn":"SN_0001"}`
+	if string(req.Body) != want {
+		t.Fatalf("body:\n got %s\nwant %s", req.Body, want)
+	}
+}
+
+func TestGenerateNfcSchemeOmitsEmptyFields(t *testing.T) {
+	server := newTestServer(t)
+	server.respond("/wxa/generatenfcscheme", `{"errcode":0,"errmsg":"ok","openlink":"weixin://dl/business/?t=NFC_TICKET"}`)
+	client, _ := newTestClient(t, server)
+
+	if _, err := client.Authorizer(testAuthorizerAppId).GenerateNfcScheme(JumpWxa{}, "MODEL_ID", ""); err != nil {
+		t.Fatal(err)
+	}
+	if body := string(server.lastRequest(t).Body); body != `{"jump_wxa":{},"model_id":"MODEL_ID"}` {
+		t.Fatalf("body: got %s", body)
+	}
+}
+
+func TestGenerateNfcSchemeModelNotRegistered(t *testing.T) {
+	server := newTestServer(t)
+	server.respond("/wxa/generatenfcscheme", `{"errcode":9800001,"errmsg":"model_id not registered"}`)
+	client, _ := newTestClient(t, server)
+
+	_, err := client.Authorizer(testAuthorizerAppId).GenerateNfcScheme(JumpWxa{}, "UNKNOWN_MODEL", "")
+	if !errors.Is(err, ErrNfcModelNotRegistered) {
+		t.Fatalf("got %v, want ErrNfcModelNotRegistered", err)
+	}
+}
+
+func TestGenerateNfcSchemeValidation(t *testing.T) {
+	server := newTestServer(t)
+	client, _ := newTestClient(t, server)
+	authorizer := client.Authorizer(testAuthorizerAppId)
+
+	if _, err := authorizer.GenerateNfcScheme(JumpWxa{}, "", "SN_0001"); err == nil {
+		t.Error("missing model_id: expected validation error")
+	}
+	if _, err := authorizer.GenerateNfcScheme(JumpWxa{EnvVersion: "beta"}, "MODEL_ID", ""); err == nil {
+		t.Error("invalid env_version: expected validation error")
+	}
+	if n := server.requestCount(); n != 0 {
+		t.Fatalf("invalid requests sent %d requests", n)
+	}
+}
@@ -0,0 +1,152 @@
+package open
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"github.com/mrwangjinjin/go-wechat/core"
+	"unicode/utf8"
+)
+
+// msgSecCheckMaxContent 文本内容安全检测的最大字数
+const msgSecCheckMaxContent = 2500
+
+// 内容安全检测场景
+const (
+	SecSceneProfile   = 1 // 资料
+	SecSceneComment   = 2 // 评论
+	SecSceneForum     = 3 // 论坛
+	SecSceneSocialLog = 4 // 社交日志
+)
+
+// 媒体类型
+const (
+	MediaTypeAudio = 1
+	MediaTypeImage = 2
+)
+
+// MsgSecCheckRequest 文本内容安全检测请求
+type MsgSecCheckRequest struct {
+	Content   string `json:"content"`
+	Version   int    `json:"version"`
+	Scene     int    `json:"scene"`
+	OpenId    string `json:"openid"`
+	Title     string `json:"title,omitempty"`
+	Nickname  string `json:"nickname,omitempty"`
+	Signature string `json:"signature,omitempty"`
+}
+
+// MsgSecCheckResult 文本内容安全检测结果
+type MsgSecCheckResult struct {
+	TraceId string                `json:"trace_id"`
+	Result  core.SecurityResult   `json:"result"`
+	Detail  []core.SecurityDetail `json:"detail"`
+}
+
+// IsRisky 是否命中风险内容
+func (self *MsgSecCheckResult) IsRisky() bool {
+	return self.Result.Suggest.IsRisky()
+}
+
+// MsgSecCheck 文本内容安全检测(2.0版本)
+func (self *AuthorizerClient) MsgSecCheck(req MsgSecCheckRequest) (*MsgSecCheckResult, error) {
+	if req.Content == "" {
+		return nil, errors.New("检测内容不能为空")
+	}
+	if utf8.RuneCountInString(req.Content) > msgSecCheckMaxContent {
+		return nil, errors.New("检测内容不能超过2500字")
+	}
+	if req.OpenId == "" {
+		return nil, errors.New("openid不能为空")
+	}
+	if req.Version == 0 {
+		req.Version = 2
+	}
+	token, err := self.AccessToken()
+	if err != nil {
+		return nil, err
+	}
+	var result MsgSecCheckResult
+	if err := self.client.postJSON(self.client.Endpoint.MsgSecCheck(token), req, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// MsgSecCheck 使用指定授权方的令牌进行文本内容安全检测
+func (self *Client) MsgSecCheck(authorizerAppId string, req MsgSecCheckRequest) (*MsgSecCheckResult, error) {
+	return self.Authorizer(authorizerAppId).MsgSecCheck(req)
+}
+
+// MediaCheckRequest 音视频内容安全异步检测请求, Version为空时默认为2
+type MediaCheckRequest struct {
+	MediaUrl  string `json:"media_url"`
+	MediaType int    `json:"media_type"`
+	Version   int    `json:"version"`
+	OpenId    string `json:"openid"`
+	Scene     int    `json:"scene"`
+}
+
+// MediaCheckCallback 音视频内容安全异步检测结果
+type MediaCheckCallback = core.MediaCheckEvent
+
+// MediaCheckAsync 音视频内容安全异步检测, 返回trace_id
+// 检测结果通过wxa_media_check事件推送, 可使用core.EventDispatcher.OnMediaCheckTrace接收
+func (self *AuthorizerClient) MediaCheckAsync(mediaUrl string, mediaType int, openId string, scene int) (string, error) {
+	return self.MediaCheck(MediaCheckRequest{
+		MediaUrl:  mediaUrl,
+		MediaType: mediaType,
+		OpenId:    openId,
+		Scene:     scene,
+	})
+}
+
+// MediaCheck 音视频内容安全异步检测, 返回trace_id
+func (self *AuthorizerClient) MediaCheck(req MediaCheckRequest) (string, error) {
+	if req.MediaUrl == "" {
+		return "", errors.New("media_url不能为空")
+	}
+	if req.MediaType != MediaTypeAudio && req.MediaType != MediaTypeImage {
+		return "", errors.New("media_type无效")
+	}
+	if req.Version == 0 {
+		req.Version = 2
+	}
+	if req.Version != 2 {
+		return "", errors.New("仅支持2.0版本的接口")
+	}
+	if req.OpenId == "" {
+		return "", errors.New("openid不能为空")
+	}
+	if req.Scene < SecSceneProfile || req.Scene > SecSceneSocialLog {
+		return "", errors.New("scene取值范围为1-4")
+	}
+	token, err := self.AccessToken()
+	if err != nil {
+		return "", err
+	}
+	var resp struct {
+		TraceId string `json:"trace_id"`
+	}
+	if err := self.client.postJSON(self.client.Endpoint.MediaCheckAsync(token), req, &resp); err != nil {
+		return "", err
+	}
+	return resp.TraceId, nil
+}
+
+// MediaCheckAsync 使用指定授权方的令牌进行音视频内容安全异步检测, 返回trace_id
+func (self *Client) MediaCheckAsync(authorizerAppId string, req MediaCheckRequest) (string, error) {
+	return self.Authorizer(authorizerAppId).MediaCheck(req)
+}
+
+// ParseMediaCheckCallback 解析解密后的wxa_media_check推送
+func ParseMediaCheckCallback(plaintext []byte) (*MediaCheckCallback, error) {
+	var callback MediaCheckCallback
+	if err := xml.Unmarshal(plaintext, &callback); err != nil {
+		return nil, err
+	}
+	if callback.Event != core.EventWxaMediaCheck {
+		return nil, fmt.Errorf("不是音视频内容安全检测推送: %s", callback.Event)
+	}
+	return &callback, nil
+}
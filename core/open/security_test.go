@@ -0,0 +1,234 @@
+package open
+
+import (
+	"github.com/mrwangjinjin/go-wechat/core"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestMsgSecCheckPass(t *testing.T) {
+	server := newTestServer(t)
+	server.respond("/wxa/msg_sec_check", `{"errcode":0,"errmsg":"ok","result":{"suggest":"pass","label":100},"detail":[{"strategy":"content_model","errcode":0,"suggest":"pass","label":100,"prob":90}],"trace_id":"60ae120f-371d5872-7941a05b"}`)
+	client, _ := newTestClient(t, server)
+
+	result, err := client.Authorizer(testAuthorizerAppId).MsgSecCheck(MsgSecCheckRequest{
+		Content: "你好",
+		Scene:   SecSceneComment,
+		OpenId:  "OPENID",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.IsRisky() || result.Result.Suggest != core.SuggestPass || result.TraceId != "60ae120f-371d5872-7941a05b" {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+	req := server.lastRequest(t)
+	if got := req.Query["access_token"]; len(got) != 1 || got[0] != testAuthorizerToken {
+		t.Fatalf("access_token: got %v", got)
+	}
+	body := decodeBody(t, req)
+	want := map[string]interface{}{"content": "你好", "version": float64(2), "scene": float64(SecSceneComment), "openid": "OPENID"}
+	if !reflect.DeepEqual(body, want) {
+		t.Fatalf("body: got %v, want %v", body, want)
+	}
+}
+
+func TestMsgSecCheckRisky(t *testing.T) {
+	server := newTestServer(t)
+	server.respond("/wxa/msg_sec_check", `{"errcode":0,"errmsg":"ok","result":{"suggest":"risky","label":20001},"detail":[{"strategy":"content_model","errcode":0,"suggest":"risky","label":20001,"prob":90},{"strategy":"keyword","errcode":0,"suggest":"risky","label":20006,"level":20,"keyword":"命中词"}],"trace_id":"60ae120f-371d5872-7941a05c"}`)
+	client, _ := newTestClient(t, server)
+
+	result, err := client.MsgSecCheck(testAuthorizerAppId, MsgSecCheckRequest{
+		Content: "命中词",
+		Scene:   SecSceneForum,
+		OpenId:  "OPENID",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.IsRisky() || result.Result.Label != 20001 {
+		t.Fatalf("unexpected result: %+v", result.Result)
+	}
+	want := []core.SecurityDetail{
+		{Strategy: "content_model", Suggest: core.SuggestRisky, Label: 20001, Prob: 90},
+		{Strategy: "keyword", Suggest: core.SuggestRisky, Label: 20006, Level: 20, Keyword: "命中词"},
+	}
+	if !reflect.DeepEqual(result.Detail, want) {
+		t.Fatalf("detail: got %+v, want %+v", result.Detail, want)
+	}
+}
+
+func TestMsgSecCheckValidation(t *testing.T) {
+	server := newTestServer(t)
+	client, _ := newTestClient(t, server)
+	authorizer := client.Authorizer(testAuthorizerAppId)
+
+	for name, req := range map[string]MsgSecCheckRequest{
+		"empty content": {Scene: SecSceneComment, OpenId: "OPENID"},
+		"too long":      {Content: strings.Repeat("字", msgSecCheckMaxContent+1), Scene: SecSceneComment, OpenId: "OPENID"},
+		"no openid":     {Content: "你好", Scene: SecSceneComment},
+	} {
+		if _, err := authorizer.MsgSecCheck(req); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+	if n := server.requestCount(); n != 0 {
+		t.Fatalf("invalid requests sent %d requests", n)
+	}
+}
+
+func TestMediaCheckAsync(t *testing.T) {
+	server := newTestServer(t)
+	server.respond("/wxa/media_check_async", `{"errcode":0,"errmsg":"ok","trace_id":"967e945cd8a3e458f3c74dcb886068e9"}`)
+	client, _ := newTestClient(t, server)
+
+	traceId, err := client.Authorizer(testAuthorizerAppId).MediaCheckAsync("https://example.com/a.png", MediaTypeImage, "OPENID", SecSceneProfile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if traceId != "967e945cd8a3e458f3c74dcb886068e9" {
+		t.Fatalf("trace_id: got %q", traceId)
+	}
+	body := decodeBody(t, server.lastRequest(t))
+	want := map[string]interface{}{"media_url": "https://example.com/a.png", "media_type": float64(MediaTypeImage), "version": float64(2), "openid": "OPENID", "scene": float64(SecSceneProfile)}
+	if !reflect.DeepEqual(body, want) {
+		t.Fatalf("body: got %v, want %v", body, want)
+	}
+
+	for name, call := range map[string]func() (string, error){
+		"no media_url": func() (string, error) {
+			return client.Authorizer(testAuthorizerAppId).MediaCheckAsync("", MediaTypeImage, "OPENID", SecSceneProfile)
+		},
+		"bad media_type": func() (string, error) {
+			return client.Authorizer(testAuthorizerAppId).MediaCheckAsync("https://example.com/a.png", 3, "OPENID", SecSceneProfile)
+		},
+		"bad scene": func() (string, error) {
+			return client.Authorizer(testAuthorizerAppId).MediaCheckAsync("https://example.com/a.png", MediaTypeImage, "OPENID", 5)
+		},
+	} {
+		if _, err := call(); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+	if n := server.requestCount(); n != 1 {
+		t.Fatalf("got %d requests, want 1", n)
+	}
+}
+
+func TestParseMediaCheckCallbackRisky(t *testing.T) {
+	push := `<xml><ToUserName><![CDATA[gh_38cc49f9733b]]></ToUserName><FromUserName><![CDATA[oH1fu0FdHqpToe2T6gBj0WyB8iS1]]></FromUserName><CreateTime>1626959646</CreateTime><MsgType><![CDATA[event]]></MsgType><Event><![CDATA[wxa_media_check]]></Event><appid><![CDATA[wx_authorizer]]></appid><trace_id><![CDATA[967e945cd8a3e458f3c74dcb886068e9]]></trace_id><version>2</version><detail><strategy><![CDATA[content_model]]></strategy><errcode>0</errcode><suggest><![CDATA[risky]]></suggest><label>20002</label><prob>90</prob></detail><errcode>0</errcode><errmsg><![CDATA[ok]]></errmsg><result><suggest><![CDATA[risky]]></suggest><label>20002</label></result></xml>`
+
+	callback, err := ParseMediaCheckCallback([]byte(push))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if callback.TraceId != "967e945cd8a3e458f3c74dcb886068e9" || callback.AppId != testAuthorizerAppId || !callback.Result.Suggest.IsRisky() || callback.Result.Label != 20002 {
+		t.Fatalf("unexpected callback: %+v", callback)
+	}
+	want := []core.SecurityDetail{{Strategy: "content_model", Suggest: core.SuggestRisky, Label: 20002, Prob: 90}}
+	if !reflect.DeepEqual(callback.Detail, want) {
+		t.Fatalf("detail: got %+v, want %+v", callback.Detail, want)
+	}
+
+	if _, err := ParseMediaCheckCallback([]byte(`<xml><Event><![CDATA[weapp_audit_success]]></Event></xml>`)); err == nil {
+		t.Fatal("expected error for other events")
+	}
+}
+
+func TestMsgSecCheckContentLimit(t *testing.T) {
+	server := newTestServer(t)
+	server.respond("/wxa/msg_sec_check", `{"errcode":0,"errmsg":"ok","result":{"suggest":"pass","label":100},"trace_id":"TRACE"}`)
+	client, _ := newTestClient(t, server)
+
+	content := strings.Repeat("字", msgSecCheckMaxContent)
+	if _, err := client.MsgSecCheck(testAuthorizerAppId, MsgSecCheckRequest{Content: content, Scene: SecSceneSocialLog, OpenId: "OPENID"}); err != nil {
+		t.Fatalf("content at the limit: %v", err)
+	}
+	if got := decodeBody(t, server.lastRequest(t))["content"]; got != content {
+		t.Fatal("content at the limit was not sent verbatim")
+	}
+}
+
+func TestMsgSecCheckOptionalFields(t *testing.T) {
+	server := newTestServer(t)
+	server.respond("/wxa/msg_sec_check", `{"errcode":0,"errmsg":"ok","result":{"suggest":"review","label":21000},"trace_id":"TRACE"}`)
+	client, _ := newTestClient(t, server)
+
+	result, err := client.MsgSecCheck(testAuthorizerAppId, MsgSecCheckRequest{
+		Content:   "个人简介",
+		Scene:     SecSceneProfile,
+		OpenId:    "OPENID",
+		Title:     "标题",
+		Nickname:  "昵称",
+		Signature: "签名",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Result.Suggest != core.SuggestReview || result.Result.Label != 21000 || result.IsRisky() {
+		t.Fatalf("unexpected result: %+v", result.Result)
+	}
+	want := `{"content":"个人简介","version":2,"scene":1,"openid":"OPENID","title":"标题","nickname":"昵称","signature":"签名"}`
+	if body := string(server.lastRequest(t).Body); body != want {
+		t.Fatalf("body:\n got %s\nwant %s", body, want)
+	}
+}
+
+func TestClientMediaCheckAsync(t *testing.T) {
+	server := newTestServer(t)
+	server.respond("/wxa/media_check_async", `{"errcode":0,"errmsg":"ok","trace_id":"TRACE_AUDIO"}`)
+	client, _ := newTestClient(t, server)
+
+	traceId, err := client.MediaCheckAsync(testAuthorizerAppId, MediaCheckRequest{
+		MediaUrl:  "https://example.com/a.mp3",
+		MediaType: MediaTypeAudio,
+		Version:   2,
+		OpenId:    "OPENID",
+		Scene:     SecSceneSocialLog,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if traceId != "TRACE_AUDIO" {
+		t.Fatalf("trace_id: got %q", traceId)
+	}
+	req := server.lastRequest(t)
+	if got := req.Query["access_token"]; len(got) != 1 || got[0] != testAuthorizerToken {
+		t.Fatalf("access_token: got %v", got)
+	}
+	want := `{"media_url":"https://example.com/a.mp3","media_type":1,"version":2,"openid":"OPENID","scene":4}`
+	if string(req.Body) != want {
+		t.Fatalf("body:\n got %s\nwant %s", req.Body, want)
+	}
+
+	if _, err := client.MediaCheckAsync(testAuthorizerAppId, MediaCheckRequest{
+		MediaUrl:  "https://example.com/a.mp3",
+		MediaType: MediaTypeAudio,
+		Version:   1,
+		OpenId:    "OPENID",
+		Scene:     SecSceneSocialLog,
+	}); err == nil {
+		t.Fatal("version 1: expected validation error")
+	}
+	if n := server.requestCount(); n != 1 {
+		t.Fatalf("got %d requests, want 1", n)
+	}
+}
+
+func TestParseMediaCheckCallbackPass(t *testing.T) {
+	push := `<xml><ToUserName><![CDATA[gh_38cc49f9733b]]></ToUserName><FromUserName><![CDATA[oH1fu0FdHqpToe2T6gBj0WyB8iS1]]></FromUserName><CreateTime>1626959646</CreateTime><MsgType><![CDATA[event]]></MsgType><Event><![CDATA[wxa_media_check]]></Event><appid><![CDATA[wx_authorizer]]></appid><trace_id><![CDATA[TRACE_PASS]]></trace_id><version>2</version><detail><strategy><![CDATA[content_model]]></strategy><errcode>0</errcode><suggest><![CDATA[pass]]></suggest><label>100</label><prob>90</prob></detail><errcode>0</errcode><errmsg><![CDATA[ok]]></errmsg><result><suggest><![CDATA[pass]]></suggest><label>100</label></result></xml>`
+
+	callback, err := ParseMediaCheckCallback([]byte(push))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if callback.TraceId != "TRACE_PASS" || callback.Version != 2 || callback.CreateTime != 1626959646 ||
+		callback.Result.Suggest != core.SuggestPass || callback.Result.Suggest.IsRisky() || len(callback.Detail) != 1 {
+		t.Fatalf("unexpected callback: %+v", callback)
+	}
+
+	if _, err := ParseMediaCheckCallback([]byte(`not xml`)); err == nil {
+		t.Fatal("expected error for invalid XML")
+	}
+}
@@ -0,0 +1,28 @@
+package open
+
+// Session 小程序登录会话
+type Session struct {
+	OpenId     string `json:"openid"`
+	SessionKey string `json:"session_key"`
+	UnionId    string `json:"unionid"`
+}
+
+// Code2SessionResult 同Session
+type Code2SessionResult = Session
+
+// Code2Session 第三方平台代小程序登录, 使用wx.login的code换取session_key, code无效时返回ErrInvalidCode
+// 返回的session_key属于敏感信息, 不会写入日志
+func (self *Client) Code2Session(authorizerAppId, jsCode string) (*Session, error) {
+	if jsCode == "" {
+		return nil, ErrInvalidCode
+	}
+	token, err := self.ApiComponentToken()
+	if err != nil {
+		return nil, err
+	}
+	var session Session
+	if err := self.getJSON(self.Endpoint.JsCode2Session(authorizerAppId, jsCode, self.AppId, token), &session); err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
@@ -0,0 +1,66 @@
+package open
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCode2Session(t *testing.T) {
+	server := newTestServer(t)
+	server.respond("/sns/component/jscode2session", `{"openid":"OPENID","session_key":"SESSIONKEY","unionid":"UNIONID"}`)
+	client, logger := newTestClient(t, server)
+
+	session, err := client.Code2Session(testAuthorizerAppId, "JSCODE")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Code2SessionResult{OpenId: "OPENID", SessionKey: "SESSIONKEY", UnionId: "UNIONID"}
+	if *session != want {
+		t.Fatalf("got %+v, want %+v", *session, want)
+	}
+	req := server.lastRequest(t)
+	for key, value := range map[string]string{
+		"appid":                  testAuthorizerAppId,
+		"js_code":                "JSCODE",
+		"grant_type":             "authorization_code",
+		"component_appid":        testAppId,
+		"component_access_token": testComponentToken,
+	} {
+		if got := req.Query[key]; len(got) != 1 || got[0] != value {
+			t.Errorf("query %s: got %v, want %s", key, got, value)
+		}
+	}
+	if logged := logger.String(); logged != "" {
+		t.Errorf("session must not be logged, got: %s", logged)
+	}
+}
+
+func TestCode2SessionWithoutUnionId(t *testing.T) {
+	server := newTestServer(t)
+	server.respond("/sns/component/jscode2session", `{"openid":"OPENID","session_key":"SESSIONKEY"}`)
+	client, _ := newTestClient(t, server)
+
+	session, err := client.Code2Session(testAuthorizerAppId, "JSCODE")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if session.OpenId != "OPENID" || session.SessionKey != "SESSIONKEY" || session.UnionId != "" {
+		t.Fatalf("got %+v", *session)
+	}
+}
+
+func TestCode2SessionInvalidCode(t *testing.T) {
+	server := newTestServer(t)
+	server.respond("/sns/component/jscode2session", `{"errcode":40029,"errmsg":"invalid code"}`)
+	client, _ := newTestClient(t, server)
+
+	if _, err := client.Code2Session(testAuthorizerAppId, "bad"); !errors.Is(err, ErrInvalidCode) {
+		t.Fatalf("got %v, want ErrInvalidCode", err)
+	}
+	if _, err := client.Code2Session(testAuthorizerAppId, ""); !errors.Is(err, ErrInvalidCode) {
+		t.Fatalf("empty code: got %v, want ErrInvalidCode", err)
+	}
+	if n := server.requestCount(); n != 1 {
+		t.Fatalf("got %d requests, want 1", n)
+	}
+}
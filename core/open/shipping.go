@@ -0,0 +1,192 @@
+package open
+
+import (
+	"errors"
+	"time"
+)
+
+// shippingTimeLayout 发货时间格式, RFC3339且精确到毫秒
+const shippingTimeLayout = "2006-01-02T15:04:05.000Z07:00"
+
+// 订单单号类型
+const (
+	OrderNumberTypeOutTradeNo    = 1 // 商户号+商户订单号
+	OrderNumberTypeTransactionId = 2 // 微信支付单号
+)
+
+// 物流模式
+const (
+	LogisticsTypeExpress  = 1 // 实体物流配送
+	LogisticsTypeLocal    = 2 // 同城配送
+	LogisticsTypeVirtual  = 3 // 虚拟商品
+	LogisticsTypeSelfPick = 4 // 用户自提
+)
+
+// 发货模式
+const (
+	DeliveryModeUnified = 1 // 统一发货
+	DeliveryModeSplit   = 2 // 分拆发货
+)
+
+// OrderKey 订单标识, 使用NewOrderKeyByTransactionId或NewOrderKeyByOutTradeNo创建
+type OrderKey struct {
+	OrderNumberType int    `json:"order_number_type"`
+	TransactionId   string `json:"transaction_id,omitempty"`
+	MchId           string `json:"mchid,omitempty"`
+	OutTradeNo      string `json:"out_trade_no,omitempty"`
+}
+
+// NewOrderKeyByTransactionId 按微信支付单号标识订单
+func NewOrderKeyByTransactionId(transactionId string) OrderKey {
+	return OrderKey{OrderNumberType: OrderNumberTypeTransactionId, TransactionId: transactionId}
+}
+
+// NewOrderKeyByOutTradeNo 按商户号和商户订单号标识订单
+func NewOrderKeyByOutTradeNo(mchId, outTradeNo string) OrderKey {
+	return OrderKey{OrderNumberType: OrderNumberTypeOutTradeNo, MchId: mchId, OutTradeNo: outTradeNo}
+}
+
+func (self *OrderKey) validate() error {
+	switch self.OrderNumberType {
+	case OrderNumberTypeTransactionId:
+		if self.TransactionId == "" || self.MchId != "" || self.OutTradeNo != "" {
+			return errors.New("按微信支付单号标识订单时只能填写transaction_id")
+		}
+	case OrderNumberTypeOutTradeNo:
+		if self.MchId == "" || self.OutTradeNo == "" || self.TransactionId != "" {
+			return errors.New("按商户订单号标识订单时必须且只能填写mchid和out_trade_no")
+		}
+	default:
+		return errors.New("order_number_type取值为1或2")
+	}
+	return nil
+}
+
+// ShippingContact 联系方式, 顺丰必填, 需掩码处理
+type ShippingContact struct {
+	ConsignorContact string `json:"consignor_contact,omitempty"`
+	ReceiverContact  string `json:"receiver_contact,omitempty"`
+}
+
+// ShippingItem 物流信息
+type ShippingItem struct {
+	TrackingNo     string           `json:"tracking_no,omitempty"`
+	ExpressCompany string           `json:"express_company,omitempty"`
+	ItemDesc       string           `json:"item_desc"`
+	Contact        *ShippingContact `json:"contact,omitempty"`
+}
+
+// ShippingInfoRequest 发货信息, UploadTime为空时使用当前时间
+type ShippingInfoRequest struct {
+	OrderKey       OrderKey
+	LogisticsType  int
+	DeliveryMode   int
+	IsAllDelivered bool
+	ShippingList   []ShippingItem
+	UploadTime     time.Time
+	PayerOpenId    string
+}
+
+// ShippingOrder 订单发货信息
+type ShippingOrder struct {
+	TransactionId   string `json:"transaction_id"`
+	MerchantId      string `json:"merchant_id"`
+	SubMerchantId   string `json:"sub_merchant_id"`
+	MerchantTradeNo string `json:"merchant_trade_no"`
+	Description     string `json:"description"`
+	PaidAmount      int64  `json:"paid_amount"`
+	OpenId          string `json:"openid"`
+	TradeCreateTime int64  `json:"trade_create_time"`
+	PayTime         int64  `json:"pay_time"`
+	InComplaint     bool   `json:"in_complaint"`
+	OrderState      int    `json:"order_state"`
+	Shipping        struct {
+		DeliveryMode        int            `json:"delivery_mode"`
+		LogisticsType       int            `json:"logistics_type"`
+		FinishShipping      bool           `json:"finish_shipping"`
+		GoodsDesc           string         `json:"goods_desc"`
+		FinishShippingCount int            `json:"finish_shipping_count"`
+		ShippingList        []ShippingItem `json:"shipping_list"`
+	} `json:"shipping"`
+}
+
+// UploadShippingInfo 发货信息录入
+func (self *AuthorizerClient) UploadShippingInfo(req ShippingInfoRequest) error {
+	if err := req.OrderKey.validate(); err != nil {
+		return err
+	}
+	if req.LogisticsType < LogisticsTypeExpress || req.LogisticsType > LogisticsTypeSelfPick {
+		return errors.New("logistics_type取值范围为1-4")
+	}
+	if req.DeliveryMode == 0 {
+		req.DeliveryMode = DeliveryModeUnified
+	}
+	if len(req.ShippingList) == 0 || len(req.ShippingList) > 10 {
+		return errors.New("物流信息为1-10条")
+	}
+	if req.PayerOpenId == "" {
+		return errors.New("支付者openid不能为空")
+	}
+	if req.UploadTime.IsZero() {
+		req.UploadTime = time.Now()
+	}
+	token, err := self.AccessToken()
+	if err != nil {
+		return err
+	}
+	data := map[string]interface{}{
+		"order_key":      req.OrderKey,
+		"logistics_type": req.LogisticsType,
+		"delivery_mode":  req.DeliveryMode,
+		"shipping_list":  req.ShippingList,
+		"upload_time":    req.UploadTime.Format(shippingTimeLayout),
+		"payer":          map[string]string{"openid": req.PayerOpenId},
+	}
+	if req.DeliveryMode == DeliveryModeSplit {
+		data["is_all_delivered"] = req.IsAllDelivered
+	}
+	return self.client.postJSON(self.client.Endpoint.UploadShippingInfo(token), data, nil)
+}
+
+// GetOrder 查询订单发货状态
+func (self *AuthorizerClient) GetOrder(orderKey OrderKey) (*ShippingOrder, error) {
+	if err := orderKey.validate(); err != nil {
+		return nil, err
+	}
+	token, err := self.AccessToken()
+	if err != nil {
+		return nil, err
+	}
+	data := map[string]interface{}{}
+	if orderKey.OrderNumberType == OrderNumberTypeTransactionId {
+		data["transaction_id"] = orderKey.TransactionId
+	} else {
+		data["merchant_id"] = orderKey.MchId
+		data["merchant_trade_no"] = orderKey.OutTradeNo
+	}
+	var resp struct {
+		Order ShippingOrder `json:"order"`
+	}
+	if err := self.client.postJSON(self.client.Endpoint.GetShippingOrder(token), data, &resp); err != nil {
+		return nil, err
+	}
+	return &resp.Order, nil
+}
+
+// IsTradeManaged 查询小程序是否已开通发货信息管理服务
+func (self *AuthorizerClient) IsTradeManaged() (bool, error) {
+	token, err := self.AccessToken()
+	if err != nil {
+		return false, err
+	}
+	var resp struct {
+		IsTradeManaged bool `json:"is_trade_managed"`
+	}
+	err = self.client.postJSON(self.client.Endpoint.IsTradeManaged(token), map[string]interface{}{
+		"appid": self.AuthorizerAppId,
+	}, &resp)
+	if err != nil {
+		return false, err
+	}
+	return resp.IsTradeManaged, nil
+}
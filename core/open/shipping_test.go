@@ -0,0 +1,161 @@
+package open
+
+import (
+	"testing"
+	"time"
+)
+
+// testUploadTime 固定的发货时间, 东八区且带毫秒
+var testUploadTime = time.Date(2024, time.January, 10, 12, 30, 45, 123000000, time.FixedZone("CST", 8*3600))
+
+func TestUploadShippingInfoByTransactionId(t *testing.T) {
+	server := newTestServer(t)
+	client, _ := newTestClient(t, server)
+
+	err := client.Authorizer(testAuthorizerAppId).UploadShippingInfo(ShippingInfoRequest{
+		OrderKey:      NewOrderKeyByTransactionId("4200000000000000000"),
+		LogisticsType: LogisticsTypeExpress,
+		ShippingList: []ShippingItem{{
+			TrackingNo:     "SF1234567890",
+			ExpressCompany: "SF",
+			ItemDesc:       "微信红包抱枕*1个",
+			Contact:        &ShippingContact{ReceiverContact: "189****1234"},
+		}},
+		UploadTime:  testUploadTime,
+		PayerOpenId: "OPENID",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	req := server.lastRequest(t)
+	if req.Path != "/wxa/sec/order/upload_shipping_info" {
+		t.Fatalf("path: got %s", req.Path)
+	}
+	if got := req.Query["access_token"]; len(got) != 1 || got[0] != testAuthorizerToken {
+		t.Fatalf("access_token: got %v", got)
+	}
+	want := `{"delivery_mode":1,"logistics_type":1,"order_key":{"order_number_type":2,"transaction_id":"4200000000000000000"},"payer":{"openid":"OPENID"},` +
+		`"shipping_list":[{"tracking_no":"SF1234567890","express_company":"SF","item_desc":"微信红包抱枕*1个","contact":{"receiver_contact":"189****1234"}}],` +
+		`"upload_time":"2024-01-10T12:30:45.123+08:00"}`
+	if string(req.Body) != want {
+		t.Fatalf("body:\n got %s\nwant %s", req.Body, want)
+	}
+}
+
+func TestUploadShippingInfoByOutTradeNo(t *testing.T) {
+	server := newTestServer(t)
+	client, _ := newTestClient(t, server)
+
+	err := client.Authorizer(testAuthorizerAppId).UploadShippingInfo(ShippingInfoRequest{
+		OrderKey:       NewOrderKeyByOutTradeNo("1900000001", "ORDER_1"),
+		LogisticsType:  LogisticsTypeSelfPick,
+		DeliveryMode:   DeliveryModeSplit,
+		IsAllDelivered: true,
+		ShippingList:   []ShippingItem{{ItemDesc: "自提商品"}},
+		UploadTime:     testUploadTime.UTC(),
+		PayerOpenId:    "OPENID",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"delivery_mode":2,"is_all_delivered":true,"logistics_type":4,"order_key":{"order_number_type":1,"mchid":"1900000001","out_trade_no":"ORDER_1"},"payer":{"openid":"OPENID"},` +
+		`"shipping_list":[{"item_desc":"自提商品"}],"upload_time":"2024-01-10T04:30:45.123Z"}`
+	if body := string(server.lastRequest(t).Body); body != want {
+		t.Fatalf("body:\n got %s\nwant %s", body, want)
+	}
+}
+
+func TestUploadShippingInfoDefaultsUploadTime(t *testing.T) {
+	server := newTestServer(t)
+	client, _ := newTestClient(t, server)
+	before := time.Now().Truncate(time.Millisecond)
+
+	err := client.Authorizer(testAuthorizerAppId).UploadShippingInfo(ShippingInfoRequest{
+		OrderKey:      NewOrderKeyByTransactionId("4200000000000000000"),
+		LogisticsType: LogisticsTypeVirtual,
+		ShippingList:  []ShippingItem{{ItemDesc: "会员卡"}},
+		PayerOpenId:   "OPENID",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	uploadTime, err := time.Parse(time.RFC3339Nano, decodeBody(t, server.lastRequest(t))["upload_time"].(string))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if uploadTime.Before(before) || uploadTime.After(time.Now()) {
+		t.Fatalf("upload_time: got %v, want now", uploadTime)
+	}
+}
+
+func TestUploadShippingInfoValidation(t *testing.T) {
+	server := newTestServer(t)
+	client, _ := newTestClient(t, server)
+	authorizer := client.Authorizer(testAuthorizerAppId)
+	valid := ShippingInfoRequest{
+		OrderKey:      NewOrderKeyByTransactionId("4200000000000000000"),
+		LogisticsType: LogisticsTypeExpress,
+		ShippingList:  []ShippingItem{{ItemDesc: "商品"}},
+		PayerOpenId:   "OPENID",
+	}
+
+	invalid := map[string]func(req *ShippingInfoRequest){
+		"mixed order key":     func(req *ShippingInfoRequest) { req.OrderKey.MchId = "1900000001" },
+		"missing out_trade":   func(req *ShippingInfoRequest) { req.OrderKey = NewOrderKeyByOutTradeNo("1900000001", "") },
+		"unknown key type":    func(req *ShippingInfoRequest) { req.OrderKey = OrderKey{TransactionId: "4200000000000000000"} },
+		"logistics type":      func(req *ShippingInfoRequest) { req.LogisticsType = 5 },
+		"empty shipping list": func(req *ShippingInfoRequest) { req.ShippingList = nil },
+		"too many shipping":   func(req *ShippingInfoRequest) { req.ShippingList = make([]ShippingItem, 11) },
+		"missing payer":       func(req *ShippingInfoRequest) { req.PayerOpenId = "" },
+	}
+	for name, mutate := range invalid {
+		req := valid
+		mutate(&req)
+		if err := authorizer.UploadShippingInfo(req); err == nil {
+			t.Errorf("%s: expected validation error", name)
+		}
+	}
+	if n := server.requestCount(); n != 0 {
+		t.Fatalf("invalid requests sent %d requests", n)
+	}
+}
+
+func TestGetShippingOrder(t *testing.T) {
+	server := newTestServer(t)
+	server.respond("/wxa/sec/order/get_order", `{"errcode":0,"errmsg":"ok","order":{"transaction_id":"4200000000000000000","merchant_id":"1900000001","sub_merchant_id":"","merchant_trade_no":"ORDER_1","description":"微信红包抱枕","paid_amount":1000,"openid":"OPENID","trade_create_time":1704860000,"pay_time":1704860100,"in_complaint":false,"order_state":2,"shipping":{"delivery_mode":1,"logistics_type":1,"finish_shipping":true,"goods_desc":"微信红包抱枕*1个","finish_shipping_count":1,"shipping_list":[{"tracking_no":"SF1234567890","express_company":"SF","item_desc":"微信红包抱枕*1个","contact":{"receiver_contact":"189****1234"}}]}}}`)
+	client, _ := newTestClient(t, server)
+	authorizer := client.Authorizer(testAuthorizerAppId)
+
+	order, err := authorizer.GetOrder(NewOrderKeyByTransactionId("4200000000000000000"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if body := string(server.lastRequest(t).Body); body != `{"transaction_id":"4200000000000000000"}` {
+		t.Fatalf("body: got %s", body)
+	}
+	if order.MerchantTradeNo != "ORDER_1" || order.PaidAmount != 1000 || order.OrderState != 2 || !order.Shipping.FinishShipping ||
+		len(order.Shipping.ShippingList) != 1 || order.Shipping.ShippingList[0].Contact.ReceiverContact != "189****1234" {
+		t.Fatalf("order: got %+v", order)
+	}
+
+	if _, err := authorizer.GetOrder(NewOrderKeyByOutTradeNo("1900000001", "ORDER_1")); err != nil {
+		t.Fatal(err)
+	}
+	if body := string(server.lastRequest(t).Body); body != `{"merchant_id":"1900000001","merchant_trade_no":"ORDER_1"}` {
+		t.Fatalf("body: got %s", body)
+	}
+}
+
+func TestIsTradeManaged(t *testing.T) {
+	server := newTestServer(t)
+	server.respond("/wxa/sec/order/is_trade_managed", `{"errcode":0,"errmsg":"ok","is_trade_managed":true}`)
+	client, _ := newTestClient(t, server)
+
+	managed, err := client.Authorizer(testAuthorizerAppId).IsTradeManaged()
+	if err != nil || !managed {
+		t.Fatalf("got (%v, %v), want true", managed, err)
+	}
+	if body := string(server.lastRequest(t).Body); body != `{"appid":"wx_authorizer"}` {
+		t.Fatalf("body: got %s", body)
+	}
+}
@@ -0,0 +1,71 @@
+package open
+
+import (
+	"errors"
+	"github.com/mrwangjinjin/go-wechat/pkg/util"
+	"time"
+)
+
+const StableTokenCacheKeyPrefix = "CACHE_STABLE_TOKEN@@"
+
+// StableToken 稳定版接口调用凭据
+type StableToken struct {
+	AccessToken string `json:"access_token"`
+	ExpiresIn   int64  `json:"expires_in"`
+}
+
+// GetStableToken 获取稳定版接口调用凭据, 适用于非第三方平台的直连模式
+// forceRefresh为true时强制刷新, 否则优先使用缓存
+func (self *Client) GetStableToken(appId, appSecret string, forceRefresh bool) (*StableToken, error) {
+	if appId == "" || appSecret == "" {
+		return nil, errors.New("appid和secret不能为空")
+	}
+	if !forceRefresh {
+		if token := self.cachedStableToken(appId); token != nil {
+			return token, nil
+		}
+	}
+	var token StableToken
+	err := self.postJSON(self.Endpoint.StableToken(), map[string]interface{}{
+		"grant_type":    "client_credential",
+		"appid":         appId,
+		"secret":        appSecret,
+		"force_refresh": forceRefresh,
+	}, &token)
+	if err != nil {
+		return nil, err
+	}
+	if token.AccessToken == "" || token.ExpiresIn <= 0 {
+		return nil, errors.New("获取稳定版Token失败")
+	}
+	self.cacheSetEx(StableTokenCacheKeyPrefix+appId, map[string]interface{}{
+		"access_token": token.AccessToken,
+		"expires_in":   time.Now().Unix() + token.ExpiresIn,
+	}, token.ExpiresIn)
+	return &token, nil
+}
+
+// cachedStableToken 读取缓存中未过期的稳定版凭据, 不存在或已过期时返回nil
+func (self *Client) cachedStableToken(appId string) *StableToken {
+	if !self.Cache.Exists(StableTokenCacheKeyPrefix + appId) {
+		return nil
+	}
+	resp, err := self.Cache.Get(StableTokenCacheKeyPrefix + appId)
+	if err != nil {
+		return nil
+	}
+	cached := util.JsonUnmarshal(resp)
+	if cached == nil {
+		return nil
+	}
+	accessToken, _ := cached["access_token"].(string)
+	expiresAt, _ := cached["expires_in"].(float64)
+	remaining := int64(expiresAt) - time.Now().Unix()
+	if accessToken == "" || remaining <= 0 {
+		return nil
+	}
+	return &StableToken{
+		AccessToken: accessToken,
+		ExpiresIn:   remaining,
+	}
+}
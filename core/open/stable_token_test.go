@@ -0,0 +1,101 @@
+package open
+
+import (
+	"testing"
+	"time"
+)
+
+func TestGetStableTokenUsesCache(t *testing.T) {
+	server := newTestServer(t)
+	server.respond("/cgi-bin/stable_token", `{"access_token":"STABLE_TOKEN","expires_in":7200}`)
+	client, _ := newTestClient(t, server)
+
+	token, err := client.GetStableToken("wx_direct", "secret", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token.AccessToken != "STABLE_TOKEN" || token.ExpiresIn != 7200 {
+		t.Fatalf("token: got %+v", token)
+	}
+	want := `{"appid":"wx_direct","force_refresh":false,"grant_type":"client_credential","secret":"secret"}`
+	if body := string(server.lastRequest(t).Body); body != want {
+		t.Fatalf("body:\n got %s\nwant %s", body, want)
+	}
+
+	cached, err := client.GetStableToken("wx_direct", "secret", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cached.AccessToken != "STABLE_TOKEN" || cached.ExpiresIn <= 7190 || cached.ExpiresIn > 7200 {
+		t.Fatalf("cached token: got %+v", cached)
+	}
+	if n := server.requestCount(); n != 1 {
+		t.Fatalf("cached token sent %d requests, want 1", n)
+	}
+}
+
+func TestGetStableTokenForceRefresh(t *testing.T) {
+	server := newTestServer(t)
+	server.respondSequence("/cgi-bin/stable_token",
+		`{"access_token":"STABLE_TOKEN","expires_in":7200}`,
+		`{"access_token":"FORCED_TOKEN","expires_in":7200}`,
+	)
+	client, _ := newTestClient(t, server)
+
+	if _, err := client.GetStableToken("wx_direct", "secret", false); err != nil {
+		t.Fatal(err)
+	}
+	token, err := client.GetStableToken("wx_direct", "secret", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token.AccessToken != "FORCED_TOKEN" {
+		t.Fatalf("token: got %+v, want FORCED_TOKEN", token)
+	}
+	bodies := server.requestBodies(t, "/cgi-bin/stable_token")
+	if len(bodies) != 2 || bodies[1]["force_refresh"] != true {
+		t.Fatalf("bodies: got %v, want second request with force_refresh", bodies)
+	}
+
+	cached, err := client.GetStableToken("wx_direct", "secret", false)
+	if err != nil || cached.AccessToken != "FORCED_TOKEN" {
+		t.Fatalf("got (%+v, %v), want cached FORCED_TOKEN", cached, err)
+	}
+	if n := server.requestCount(); n != 2 {
+		t.Fatalf("sent %d requests, want 2", n)
+	}
+}
+
+func TestGetStableTokenRefetchesExpiredEntry(t *testing.T) {
+	server := newTestServer(t)
+	server.respond("/cgi-bin/stable_token", `{"access_token":"STABLE_TOKEN","expires_in":7200}`)
+	client, _ := newTestClient(t, server)
+	_ = client.Cache.SetEx(StableTokenCacheKeyPrefix+"wx_direct", map[string]interface{}{
+		"access_token": "EXPIRED_TOKEN",
+		"expires_in":   time.Now().Unix() - 1,
+	}, 3600)
+
+	token, err := client.GetStableToken("wx_direct", "secret", false)
+	if err != nil || token.AccessToken != "STABLE_TOKEN" {
+		t.Fatalf("got (%+v, %v), want STABLE_TOKEN", token, err)
+	}
+	if n := server.requestCount(); n != 1 {
+		t.Fatalf("sent %d requests, want 1", n)
+	}
+}
+
+func TestGetStableTokenRejectsEmptyToken(t *testing.T) {
+	server := newTestServer(t)
+	server.respond("/cgi-bin/stable_token", `{"access_token":"","expires_in":0}`)
+	client, _ := newTestClient(t, server)
+
+	if _, err := client.GetStableToken("wx_direct", "secret", false); err == nil {
+		t.Fatal("expected error for empty access_token")
+	}
+	if client.Cache.Exists(StableTokenCacheKeyPrefix + "wx_direct") {
+		t.Fatal("empty token must not be cached")
+	}
+	if _, err := client.GetStableToken("", "secret", false); err == nil {
+		t.Fatal("expected validation error for empty appid")
+	}
+}
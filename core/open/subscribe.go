@@ -0,0 +1,161 @@
+package open
+
+import (
+	"errors"
+	"net/url"
+)
+
+// SubscribeValue 订阅消息模板字段值
+type SubscribeValue struct {
+	Value string `json:"value"`
+}
+
+// SubscribeTemplate 个人模板
+type SubscribeTemplate struct {
+	PriTmplId string `json:"priTmplId"`
+	Title     string `json:"title"`
+	Content   string `json:"content"`
+	Example   string `json:"example"`
+	Type      int    `json:"type"`
+}
+
+// SubscribeCategory 小程序账号的类目
+type SubscribeCategory struct {
+	Id   int64  `json:"id"`
+	Name string `json:"name"`
+}
+
+// PubTemplateTitle 公共模板标题
+type PubTemplateTitle struct {
+	Tid        int64  `json:"tid"`
+	Title      string `json:"title"`
+	Type       int    `json:"type"`
+	CategoryId string `json:"categoryId"`
+}
+
+// PubTemplateTitles 公共模板标题列表
+type PubTemplateTitles struct {
+	Count int64              `json:"count"`
+	Data  []PubTemplateTitle `json:"data"`
+}
+
+// PubTemplateKeyword 公共模板关键词
+type PubTemplateKeyword struct {
+	Kid     int64  `json:"kid"`
+	Name    string `json:"name"`
+	Example string `json:"example"`
+	Rule    string `json:"rule"`
+}
+
+// AddTemplate 从公共模板库选用模板, 返回个人模板id
+func (self *AuthorizerClient) AddTemplate(tid string, kidList []int, sceneDesc string) (string, error) {
+	token, err := self.AccessToken()
+	if err != nil {
+		return "", err
+	}
+	var resp struct {
+		PriTmplId string `json:"priTmplId"`
+	}
+	err = self.client.postJSON(self.client.Endpoint.AddSubscribeTemplate(token), map[string]interface{}{
+		"tid":       tid,
+		"kidList":   kidList,
+		"sceneDesc": sceneDesc,
+	}, &resp)
+	if err != nil {
+		return "", err
+	}
+	return resp.PriTmplId, nil
+}
+
+// DeleteTemplate 删除个人模板
+func (self *AuthorizerClient) DeleteTemplate(priTmplId string) error {
+	token, err := self.AccessToken()
+	if err != nil {
+		return err
+	}
+	return self.client.postJSON(self.client.Endpoint.DeleteSubscribeTemplate(token), map[string]interface{}{
+		"priTmplId": priTmplId,
+	}, nil)
+}
+
+// GetTemplates 获取个人模板列表
+func (self *AuthorizerClient) GetTemplates() ([]SubscribeTemplate, error) {
+	token, err := self.AccessToken()
+	if err != nil {
+		return nil, err
+	}
+	var resp struct {
+		Data []SubscribeTemplate `json:"data"`
+	}
+	if err := self.client.getJSON(self.client.Endpoint.GetSubscribeTemplates(token), &resp); err != nil {
+		return nil, err
+	}
+	return resp.Data, nil
+}
+
+// GetCategory 获取小程序账号的类目
+func (self *AuthorizerClient) GetCategory() ([]SubscribeCategory, error) {
+	token, err := self.AccessToken()
+	if err != nil {
+		return nil, err
+	}
+	var resp struct {
+		Data []SubscribeCategory `json:"data"`
+	}
+	if err := self.client.getJSON(self.client.Endpoint.GetSubscribeCategory(token), &resp); err != nil {
+		return nil, err
+	}
+	return resp.Data, nil
+}
+
+// GetPubTemplateTitles 获取类目下的公共模板标题, ids为逗号分隔的类目id
+func (self *AuthorizerClient) GetPubTemplateTitles(ids string, start, limit int) (*PubTemplateTitles, error) {
+	token, err := self.AccessToken()
+	if err != nil {
+		return nil, err
+	}
+	var resp PubTemplateTitles
+	if err := self.client.getJSON(self.client.Endpoint.GetPubTemplateTitles(token, url.QueryEscape(ids), start, limit), &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// GetPubTemplateKeywords 获取公共模板的关键词列表
+func (self *AuthorizerClient) GetPubTemplateKeywords(tid string) ([]PubTemplateKeyword, error) {
+	token, err := self.AccessToken()
+	if err != nil {
+		return nil, err
+	}
+	var resp struct {
+		Data []PubTemplateKeyword `json:"data"`
+	}
+	if err := self.client.getJSON(self.client.Endpoint.GetPubTemplateKeywords(token, url.QueryEscape(tid)), &resp); err != nil {
+		return nil, err
+	}
+	return resp.Data, nil
+}
+
+// SendSubscribeMessage 发送订阅消息
+// 用户拒收时返回ErrUserRefused, 模板参数格式不匹配时返回ErrDataFormat
+func (self *AuthorizerClient) SendSubscribeMessage(toUser, templateId, page string, data map[string]SubscribeValue, miniprogramState string) error {
+	if toUser == "" || templateId == "" {
+		return errors.New("touser和template_id不能为空")
+	}
+	token, err := self.AccessToken()
+	if err != nil {
+		return err
+	}
+	body := map[string]interface{}{
+		"touser":      toUser,
+		"template_id": templateId,
+		"data":        data,
+	}
+	if page != "" {
+		body["page"] = page
+	}
+	if miniprogramState != "" {
+		body["miniprogram_state"] = miniprogramState
+	}
+	return self.client.postJSON(self.client.Endpoint.SendSubscribeMessage(token), body, nil)
+}
@@ -0,0 +1,148 @@
+package open
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestSendSubscribeMessageRequestBody(t *testing.T) {
+	server := newTestServer(t)
+	client, _ := newTestClient(t, server)
+
+	err := client.Authorizer(testAuthorizerAppId).SendSubscribeMessage("OPENID", "TEMPLATE_ID", "index?foo=bar", map[string]SubscribeValue{
+		"thing1": {Value: "339208499"},
+		"time2":  {Value: "2015年01月05日"},
+	}, "trial")
+	if err != nil {
+		t.Fatal(err)
+	}
+	req := server.lastRequest(t)
+	if req.Path != "/cgi-bin/message/subscribe/send" {
+		t.Fatalf("path: got %s", req.Path)
+	}
+	if got := req.Query["access_token"]; len(got) != 1 || got[0] != testAuthorizerToken {
+		t.Fatalf("access_token: got %v", got)
+	}
+	want := `{"data":{"thing1":{"value":"339208499"},"time2":{"value":"2015年01月05日"}},"miniprogram_state":"trial","page":"index?foo=bar","template_id":"TEMPLATE_ID","touser":"OPENID"}`
+	if string(req.Body) != want {
+		t.Fatalf("body:\n got %s\nwant %s", req.Body, want)
+	}
+}
+
+func TestSendSubscribeMessageOmitsOptionalFields(t *testing.T) {
+	server := newTestServer(t)
+	client, _ := newTestClient(t, server)
+
+	err := client.Authorizer(testAuthorizerAppId).SendSubscribeMessage("OPENID", "TEMPLATE_ID", "", map[string]SubscribeValue{
+		"thing1": {Value: "ok"},
+	}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"data":{"thing1":{"value":"ok"}},"template_id":"TEMPLATE_ID","touser":"OPENID"}`
+	if body := string(server.lastRequest(t).Body); body != want {
+		t.Fatalf("body:\n got %s\nwant %s", body, want)
+	}
+}
+
+func TestSendSubscribeMessageValidation(t *testing.T) {
+	server := newTestServer(t)
+	client, _ := newTestClient(t, server)
+	authorizer := client.Authorizer(testAuthorizerAppId)
+
+	if err := authorizer.SendSubscribeMessage("", "TEMPLATE_ID", "", nil, ""); err == nil {
+		t.Error("missing touser: expected validation error")
+	}
+	if err := authorizer.SendSubscribeMessage("OPENID", "", "", nil, ""); err == nil {
+		t.Error("missing template_id: expected validation error")
+	}
+	if n := server.requestCount(); n != 0 {
+		t.Fatalf("invalid requests sent %d requests", n)
+	}
+}
+
+func TestSendSubscribeMessageErrors(t *testing.T) {
+	for _, tc := range []struct {
+		body string
+		want error
+	}{
+		{`{"errcode":43101,"errmsg":"user refuse to accept the msg"}`, ErrUserRefused},
+		{`{"errcode":47003,"errmsg":"argument invalid! data.thing1.value invalid"}`, ErrDataFormat},
+	} {
+		server := newTestServer(t)
+		server.respond("/cgi-bin/message/subscribe/send", tc.body)
+		client, _ := newTestClient(t, server)
+
+		err := client.Authorizer(testAuthorizerAppId).SendSubscribeMessage("OPENID", "TEMPLATE_ID", "", map[string]SubscribeValue{
+			"thing1": {Value: "ok"},
+		}, "")
+		if !errors.Is(err, tc.want) {
+			t.Errorf("%s: got %v, want %v", tc.body, err, tc.want)
+		}
+	}
+}
+
+func TestSubscribeTemplateManagement(t *testing.T) {
+	server := newTestServer(t)
+	server.respond("/wxaapi/newtmpl/addtemplate", `{"errcode":0,"errmsg":"ok","priTmplId":"PRI_TMPL_ID"}`)
+	server.respond("/wxaapi/newtmpl/gettemplate", `{"errcode":0,"errmsg":"ok","data":[{"priTmplId":"PRI_TMPL_ID","title":"报名结果通知","content":"会议时间:{{date2.DATA}}\n会议地点:{{thing1.DATA}}\n","example":"会议时间:2016年8月8日\n会议地点:TIT会议室\n","type":2}]}`)
+	server.respond("/wxaapi/newtmpl/getcategory", `{"errcode":0,"errmsg":"ok","data":[{"id":616,"name":"公交"},{"id":627,"name":"旅游服务"}]}`)
+	server.respond("/wxaapi/newtmpl/getpubtemplatetitles", `{"errcode":0,"errmsg":"ok","count":55,"data":[{"tid":99,"title":"付款成功通知","type":2,"categoryId":"616"}]}`)
+	server.respond("/wxaapi/newtmpl/getpubtemplatekeywords", `{"errcode":0,"errmsg":"ok","count":2,"data":[{"kid":1,"name":"物品名称","example":"名称","rule":"thing"},{"kid":2,"name":"购买时间","example":"2022年01月01日","rule":"time"}]}`)
+	client, _ := newTestClient(t, server)
+	authorizer := client.Authorizer(testAuthorizerAppId)
+
+	priTmplId, err := authorizer.AddTemplate("401", []int{1, 2}, "测试数据")
+	if err != nil || priTmplId != "PRI_TMPL_ID" {
+		t.Fatalf("got (%s, %v), want PRI_TMPL_ID", priTmplId, err)
+	}
+	if body := string(server.lastRequest(t).Body); body != `{"kidList":[1,2],"sceneDesc":"测试数据","tid":"401"}` {
+		t.Fatalf("add template body: %s", body)
+	}
+
+	if err := authorizer.DeleteTemplate("PRI_TMPL_ID"); err != nil {
+		t.Fatal(err)
+	}
+	if body := string(server.lastRequest(t).Body); body != `{"priTmplId":"PRI_TMPL_ID"}` {
+		t.Fatalf("delete template body: %s", body)
+	}
+
+	templates, err := authorizer.GetTemplates()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(templates) != 1 || templates[0].PriTmplId != "PRI_TMPL_ID" || templates[0].Title != "报名结果通知" || templates[0].Type != 2 {
+		t.Fatalf("templates: got %+v", templates)
+	}
+
+	categories, err := authorizer.GetCategory()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(categories) != 2 || categories[1].Id != 627 || categories[1].Name != "旅游服务" {
+		t.Fatalf("categories: got %+v", categories)
+	}
+
+	titles, err := authorizer.GetPubTemplateTitles("616,627", 0, 30)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if titles.Count != 55 || len(titles.Data) != 1 || titles.Data[0].Tid != 99 || titles.Data[0].CategoryId != "616" {
+		t.Fatalf("titles: got %+v", titles)
+	}
+	req := server.lastRequest(t)
+	if req.RawQuery != "access_token="+testAuthorizerToken+"&ids=616%2C627&start=0&limit=30" {
+		t.Fatalf("titles query: got %s", req.RawQuery)
+	}
+
+	keywords, err := authorizer.GetPubTemplateKeywords("99")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(keywords) != 2 || keywords[1].Kid != 2 || keywords[1].Rule != "time" {
+		t.Fatalf("keywords: got %+v", keywords)
+	}
+	if got := server.lastRequest(t).Query["tid"]; len(got) != 1 || got[0] != "99" {
+		t.Fatalf("tid: got %v", got)
+	}
+}
@@ -0,0 +1,194 @@
+package open
+
+import "errors"
+
+// tagBatchMax 批量打标签单次最大openid数
+const tagBatchMax = 50
+
+// Tag 公众号用户标签
+type Tag struct {
+	Id    int64  `json:"id"`
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+// CreateTag 创建标签, 返回创建的标签
+func (self *AuthorizerClient) CreateTag(name string) (*Tag, error) {
+	if name == "" {
+		return nil, errors.New("标签名不能为空")
+	}
+	token, err := self.AccessToken()
+	if err != nil {
+		return nil, err
+	}
+	var resp struct {
+		Tag Tag `json:"tag"`
+	}
+	err = self.client.postJSON(self.client.Endpoint.CreateTag(token), map[string]interface{}{
+		"tag": map[string]interface{}{"name": name},
+	}, &resp)
+	if err != nil {
+		return nil, err
+	}
+	return &resp.Tag, nil
+}
+
+// GetTags 获取公众号已创建的标签
+func (self *AuthorizerClient) GetTags() ([]Tag, error) {
+	token, err := self.AccessToken()
+	if err != nil {
+		return nil, err
+	}
+	var resp struct {
+		Tags []Tag `json:"tags"`
+	}
+	if err := self.client.getJSON(self.client.Endpoint.GetTags(token), &resp); err != nil {
+		return nil, err
+	}
+	return resp.Tags, nil
+}
+
+// UpdateTag 编辑标签名, 系统标签返回ErrSystemTag
+func (self *AuthorizerClient) UpdateTag(tagId int64, name string) error {
+	if name == "" {
+		return errors.New("标签名不能为空")
+	}
+	token, err := self.AccessToken()
+	if err != nil {
+		return err
+	}
+	return self.client.postJSON(self.client.Endpoint.UpdateTag(token), map[string]interface{}{
+		"tag": map[string]interface{}{"id": tagId, "name": name},
+	}, nil)
+}
+
+// DeleteTag 删除标签, 系统标签返回ErrSystemTag, 标签下粉丝过多时返回ErrTagInUse
+func (self *AuthorizerClient) DeleteTag(tagId int64) error {
+	token, err := self.AccessToken()
+	if err != nil {
+		return err
+	}
+	return self.client.postJSON(self.client.Endpoint.DeleteTag(token), map[string]interface{}{
+		"tag": map[string]interface{}{"id": tagId},
+	}, nil)
+}
+
+// batchTagging 按50个openid分批打标签或取消标签
+func (self *AuthorizerClient) batchTagging(endpoint func(string) string, tagId int64, openIds []string) error {
+	if len(openIds) == 0 {
+		return errors.New("openid列表不能为空")
+	}
+	token, err := self.AccessToken()
+	if err != nil {
+		return err
+	}
+	for start := 0; start < len(openIds); start += tagBatchMax {
+		end := start + tagBatchMax
+		if end > len(openIds) {
+			end = len(openIds)
+		}
+		err := self.client.postJSON(endpoint(token), map[string]interface{}{
+			"openid_list": openIds[start:end],
+			"tagid":       tagId,
+		}, nil)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// BatchTagging 批量为用户打标签, 超过50个openid时分批请求
+func (self *AuthorizerClient) BatchTagging(tagId int64, openIds []string) error {
+	return self.batchTagging(self.client.Endpoint.BatchTagging, tagId, openIds)
+}
+
+// BatchUntagging 批量为用户取消标签, 超过50个openid时分批请求
+func (self *AuthorizerClient) BatchUntagging(tagId int64, openIds []string) error {
+	return self.batchTagging(self.client.Endpoint.BatchUntagging, tagId, openIds)
+}
+
+// GetUserTagIds 获取用户身上的标签
+func (self *AuthorizerClient) GetUserTagIds(openId string) ([]int64, error) {
+	if openId == "" {
+		return nil, errors.New("openid不能为空")
+	}
+	token, err := self.AccessToken()
+	if err != nil {
+		return nil, err
+	}
+	var resp struct {
+		TagIdList []int64 `json:"tagid_list"`
+	}
+	err = self.client.postJSON(self.client.Endpoint.GetUserTagIds(token), map[string]interface{}{
+		"openid": openId,
+	}, &resp)
+	if err != nil {
+		return nil, err
+	}
+	return resp.TagIdList, nil
+}
+
+// GetTagUsers 获取标签下的粉丝openid, 返回本页openid及下一页的next_openid, 每页最多10000个
+func (self *AuthorizerClient) GetTagUsers(tagId int64, nextOpenId string) ([]string, string, error) {
+	token, err := self.AccessToken()
+	if err != nil {
+		return nil, "", err
+	}
+	var resp struct {
+		Count int `json:"count"`
+		Data  struct {
+			OpenId []string `json:"openid"`
+		} `json:"data"`
+		NextOpenId string `json:"next_openid"`
+	}
+	err = self.client.postJSON(self.client.Endpoint.GetTagUsers(token), map[string]interface{}{
+		"tagid":       tagId,
+		"next_openid": nextOpenId,
+	}, &resp)
+	if err != nil {
+		return nil, "", err
+	}
+	return resp.Data.OpenId, resp.NextOpenId, nil
+}
+
+// TagUserIterator 标签粉丝迭代器, 按next_openid自动翻页, 每次返回一个openid
+type TagUserIterator struct {
+	client     *AuthorizerClient
+	tagId      int64
+	nextOpenId string
+	buf        []string
+	done       bool
+}
+
+// TagUsers 创建标签粉丝迭代器
+func (self *AuthorizerClient) TagUsers(tagId int64) *TagUserIterator {
+	return &TagUserIterator{
+		client: self,
+		tagId:  tagId,
+	}
+}
+
+// Next 返回下一个粉丝的openid, 遍历结束时第二个返回值为false
+func (self *TagUserIterator) Next() (string, bool, error) {
+	if len(self.buf) == 0 {
+		if self.done {
+			return "", false, nil
+		}
+		openIds, next, err := self.client.GetTagUsers(self.tagId, self.nextOpenId)
+		if err != nil {
+			return "", false, err
+		}
+		if next == "" || next == self.nextOpenId || len(openIds) == 0 {
+			self.done = true
+		}
+		self.nextOpenId = next
+		self.buf = openIds
+		if len(self.buf) == 0 {
+			return "", false, nil
+		}
+	}
+	openId := self.buf[0]
+	self.buf = self.buf[1:]
+	return openId, true, nil
+}
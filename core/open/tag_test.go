@@ -0,0 +1,48 @@
+package open
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTagUserIterator(t *testing.T) {
+	server := newTestServer(t)
+	server.respondSequence("/cgi-bin/user/tag/get",
+		`{"count":2,"data":{"openid":["OPENID_1","OPENID_2"]},"next_openid":"OPENID_2"}`,
+		`{"count":1,"data":{"openid":["OPENID_3"]},"next_openid":"OPENID_3"}`,
+		`{"count":0,"next_openid":""}`,
+	)
+	client, _ := newTestClient(t, server)
+
+	iterator := client.Authorizer(testAuthorizerAppId).TagUsers(100)
+	var openIds []string
+	drain(t, iterator, &openIds)
+	if want := []string{"OPENID_1", "OPENID_2", "OPENID_3"}; !reflect.DeepEqual(openIds, want) {
+		t.Fatalf("got %v, want %v", openIds, want)
+	}
+	bodies := server.requestBodies(t, "/cgi-bin/user/tag/get")
+	if len(bodies) != 3 {
+		t.Fatalf("got %d requests, want 3", len(bodies))
+	}
+	for i, want := range []string{"", "OPENID_2", "OPENID_3"} {
+		if bodies[i]["next_openid"] != want || bodies[i]["tagid"] != float64(100) {
+			t.Errorf("request %d: got %v", i, bodies[i])
+		}
+	}
+	if _, ok, _ := iterator.Next(); ok {
+		t.Fatal("exhausted iterator returned an item")
+	}
+	if n := server.requestCount(); n != 3 {
+		t.Fatalf("exhausted iterator sent more requests: %d", n)
+	}
+}
+
+func TestTagUserIteratorError(t *testing.T) {
+	server := newTestServer(t)
+	server.respond("/cgi-bin/user/tag/get", `{"errcode":40003,"errmsg":"invalid openid"}`)
+	client, _ := newTestClient(t, server)
+
+	if _, ok, err := client.Authorizer(testAuthorizerAppId).TagUsers(100).Next(); err == nil || ok {
+		t.Fatalf("got (%v, %v), want error", ok, err)
+	}
+}
@@ -0,0 +1,61 @@
+package open
+
+import "errors"
+
+// TemplateValue 模板消息字段值
+type TemplateValue struct {
+	Value string `json:"value"`
+	Color string `json:"color,omitempty"`
+}
+
+// TemplateMiniProgram 模板消息跳转的小程序
+type TemplateMiniProgram struct {
+	AppId    string `json:"appid"`
+	PagePath string `json:"pagepath,omitempty"`
+}
+
+// WeappTemplateMsg 小程序模板消息
+type WeappTemplateMsg struct {
+	TemplateId      string                   `json:"template_id"`
+	Page            string                   `json:"page,omitempty"`
+	FormId          string                   `json:"form_id"`
+	Data            map[string]TemplateValue `json:"data"`
+	EmphasisKeyword string                   `json:"emphasis_keyword,omitempty"`
+}
+
+// MpTemplateMsg 公众号模板消息
+type MpTemplateMsg struct {
+	AppId       string                   `json:"appid"`
+	TemplateId  string                   `json:"template_id"`
+	Url         string                   `json:"url,omitempty"`
+	MiniProgram *TemplateMiniProgram     `json:"miniprogram,omitempty"`
+	Data        map[string]TemplateValue `json:"data"`
+}
+
+// UniformMessageRequest 统一服务消息, WeappTemplateMsg与MpTemplateMsg只能填写其一
+type UniformMessageRequest struct {
+	ToUser           string            `json:"touser"`
+	WeappTemplateMsg *WeappTemplateMsg `json:"weapp_template_msg,omitempty"`
+	MpTemplateMsg    *MpTemplateMsg    `json:"mp_template_msg,omitempty"`
+}
+
+// SendUniformMessage 下发统一服务消息, touser为小程序的openid
+func (self *AuthorizerClient) SendUniformMessage(req UniformMessageRequest) error {
+	if req.ToUser == "" {
+		return errors.New("touser不能为空")
+	}
+	if (req.WeappTemplateMsg == nil) == (req.MpTemplateMsg == nil) {
+		return errors.New("weapp_template_msg与mp_template_msg必须且只能填写一个")
+	}
+	if req.MpTemplateMsg != nil && (req.MpTemplateMsg.AppId == "" || req.MpTemplateMsg.TemplateId == "") {
+		return errors.New("mp_template_msg缺少appid或template_id")
+	}
+	if req.WeappTemplateMsg != nil && req.WeappTemplateMsg.TemplateId == "" {
+		return errors.New("weapp_template_msg缺少template_id")
+	}
+	token, err := self.AccessToken()
+	if err != nil {
+		return err
+	}
+	return self.client.postJSON(self.client.Endpoint.SendUniformMessage(token), req, nil)
+}
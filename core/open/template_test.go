@@ -0,0 +1,100 @@
+package open
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestSendUniformMessageMpTemplateBody(t *testing.T) {
+	server := newTestServer(t)
+	client, _ := newTestClient(t, server)
+
+	err := client.Authorizer(testAuthorizerAppId).SendUniformMessage(UniformMessageRequest{
+		ToUser: "WEAPP_OPENID",
+		MpTemplateMsg: &MpTemplateMsg{
+			AppId:       "wx_mp",
+			TemplateId:  "TEMPLATE_ID",
+			Url:         "https://example.com",
+			MiniProgram: &TemplateMiniProgram{AppId: "wx_weapp", PagePath: "index?foo=bar"},
+			Data: map[string]TemplateValue{
+				"first":    {Value: "恭喜你购买成功!", Color: "#173177"},
+				"keyword1": {Value: "巧克力"},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	req := server.lastRequest(t)
+	if req.Path != "/cgi-bin/message/wxopen/template/uniform_send" {
+		t.Fatalf("path: got %s", req.Path)
+	}
+	if got := req.Query["access_token"]; len(got) != 1 || got[0] != testAuthorizerToken {
+		t.Fatalf("access_token: got %v", got)
+	}
+	want := `{"touser":"WEAPP_OPENID","mp_template_msg":{"appid":"wx_mp","template_id":"TEMPLATE_ID","url":"https://example.com","miniprogram":{"appid":"wx_weapp","pagepath":"index?foo=bar"},"data":{"first":{"value":"恭喜你购买成功!","color":"#173177"},"keyword1":{"value":"巧克力"}}}}`
+	if string(req.Body) != want {
+		t.Fatalf("body:\n got %s\nwant %s", req.Body, want)
+	}
+}
+
+func TestSendUniformMessageWeappTemplateBody(t *testing.T) {
+	server := newTestServer(t)
+	client, _ := newTestClient(t, server)
+
+	err := client.Authorizer(testAuthorizerAppId).SendUniformMessage(UniformMessageRequest{
+		ToUser: "WEAPP_OPENID",
+		WeappTemplateMsg: &WeappTemplateMsg{
+			TemplateId:      "TEMPLATE_ID",
+			Page:            "page/page/index",
+			FormId:          "FORMID",
+			Data:            map[string]TemplateValue{"keyword1": {Value: "339208499"}},
+			EmphasisKeyword: "keyword1.DATA",
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"touser":"WEAPP_OPENID","weapp_template_msg":{"template_id":"TEMPLATE_ID","page":"page/page/index","form_id":"FORMID","data":{"keyword1":{"value":"339208499"}},"emphasis_keyword":"keyword1.DATA"}}`
+	if body := string(server.lastRequest(t).Body); body != want {
+		t.Fatalf("body:\n got %s\nwant %s", body, want)
+	}
+}
+
+func TestSendUniformMessageValidation(t *testing.T) {
+	server := newTestServer(t)
+	client, _ := newTestClient(t, server)
+	authorizer := client.Authorizer(testAuthorizerAppId)
+	mp := &MpTemplateMsg{AppId: "wx_mp", TemplateId: "TEMPLATE_ID"}
+	weapp := &WeappTemplateMsg{TemplateId: "TEMPLATE_ID"}
+
+	for name, req := range map[string]UniformMessageRequest{
+		"missing touser":      {MpTemplateMsg: mp},
+		"no branch":           {ToUser: "OPENID"},
+		"both branches":       {ToUser: "OPENID", MpTemplateMsg: mp, WeappTemplateMsg: weapp},
+		"mp missing appid":    {ToUser: "OPENID", MpTemplateMsg: &MpTemplateMsg{TemplateId: "TEMPLATE_ID"}},
+		"mp missing template": {ToUser: "OPENID", MpTemplateMsg: &MpTemplateMsg{AppId: "wx_mp"}},
+		"weapp missing id":    {ToUser: "OPENID", WeappTemplateMsg: &WeappTemplateMsg{}},
+	} {
+		if err := authorizer.SendUniformMessage(req); err == nil {
+			t.Errorf("%s: expected validation error", name)
+		}
+	}
+	if n := server.requestCount(); n != 0 {
+		t.Fatalf("invalid requests sent %d requests", n)
+	}
+}
+
+func TestSendUniformMessageInvalidOpenId(t *testing.T) {
+	server := newTestServer(t)
+	server.respond("/cgi-bin/message/wxopen/template/uniform_send", `{"errcode":40003,"errmsg":"invalid openid rid: 5f1a2b3c"}`)
+	client, _ := newTestClient(t, server)
+
+	err := client.Authorizer(testAuthorizerAppId).SendUniformMessage(UniformMessageRequest{
+		ToUser:        "BAD_OPENID",
+		MpTemplateMsg: &MpTemplateMsg{AppId: "wx_mp", TemplateId: "TEMPLATE_ID"},
+	})
+	if !errors.Is(err, ErrInvalidOpenId) {
+		t.Fatalf("got %v, want ErrInvalidOpenId", err)
+	}
+}
@@ -0,0 +1,105 @@
+package open
+
+import (
+	"errors"
+	"net/url"
+	"unicode/utf8"
+)
+
+const (
+	// userInfoBatchMax 批量获取用户信息单次最大条数
+	userInfoBatchMax = 100
+	// userRemarkMaxLen 用户备注名最大字符数
+	userRemarkMaxLen = 30
+)
+
+// UserInfo 公众号用户信息, 用户未关注时只返回Subscribe、OpenId及UnionId
+type UserInfo struct {
+	Subscribe      int     `json:"subscribe"`
+	OpenId         string  `json:"openid"`
+	Language       string  `json:"language"`
+	SubscribeTime  int64   `json:"subscribe_time"`
+	UnionId        string  `json:"unionid"`
+	Remark         string  `json:"remark"`
+	GroupId        int64   `json:"groupid"`
+	TagIdList      []int64 `json:"tagid_list"`
+	SubscribeScene string  `json:"subscribe_scene"`
+	QrScene        int64   `json:"qr_scene"`
+	QrSceneStr     string  `json:"qr_scene_str"`
+}
+
+// IsSubscribed 用户是否关注了公众号
+func (self *UserInfo) IsSubscribed() bool {
+	return self.Subscribe == 1
+}
+
+// GetUserInfo 获取用户基本信息, lang为空时默认zh_CN
+func (self *AuthorizerClient) GetUserInfo(openId, lang string) (UserInfo, error) {
+	var info UserInfo
+	if openId == "" {
+		return info, errors.New("openid不能为空")
+	}
+	if lang == "" {
+		lang = "zh_CN"
+	}
+	token, err := self.AccessToken()
+	if err != nil {
+		return info, err
+	}
+	err = self.client.getJSON(self.client.Endpoint.GetUserInfo(token, url.QueryEscape(openId), url.QueryEscape(lang)), &info)
+	return info, err
+}
+
+// BatchGetUserInfo 批量获取用户基本信息, 超过100个openid时分批请求
+func (self *AuthorizerClient) BatchGetUserInfo(openIds []string, lang string) ([]UserInfo, error) {
+	if lang == "" {
+		lang = "zh_CN"
+	}
+	token, err := self.AccessToken()
+	if err != nil {
+		return nil, err
+	}
+	users := make([]UserInfo, 0, len(openIds))
+	for start := 0; start < len(openIds); start += userInfoBatchMax {
+		end := start + userInfoBatchMax
+		if end > len(openIds) {
+			end = len(openIds)
+		}
+		userList := make([]map[string]string, 0, end-start)
+		for _, openId := range openIds[start:end] {
+			userList = append(userList, map[string]string{
+				"openid": openId,
+				"lang":   lang,
+			})
+		}
+		var resp struct {
+			UserInfoList []UserInfo `json:"user_info_list"`
+		}
+		err := self.client.postJSON(self.client.Endpoint.BatchGetUserInfo(token), map[string]interface{}{
+			"user_list": userList,
+		}, &resp)
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, resp.UserInfoList...)
+	}
+	return users, nil
+}
+
+// UpdateUserRemark 设置用户备注名, 备注名最多30个字符, 用户未关注时返回ErrRequireSubscribe
+func (self *AuthorizerClient) UpdateUserRemark(openId, remark string) error {
+	if openId == "" {
+		return errors.New("openid不能为空")
+	}
+	if utf8.RuneCountInString(remark) > userRemarkMaxLen {
+		return errors.New("备注名最多30个字符")
+	}
+	token, err := self.AccessToken()
+	if err != nil {
+		return err
+	}
+	return self.client.postJSON(self.client.Endpoint.UpdateUserRemark(token), map[string]interface{}{
+		"openid": openId,
+		"remark": remark,
+	}, nil)
+}
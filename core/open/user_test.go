@@ -0,0 +1,143 @@
+package open
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const testSubscribedUser = `{"subscribe":1,"openid":"OPENID","language":"zh_CN","subscribe_time":1382694957,"unionid":"UNIONID","remark":"","groupid":0,"tagid_list":[128,2],"subscribe_scene":"ADD_SCENE_QR_CODE","qr_scene":98765,"qr_scene_str":""}`
+
+func TestGetUserInfoSubscribed(t *testing.T) {
+	server := newTestServer(t)
+	server.respond("/cgi-bin/user/info", testSubscribedUser)
+	client, _ := newTestClient(t, server)
+
+	info, err := client.Authorizer(testAuthorizerAppId).GetUserInfo("OPENID", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if query := server.lastRequest(t).RawQuery; query != "access_token="+testAuthorizerToken+"&openid=OPENID&lang=zh_CN" {
+		t.Fatalf("query: got %s", query)
+	}
+	if !info.IsSubscribed() || info.UnionId != "UNIONID" || info.SubscribeTime != 1382694957 || info.SubscribeScene != "ADD_SCENE_QR_CODE" ||
+		info.QrScene != 98765 || !reflect.DeepEqual(info.TagIdList, []int64{128, 2}) {
+		t.Fatalf("info: got %+v", info)
+	}
+}
+
+func TestGetUserInfoUnsubscribed(t *testing.T) {
+	server := newTestServer(t)
+	server.respond("/cgi-bin/user/info", `{"subscribe":0,"openid":"OPENID","unionid":"UNIONID","tagid_list":[]}`)
+	client, _ := newTestClient(t, server)
+
+	info, err := client.Authorizer(testAuthorizerAppId).GetUserInfo("OPENID", "en")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.IsSubscribed() || info.OpenId != "OPENID" || info.UnionId != "UNIONID" || info.SubscribeTime != 0 {
+		t.Fatalf("info: got %+v", info)
+	}
+	if got := server.lastRequest(t).Query["lang"]; len(got) != 1 || got[0] != "en" {
+		t.Fatalf("lang: got %v", got)
+	}
+
+	if _, err := client.Authorizer(testAuthorizerAppId).GetUserInfo("", ""); err == nil {
+		t.Fatal("empty openid: expected validation error")
+	}
+}
+
+func TestBatchGetUserInfo(t *testing.T) {
+	server := newTestServer(t)
+	server.respond("/cgi-bin/user/info/batchget", `{"user_info_list":[`+testSubscribedUser+`,{"subscribe":0,"openid":"OPENID2"}]}`)
+	client, _ := newTestClient(t, server)
+
+	users, err := client.Authorizer(testAuthorizerAppId).BatchGetUserInfo([]string{"OPENID", "OPENID2"}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(users) != 2 || !users[0].IsSubscribed() || users[1].IsSubscribed() || users[1].OpenId != "OPENID2" {
+		t.Fatalf("users: got %+v", users)
+	}
+	want := `{"user_list":[{"lang":"zh_CN","openid":"OPENID"},{"lang":"zh_CN","openid":"OPENID2"}]}`
+	if body := string(server.lastRequest(t).Body); body != want {
+		t.Fatalf("body:\n got %s\nwant %s", body, want)
+	}
+}
+
+func TestBatchGetUserInfoChunks(t *testing.T) {
+	server := newTestServer(t)
+	server.respondSequence("/cgi-bin/user/info/batchget",
+		`{"user_info_list":[{"subscribe":1,"openid":"wx_0"}]}`,
+		`{"user_info_list":[{"subscribe":1,"openid":"wx_100"}]}`,
+	)
+	client, _ := newTestClient(t, server)
+	openIds := make([]string, 150)
+	for i := range openIds {
+		openIds[i] = fmt.Sprintf("wx_%d", i)
+	}
+
+	users, err := client.Authorizer(testAuthorizerAppId).BatchGetUserInfo(openIds, "zh_CN")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(users) != 2 || users[0].OpenId != "wx_0" || users[1].OpenId != "wx_100" {
+		t.Fatalf("users: got %+v", users)
+	}
+	bodies := server.requestBodies(t, "/cgi-bin/user/info/batchget")
+	if len(bodies) != 2 {
+		t.Fatalf("sent %d batch requests, want 2", len(bodies))
+	}
+	for i, want := range []int{100, 50} {
+		if got := len(bodies[i]["user_list"].([]interface{})); got != want {
+			t.Errorf("batch %d: got %d openids, want %d", i, got, want)
+		}
+	}
+	if first := bodies[1]["user_list"].([]interface{})[0].(map[string]interface{}); first["openid"] != "wx_100" {
+		t.Fatalf("second batch starts with %v, want wx_100", first["openid"])
+	}
+}
+
+func TestUpdateUserRemark(t *testing.T) {
+	server := newTestServer(t)
+	client, _ := newTestClient(t, server)
+	authorizer := client.Authorizer(testAuthorizerAppId)
+
+	// 30个汉字按字符计数, 未超过限制
+	remark := strings.Repeat("备", 30)
+	if err := authorizer.UpdateUserRemark("OPENID", remark); err != nil {
+		t.Fatal(err)
+	}
+	req := server.lastRequest(t)
+	if req.Path != "/cgi-bin/user/info/updateremark" {
+		t.Fatalf("path: got %s", req.Path)
+	}
+	if got := req.Query["access_token"]; len(got) != 1 || got[0] != testAuthorizerToken {
+		t.Fatalf("access_token: got %v", got)
+	}
+	if want := `{"openid":"OPENID","remark":"` + remark + `"}`; string(req.Body) != want {
+		t.Fatalf("body:\n got %s\nwant %s", req.Body, want)
+	}
+
+	if err := authorizer.UpdateUserRemark("OPENID", strings.Repeat("备", 31)); err == nil {
+		t.Error("31 characters: expected validation error")
+	}
+	if err := authorizer.UpdateUserRemark("", "备注"); err == nil {
+		t.Error("empty openid: expected validation error")
+	}
+	if n := server.requestCount(); n != 1 {
+		t.Fatalf("sent %d requests, want 1", n)
+	}
+}
+
+func TestUpdateUserRemarkNotFollower(t *testing.T) {
+	server := newTestServer(t)
+	server.respond("/cgi-bin/user/info/updateremark", `{"errcode":43004,"errmsg":"require subscribe"}`)
+	client, _ := newTestClient(t, server)
+
+	if err := client.Authorizer(testAuthorizerAppId).UpdateUserRemark("OPENID", "备注"); !errors.Is(err, ErrRequireSubscribe) {
+		t.Fatalf("got %v, want ErrRequireSubscribe", err)
+	}
+}
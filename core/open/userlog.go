@@ -0,0 +1,193 @@
+package open
+
+import (
+	"errors"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// 实时日志级别
+const (
+	LogLevelInfo  = 2
+	LogLevelWarn  = 4
+	LogLevelError = 8
+)
+
+// realtimeLogPageSize 单次查询的最大条数
+const realtimeLogPageSize = 100
+
+// RealtimeLogQuery 实时日志查询条件, Date为日志日期, Begin/End须在同一天内
+type RealtimeLogQuery struct {
+	Date      time.Time
+	Begin     time.Time
+	End       time.Time
+	Start     int
+	Limit     int
+	TraceId   string
+	Url       string
+	OpenId    string
+	FilterMsg string
+	Level     int
+}
+
+// LogMsg 单条日志内容
+type LogMsg struct {
+	Time  time.Time
+	Level int
+	Msg   []string
+}
+
+// LogEntry 实时日志记录
+type LogEntry struct {
+	Level          int
+	Platform       int
+	LibraryVersion string
+	ClientVersion  string
+	OpenId         string
+	Time           time.Time
+	Msg            []LogMsg
+	Url            string
+	TraceId        string
+}
+
+type rawLogEntry struct {
+	Level          int    `json:"level"`
+	Platform       int    `json:"platform"`
+	LibraryVersion string `json:"libraryVersion"`
+	ClientVersion  string `json:"clientVersion"`
+	Id             string `json:"id"`
+	Timestamp      int64  `json:"timestamp"`
+	Msg            []struct {
+		Time  int64    `json:"time"`
+		Level int      `json:"level"`
+		Msg   []string `json:"msg"`
+	} `json:"msg"`
+	Url     string `json:"url"`
+	TraceId string `json:"traceid"`
+}
+
+// logTime 日志时间戳可能为秒或毫秒
+func logTime(timestamp int64) time.Time {
+	if timestamp > 1e12 {
+		return time.Unix(0, timestamp*int64(time.Millisecond))
+	}
+	return time.Unix(timestamp, 0)
+}
+
+func (self RealtimeLogQuery) values() url.Values {
+	values := url.Values{}
+	values.Set("date", self.Date.Format("20060102"))
+	values.Set("begintime", strconv.FormatInt(self.Begin.Unix(), 10))
+	values.Set("endtime", strconv.FormatInt(self.End.Unix(), 10))
+	values.Set("start", strconv.Itoa(self.Start))
+	values.Set("limit", strconv.Itoa(self.Limit))
+	if self.TraceId != "" {
+		values.Set("traceId", self.TraceId)
+	}
+	if self.Url != "" {
+		values.Set("url", self.Url)
+	}
+	if self.OpenId != "" {
+		values.Set("id", self.OpenId)
+	}
+	if self.FilterMsg != "" {
+		values.Set("filterMsg", self.FilterMsg)
+	}
+	if self.Level != 0 {
+		values.Set("level", strconv.Itoa(self.Level))
+	}
+	return values
+}
+
+// SearchRealtimeLogs 查询实时日志, 返回本页记录及符合条件的总数
+func (self *AuthorizerClient) SearchRealtimeLogs(query RealtimeLogQuery) ([]LogEntry, int, error) {
+	if query.Date.IsZero() || query.Begin.IsZero() || query.End.IsZero() {
+		return nil, 0, errors.New("日志日期和起止时间不能为空")
+	}
+	if query.Limit <= 0 || query.Limit > realtimeLogPageSize {
+		query.Limit = realtimeLogPageSize
+	}
+	token, err := self.AccessToken()
+	if err != nil {
+		return nil, 0, err
+	}
+	var resp struct {
+		Data struct {
+			List  []rawLogEntry `json:"list"`
+			Total int           `json:"total"`
+		} `json:"data"`
+	}
+	if err := self.client.getJSON(self.client.Endpoint.UserLogSearch(token, query.values().Encode()), &resp); err != nil {
+		return nil, 0, err
+	}
+	entries := make([]LogEntry, 0, len(resp.Data.List))
+	for _, raw := range resp.Data.List {
+		entry := LogEntry{
+			Level:          raw.Level,
+			Platform:       raw.Platform,
+			LibraryVersion: raw.LibraryVersion,
+			ClientVersion:  raw.ClientVersion,
+			OpenId:         raw.Id,
+			Time:           logTime(raw.Timestamp),
+			Url:            raw.Url,
+			TraceId:        raw.TraceId,
+		}
+		for _, msg := range raw.Msg {
+			entry.Msg = append(entry.Msg, LogMsg{
+				Time:  logTime(msg.Time),
+				Level: msg.Level,
+				Msg:   msg.Msg,
+			})
+		}
+		entries = append(entries, entry)
+	}
+	return entries, resp.Data.Total, nil
+}
+
+// RealtimeLogIterator 实时日志迭代器, 自动翻页
+type RealtimeLogIterator struct {
+	client  *AuthorizerClient
+	query   RealtimeLogQuery
+	max     int
+	fetched int
+	buf     []LogEntry
+	done    bool
+}
+
+// RealtimeLogs 创建实时日志迭代器, max为最多返回的条数, 0表示不限制
+func (self *AuthorizerClient) RealtimeLogs(query RealtimeLogQuery, max int) *RealtimeLogIterator {
+	return &RealtimeLogIterator{
+		client: self,
+		query:  query,
+		max:    max,
+	}
+}
+
+// Next 返回下一条日志, 没有更多日志时第二个返回值为false
+func (self *RealtimeLogIterator) Next() (LogEntry, bool, error) {
+	if self.max > 0 && self.fetched >= self.max {
+		return LogEntry{}, false, nil
+	}
+	if len(self.buf) == 0 {
+		if self.done {
+			return LogEntry{}, false, nil
+		}
+		entries, total, err := self.client.SearchRealtimeLogs(self.query)
+		if err != nil {
+			return LogEntry{}, false, err
+		}
+		self.query.Start += len(entries)
+		if len(entries) == 0 || self.query.Start >= total {
+			self.done = true
+		}
+		self.buf = entries
+		if len(self.buf) == 0 {
+			return LogEntry{}, false, nil
+		}
+	}
+	entry := self.buf[0]
+	self.buf = self.buf[1:]
+	self.fetched++
+	return entry, true, nil
+}
@@ -0,0 +1,70 @@
+package open
+
+import (
+	"testing"
+	"time"
+)
+
+func testRealtimeLogQuery() RealtimeLogQuery {
+	date := time.Date(2022, 5, 20, 0, 0, 0, 0, time.Local)
+	return RealtimeLogQuery{
+		Date:    date,
+		Begin:   date.Add(time.Hour),
+		End:     date.Add(2 * time.Hour),
+		Limit:   2,
+		TraceId: "TRACE_ID",
+		Level:   LogLevelError,
+	}
+}
+
+func TestRealtimeLogIterator(t *testing.T) {
+	server := newTestServer(t)
+	server.respondSequence("/wxaapi/userlog/userlog_search",
+		`{"errcode":0,"errmsg":"ok","data":{"list":[{"level":8,"platform":2,"libraryVersion":"2.24.0","clientVersion":"8.0.20","id":"OPENID_1","timestamp":1653012000,"msg":[{"time":1653012000123,"level":8,"msg":["error"]}],"url":"pages/index/index","traceid":"TRACE_ID"},{"id":"OPENID_2","timestamp":1653012001}],"total":3}}`,
+		`{"errcode":0,"errmsg":"ok","data":{"list":[{"id":"OPENID_3","timestamp":1653012002}],"total":3}}`,
+	)
+	client, _ := newTestClient(t, server)
+
+	var entries []LogEntry
+	drain(t, client.Authorizer(testAuthorizerAppId).RealtimeLogs(testRealtimeLogQuery(), 0), &entries)
+	if len(entries) != 3 || entries[2].OpenId != "OPENID_3" {
+		t.Fatalf("got %+v", entries)
+	}
+	first := entries[0]
+	if !first.Time.Equal(time.Unix(1653012000, 0)) || len(first.Msg) != 1 || !first.Msg[0].Time.Equal(time.Unix(1653012000, 123*int64(time.Millisecond))) {
+		t.Fatalf("timestamps not converted: %+v", first)
+	}
+	if n := server.requestCount(); n != 2 {
+		t.Fatalf("got %d requests, want 2", n)
+	}
+	query := server.lastRequest(t).Query
+	for key, value := range map[string]string{"date": "20220520", "start": "2", "limit": "2", "traceId": "TRACE_ID", "level": "8"} {
+		if got := query[key]; len(got) != 1 || got[0] != value {
+			t.Errorf("query %s: got %v, want %s", key, got, value)
+		}
+	}
+}
+
+func TestRealtimeLogIteratorMax(t *testing.T) {
+	server := newTestServer(t)
+	server.respond("/wxaapi/userlog/userlog_search", `{"errcode":0,"errmsg":"ok","data":{"list":[{"id":"OPENID_1"},{"id":"OPENID_2"}],"total":100}}`)
+	client, _ := newTestClient(t, server)
+
+	var entries []LogEntry
+	drain(t, client.Authorizer(testAuthorizerAppId).RealtimeLogs(testRealtimeLogQuery(), 3), &entries)
+	if len(entries) != 3 || server.requestCount() != 2 {
+		t.Fatalf("got %d entries in %d requests, want 3 in 2", len(entries), server.requestCount())
+	}
+}
+
+func TestRealtimeLogIteratorValidation(t *testing.T) {
+	server := newTestServer(t)
+	client, _ := newTestClient(t, server)
+
+	if _, ok, err := client.Authorizer(testAuthorizerAppId).RealtimeLogs(RealtimeLogQuery{}, 0).Next(); ok || err == nil {
+		t.Fatalf("got (%v, %v), want error", ok, err)
+	}
+	if n := server.requestCount(); n != 0 {
+		t.Fatalf("invalid request sent %d requests", n)
+	}
+}
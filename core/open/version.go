@@ -0,0 +1,84 @@
+package open
+
+import "errors"
+
+// ExpVersionInfo 体验版信息
+type ExpVersionInfo struct {
+	ExpTime    int64  `json:"exp_time"`
+	ExpVersion string `json:"exp_version"`
+	ExpDesc    string `json:"exp_desc"`
+}
+
+// ReleaseVersionInfo 线上版信息
+type ReleaseVersionInfo struct {
+	ReleaseTime    int64  `json:"release_time"`
+	ReleaseVersion string `json:"release_version"`
+	ReleaseDesc    string `json:"release_desc"`
+}
+
+// VersionInfo 小程序版本信息, 没有体验版或线上版时对应字段为nil
+type VersionInfo struct {
+	ExpInfo     *ExpVersionInfo     `json:"exp_info"`
+	ReleaseInfo *ReleaseVersionInfo `json:"release_info"`
+}
+
+// GetVersionInfo 查询小程序版本信息
+func (self *AuthorizerClient) GetVersionInfo() (*VersionInfo, error) {
+	token, err := self.AccessToken()
+	if err != nil {
+		return nil, err
+	}
+	var info VersionInfo
+	if err := self.client.postJSON(self.client.Endpoint.GetVersionInfo(token), map[string]interface{}{}, &info); err != nil {
+		return nil, err
+	}
+	if info.ExpInfo != nil && info.ExpInfo.ExpVersion == "" {
+		info.ExpInfo = nil
+	}
+	if info.ReleaseInfo != nil && info.ReleaseInfo.ReleaseVersion == "" {
+		info.ReleaseInfo = nil
+	}
+	return &info, nil
+}
+
+// SupportVersionUv 各版本用户占比
+type SupportVersionUv struct {
+	Percentage float64 `json:"percentage"`
+	Version    string  `json:"version"`
+}
+
+// SupportVersion 当前最低基础库版本及各版本用户占比
+type SupportVersion struct {
+	NowVersion string `json:"now_version"`
+	UvInfo     struct {
+		Items []SupportVersionUv `json:"items"`
+	} `json:"uv_info"`
+}
+
+// GetSupportVersion 查询当前设置的最低基础库版本及各版本用户占比
+func (self *AuthorizerClient) GetSupportVersion() (*SupportVersion, error) {
+	token, err := self.AccessToken()
+	if err != nil {
+		return nil, err
+	}
+	var version SupportVersion
+	if err := self.client.postJSON(self.client.Endpoint.GetWeappSupportVersion(token), map[string]interface{}{}, &version); err != nil {
+		return nil, err
+	}
+	return &version, nil
+}
+
+// SetSupportVersion 设置最低基础库版本
+// 版本号不合法或低于允许的最低版本时返回ErrInvalidSupportVersion
+func (self *AuthorizerClient) SetSupportVersion(version string) error {
+	if version == "" {
+		return errors.New("版本号不能为空")
+	}
+	token, err := self.AccessToken()
+	if err != nil {
+		return err
+	}
+	return self.client.postJSON(self.client.Endpoint.SetWeappSupportVersion(token), map[string]interface{}{
+		"version": version,
+	}, nil)
+}
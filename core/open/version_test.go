@@ -0,0 +1,109 @@
+package open
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestGetVersionInfo(t *testing.T) {
+	for _, tc := range []struct {
+		name        string
+		fixture     string
+		wantExp     *ExpVersionInfo
+		wantRelease *ReleaseVersionInfo
+	}{
+		{
+			name:        "both",
+			fixture:     `{"errcode":0,"errmsg":"ok","exp_info":{"exp_time":1704902400,"exp_version":"1.0.4","exp_desc":"修复登录问题"},"release_info":{"release_time":1704816000,"release_version":"1.0.3","release_desc":"首次发布"}}`,
+			wantExp:     &ExpVersionInfo{ExpTime: 1704902400, ExpVersion: "1.0.4", ExpDesc: "修复登录问题"},
+			wantRelease: &ReleaseVersionInfo{ReleaseTime: 1704816000, ReleaseVersion: "1.0.3", ReleaseDesc: "首次发布"},
+		},
+		{
+			name:    "exp only",
+			fixture: `{"errcode":0,"errmsg":"ok","exp_info":{"exp_time":1704902400,"exp_version":"1.0.0","exp_desc":"体验版"}}`,
+			wantExp: &ExpVersionInfo{ExpTime: 1704902400, ExpVersion: "1.0.0", ExpDesc: "体验版"},
+		},
+		{
+			name:    "exp only with empty release block",
+			fixture: `{"errcode":0,"errmsg":"ok","exp_info":{"exp_time":1704902400,"exp_version":"1.0.0","exp_desc":"体验版"},"release_info":{"release_time":0,"release_version":"","release_desc":""}}`,
+			wantExp: &ExpVersionInfo{ExpTime: 1704902400, ExpVersion: "1.0.0", ExpDesc: "体验版"},
+		},
+		{
+			name:    "neither",
+			fixture: `{"errcode":0,"errmsg":"ok"}`,
+		},
+	} {
+		server := newTestServer(t)
+		server.respond("/wxa/getversioninfo", tc.fixture)
+		client, _ := newTestClient(t, server)
+
+		info, err := client.Authorizer(testAuthorizerAppId).GetVersionInfo()
+		if err != nil {
+			t.Fatalf("%s: %v", tc.name, err)
+		}
+		if (info.ExpInfo == nil) != (tc.wantExp == nil) || info.ExpInfo != nil && *info.ExpInfo != *tc.wantExp {
+			t.Errorf("%s: exp_info: got %+v, want %+v", tc.name, info.ExpInfo, tc.wantExp)
+		}
+		if (info.ReleaseInfo == nil) != (tc.wantRelease == nil) || info.ReleaseInfo != nil && *info.ReleaseInfo != *tc.wantRelease {
+			t.Errorf("%s: release_info: got %+v, want %+v", tc.name, info.ReleaseInfo, tc.wantRelease)
+		}
+		req := server.lastRequest(t)
+		if got := req.Query["access_token"]; len(got) != 1 || got[0] != testAuthorizerToken {
+			t.Fatalf("%s: access_token: got %v", tc.name, got)
+		}
+		if string(req.Body) != `{}` {
+			t.Fatalf("%s: body: got %s, want {}", tc.name, req.Body)
+		}
+	}
+}
+
+func TestGetSupportVersion(t *testing.T) {
+	server := newTestServer(t)
+	server.respond("/cgi-bin/wxopen/getweappsupportversion", `{"errcode":0,"errmsg":"ok","now_version":"2.19.4","uv_info":{"items":[{"percentage":0,"version":"1.0.0"},{"percentage":12.5,"version":"2.19.4"},{"percentage":87.5,"version":"3.0.0"}]}}`)
+	client, _ := newTestClient(t, server)
+
+	version, err := client.Authorizer(testAuthorizerAppId).GetSupportVersion()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if version.NowVersion != "2.19.4" || len(version.UvInfo.Items) != 3 || version.UvInfo.Items[2] != (SupportVersionUv{Percentage: 87.5, Version: "3.0.0"}) {
+		t.Fatalf("version: got %+v", version)
+	}
+	if body := string(server.lastRequest(t).Body); body != `{}` {
+		t.Fatalf("body: got %s, want {}", body)
+	}
+}
+
+func TestSetSupportVersion(t *testing.T) {
+	server := newTestServer(t)
+	client, _ := newTestClient(t, server)
+	authorizer := client.Authorizer(testAuthorizerAppId)
+
+	if err := authorizer.SetSupportVersion("2.19.4"); err != nil {
+		t.Fatal(err)
+	}
+	req := server.lastRequest(t)
+	if req.Path != "/cgi-bin/wxopen/setweappsupportversion" {
+		t.Fatalf("path: got %s", req.Path)
+	}
+	if string(req.Body) != `{"version":"2.19.4"}` {
+		t.Fatalf("body: got %s", req.Body)
+	}
+
+	if err := authorizer.SetSupportVersion(""); err == nil {
+		t.Fatal("empty version: expected validation error")
+	}
+	if n := server.requestCount(); n != 1 {
+		t.Fatalf("sent %d requests, want 1", n)
+	}
+}
+
+func TestSetSupportVersionTooLow(t *testing.T) {
+	server := newTestServer(t)
+	server.respond("/cgi-bin/wxopen/setweappsupportversion", `{"errcode":40097,"errmsg":"invalid args"}`)
+	client, _ := newTestClient(t, server)
+
+	if err := client.Authorizer(testAuthorizerAppId).SetSupportVersion("1.0.0"); !errors.Is(err, ErrInvalidSupportVersion) {
+		t.Fatalf("got %v, want ErrInvalidSupportVersion", err)
+	}
+}
@@ -0,0 +1,169 @@
+package open
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/mrwangjinjin/go-wechat/pkg/util"
+	"image"
+	_ "image/jpeg"
+	_ "image/png"
+	"sync"
+	"time"
+)
+
+// GetWxaCodeImage 小程序码, 返回解码后的图片及其格式(png/jpeg)
+func (self *Client) GetWxaCodeImage(authorizerAccessToken string, data map[string]interface{}) (image.Image, string, error) {
+	body, err := self.GetWxaCode(authorizerAccessToken, data)
+	if err != nil {
+		return nil, "", err
+	}
+	return image.Decode(bytes.NewReader(body))
+}
+
+// GetWxaCode 使用授权方令牌获取小程序码, 授权方未授权或令牌无效时返回错误
+// 启用WithWxaCodeCache时相同参数的请求直接返回缓存
+func (self *AuthorizerClient) GetWxaCode(ctx context.Context, data map[string]interface{}) ([]byte, error) {
+	return self.cachedWxaCode("getwxacode", data, func(token string) ([]byte, error) {
+		return self.client.GetWxaCodeContext(ctx, token, data)
+	})
+}
+
+// GetWxaCodeUnlimit 使用授权方令牌获取不限制的小程序码, 启用WithWxaCodeCache时相同参数的请求直接返回缓存
+func (self *AuthorizerClient) GetWxaCodeUnlimit(ctx context.Context, opts WxaCodeUnlimitOptions) ([]byte, error) {
+	return self.cachedWxaCode("getwxacodeunlimit", opts, func(token string) ([]byte, error) {
+		return self.client.GetWxaCodeUnlimitContext(ctx, token, opts)
+	})
+}
+
+// cachedWxaCode 按授权方、接口及请求参数的哈希缓存小程序码
+func (self *AuthorizerClient) cachedWxaCode(kind string, params interface{}, fetch func(token string) ([]byte, error)) ([]byte, error) {
+	ttl := int64(self.client.wxaCodeCacheTTL / time.Second)
+	var key string
+	if ttl > 0 {
+		body, err := json.Marshal(params)
+		if err != nil {
+			return nil, err
+		}
+		sum := sha256.Sum256(body)
+		key = WxaCodeCacheKeyPrefix + self.AuthorizerAppId + "@@" + kind + "@@" + hex.EncodeToString(sum[:])
+		if resp, err := self.client.Cache.Get(key); err == nil {
+			if encoded, ok := util.JsonUnmarshal(resp)["data"].(string); ok {
+				if data, err := base64.StdEncoding.DecodeString(encoded); err == nil {
+					return data, nil
+				}
+			}
+		}
+	}
+	token, err := self.AccessToken()
+	if err != nil {
+		return nil, err
+	}
+	data, err := fetch(token)
+	if err != nil {
+		return nil, err
+	}
+	if ttl > 0 {
+		self.client.cacheSetEx(key, map[string]interface{}{"data": data}, ttl)
+	}
+	return data, nil
+}
+
+// 小程序版本
+const (
+	EnvVersionRelease = "release"
+	EnvVersionTrial   = "trial"
+	EnvVersionDevelop = "develop"
+)
+
+func checkEnvVersion(envVersion string) error {
+	switch envVersion {
+	case "", EnvVersionRelease, EnvVersionTrial, EnvVersionDevelop:
+		return nil
+	}
+	return fmt.Errorf("不支持的env_version:%s, 取值为release/trial/develop", envVersion)
+}
+
+// LineColor 小程序码线条颜色
+type LineColor struct {
+	R int `json:"r"`
+	G int `json:"g"`
+	B int `json:"b"`
+}
+
+// WxaCodeUnlimitOptions 获取不限制的小程序码参数
+type WxaCodeUnlimitOptions struct {
+	Scene     string     `json:"scene"`
+	Page      string     `json:"page,omitempty"`
+	CheckPath *bool      `json:"check_path,omitempty"`
+	Width     int        `json:"width,omitempty"`
+	AutoColor bool       `json:"auto_color,omitempty"`
+	LineColor *LineColor `json:"line_color,omitempty"`
+	IsHyaline bool       `json:"is_hyaline,omitempty"`
+	// EnvVersion 要打开的小程序版本, 为空时默认正式版
+	EnvVersion string `json:"env_version,omitempty"`
+}
+
+// WxaCodeResult 批量生成小程序码的单个结果
+type WxaCodeResult struct {
+	Scene string
+	Data  []byte
+	Err   error
+}
+
+// GetWxaCodeUnlimit 获取不限制的小程序码
+func (self *Client) GetWxaCodeUnlimit(authorizerAccessToken string, opts WxaCodeUnlimitOptions) ([]byte, error) {
+	return self.GetWxaCodeUnlimitContext(context.Background(), authorizerAccessToken, opts)
+}
+
+// GetWxaCodeUnlimitContext 获取不限制的小程序码, ctx取消时中止请求
+func (self *Client) GetWxaCodeUnlimitContext(ctx context.Context, authorizerAccessToken string, opts WxaCodeUnlimitOptions) ([]byte, error) {
+	if opts.Scene == "" {
+		return nil, errors.New("scene不能为空")
+	}
+	if len(opts.Scene) > util.MaxSceneLength {
+		return nil, errors.New("scene最大32个可见字符")
+	}
+	if err := checkEnvVersion(opts.EnvVersion); err != nil {
+		return nil, err
+	}
+	return self.postBinary(ctx, self.Endpoint.GetWxaCodeUnlimit(authorizerAccessToken), opts)
+}
+
+// BatchGetWxaCodeUnlimit 使用授权方令牌并发批量获取不限制的小程序码, concurrency为最大并发数
+// 结果与requests一一对应, ctx取消后未开始的请求以ctx.Err()作为结果; 授权方令牌不可用时直接返回错误
+func (self *AuthorizerClient) BatchGetWxaCodeUnlimit(ctx context.Context, requests []WxaCodeUnlimitOptions, concurrency int) ([]WxaCodeResult, error) {
+	if _, err := self.AccessToken(); err != nil {
+		return nil, err
+	}
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+	results := make([]WxaCodeResult, len(requests))
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i, opts := range requests {
+		results[i].Scene = opts.Scene
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			results[i].Err = ctx.Err()
+			continue
+		}
+		wg.Add(1)
+		go func(i int, opts WxaCodeUnlimitOptions) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			results[i].Data, results[i].Err = self.GetWxaCodeUnlimit(ctx, opts)
+		}(i, opts)
+	}
+	wg.Wait()
+	return results, ctx.Err()
+}
@@ -0,0 +1,264 @@
+package open
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/mrwangjinjin/go-wechat/core"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestGetWxaCodeUnlimitDevelopEnvVersion(t *testing.T) {
+	server := newTestServer(t)
+	image := testPNG(t)
+	server.respondBinary("/wxa/getwxacodeunlimit", "image/png", image)
+	client, _ := newTestClient(t, server)
+
+	data, err := client.GetWxaCodeUnlimit(testAuthorizerToken, WxaCodeUnlimitOptions{
+		Scene:      "id=1",
+		EnvVersion: EnvVersionDevelop,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(data, image) {
+		t.Fatalf("got %q, want the image body", data)
+	}
+	assertTestPNG(t, data)
+	body := decodeBody(t, server.lastRequest(t))
+	if body["env_version"] != "develop" || body["scene"] != "id=1" {
+		t.Fatalf("unexpected body: %v", body)
+	}
+}
+
+func TestGetWxaCodeUnlimitOmitsEmptyEnvVersion(t *testing.T) {
+	server := newTestServer(t)
+	server.respondBinary("/wxa/getwxacodeunlimit", "image/png", testPNG(t))
+	client, _ := newTestClient(t, server)
+
+	if _, err := client.GetWxaCodeUnlimit(testAuthorizerToken, WxaCodeUnlimitOptions{Scene: "id=1"}); err != nil {
+		t.Fatal(err)
+	}
+	if body := decodeBody(t, server.lastRequest(t)); len(body) != 1 || body["scene"] != "id=1" {
+		t.Fatalf("got %v, want only scene", body)
+	}
+	for _, envVersion := range []string{EnvVersionRelease, EnvVersionTrial} {
+		if _, err := client.GetWxaCodeUnlimit(testAuthorizerToken, WxaCodeUnlimitOptions{Scene: "id=1", EnvVersion: envVersion}); err != nil {
+			t.Fatal(err)
+		}
+		if body := decodeBody(t, server.lastRequest(t)); body["env_version"] != envVersion {
+			t.Fatalf("env_version: got %v, want %s", body["env_version"], envVersion)
+		}
+	}
+}
+
+func TestGetWxaCodeRejectsUnknownEnvVersion(t *testing.T) {
+	server := newTestServer(t)
+	client, _ := newTestClient(t, server)
+
+	if _, err := client.GetWxaCodeUnlimit(testAuthorizerToken, WxaCodeUnlimitOptions{Scene: "a", EnvVersion: "beta"}); err == nil {
+		t.Fatal("expected error for unknown env_version")
+	}
+	if _, err := client.GetWxaCode(testAuthorizerToken, map[string]interface{}{"env_version": "beta"}); err == nil {
+		t.Fatal("expected error for unknown env_version")
+	}
+	if n := server.requestCount(); n != 0 {
+		t.Fatalf("invalid requests sent %d requests", n)
+	}
+}
+
+func TestGetWxaCodeResponseSizeLimit(t *testing.T) {
+	server := newTestServer(t)
+	server.respondBinary("/wxa/getwxacode", "image/png", bytes.Repeat([]byte("x"), 64))
+	client, _ := newTestClient(t, server)
+	client.Http.SetMaxResponseBytes(32)
+
+	_, err := client.GetWxaCode(testAuthorizerToken, map[string]interface{}{"path": "pages/index"})
+	var tooLarge *core.ResponseTooLargeError
+	if !errors.As(err, &tooLarge) {
+		t.Fatalf("got %v, want *core.ResponseTooLargeError", err)
+	}
+}
+
+func TestGetWxaCodeJSONError(t *testing.T) {
+	server := newTestServer(t)
+	server.respond("/wxa/getwxacode", `{"errcode":45009,"errmsg":"reach max api daily quota limit"}`)
+	client, _ := newTestClient(t, server)
+
+	if _, err := client.GetWxaCode(testAuthorizerToken, nil); !errors.Is(err, ErrApiDailyQuota) {
+		t.Fatalf("got %v, want ErrApiDailyQuota", err)
+	}
+}
+
+func TestGetWxaCodeImage(t *testing.T) {
+	server := newTestServer(t)
+	server.respondBinary("/wxa/getwxacode", "image/png", testPNG(t))
+	client, _ := newTestClient(t, server)
+
+	img, format, err := client.GetWxaCodeImage(testAuthorizerToken, map[string]interface{}{"path": "pages/index"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if format != "png" {
+		t.Fatalf("format = %q, want png", format)
+	}
+	assertTestImage(t, img)
+}
+
+func TestGetWxaCodeImageJSONError(t *testing.T) {
+	server := newTestServer(t)
+	server.respond("/wxa/getwxacode", `{"errcode":41030,"errmsg":"invalid page"}`)
+	client, _ := newTestClient(t, server)
+
+	img, format, err := client.GetWxaCodeImage(testAuthorizerToken, map[string]interface{}{"path": "pages/missing"})
+	var apiErr *Error
+	if !errors.As(err, &apiErr) || apiErr.ErrCode != 41030 {
+		t.Fatalf("got %v, want errcode 41030", err)
+	}
+	if img != nil || format != "" {
+		t.Fatalf("got image %v (%s) with error %v", img, format, err)
+	}
+}
+
+// concurrencyTransport 记录同时进行中的请求数的最大值, 每个请求延迟delay以便并发重叠
+type concurrencyTransport struct {
+	delay    time.Duration
+	inflight int32
+	peak     int32
+}
+
+func (self *concurrencyTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	n := atomic.AddInt32(&self.inflight, 1)
+	defer atomic.AddInt32(&self.inflight, -1)
+	for {
+		peak := atomic.LoadInt32(&self.peak)
+		if n <= peak || atomic.CompareAndSwapInt32(&self.peak, peak, n) {
+			break
+		}
+	}
+	time.Sleep(self.delay)
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestBatchGetWxaCodeUnlimit(t *testing.T) {
+	server := newTestServer(t)
+	image := testPNG(t)
+	server.respondBinary("/wxa/getwxacodeunlimit", "image/png", image)
+	client, _ := newTestClient(t, server)
+	transport := &concurrencyTransport{delay: 10 * time.Millisecond}
+	client.Http.SetHttpClient(&http.Client{Transport: transport})
+
+	requests := make([]WxaCodeUnlimitOptions, 20)
+	for i := range requests {
+		requests[i] = WxaCodeUnlimitOptions{Scene: fmt.Sprintf("id=%d", i), Page: "pages/index"}
+	}
+	results, err := client.Authorizer(testAuthorizerAppId).BatchGetWxaCodeUnlimit(context.Background(), requests, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != len(requests) {
+		t.Fatalf("got %d results, want %d", len(results), len(requests))
+	}
+	for i, result := range results {
+		if result.Scene != requests[i].Scene || result.Err != nil || !bytes.Equal(result.Data, image) {
+			t.Fatalf("result %d: got scene %q, err %v, %d bytes", i, result.Scene, result.Err, len(result.Data))
+		}
+	}
+	if peak := atomic.LoadInt32(&transport.peak); peak != 4 {
+		t.Fatalf("max concurrent requests: got %d, want 4", peak)
+	}
+	scenes := map[string]bool{}
+	for _, body := range server.requestBodies(t, "/wxa/getwxacodeunlimit") {
+		scenes[body["scene"].(string)] = true
+	}
+	if len(scenes) != len(requests) {
+		t.Fatalf("server saw %d distinct scenes, want %d", len(scenes), len(requests))
+	}
+	if got := server.lastRequest(t).Query["access_token"]; len(got) != 1 || got[0] != testAuthorizerToken {
+		t.Fatalf("access_token: got %v", got)
+	}
+}
+
+func TestBatchGetWxaCodeUnlimitCancel(t *testing.T) {
+	server := newTestServer(t)
+	server.respondBinary("/wxa/getwxacodeunlimit", "image/png", testPNG(t))
+	client, _ := newTestClient(t, server)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	results, err := client.Authorizer(testAuthorizerAppId).BatchGetWxaCodeUnlimit(ctx, []WxaCodeUnlimitOptions{{Scene: "a=1"}, {Scene: "a=2"}}, 1)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("got %v, want context.Canceled", err)
+	}
+	for i, result := range results {
+		if result.Err == nil || result.Data != nil {
+			t.Fatalf("result %d: got %+v, want an error", i, result)
+		}
+	}
+}
+
+func TestBatchGetWxaCodeUnlimitNotAuthorized(t *testing.T) {
+	server := newTestServer(t)
+	client, _ := newTestClient(t, server)
+
+	results, err := client.Authorizer("wx_unknown").BatchGetWxaCodeUnlimit(context.Background(), []WxaCodeUnlimitOptions{{Scene: "a=1"}}, 4)
+	if !errors.Is(err, ErrAuthorizerNotAuthorized) || results != nil {
+		t.Fatalf("got (%v, %v), want ErrAuthorizerNotAuthorized", results, err)
+	}
+	if n := server.requestCount(); n != 0 {
+		t.Fatalf("got %d requests, want 0", n)
+	}
+}
+
+func TestWxaCodeCache(t *testing.T) {
+	server := newTestServer(t)
+	image := testPNG(t)
+	server.respondBinary("/wxa/getwxacodeunlimit", "image/png", image)
+	server.respondBinary("/wxa/getwxacode", "image/png", image)
+	client, _ := newTestClient(t, server)
+	WithWxaCodeCache(time.Minute)(client)
+	authorizer := client.Authorizer(testAuthorizerAppId)
+
+	for i := 0; i < 2; i++ {
+		data, err := authorizer.GetWxaCodeUnlimit(context.Background(), WxaCodeUnlimitOptions{Scene: "id=1"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertTestPNG(t, data)
+	}
+	if n := server.requestCount(); n != 1 {
+		t.Fatalf("identical requests: got %d HTTP requests, want 1", n)
+	}
+
+	if _, err := authorizer.GetWxaCodeUnlimit(context.Background(), WxaCodeUnlimitOptions{Scene: "id=2"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := authorizer.GetWxaCode(context.Background(), map[string]interface{}{"path": "pages/index"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := authorizer.GetWxaCode(context.Background(), map[string]interface{}{"path": "pages/index"}); err != nil {
+		t.Fatal(err)
+	}
+	if n := server.requestCount(); n != 3 {
+		t.Fatalf("got %d HTTP requests, want 3", n)
+	}
+}
+
+func TestWxaCodeCacheDisabledByDefault(t *testing.T) {
+	server := newTestServer(t)
+	server.respondBinary("/wxa/getwxacodeunlimit", "image/png", testPNG(t))
+	client, _ := newTestClient(t, server)
+
+	for i := 0; i < 2; i++ {
+		if _, err := client.Authorizer(testAuthorizerAppId).GetWxaCodeUnlimit(context.Background(), WxaCodeUnlimitOptions{Scene: "id=1"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if n := server.requestCount(); n != 2 {
+		t.Fatalf("got %d HTTP requests, want 2 without the cache", n)
+	}
+}
@@ -0,0 +1,70 @@
+package open
+
+import "errors"
+
+// LinkedMiniProgram 公众号关联的小程序, Status: 1已关联 2等待小程序管理员确认 3小程序管理员拒绝 12等待公众号管理员确认 13公众号管理员拒绝
+type LinkedMiniProgram struct {
+	Status     int    `json:"status"`
+	UserName   string `json:"username"`
+	AppId      string `json:"appid"`
+	Source     string `json:"source"`
+	NickName   string `json:"nickname"`
+	Selected   int    `json:"selected"`
+	Released   int    `json:"released"`
+	HeadImgUrl string `json:"headimg_url"`
+	Email      string `json:"email"`
+}
+
+// LinkedAccounts 公众号关联的小程序列表
+type LinkedAccounts struct {
+	Items []LinkedMiniProgram `json:"items"`
+}
+
+// GetLinkedAccounts 获取公众号关联的小程序
+func (self *AuthorizerClient) GetLinkedAccounts() (*LinkedAccounts, error) {
+	token, err := self.AccessToken()
+	if err != nil {
+		return nil, err
+	}
+	var resp struct {
+		WxOpens LinkedAccounts `json:"wxopens"`
+	}
+	if err := self.client.postJSON(self.client.Endpoint.WxampLinkGet(token), map[string]interface{}{}, &resp); err != nil {
+		return nil, err
+	}
+	return &resp.WxOpens, nil
+}
+
+// LinkMiniProgram 公众号关联小程序, notifyUsers和showProfile取值为"0"或"1"
+// 已发送关联邀请、等待小程序管理员确认时返回ErrLinkPending
+func (self *AuthorizerClient) LinkMiniProgram(appId, notifyUsers, showProfile string) error {
+	if appId == "" {
+		return errors.New("appid不能为空")
+	}
+	if (notifyUsers != "0" && notifyUsers != "1") || (showProfile != "0" && showProfile != "1") {
+		return errors.New("notify_users和show_profile取值为0或1")
+	}
+	token, err := self.AccessToken()
+	if err != nil {
+		return err
+	}
+	return self.client.postJSON(self.client.Endpoint.WxampLink(token), map[string]interface{}{
+		"appid":        appId,
+		"notify_users": notifyUsers,
+		"show_profile": showProfile,
+	}, nil)
+}
+
+// UnlinkMiniProgram 解除公众号与小程序的关联
+func (self *AuthorizerClient) UnlinkMiniProgram(appId string) error {
+	if appId == "" {
+		return errors.New("appid不能为空")
+	}
+	token, err := self.AccessToken()
+	if err != nil {
+		return err
+	}
+	return self.client.postJSON(self.client.Endpoint.WxampUnlink(token), map[string]interface{}{
+		"appid": appId,
+	}, nil)
+}
@@ -0,0 +1,93 @@
+package open
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestGetLinkedAccounts(t *testing.T) {
+	server := newTestServer(t)
+	server.respond("/cgi-bin/wxopen/wxamplinkget", `{"errcode":0,"errmsg":"ok","wxopens":{"items":[{"status":1,"username":"gh_008feb2d8bc5","appid":"wx_weapp","source":"公众号","nickname":"小程序","selected":1,"released":1,"headimg_url":"https://example.com/head.png","email":"a@example.com"},{"status":2,"username":"gh_pending","appid":"wx_pending","nickname":"待确认"}]}}`)
+	client, _ := newTestClient(t, server)
+
+	accounts, err := client.Authorizer(testAuthorizerAppId).GetLinkedAccounts()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(accounts.Items) != 2 {
+		t.Fatalf("items: got %+v", accounts.Items)
+	}
+	if item := accounts.Items[0]; item.Status != 1 || item.AppId != "wx_weapp" || item.UserName != "gh_008feb2d8bc5" || item.Selected != 1 || item.HeadImgUrl != "https://example.com/head.png" {
+		t.Fatalf("item: got %+v", item)
+	}
+	if accounts.Items[1].Status != 2 {
+		t.Fatalf("pending item: got %+v", accounts.Items[1])
+	}
+	if body := string(server.lastRequest(t).Body); body != `{}` {
+		t.Fatalf("body: got %s, want {}", body)
+	}
+}
+
+func TestLinkMiniProgram(t *testing.T) {
+	server := newTestServer(t)
+	client, _ := newTestClient(t, server)
+	authorizer := client.Authorizer(testAuthorizerAppId)
+
+	if err := authorizer.LinkMiniProgram("wx_weapp", "1", "0"); err != nil {
+		t.Fatal(err)
+	}
+	req := server.lastRequest(t)
+	if req.Path != "/cgi-bin/wxopen/wxamplink" {
+		t.Fatalf("path: got %s", req.Path)
+	}
+	if got := req.Query["access_token"]; len(got) != 1 || got[0] != testAuthorizerToken {
+		t.Fatalf("access_token: got %v", got)
+	}
+	if string(req.Body) != `{"appid":"wx_weapp","notify_users":"1","show_profile":"0"}` {
+		t.Fatalf("body: got %s", req.Body)
+	}
+
+	if err := authorizer.LinkMiniProgram("", "1", "1"); err == nil {
+		t.Error("empty appid: expected validation error")
+	}
+	if err := authorizer.LinkMiniProgram("wx_weapp", "2", "1"); err == nil {
+		t.Error("notify_users 2: expected validation error")
+	}
+	if n := server.requestCount(); n != 1 {
+		t.Fatalf("sent %d requests, want 1", n)
+	}
+}
+
+func TestLinkMiniProgramPending(t *testing.T) {
+	server := newTestServer(t)
+	server.respond("/cgi-bin/wxopen/wxamplink", `{"errcode":89010,"errmsg":"link message has sent"}`)
+	client, _ := newTestClient(t, server)
+
+	if err := client.Authorizer(testAuthorizerAppId).LinkMiniProgram("wx_weapp", "1", "1"); !errors.Is(err, ErrLinkPending) {
+		t.Fatalf("got %v, want ErrLinkPending", err)
+	}
+}
+
+func TestUnlinkMiniProgram(t *testing.T) {
+	server := newTestServer(t)
+	client, _ := newTestClient(t, server)
+	authorizer := client.Authorizer(testAuthorizerAppId)
+
+	if err := authorizer.UnlinkMiniProgram("wx_weapp"); err != nil {
+		t.Fatal(err)
+	}
+	req := server.lastRequest(t)
+	if req.Path != "/cgi-bin/wxopen/wxampunlink" {
+		t.Fatalf("path: got %s", req.Path)
+	}
+	if string(req.Body) != `{"appid":"wx_weapp"}` {
+		t.Fatalf("body: got %s", req.Body)
+	}
+
+	if err := authorizer.UnlinkMiniProgram(""); err == nil {
+		t.Fatal("empty appid: expected validation error")
+	}
+	if n := server.requestCount(); n != 1 {
+		t.Fatalf("sent %d requests, want 1", n)
+	}
+}
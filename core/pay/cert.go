@@ -0,0 +1,143 @@
+package pay
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/mrwangjinjin/go-wechat/internal/singleflight"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	// certRefreshInterval 平台证书最长刷新间隔
+	certRefreshInterval = 12 * time.Hour
+	// certRefreshAhead 证书过期前提前刷新的时间
+	certRefreshAhead = 24 * time.Hour
+	// certRefreshBackoff 两次下载证书的最小间隔, Wechatpay-Serial可被伪造, 未知序列号不能每次都触发下载
+	certRefreshBackoff = time.Minute
+)
+
+// CertManager 自动下载并缓存微信支付平台证书, 实现CertProvider
+type CertManager struct {
+	client    *Client
+	group     singleflight.Group
+	mu        sync.RWMutex
+	certs     map[string]*x509.Certificate
+	refreshAt time.Time
+	// attemptAt 最近一次下载证书结束的时间, 无论成功与否; 下载进行中的并发调用仍会共享这次下载
+	attemptAt time.Time
+}
+
+// NewCertManager 创建平台证书管理器, 使用client的商户证书签名下载请求,
+// 下载的证书由APIv3密钥加密, 因此client的Config.ApiV3Key不能为空
+func NewCertManager(client *Client) (*CertManager, error) {
+	if len(client.config.ApiV3Key) != 32 {
+		return nil, errors.New("APIv3密钥长度必须为32字节")
+	}
+	return &CertManager{
+		client: client,
+		certs:  map[string]*x509.Certificate{},
+	}, nil
+}
+
+// Certificate 按序列号获取平台证书, 证书即将过期或序列号未知时重新下载,
+// 距上次下载不足1分钟时不再下载, 直接使用已缓存的证书
+func (self *CertManager) Certificate(serialNo string) (*x509.Certificate, error) {
+	self.mu.RLock()
+	cert, ok := self.certs[serialNo]
+	now := time.Now()
+	stale := now.After(self.refreshAt)
+	backoff := now.Sub(self.attemptAt) < certRefreshBackoff
+	self.mu.RUnlock()
+	if ok && !stale {
+		return cert, nil
+	}
+	if !backoff {
+		// 并发的调用共享同一次下载
+		_, err, _ := self.group.Do("certificates", func() (interface{}, error) {
+			return nil, self.Refresh(context.Background())
+		})
+		if err == nil {
+			return StaticCertProvider(self.snapshot()).Certificate(serialNo)
+		}
+		if !ok || now.After(cert.NotAfter) {
+			return nil, err
+		}
+		// 下载失败时继续使用未过期的旧证书
+		return cert, nil
+	}
+	if ok && now.Before(cert.NotAfter) {
+		return cert, nil
+	}
+	return nil, fmt.Errorf("%w:%s", ErrCertificateNotFound, serialNo)
+}
+
+func (self *CertManager) snapshot() map[string]*x509.Certificate {
+	self.mu.RLock()
+	defer self.mu.RUnlock()
+	certs := make(map[string]*x509.Certificate, len(self.certs))
+	for serialNo, cert := range self.certs {
+		certs[serialNo] = cert
+	}
+	return certs
+}
+
+// Refresh 下载平台证书, 校验响应签名后替换缓存
+func (self *CertManager) Refresh(ctx context.Context) error {
+	defer func() {
+		self.mu.Lock()
+		self.attemptAt = time.Now()
+		self.mu.Unlock()
+	}()
+	headers, body, err := self.client.send(ctx, http.MethodGet, "/v3/certificates", nil)
+	if err != nil {
+		return err
+	}
+	var resp struct {
+		Data []struct {
+			SerialNo           string         `json:"serial_no"`
+			EffectiveTime      string         `json:"effective_time"`
+			ExpireTime         string         `json:"expire_time"`
+			EncryptCertificate NotifyResource `json:"encrypt_certificate"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return err
+	}
+	if len(resp.Data) == 0 {
+		return errors.New("平台证书列表为空")
+	}
+	certs := make(map[string]*x509.Certificate, len(resp.Data))
+	refreshAt := time.Now().Add(certRefreshInterval)
+	for _, item := range resp.Data {
+		pemBytes, err := DecryptResource(item.EncryptCertificate, self.client.config.ApiV3Key)
+		if err != nil {
+			return err
+		}
+		cert, err := LoadCertificate(pemBytes)
+		if err != nil {
+			return err
+		}
+		certs[item.SerialNo] = cert
+		if ahead := cert.NotAfter.Add(-certRefreshAhead); ahead.Before(refreshAt) {
+			refreshAt = ahead
+		}
+	}
+	// 证书下载接口的响应同样由平台证书签名, 使用刚下载的证书验证
+	if err := VerifyNotify(headers, body, StaticCertProvider(certs)); err != nil {
+		return err
+	}
+	if floor := time.Now().Add(time.Hour); refreshAt.Before(floor) {
+		// 旧证书临近过期且尚无新证书时, 避免每次验签都重新下载
+		refreshAt = floor
+	}
+	self.mu.Lock()
+	self.certs = certs
+	self.refreshAt = refreshAt
+	self.mu.Unlock()
+	return nil
+}
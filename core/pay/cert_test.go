@@ -0,0 +1,187 @@
+package pay
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// encryptResource 使用testApiV3Key加密plaintext, 与微信支付下发的encrypt_certificate格式一致
+func encryptResource(t *testing.T, plaintext []byte) NotifyResource {
+	t.Helper()
+	block, err := aes.NewCipher([]byte(testApiV3Key))
+	if err != nil {
+		t.Fatal(err)
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		t.Fatal(err)
+	}
+	nonce := "a1b2c3d4e5f6"
+	return NotifyResource{
+		Algorithm:      "AEAD_AES_256_GCM",
+		Ciphertext:     base64.StdEncoding.EncodeToString(gcm.Seal(nil, []byte(nonce), plaintext, []byte("certificate"))),
+		AssociatedData: "certificate",
+		Nonce:          nonce,
+	}
+}
+
+// certServer 模拟/v3/certificates接口, 记录下载次数
+type certServer struct {
+	*httptest.Server
+	downloads int32
+	delay     time.Duration
+	// forge 为true时响应签名使用错误的内容
+	forge bool
+}
+
+func newCertServer(t *testing.T) *certServer {
+	key, _, certPem := testPlatformCert(t)
+	server := &certServer{}
+	body, err := json.Marshal(map[string]interface{}{
+		"data": []map[string]interface{}{{
+			"serial_no":           testCertSerial,
+			"effective_time":      "2023-01-01T00:00:00+08:00",
+			"expire_time":         "2028-01-01T00:00:00+08:00",
+			"encrypt_certificate": encryptResource(t, certPem),
+		}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	server.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v3/certificates" || r.Header.Get("Authorization") == "" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		atomic.AddInt32(&server.downloads, 1)
+		time.Sleep(server.delay)
+		signed := body
+		if server.forge {
+			signed = []byte("forged")
+		}
+		for name, values := range signedHeaders(t, key, testCertSerial, time.Now(), signed) {
+			w.Header()[name] = values
+		}
+		_, _ = w.Write(body)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func newTestCertManager(t *testing.T, server *certServer) *CertManager {
+	key, _, _ := testPlatformCert(t)
+	client, err := NewClient(&Config{
+		MchId:      "1900000001",
+		SerialNo:   "MERCHANT_SERIAL",
+		PrivateKey: key,
+		ApiV3Key:   testApiV3Key,
+		BaseUrl:    server.URL,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	manager, err := NewCertManager(client)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return manager
+}
+
+func TestCertManagerDownloadsCertificate(t *testing.T) {
+	server := newCertServer(t)
+	manager := newTestCertManager(t, server)
+	_, want, _ := testPlatformCert(t)
+
+	for i := 0; i < 3; i++ {
+		cert, err := manager.Certificate(testCertSerial)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !cert.Equal(want) {
+			t.Fatal("downloaded certificate does not match")
+		}
+	}
+	if n := atomic.LoadInt32(&server.downloads); n != 1 {
+		t.Fatalf("downloaded %d times, want 1", n)
+	}
+}
+
+func TestCertManagerUnknownSerialIsRateLimited(t *testing.T) {
+	server := newCertServer(t)
+	manager := newTestCertManager(t, server)
+	if _, err := manager.Certificate(testCertSerial); err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 10; i++ {
+		if _, err := manager.Certificate("FORGED_SERIAL"); !errors.Is(err, ErrCertificateNotFound) {
+			t.Fatalf("got %v, want ErrCertificateNotFound", err)
+		}
+	}
+	if n := atomic.LoadInt32(&server.downloads); n != 1 {
+		t.Fatalf("forged serials caused %d downloads, want 1", n)
+	}
+}
+
+func TestCertManagerConcurrentRefreshIsShared(t *testing.T) {
+	server := newCertServer(t)
+	server.delay = 100 * time.Millisecond
+	manager := newTestCertManager(t, server)
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 10)
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := manager.Certificate(testCertSerial); err != nil {
+				errs <- err
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+	if n := atomic.LoadInt32(&server.downloads); n != 1 {
+		t.Fatalf("downloaded %d times, want 1", n)
+	}
+}
+
+func TestCertManagerRejectsForgedResponse(t *testing.T) {
+	server := newCertServer(t)
+	server.forge = true
+	manager := newTestCertManager(t, server)
+
+	if _, err := manager.Certificate(testCertSerial); err == nil {
+		t.Fatal("expected signature error")
+	}
+	// 失败后处于退避期, 不再立即重新下载
+	if _, err := manager.Certificate(testCertSerial); err == nil {
+		t.Fatal("expected error during backoff")
+	}
+	if n := atomic.LoadInt32(&server.downloads); n != 1 {
+		t.Fatalf("downloaded %d times, want 1", n)
+	}
+}
+
+func TestNewCertManagerRequiresApiV3Key(t *testing.T) {
+	key, _, _ := testPlatformCert(t)
+	client, err := NewClient(&Config{MchId: "1900000001", SerialNo: "MERCHANT_SERIAL", PrivateKey: key})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewCertManager(client); err == nil {
+		t.Fatal("expected error for missing APIv3 key")
+	}
+}
@@ -0,0 +1,105 @@
+package pay
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// Amount 订单金额, 单位为分
+type Amount struct {
+	Total    int64  `json:"total"`
+	Currency string `json:"currency,omitempty"`
+}
+
+// Payer 支付者
+type Payer struct {
+	OpenId string `json:"openid"`
+}
+
+// UnifiedOrderRequest JSAPI下单请求, AppId/MchId/NotifyUrl为空时使用Config中的值
+type UnifiedOrderRequest struct {
+	AppId       string `json:"appid"`
+	MchId       string `json:"mchid"`
+	Description string `json:"description"`
+	OutTradeNo  string `json:"out_trade_no"`
+	TimeExpire  string `json:"time_expire,omitempty"`
+	Attach      string `json:"attach,omitempty"`
+	NotifyUrl   string `json:"notify_url"`
+	GoodsTag    string `json:"goods_tag,omitempty"`
+	Amount      Amount `json:"amount"`
+	Payer       Payer  `json:"payer"`
+}
+
+// UnifiedOrderResponse JSAPI下单结果
+type UnifiedOrderResponse struct {
+	PrepayId string `json:"prepay_id"`
+}
+
+// JsApiPayParams 小程序wx.requestPayment参数
+type JsApiPayParams struct {
+	AppId     string `json:"appId"`
+	TimeStamp string `json:"timeStamp"`
+	NonceStr  string `json:"nonceStr"`
+	Package   string `json:"package"`
+	SignType  string `json:"signType"`
+	PaySign   string `json:"paySign"`
+}
+
+// UnifiedOrder JSAPI下单, 返回prepay_id
+func (self *Client) UnifiedOrder(ctx context.Context, req UnifiedOrderRequest) (*UnifiedOrderResponse, error) {
+	if req.AppId == "" {
+		req.AppId = self.config.AppId
+	}
+	if req.MchId == "" {
+		req.MchId = self.config.MchId
+	}
+	if req.NotifyUrl == "" {
+		req.NotifyUrl = self.config.NotifyUrl
+	}
+	if req.AppId == "" || req.OutTradeNo == "" || req.Description == "" || req.NotifyUrl == "" {
+		return nil, errors.New("appid、商户订单号、商品描述和通知地址不能为空")
+	}
+	if req.Amount.Total <= 0 {
+		return nil, errors.New("订单金额必须大于0")
+	}
+	if req.Payer.OpenId == "" {
+		return nil, errors.New("支付者openid不能为空")
+	}
+	var resp UnifiedOrderResponse
+	if err := self.postJSON(ctx, "/v3/pay/transactions/jsapi", req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// BuildJsApiPayParams 生成小程序调起支付的参数及签名
+func (self *Client) BuildJsApiPayParams(prepayId string) (*JsApiPayParams, error) {
+	if prepayId == "" {
+		return nil, errors.New("prepay_id不能为空")
+	}
+	nonce, err := nonceStr()
+	if err != nil {
+		return nil, err
+	}
+	return self.jsApiPayParams(prepayId, time.Now(), nonce)
+}
+
+// jsApiPayParams 使用指定的时间戳和随机串生成调起支付的参数
+func (self *Client) jsApiPayParams(prepayId string, timestamp time.Time, nonce string) (*JsApiPayParams, error) {
+	params := &JsApiPayParams{
+		AppId:     self.config.AppId,
+		TimeStamp: strconv.FormatInt(timestamp.Unix(), 10),
+		NonceStr:  nonce,
+		Package:   "prepay_id=" + prepayId,
+		SignType:  "RSA",
+	}
+	var err error
+	params.PaySign, err = self.sign(fmt.Sprintf("%s\n%s\n%s\n%s\n", params.AppId, params.TimeStamp, params.NonceStr, params.Package))
+	if err != nil {
+		return nil, err
+	}
+	return params, nil
+}
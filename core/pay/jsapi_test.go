@@ -0,0 +1,100 @@
+package pay
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestUnifiedOrder(t *testing.T) {
+	server := newPayServer(t, http.StatusOK, `{"prepay_id":"wx201410272009395522657a690389285100"}`)
+	client := testMerchantClient(t, server.URL)
+
+	resp, err := client.UnifiedOrder(context.Background(), UnifiedOrderRequest{
+		Description: "测试商品",
+		OutTradeNo:  "ORDER_1",
+		Amount:      Amount{Total: 100, Currency: "CNY"},
+		Payer:       Payer{OpenId: "OPENID"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.PrepayId != "wx201410272009395522657a690389285100" {
+		t.Fatalf("prepay_id = %q", resp.PrepayId)
+	}
+
+	req := server.lastRequest(t)
+	if req.Method != http.MethodPost || req.RequestURI != "/v3/pay/transactions/jsapi" {
+		t.Fatalf("request = %s %s", req.Method, req.RequestURI)
+	}
+	if ct := req.Header.Get("Content-Type"); ct != "application/json" {
+		t.Fatalf("Content-Type = %q", ct)
+	}
+	want := `{"appid":"wxd678efh567hg6787","mchid":"1900000001","description":"测试商品","out_trade_no":"ORDER_1","notify_url":"https://example.com/pay/notify","amount":{"total":100,"currency":"CNY"},"payer":{"openid":"OPENID"}}`
+	if string(req.Body) != want {
+		t.Fatalf("body = %s\nwant %s", req.Body, want)
+	}
+	verifyAuthorization(t, client, req)
+}
+
+func TestUnifiedOrderValidation(t *testing.T) {
+	server := newPayServer(t, http.StatusOK, `{}`)
+	client := testMerchantClient(t, server.URL)
+
+	valid := UnifiedOrderRequest{
+		Description: "测试商品",
+		OutTradeNo:  "ORDER_1",
+		Amount:      Amount{Total: 100},
+		Payer:       Payer{OpenId: "OPENID"},
+	}
+	cases := map[string]func(req *UnifiedOrderRequest){
+		"no out_trade_no": func(req *UnifiedOrderRequest) { req.OutTradeNo = "" },
+		"no description":  func(req *UnifiedOrderRequest) { req.Description = "" },
+		"zero amount":     func(req *UnifiedOrderRequest) { req.Amount.Total = 0 },
+		"no openid":       func(req *UnifiedOrderRequest) { req.Payer.OpenId = "" },
+	}
+	for name, mutate := range cases {
+		req := valid
+		mutate(&req)
+		if _, err := client.UnifiedOrder(context.Background(), req); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+	if n := server.requestCount(); n != 0 {
+		t.Fatalf("sent %d requests for invalid orders", n)
+	}
+}
+
+func TestJsApiPayParamsKnownVector(t *testing.T) {
+	client := testMerchantClient(t, DefaultBaseUrl)
+
+	params, err := client.jsApiPayParams("wx201410272009395522657a690389285100", time.Unix(1414561699, 0), "5K8264ILTKCH16CQ2502SI8ZNMTM67VS")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if params.AppId != testAppId || params.TimeStamp != "1414561699" || params.NonceStr != "5K8264ILTKCH16CQ2502SI8ZNMTM67VS" ||
+		params.Package != "prepay_id=wx201410272009395522657a690389285100" || params.SignType != "RSA" {
+		t.Fatalf("unexpected params: %+v", params)
+	}
+	// 由openssl dgst -sha256 -sign对"appId\ntimeStamp\nnonceStr\npackage\n"签名得到
+	const wantPaySign = "E1PjBU3Mp9TKrF1awDq44zeZFfV/2l91wnKH9USBA9hFmHwK+CXe3wgQxWTpuwKrYpN4LQVvAaYZzQbJQNaAto9DIiBxkKoFe0jOZIdvQG+5m7hCbPerPF3FxUFEMRrgYC3Kx9Myh9JNCa0lb32HsRiBMKOkoKAq9vfjMPu7VKZrC1G0pSAjTwZYEJjBCJqF+5t5Q43bS6TOamB5ohXa/vwiZu86pZR+y3L84o+CIZl/JFMms1mgKq8Bf8KbUhYF7Ir2dZ6jj2hq+GbVV/2ltM183ktE6Pf/32r6zfDtBnDusebRLJNh9pfpNT6F5L/AVkbKu0vWTASpROhFOxxL9Q=="
+	if params.PaySign != wantPaySign {
+		t.Fatalf("paySign = %s\nwant %s", params.PaySign, wantPaySign)
+	}
+}
+
+func TestBuildJsApiPayParams(t *testing.T) {
+	client := testMerchantClient(t, DefaultBaseUrl)
+
+	if _, err := client.BuildJsApiPayParams(""); err == nil {
+		t.Fatal("expected error for empty prepay_id")
+	}
+	params, err := client.BuildJsApiPayParams("wx201410272009395522657a690389285100")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if params.NonceStr == "" || params.TimeStamp == "" || params.PaySign == "" {
+		t.Fatalf("unexpected params: %+v", params)
+	}
+}
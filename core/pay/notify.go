@@ -0,0 +1,217 @@
+package pay
+
+import (
+	"crypto"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"github.com/mrwangjinjin/go-wechat/core"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	// notifyMaxSkew 通知时间戳与当前时间允许的最大偏差, 超出时视为重放
+	notifyMaxSkew = 5 * time.Minute
+	// notifyDedupeExpires 通知id去重记录的保留秒数, 微信支付在24小时内重试未应答成功的通知
+	notifyDedupeExpires = 24 * 60 * 60
+)
+
+// NotifyCacheKeyPrefix 已处理的回调通知id的缓存前缀
+const NotifyCacheKeyPrefix = "CACHE_PAY_NOTIFY@@"
+
+var (
+	// ErrNotifyExpired 通知时间戳与当前时间相差超过5分钟
+	ErrNotifyExpired = errors.New("通知时间戳已过期, 可能是重放请求")
+	// ErrNotifyDuplicated 通知已处理过, 应直接应答成功
+	ErrNotifyDuplicated = errors.New("通知已处理")
+)
+
+// CertProvider 按证书序列号提供微信支付平台证书
+type CertProvider interface {
+	Certificate(serialNo string) (*x509.Certificate, error)
+}
+
+// ErrCertificateNotFound 证书序列号对应的平台证书不存在
+var ErrCertificateNotFound = errors.New("平台证书不存在")
+
+// StaticCertProvider 固定的平台证书集合, key为证书序列号
+type StaticCertProvider map[string]*x509.Certificate
+
+func (self StaticCertProvider) Certificate(serialNo string) (*x509.Certificate, error) {
+	cert, ok := self[serialNo]
+	if !ok {
+		return nil, fmt.Errorf("%w:%s", ErrCertificateNotFound, serialNo)
+	}
+	return cert, nil
+}
+
+// LoadCertificate 解析PEM格式的平台证书
+func LoadCertificate(pemBytes []byte) (*x509.Certificate, error) {
+	block, _ := pem.Decode(pemBytes)
+	if block == nil {
+		return nil, errors.New("证书格式错误")
+	}
+	return x509.ParseCertificate(block.Bytes)
+}
+
+// NotifyResource 回调通知中的加密数据
+type NotifyResource struct {
+	Algorithm      string `json:"algorithm"`
+	Ciphertext     string `json:"ciphertext"`
+	AssociatedData string `json:"associated_data"`
+	OriginalType   string `json:"original_type"`
+	Nonce          string `json:"nonce"`
+}
+
+// Notify 回调通知
+type Notify struct {
+	Id           string         `json:"id"`
+	CreateTime   string         `json:"create_time"`
+	EventType    string         `json:"event_type"`
+	ResourceType string         `json:"resource_type"`
+	Resource     NotifyResource `json:"resource"`
+	Summary      string         `json:"summary"`
+}
+
+// TransactionAmount 订单金额
+type TransactionAmount struct {
+	Total         int64  `json:"total"`
+	PayerTotal    int64  `json:"payer_total"`
+	Currency      string `json:"currency"`
+	PayerCurrency string `json:"payer_currency"`
+}
+
+// Transaction 支付成功通知中的订单信息
+type Transaction struct {
+	AppId          string            `json:"appid"`
+	MchId          string            `json:"mchid"`
+	OutTradeNo     string            `json:"out_trade_no"`
+	TransactionId  string            `json:"transaction_id"`
+	TradeType      string            `json:"trade_type"`
+	TradeState     string            `json:"trade_state"`
+	TradeStateDesc string            `json:"trade_state_desc"`
+	BankType       string            `json:"bank_type"`
+	Attach         string            `json:"attach"`
+	SuccessTime    string            `json:"success_time"`
+	Payer          Payer             `json:"payer"`
+	Amount         TransactionAmount `json:"amount"`
+}
+
+// VerifyNotify 使用平台证书验证回调通知的Wechatpay-Signature, Wechatpay-Timestamp与当前时间相差超过5分钟时返回ErrNotifyExpired
+func VerifyNotify(headers http.Header, body []byte, certProvider CertProvider) error {
+	timestamp := headers.Get("Wechatpay-Timestamp")
+	nonce := headers.Get("Wechatpay-Nonce")
+	signature := headers.Get("Wechatpay-Signature")
+	serialNo := headers.Get("Wechatpay-Serial")
+	if timestamp == "" || nonce == "" || signature == "" || serialNo == "" {
+		return errors.New("缺少签名请求头")
+	}
+	// 先检查时间戳再查找证书, 过期的通知不会触发平台证书下载
+	unix, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return errors.New("时间戳格式错误")
+	}
+	if skew := time.Since(time.Unix(unix, 0)); skew > notifyMaxSkew || skew < -notifyMaxSkew {
+		return ErrNotifyExpired
+	}
+	cert, err := certProvider.Certificate(serialNo)
+	if err != nil {
+		return err
+	}
+	publicKey, ok := cert.PublicKey.(*rsa.PublicKey)
+	if !ok {
+		return errors.New("平台证书不是RSA公钥")
+	}
+	sign, err := base64.StdEncoding.DecodeString(signature)
+	if err != nil {
+		return errors.New("签名格式错误")
+	}
+	hashed := sha256.Sum256([]byte(fmt.Sprintf("%s\n%s\n%s\n", timestamp, nonce, body)))
+	if err := rsa.VerifyPKCS1v15(publicKey, crypto.SHA256, hashed[:], sign); err != nil {
+		return errors.New("签名验证失败")
+	}
+	return nil
+}
+
+// DecryptResource 使用APIv3密钥解密回调通知中的AEAD_AES_256_GCM数据
+func DecryptResource(resource NotifyResource, apiV3Key string) ([]byte, error) {
+	if resource.Algorithm != "AEAD_AES_256_GCM" {
+		return nil, fmt.Errorf("不支持的加密算法:%s", resource.Algorithm)
+	}
+	if len(apiV3Key) != 32 {
+		return nil, errors.New("APIv3密钥长度必须为32字节")
+	}
+	ciphertext, err := base64.StdEncoding.DecodeString(resource.Ciphertext)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher([]byte(apiV3Key))
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCMWithNonceSize(block, len(resource.Nonce))
+	if err != nil {
+		return nil, err
+	}
+	return gcm.Open(nil, []byte(resource.Nonce), ciphertext, []byte(resource.AssociatedData))
+}
+
+// DecryptNotify 验证签名并解密支付成功通知, 不对通知去重, 需要去重时使用HandleNotify
+func DecryptNotify(headers http.Header, body []byte, apiV3Key string, certProvider CertProvider) (*Transaction, error) {
+	_, transaction, err := decryptNotify(headers, body, apiV3Key, certProvider)
+	return transaction, err
+}
+
+// HandleNotify 验证签名并解密支付成功通知, 按通知id去重后调用handle
+// 同一通知24小时内只处理一次, 重复的通知返回ErrNotifyDuplicated, 此时应直接应答成功;
+// handle返回错误时清除去重记录, 微信支付重试时会再次处理
+func HandleNotify(headers http.Header, body []byte, apiV3Key string, certProvider CertProvider, cache core.Cache, handle func(*Transaction) error) error {
+	notify, transaction, err := decryptNotify(headers, body, apiV3Key, certProvider)
+	if err != nil {
+		return err
+	}
+	if notify.Id == "" {
+		return errors.New("通知id不能为空")
+	}
+	key := NotifyCacheKeyPrefix + notify.Id
+	ok, err := cache.SetNX(key, notify.CreateTime, notifyDedupeExpires)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrNotifyDuplicated
+	}
+	if err := handle(transaction); err != nil {
+		_ = cache.Delete(key)
+		return err
+	}
+	return nil
+}
+
+func decryptNotify(headers http.Header, body []byte, apiV3Key string, certProvider CertProvider) (*Notify, *Transaction, error) {
+	if err := VerifyNotify(headers, body, certProvider); err != nil {
+		return nil, nil, err
+	}
+	var notify Notify
+	if err := json.Unmarshal(body, &notify); err != nil {
+		return nil, nil, err
+	}
+	plaintext, err := DecryptResource(notify.Resource, apiV3Key)
+	if err != nil {
+		return nil, nil, err
+	}
+	var transaction Transaction
+	if err := json.Unmarshal(plaintext, &transaction); err != nil {
+		return nil, nil, err
+	}
+	return &notify, &transaction, nil
+}
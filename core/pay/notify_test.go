@@ -0,0 +1,139 @@
+package pay
+
+import (
+	"crypto/x509"
+	"errors"
+	"github.com/mrwangjinjin/go-wechat/core/cache/lru"
+	"testing"
+	"time"
+)
+
+// testResource 固定的AEAD_AES_256_GCM密文, 由testApiV3Key、nonce和associated_data加密testTransactionJSON得到
+var testResource = NotifyResource{
+	Algorithm:      "AEAD_AES_256_GCM",
+	Ciphertext:     "DUcSk7X3A8MEOkED25KWgBV23wSzmYbEHgkJIy11/uroOkdL04J1O8WXf+3YzuhfD9np5Vo81nyZhuSgGpzZAtNmDsZkhjh2I0ohEhNXUt0d/lWO9RkHjXzvbd3QR8KdE1/JnXfPm35OCz62T3J9rT2GaTJewLpSyxfvcX9GsMYOItTC0zINTBDc/n+u5Kn5NsnYLYhZeKN3C2WxEvX4uPuGqm7VR3LFb+U/gbNHTMENaDxzzAcS3MkbCYEdSB2x9CvxwrCapdOxfU5iZrTJUC46lGpKN4cpivV6vVUtFyBdC62PI5rVAozNwJzCi1R0U1kSwQlquGFtunljPh5j1EhtP+4zIfRb",
+	AssociatedData: "transaction",
+	OriginalType:   "transaction",
+	Nonce:          "fdasflkja484",
+}
+
+const testTransactionJSON = `{"appid":"wx_app","mchid":"1900000001","out_trade_no":"ORDER_1","transaction_id":"4200000001","trade_type":"JSAPI","trade_state":"SUCCESS","payer":{"openid":"OPENID"},"amount":{"total":100,"payer_total":100,"currency":"CNY","payer_currency":"CNY"}}`
+
+const testNotifyBody = `{"id":"EV-2018022511223320873","create_time":"2015-05-20T13:29:35+08:00","resource_type":"encrypt-resource","event_type":"TRANSACTION.SUCCESS","summary":"支付成功","resource":{"algorithm":"AEAD_AES_256_GCM","ciphertext":"DUcSk7X3A8MEOkED25KWgBV23wSzmYbEHgkJIy11/uroOkdL04J1O8WXf+3YzuhfD9np5Vo81nyZhuSgGpzZAtNmDsZkhjh2I0ohEhNXUt0d/lWO9RkHjXzvbd3QR8KdE1/JnXfPm35OCz62T3J9rT2GaTJewLpSyxfvcX9GsMYOItTC0zINTBDc/n+u5Kn5NsnYLYhZeKN3C2WxEvX4uPuGqm7VR3LFb+U/gbNHTMENaDxzzAcS3MkbCYEdSB2x9CvxwrCapdOxfU5iZrTJUC46lGpKN4cpivV6vVUtFyBdC62PI5rVAozNwJzCi1R0U1kSwQlquGFtunljPh5j1EhtP+4zIfRb","associated_data":"transaction","original_type":"transaction","nonce":"fdasflkja484"}}`
+
+func TestDecryptResource(t *testing.T) {
+	plaintext, err := DecryptResource(testResource, testApiV3Key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(plaintext) != testTransactionJSON {
+		t.Fatalf("got %s", plaintext)
+	}
+
+	tampered := testResource
+	tampered.AssociatedData = "refund"
+	if _, err := DecryptResource(tampered, testApiV3Key); err == nil {
+		t.Fatal("expected error for mismatched associated_data")
+	}
+	if _, err := DecryptResource(testResource, "short"); err == nil {
+		t.Fatal("expected error for invalid APIv3 key")
+	}
+	unsupported := testResource
+	unsupported.Algorithm = "AEAD_SM4_GCM"
+	if _, err := DecryptResource(unsupported, testApiV3Key); err == nil {
+		t.Fatal("expected error for unsupported algorithm")
+	}
+}
+
+func TestDecryptNotify(t *testing.T) {
+	key, cert, _ := testPlatformCert(t)
+	certs := StaticCertProvider{testCertSerial: cert}
+	body := []byte(testNotifyBody)
+
+	transaction, err := DecryptNotify(signedHeaders(t, key, testCertSerial, time.Now(), body), body, testApiV3Key, certs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if transaction.OutTradeNo != "ORDER_1" || transaction.TradeState != "SUCCESS" || transaction.Amount.Total != 100 || transaction.Payer.OpenId != "OPENID" {
+		t.Fatalf("unexpected transaction: %+v", transaction)
+	}
+}
+
+func TestVerifyNotifyRejectsBadSignature(t *testing.T) {
+	key, cert, _ := testPlatformCert(t)
+	certs := StaticCertProvider{testCertSerial: cert}
+	body := []byte(testNotifyBody)
+	headers := signedHeaders(t, key, testCertSerial, time.Now(), body)
+
+	if err := VerifyNotify(headers, []byte(`{"id":"forged"}`), certs); err == nil {
+		t.Fatal("expected error for tampered body")
+	}
+	if err := VerifyNotify(headers, body, StaticCertProvider{}); err == nil {
+		t.Fatal("expected error for unknown serial")
+	}
+	headers.Del("Wechatpay-Signature")
+	if err := VerifyNotify(headers, body, certs); err == nil {
+		t.Fatal("expected error for missing signature header")
+	}
+}
+
+// countingCertProvider 记录证书查询次数
+type countingCertProvider struct {
+	StaticCertProvider
+	count int
+}
+
+func (self *countingCertProvider) Certificate(serialNo string) (*x509.Certificate, error) {
+	self.count++
+	return self.StaticCertProvider.Certificate(serialNo)
+}
+
+func TestVerifyNotifyRejectsReplay(t *testing.T) {
+	key, cert, _ := testPlatformCert(t)
+	certs := &countingCertProvider{StaticCertProvider: StaticCertProvider{testCertSerial: cert}}
+	body := []byte(testNotifyBody)
+
+	for _, timestamp := range []time.Time{time.Now().Add(-6 * time.Minute), time.Now().Add(6 * time.Minute)} {
+		headers := signedHeaders(t, key, testCertSerial, timestamp, body)
+		if err := VerifyNotify(headers, body, certs); !errors.Is(err, ErrNotifyExpired) {
+			t.Fatalf("timestamp %v: got %v, want ErrNotifyExpired", timestamp, err)
+		}
+	}
+	if certs.count != 0 {
+		t.Fatalf("expired notify looked up %d certificates", certs.count)
+	}
+	headers := signedHeaders(t, key, testCertSerial, time.Now().Add(-4*time.Minute), body)
+	if err := VerifyNotify(headers, body, certs); err != nil {
+		t.Fatalf("notify within 5 minutes: %v", err)
+	}
+}
+
+func TestHandleNotifyDeduplicates(t *testing.T) {
+	key, cert, _ := testPlatformCert(t)
+	certs := StaticCertProvider{testCertSerial: cert}
+	cache := lru.NewLRUCache(0)
+	body := []byte(testNotifyBody)
+	handled := 0
+	handle := func(transaction *Transaction) error {
+		handled++
+		if handled == 1 {
+			return errors.New("database unavailable")
+		}
+		return nil
+	}
+
+	// 第一次处理失败, 去重记录被清除, 重试时再次处理
+	if err := HandleNotify(signedHeaders(t, key, testCertSerial, time.Now(), body), body, testApiV3Key, certs, cache, handle); err == nil {
+		t.Fatal("expected handler error")
+	}
+	if err := HandleNotify(signedHeaders(t, key, testCertSerial, time.Now(), body), body, testApiV3Key, certs, cache, handle); err != nil {
+		t.Fatal(err)
+	}
+	err := HandleNotify(signedHeaders(t, key, testCertSerial, time.Now(), body), body, testApiV3Key, certs, cache, handle)
+	if !errors.Is(err, ErrNotifyDuplicated) {
+		t.Fatalf("got %v, want ErrNotifyDuplicated", err)
+	}
+	if handled != 2 {
+		t.Fatalf("handler called %d times, want 2", handled)
+	}
+}
@@ -0,0 +1,198 @@
+package pay
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"github.com/mrwangjinjin/go-wechat/core"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+const DefaultBaseUrl = "https://api.mch.weixin.qq.com"
+
+// Config 商户配置
+type Config struct {
+	MchId string
+	AppId string
+	// SerialNo 商户API证书序列号
+	SerialNo string
+	// PrivateKey 商户API证书私钥
+	PrivateKey *rsa.PrivateKey
+	// ApiV3Key 商户APIv3密钥, 用于解密回调通知
+	ApiV3Key  string
+	NotifyUrl string
+	BaseUrl   string
+	// HttpClient 发起请求使用的http.Client, 为nil时使用30秒超时的默认客户端
+	HttpClient *http.Client
+}
+
+// Error 微信支付V3接口错误
+type Error struct {
+	StatusCode int    `json:"-"`
+	Code       string `json:"code"`
+	Message    string `json:"message"`
+}
+
+func (self *Error) Error() string {
+	return fmt.Sprintf("支付失败:%s(%s)", self.Message, self.Code)
+}
+
+type Client struct {
+	config *Config
+	http   *http.Client
+}
+
+func NewClient(config *Config) (*Client, error) {
+	if config.MchId == "" || config.SerialNo == "" || config.PrivateKey == nil {
+		return nil, errors.New("商户号、证书序列号和私钥不能为空")
+	}
+	if config.BaseUrl == "" {
+		config.BaseUrl = DefaultBaseUrl
+	}
+	httpClient := config.HttpClient
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: 30 * time.Second}
+	}
+	return &Client{
+		config: config,
+		http:   httpClient,
+	}, nil
+}
+
+// LoadPrivateKey 解析PEM格式的商户私钥(apiclient_key.pem)
+func LoadPrivateKey(pemBytes []byte) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode(pemBytes)
+	if block == nil {
+		return nil, errors.New("私钥格式错误")
+	}
+	if key, err := x509.ParsePKCS8PrivateKey(block.Bytes); err == nil {
+		rsaKey, ok := key.(*rsa.PrivateKey)
+		if !ok {
+			return nil, errors.New("私钥不是RSA私钥")
+		}
+		return rsaKey, nil
+	}
+	return x509.ParsePKCS1PrivateKey(block.Bytes)
+}
+
+// LoadPrivateKeyFromFile 从文件读取商户私钥
+func LoadPrivateKeyFromFile(path string) (*rsa.PrivateKey, error) {
+	pemBytes, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return LoadPrivateKey(pemBytes)
+}
+
+// sign 使用商户私钥对message做SHA256-RSA签名
+func (self *Client) sign(message string) (string, error) {
+	hashed := sha256.Sum256([]byte(message))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, self.config.PrivateKey, crypto.SHA256, hashed[:])
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(signature), nil
+}
+
+// authorization 生成V3接口的Authorization请求头
+func (self *Client) authorization(method, rawUrl string, body []byte) (string, error) {
+	u, err := url.Parse(rawUrl)
+	if err != nil {
+		return "", err
+	}
+	nonce, err := nonceStr()
+	if err != nil {
+		return "", err
+	}
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	signature, err := self.sign(fmt.Sprintf("%s\n%s\n%s\n%s\n%s\n", method, u.RequestURI(), timestamp, nonce, body))
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf(`WECHATPAY2-SHA256-RSA2048 mchid="%s",nonce_str="%s",signature="%s",timestamp="%s",serial_no="%s"`,
+		self.config.MchId, nonce, signature, timestamp, self.config.SerialNo), nil
+}
+
+// postJSON 签名并提交V3请求, 非2xx响应解析为*Error
+func (self *Client) postJSON(ctx context.Context, path string, data interface{}, result interface{}) error {
+	body, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	return self.do(ctx, http.MethodPost, path, body, result)
+}
+
+// getJSON 签名并发起V3 GET请求, 非2xx响应解析为*Error
+func (self *Client) getJSON(ctx context.Context, path string, result interface{}) error {
+	return self.do(ctx, http.MethodGet, path, nil, result)
+}
+
+func (self *Client) do(ctx context.Context, method, path string, body []byte, result interface{}) error {
+	_, respBody, err := self.send(ctx, method, path, body)
+	if err != nil {
+		return err
+	}
+	if result == nil {
+		return nil
+	}
+	return json.Unmarshal(respBody, result)
+}
+
+// send 签名并发送V3请求, 返回响应头和响应体, 非2xx响应解析为*Error
+func (self *Client) send(ctx context.Context, method, path string, body []byte) (http.Header, []byte, error) {
+	rawUrl := self.config.BaseUrl + path
+	authorization, err := self.authorization(method, rawUrl, body)
+	if err != nil {
+		return nil, nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, method, rawUrl, bytes.NewReader(body))
+	if err != nil {
+		return nil, nil, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", core.ContentTypeJSON)
+	}
+	req.Header.Set("Accept", core.ContentTypeJSON)
+	req.Header.Set("Authorization", authorization)
+	req.Header.Set("User-Agent", core.DefaultUserAgent)
+	resp, err := self.http.Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		payErr := &Error{StatusCode: resp.StatusCode}
+		if err := json.Unmarshal(respBody, payErr); err != nil || payErr.Code == "" {
+			payErr.Code = strconv.Itoa(resp.StatusCode)
+			payErr.Message = "网络错误"
+		}
+		return nil, nil, payErr
+	}
+	return resp.Header, respBody, nil
+}
+
+func nonceStr() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
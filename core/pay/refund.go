@@ -0,0 +1,102 @@
+package pay
+
+import (
+	"context"
+	"errors"
+	"net/url"
+)
+
+// 退款状态
+const (
+	RefundStatusSuccess    = "SUCCESS"
+	RefundStatusClosed     = "CLOSED"
+	RefundStatusProcessing = "PROCESSING"
+	RefundStatusAbnormal   = "ABNORMAL"
+)
+
+// RefundAmount 退款金额, 单位为分, Refund小于Total时为部分退款
+type RefundAmount struct {
+	Refund   int64  `json:"refund"`
+	Total    int64  `json:"total"`
+	Currency string `json:"currency"`
+}
+
+// RefundRequest 申请退款请求, TransactionId与OutTradeNo二选一
+type RefundRequest struct {
+	TransactionId string       `json:"transaction_id,omitempty"`
+	OutTradeNo    string       `json:"out_trade_no,omitempty"`
+	OutRefundNo   string       `json:"out_refund_no"`
+	Reason        string       `json:"reason,omitempty"`
+	NotifyUrl     string       `json:"notify_url,omitempty"`
+	Amount        RefundAmount `json:"amount"`
+}
+
+// RefundResponseAmount 退款结果金额
+type RefundResponseAmount struct {
+	Total            int64  `json:"total"`
+	Refund           int64  `json:"refund"`
+	PayerTotal       int64  `json:"payer_total"`
+	PayerRefund      int64  `json:"payer_refund"`
+	SettlementRefund int64  `json:"settlement_refund"`
+	SettlementTotal  int64  `json:"settlement_total"`
+	DiscountRefund   int64  `json:"discount_refund"`
+	Currency         string `json:"currency"`
+}
+
+// RefundResponse 退款结果, 退款为异步处理, Status为PROCESSING时需稍后查询或等待回调
+type RefundResponse struct {
+	RefundId            string               `json:"refund_id"`
+	OutRefundNo         string               `json:"out_refund_no"`
+	TransactionId       string               `json:"transaction_id"`
+	OutTradeNo          string               `json:"out_trade_no"`
+	Channel             string               `json:"channel"`
+	UserReceivedAccount string               `json:"user_received_account"`
+	SuccessTime         string               `json:"success_time"`
+	CreateTime          string               `json:"create_time"`
+	Status              string               `json:"status"`
+	FundsAccount        string               `json:"funds_account"`
+	Amount              RefundResponseAmount `json:"amount"`
+}
+
+// IsSuccess 退款是否已到账
+func (self *RefundResponse) IsSuccess() bool {
+	return self.Status == RefundStatusSuccess
+}
+
+// IsProcessing 退款是否处理中
+func (self *RefundResponse) IsProcessing() bool {
+	return self.Status == RefundStatusProcessing
+}
+
+// Refund 申请退款
+func (self *Client) Refund(ctx context.Context, req RefundRequest) (*RefundResponse, error) {
+	if (req.TransactionId == "") == (req.OutTradeNo == "") {
+		return nil, errors.New("transaction_id和out_trade_no必须且只能填写一个")
+	}
+	if req.OutRefundNo == "" {
+		return nil, errors.New("商户退款单号不能为空")
+	}
+	if req.Amount.Refund <= 0 || req.Amount.Refund > req.Amount.Total {
+		return nil, errors.New("退款金额必须大于0且不能超过订单金额")
+	}
+	if req.Amount.Currency == "" {
+		req.Amount.Currency = "CNY"
+	}
+	var resp RefundResponse
+	if err := self.postJSON(ctx, "/v3/refund/domestic/refunds", req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// QueryRefund 查询单笔退款
+func (self *Client) QueryRefund(ctx context.Context, outRefundNo string) (*RefundResponse, error) {
+	if outRefundNo == "" {
+		return nil, errors.New("商户退款单号不能为空")
+	}
+	var resp RefundResponse
+	if err := self.getJSON(ctx, "/v3/refund/domestic/refunds/"+url.PathEscape(outRefundNo), &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
@@ -0,0 +1,119 @@
+package pay
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+)
+
+const testRefundJSON = `{"refund_id":"50000000382019052709732678859","out_refund_no":"REFUND/1","transaction_id":"4200000001","out_trade_no":"ORDER_1","channel":"ORIGINAL","user_received_account":"招商银行信用卡0403","create_time":"2020-12-01T16:18:12+08:00","status":"PROCESSING","funds_account":"AVAILABLE","amount":{"total":100,"refund":30,"payer_total":100,"payer_refund":30,"settlement_refund":30,"settlement_total":100,"discount_refund":0,"currency":"CNY"}}`
+
+func TestRefund(t *testing.T) {
+	server := newPayServer(t, http.StatusOK, testRefundJSON)
+	client := testMerchantClient(t, server.URL)
+
+	resp, err := client.Refund(context.Background(), RefundRequest{
+		OutTradeNo:  "ORDER_1",
+		OutRefundNo: "REFUND/1",
+		Reason:      "商品已售完",
+		Amount:      RefundAmount{Refund: 30, Total: 100},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.RefundId != "50000000382019052709732678859" || !resp.IsProcessing() || resp.IsSuccess() || resp.Amount.Refund != 30 || resp.Amount.PayerRefund != 30 {
+		t.Fatalf("unexpected response: %+v", resp)
+	}
+
+	req := server.lastRequest(t)
+	if req.Method != http.MethodPost || req.RequestURI != "/v3/refund/domestic/refunds" {
+		t.Fatalf("request = %s %s", req.Method, req.RequestURI)
+	}
+	want := `{"out_trade_no":"ORDER_1","out_refund_no":"REFUND/1","reason":"商品已售完","amount":{"refund":30,"total":100,"currency":"CNY"}}`
+	if string(req.Body) != want {
+		t.Fatalf("body = %s\nwant %s", req.Body, want)
+	}
+	verifyAuthorization(t, client, req)
+}
+
+func TestRefundValidation(t *testing.T) {
+	server := newPayServer(t, http.StatusOK, testRefundJSON)
+	client := testMerchantClient(t, server.URL)
+
+	cases := map[string]RefundRequest{
+		"no order":         {OutRefundNo: "R1", Amount: RefundAmount{Refund: 1, Total: 1}},
+		"both order ids":   {TransactionId: "T1", OutTradeNo: "O1", OutRefundNo: "R1", Amount: RefundAmount{Refund: 1, Total: 1}},
+		"no out_refund_no": {OutTradeNo: "O1", Amount: RefundAmount{Refund: 1, Total: 1}},
+		"zero refund":      {OutTradeNo: "O1", OutRefundNo: "R1", Amount: RefundAmount{Refund: 0, Total: 1}},
+		"refund > total":   {OutTradeNo: "O1", OutRefundNo: "R1", Amount: RefundAmount{Refund: 2, Total: 1}},
+	}
+	for name, req := range cases {
+		if _, err := client.Refund(context.Background(), req); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+	if n := server.requestCount(); n != 0 {
+		t.Fatalf("sent %d requests for invalid refunds", n)
+	}
+}
+
+func TestQueryRefundEscapesOutRefundNo(t *testing.T) {
+	server := newPayServer(t, http.StatusOK, testRefundJSON)
+	client := testMerchantClient(t, server.URL)
+
+	resp, err := client.QueryRefund(context.Background(), "REFUND/1 ?")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.OutRefundNo != "REFUND/1" {
+		t.Fatalf("unexpected response: %+v", resp)
+	}
+
+	req := server.lastRequest(t)
+	if req.Method != http.MethodGet || req.RequestURI != "/v3/refund/domestic/refunds/REFUND%2F1%20%3F" {
+		t.Fatalf("request = %s %s", req.Method, req.RequestURI)
+	}
+	if len(req.Body) != 0 || req.Header.Get("Content-Type") != "" {
+		t.Fatalf("GET sent body %q with Content-Type %q", req.Body, req.Header.Get("Content-Type"))
+	}
+	verifyAuthorization(t, client, req)
+
+	if _, err := client.QueryRefund(context.Background(), ""); err == nil {
+		t.Fatal("expected error for empty out_refund_no")
+	}
+}
+
+func TestRefundErrorResponse(t *testing.T) {
+	cases := []struct {
+		name   string
+		status int
+		body   string
+		want   Error
+	}{
+		{"api error", http.StatusForbidden, `{"code":"NOT_ENOUGH","message":"基本账户余额不足"}`, Error{StatusCode: http.StatusForbidden, Code: "NOT_ENOUGH", Message: "基本账户余额不足"}},
+		{"not found", http.StatusNotFound, `{"code":"RESOURCE_NOT_EXISTS","message":"退款单不存在"}`, Error{StatusCode: http.StatusNotFound, Code: "RESOURCE_NOT_EXISTS", Message: "退款单不存在"}},
+		{"unparsable body", http.StatusBadGateway, `<html>bad gateway</html>`, Error{StatusCode: http.StatusBadGateway, Code: "502", Message: "网络错误"}},
+	}
+	for _, c := range cases {
+		server := newPayServer(t, c.status, c.body)
+		client := testMerchantClient(t, server.URL)
+
+		_, err := client.Refund(context.Background(), RefundRequest{
+			TransactionId: "4200000001",
+			OutRefundNo:   "R1",
+			Amount:        RefundAmount{Refund: 1, Total: 1},
+		})
+		var payErr *Error
+		if !errors.As(err, &payErr) {
+			t.Fatalf("%s: err = %v, want *Error", c.name, err)
+		}
+		if *payErr != c.want {
+			t.Fatalf("%s: err = %+v, want %+v", c.name, *payErr, c.want)
+		}
+
+		if _, err := client.QueryRefund(context.Background(), "R1"); !errors.As(err, &payErr) || *payErr != c.want {
+			t.Fatalf("%s: QueryRefund err = %v", c.name, err)
+		}
+	}
+}
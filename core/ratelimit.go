@@ -0,0 +1,99 @@
+package core
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// RateLimiter 请求限流, 在每次请求前调用, 阻塞至允许发送或ctx取消
+type RateLimiter interface {
+	Wait(ctx context.Context, endpoint string) error
+}
+
+// tokenBucket 令牌桶
+type tokenBucket struct {
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+// reserve 取出一个令牌, 返回需要等待的时长
+func (self *tokenBucket) reserve(now time.Time) time.Duration {
+	self.tokens += now.Sub(self.last).Seconds() * self.rate
+	if self.tokens > self.burst {
+		self.tokens = self.burst
+	}
+	self.last = now
+	self.tokens--
+	if self.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-self.tokens / self.rate * float64(time.Second))
+}
+
+// MemoryRateLimiter 基于内存令牌桶的限流器
+// 未单独设置的接口共用默认令牌桶, endpoint为请求路径, 如/wxa/getwxacodeunlimit
+type MemoryRateLimiter struct {
+	mu      sync.Mutex
+	def     *tokenBucket
+	buckets map[string]*tokenBucket
+}
+
+// NewMemoryRateLimiter 创建限流器, rate为每秒请求数, burst为允许的突发请求数
+func NewMemoryRateLimiter(rate float64, burst int) *MemoryRateLimiter {
+	return &MemoryRateLimiter{
+		def:     newTokenBucket(rate, burst),
+		buckets: map[string]*tokenBucket{},
+	}
+}
+
+func newTokenBucket(rate float64, burst int) *tokenBucket {
+	if burst < 1 {
+		burst = 1
+	}
+	return &tokenBucket{
+		rate:   rate,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+// SetLimit 为指定接口单独设置限流
+func (self *MemoryRateLimiter) SetLimit(endpoint string, rate float64, burst int) {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+	self.buckets[endpoint] = newTokenBucket(rate, burst)
+}
+
+func (self *MemoryRateLimiter) Wait(ctx context.Context, endpoint string) error {
+	self.mu.Lock()
+	bucket, ok := self.buckets[endpoint]
+	if !ok {
+		bucket = self.def
+	}
+	if bucket.rate <= 0 {
+		self.mu.Unlock()
+		return nil
+	}
+	delay := bucket.reserve(time.Now())
+	self.mu.Unlock()
+	if delay <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		// 归还未使用的令牌
+		self.mu.Lock()
+		bucket.tokens++
+		self.mu.Unlock()
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,106 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestTokenBucketReserve(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	bucket := &tokenBucket{rate: 10, burst: 2, tokens: 2, last: now}
+
+	for i, want := range []time.Duration{0, 0, 100 * time.Millisecond, 200 * time.Millisecond} {
+		if got := bucket.reserve(now); got != want {
+			t.Fatalf("reserve %d: got %v, want %v", i, got, want)
+		}
+	}
+	// 1秒后补充的令牌不超过burst
+	if got := bucket.reserve(now.Add(time.Second)); got != 0 {
+		t.Fatalf("after refill: got %v, want 0", got)
+	}
+	if bucket.tokens > bucket.burst {
+		t.Fatalf("tokens %v exceed burst %v", bucket.tokens, bucket.burst)
+	}
+}
+
+func TestMemoryRateLimiterSpacesRequests(t *testing.T) {
+	limiter := NewMemoryRateLimiter(50, 1)
+
+	start := time.Now()
+	for i := 0; i < 4; i++ {
+		if err := limiter.Wait(context.Background(), "/cgi-bin/message/custom/send"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// 第一次使用突发令牌, 其后每次间隔20ms
+	if elapsed := time.Since(start); elapsed < 55*time.Millisecond {
+		t.Fatalf("4 requests at 50/s took %v, want about 60ms", elapsed)
+	}
+}
+
+func TestMemoryRateLimiterPerEndpoint(t *testing.T) {
+	limiter := NewMemoryRateLimiter(0, 0)
+	limiter.SetLimit("/wxa/getwxacodeunlimit", 1, 1)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	for i := 0; i < 10; i++ {
+		if err := limiter.Wait(ctx, "/wxa/getwxacode"); err != nil {
+			t.Fatalf("unlimited endpoint: %v", err)
+		}
+	}
+	if err := limiter.Wait(ctx, "/wxa/getwxacodeunlimit"); err != nil {
+		t.Fatal(err)
+	}
+	if err := limiter.Wait(ctx, "/wxa/getwxacodeunlimit"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("got %v, want context.DeadlineExceeded", err)
+	}
+}
+
+func TestMemoryRateLimiterHonoursCancel(t *testing.T) {
+	limiter := NewMemoryRateLimiter(1, 1)
+	if err := limiter.Wait(context.Background(), "/"); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		cancel()
+	}()
+	start := time.Now()
+	if err := limiter.Wait(ctx, "/"); !errors.Is(err, context.Canceled) {
+		t.Fatalf("got %v, want context.Canceled", err)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Fatalf("cancelled wait took %v", elapsed)
+	}
+	// 取消的请求归还令牌, 不会让后续请求多等一个间隔
+	limiter.mu.Lock()
+	tokens := limiter.def.tokens
+	limiter.mu.Unlock()
+	if tokens < -0.1 {
+		t.Fatalf("tokens after cancel = %v, want the reservation returned", tokens)
+	}
+}
+
+func TestHttpClientUsesRateLimiter(t *testing.T) {
+	server := newRecordingServer(t)
+	client := NewHttpClient()
+	client.SetRateLimiter(NewMemoryRateLimiter(1, 1))
+
+	if _, _, err := client.Get(server.URL + "/cgi-bin/token"); err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, _, err := client.GetContext(ctx, server.URL+"/cgi-bin/token"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("got %v, want context.DeadlineExceeded", err)
+	}
+	if n := atomic.LoadInt32(&server.requests); n != 1 {
+		t.Fatalf("server got %d requests, want 1", n)
+	}
+}
@@ -0,0 +1,32 @@
+package core
+
+// SecuritySuggest 内容安全检测建议
+type SecuritySuggest string
+
+const (
+	SuggestPass   SecuritySuggest = "pass"
+	SuggestReview SecuritySuggest = "review"
+	SuggestRisky  SecuritySuggest = "risky"
+)
+
+// IsRisky 是否命中风险
+func (self SecuritySuggest) IsRisky() bool {
+	return self == SuggestRisky
+}
+
+// SecurityResult 内容安全综合结果
+type SecurityResult struct {
+	Suggest SecuritySuggest `json:"suggest" xml:"suggest"`
+	Label   int             `json:"label" xml:"label"`
+}
+
+// SecurityDetail 内容安全详细检测结果
+type SecurityDetail struct {
+	Strategy string          `json:"strategy" xml:"strategy"`
+	ErrCode  int64           `json:"errcode" xml:"errcode"`
+	Suggest  SecuritySuggest `json:"suggest" xml:"suggest"`
+	Label    int             `json:"label" xml:"label"`
+	Prob     int             `json:"prob" xml:"prob"`
+	Keyword  string          `json:"keyword" xml:"keyword"`
+	Level    int             `json:"level" xml:"level"`
+}
@@ -2,9 +2,11 @@ package core
 
 import (
 	"encoding/xml"
+	"fmt"
 	"io/ioutil"
 	"log"
 	"net/http"
+	"time"
 )
 
 const (
@@ -33,15 +35,40 @@ type Server struct {
 	AppSecret string
 	Token     string
 	AesKey    string
+	// Logger 日志, 为nil时使用DefaultLogger
+	Logger Logger
+	// ErrorReporter 写入ticket失败等内部错误的上报函数, 可为nil
+	ErrorReporter ErrorReporter
 }
 
 func NewServer(clientConfig *ClientConfig, cache Cache) *Server {
 	return &Server{
-		Cache:     cache,
-		AppId:     clientConfig.AppId,
-		AppSecret: clientConfig.AppSecret,
-		Token:     clientConfig.Token,
-		AesKey:    clientConfig.AesKey,
+		Cache:         cache,
+		AppId:         clientConfig.AppId,
+		AppSecret:     clientConfig.AppSecret,
+		Token:         clientConfig.Token,
+		AesKey:        clientConfig.AesKey,
+		Logger:        clientConfig.Logger,
+		ErrorReporter: clientConfig.ErrorReporter,
+	}
+}
+
+// logger 返回配置的日志, 未配置(如直接构造Server)时使用DefaultLogger
+func (self *Server) logger() Logger {
+	if self.Logger != nil {
+		return self.Logger
+	}
+	return DefaultLogger
+}
+
+func (self *Server) reportCacheError(key string, err error) {
+	if err == nil {
+		return
+	}
+	err = fmt.Errorf("写入缓存%s失败: %w", key, err)
+	self.logger().Printf("%v", err)
+	if self.ErrorReporter != nil {
+		self.ErrorReporter(err)
 	}
 }
 
@@ -77,11 +104,12 @@ func (self *Server) Serve(w http.ResponseWriter, r *http.Request, eventHandler E
 		// 处理推送事件
 		switch decryptMsg.InfoType {
 		case EventComponentVerifyTicket:
-			if !self.Cache.Exists(ComponentTicketCacheKeyPrefix + self.AppId) {
-				_ = self.Cache.SetEx(ComponentTicketCacheKeyPrefix+self.AppId, map[string]interface{}{
-					"component_verify_ticket": decryptMsg.ComponentVerifyTicket,
-				}, 3600*10)
-			}
+			// 微信每10分钟推送一次ticket, 始终保存最新的ticket和接收时间, 供后台监控判断ticket是否停止推送
+			key := ComponentTicketCacheKeyPrefix + self.AppId
+			self.reportCacheError(key, self.Cache.SetEx(key, map[string]interface{}{
+				"component_verify_ticket": decryptMsg.ComponentVerifyTicket,
+				"received_at":             time.Now().Unix(),
+			}, 3600*10))
 			w.WriteHeader(http.StatusOK)
 			_, _ = w.Write([]byte("success"))
 			break
@@ -179,3 +207,42 @@ func (self *Server) NewTextMessage(w http.ResponseWriter, text *Text) ([]byte, e
 	}
 	return buf, nil
 }
+
+// DispatchServe 处理推送消息, 解密后交由dispatcher分发
+func (self *Server) DispatchServe(w http.ResponseWriter, r *http.Request, dispatcher *EventDispatcher) {
+	encryptType := r.URL.Query().Get("encrypt_type")
+	if encryptType == "" {
+		return
+	}
+	var plaintext []byte
+	switch encryptType {
+	default:
+		fallthrough
+	case "aes":
+		decoder := MessageDecoder{
+			Signature:    r.URL.Query().Get("signature"),
+			Timestamp:    r.URL.Query().Get("timestamp"),
+			Nonce:        r.URL.Query().Get("nonce"),
+			MsgSignature: r.URL.Query().Get("msg_signature"),
+			EncryptMsg:   self.ReadXML(r),
+		}
+		// 验证签名
+		if !decoder.VerifySignature(self.Token) {
+			return
+		}
+		// 解密消息
+		decryptMsg, err := decoder.Decrypt(self.AppId, self.AesKey)
+		if err != nil {
+			return
+		}
+		plaintext = decryptMsg
+	case "raw":
+		plaintext = self.ReadXML(r)
+	}
+	if err := dispatcher.Dispatch(plaintext); err != nil {
+		self.logger().Printf("分发推送消息失败: %v", err)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write([]byte("success"))
+}
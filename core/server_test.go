@@ -0,0 +1,173 @@
+package core
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"github.com/mrwangjinjin/go-wechat/internal/util"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+const (
+	testServerAppId  = "wx_component"
+	testServerToken  = "TOKEN"
+	testServerAesKey = "abcdefghijklmnopqrstuvwxyz0123456789ABCDEFG"
+)
+
+// memoryCache 测试用的内存缓存, 不处理过期
+type memoryCache map[string]string
+
+func (self memoryCache) Set(key string, val interface{}) error {
+	return self.SetEx(key, val, 0)
+}
+
+func (self memoryCache) SetEx(key string, val interface{}, expires int64) error {
+	value, err := json.Marshal(val)
+	if err != nil {
+		return err
+	}
+	self[key] = string(value)
+	return nil
+}
+
+func (self memoryCache) SetNX(key string, val interface{}, expires int64) (bool, error) {
+	if _, ok := self[key]; ok {
+		return false, nil
+	}
+	return true, self.SetEx(key, val, expires)
+}
+
+func (self memoryCache) Get(key string) (string, error) {
+	value, ok := self[key]
+	if !ok {
+		return "", ErrCacheMiss
+	}
+	return value, nil
+}
+
+func (self memoryCache) Exists(key string) bool {
+	_, ok := self[key]
+	return ok
+}
+
+func (self memoryCache) Delete(key string) error {
+	delete(self, key)
+	return nil
+}
+
+// failingCache SetEx总是失败, 其余操作使用内存缓存
+type failingCache struct {
+	Cache
+}
+
+func (self *failingCache) SetEx(key string, val interface{}, expires int64) error {
+	return errors.New("redis: connection refused")
+}
+
+type recordingLogger struct {
+	lines []string
+}
+
+func (self *recordingLogger) Printf(format string, v ...interface{}) {
+	self.lines = append(self.lines, fmt.Sprintf(format, v...))
+}
+
+// encryptedPush 按微信的格式加密plaintext并签名, 返回推送请求
+func encryptedPush(t *testing.T, plaintext string) *http.Request {
+	t.Helper()
+	encoder := MessageEncoder{Nonce: "NONCE", RawMsg: []byte(plaintext)}
+	encoded, err := encoder.EncodeMessage(testServerAppId, testServerToken, testServerAesKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var cipher CipherResponseHttpBody
+	if err := xml.Unmarshal([]byte(encoded), &cipher); err != nil {
+		t.Fatal(err)
+	}
+	query := url.Values{
+		"encrypt_type":  {"aes"},
+		"timestamp":     {cipher.TimeStamp},
+		"nonce":         {cipher.Nonce},
+		"signature":     {util.Sign(testServerToken, cipher.TimeStamp, cipher.Nonce)},
+		"msg_signature": {cipher.MsgSignature},
+	}
+	body := fmt.Sprintf("<xml><AppId><![CDATA[%s]]></AppId><Encrypt><![CDATA[%s]]></Encrypt></xml>", testServerAppId, cipher.Encrypt)
+	return httptest.NewRequest(http.MethodPost, "/notify?"+query.Encode(), strings.NewReader(body))
+}
+
+func TestServeReportsTicketWriteFailure(t *testing.T) {
+	logger := &recordingLogger{}
+	var reported []error
+	server := NewServer(&ClientConfig{
+		AppId:         testServerAppId,
+		Token:         testServerToken,
+		AesKey:        testServerAesKey,
+		Logger:        logger,
+		ErrorReporter: func(err error) { reported = append(reported, err) },
+	}, &failingCache{Cache: memoryCache{}})
+
+	w := httptest.NewRecorder()
+	push := `<xml><AppId><![CDATA[wx_component]]></AppId><CreateTime>1413192605</CreateTime><InfoType><![CDATA[component_verify_ticket]]></InfoType><ComponentVerifyTicket><![CDATA[TICKET]]></ComponentVerifyTicket></xml>`
+	server.Serve(w, encryptedPush(t, push), func(message *NotifyMessage) {
+		t.Fatalf("ticket push passed to event handler: %+v", message)
+	})
+
+	if w.Code != http.StatusOK || w.Body.String() != "success" {
+		t.Fatalf("got %d %q, want 200 success", w.Code, w.Body.String())
+	}
+	if len(reported) != 1 || !strings.Contains(reported[0].Error(), ComponentTicketCacheKeyPrefix+testServerAppId) {
+		t.Fatalf("reported %v, want one ticket write error", reported)
+	}
+	if len(logger.lines) != 1 || !strings.Contains(logger.lines[0], "connection refused") {
+		t.Fatalf("logged %q, want the cache error", logger.lines)
+	}
+}
+
+func TestServeStoresTicket(t *testing.T) {
+	cache := memoryCache{}
+	server := NewServer(&ClientConfig{AppId: testServerAppId, Token: testServerToken, AesKey: testServerAesKey, Logger: &recordingLogger{}}, cache)
+
+	for _, ticket := range []string{"TICKET_1", "TICKET_2"} {
+		push := fmt.Sprintf(`<xml><AppId><![CDATA[wx_component]]></AppId><InfoType><![CDATA[component_verify_ticket]]></InfoType><ComponentVerifyTicket><![CDATA[%s]]></ComponentVerifyTicket></xml>`, ticket)
+		server.Serve(httptest.NewRecorder(), encryptedPush(t, push), func(message *NotifyMessage) {})
+	}
+	stored, err := cache.Get(ComponentTicketCacheKeyPrefix + testServerAppId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 始终保存最新推送的ticket
+	if !strings.Contains(stored, `"component_verify_ticket":"TICKET_2"`) || !strings.Contains(stored, `"received_at":`) {
+		t.Fatalf("unexpected ticket record: %s", stored)
+	}
+}
+
+func TestDispatchServeLogsDispatchError(t *testing.T) {
+	logger := &recordingLogger{}
+	server := NewServer(&ClientConfig{AppId: testServerAppId, Token: testServerToken, AesKey: testServerAesKey, Logger: logger}, memoryCache{})
+	dispatcher := NewEventDispatcher()
+	var traced []*MediaCheckEvent
+	dispatcher.OnMediaCheckTrace("TRACE_1", func(event *MediaCheckEvent) { traced = append(traced, event) })
+
+	w := httptest.NewRecorder()
+	server.DispatchServe(w, encryptedPush(t, string(mediaCheckPush("TRACE_1"))), dispatcher)
+	if w.Code != http.StatusOK || w.Body.String() != "success" {
+		t.Fatalf("got %d %q, want 200 success", w.Code, w.Body.String())
+	}
+	if len(traced) != 1 || traced[0].Result.Suggest != SuggestPass {
+		t.Fatalf("traced %+v", traced)
+	}
+
+	w = httptest.NewRecorder()
+	server.DispatchServe(w, encryptedPush(t, `<xml><MsgType><![CDATA[event]]></MsgType><Event><![CDATA[wxa_media_check]]></Event><trace_id>`), dispatcher)
+	if w.Body.String() == "success" {
+		t.Fatal("malformed push acknowledged")
+	}
+	if len(logger.lines) != 1 || !strings.Contains(logger.lines[0], "分发推送消息失败") {
+		t.Fatalf("logged %q, want the dispatch error", logger.lines)
+	}
+}
@@ -24,10 +24,14 @@ func main() {
 	}
 	server := core.NewServer(config, cache)
 	http.HandleFunc("/api/notify", func(w http.ResponseWriter, r *http.Request) {
-		server.Serve(w, r)
+		server.Serve(w, r, func(message *core.NotifyMessage) {
+			log.Println(message.InfoType, message.AuthorizerAppid)
+		})
 	})
 	http.HandleFunc("/api/event", func(w http.ResponseWriter, r *http.Request) {
-		server.EventServe(w, r)
+		server.EventServe(w, r, func(message *core.EventMessage) {
+			log.Println(message.Event)
+		})
 	})
 	log.Println("Server listen at 127.0.0.1:9595")
 	err := http.ListenAndServe("127.0.0.1:9595", nil)
@@ -0,0 +1,41 @@
+package singleflight
+
+import "sync"
+
+type call struct {
+	wg  sync.WaitGroup
+	val interface{}
+	err error
+}
+
+// Group 合并同一key的并发调用, 只执行一次fn, 其余调用等待并共享结果
+type Group struct {
+	mu    sync.Mutex
+	calls map[string]*call
+}
+
+// Do 执行fn并返回结果, shared表示结果是否与其它调用共享
+func (self *Group) Do(key string, fn func() (interface{}, error)) (val interface{}, err error, shared bool) {
+	self.mu.Lock()
+	if self.calls == nil {
+		self.calls = map[string]*call{}
+	}
+	if c, ok := self.calls[key]; ok {
+		self.mu.Unlock()
+		c.wg.Wait()
+		return c.val, c.err, true
+	}
+	c := &call{}
+	c.wg.Add(1)
+	self.calls[key] = c
+	self.mu.Unlock()
+
+	defer func() {
+		self.mu.Lock()
+		delete(self.calls, key)
+		self.mu.Unlock()
+		c.wg.Done()
+	}()
+	c.val, c.err = fn()
+	return c.val, c.err, false
+}
@@ -0,0 +1,76 @@
+package util
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// MaxSceneLength 小程序码scene参数的最大长度
+const MaxSceneLength = 32
+
+// sceneChars scene允许的字符, 不含用作分隔符的=和&
+const sceneChars = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz!#$'()*+,/:;?@-._~"
+
+func checkSceneToken(v string) error {
+	for _, c := range v {
+		if !strings.ContainsRune(sceneChars, c) {
+			return fmt.Errorf("scene包含不支持的字符:%q", c)
+		}
+	}
+	return nil
+}
+
+// EncodeScene 将参数编码为k1=v1&k2=v2形式的scene, 按key排序, 超过32个字符时返回错误
+func EncodeScene(params map[string]string) (string, error) {
+	keys := make([]string, 0, len(params))
+	for k := range params {
+		if k == "" {
+			return "", errors.New("scene参数名不能为空")
+		}
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	pairs := make([]string, 0, len(keys))
+	for _, k := range keys {
+		if err := checkSceneToken(k); err != nil {
+			return "", err
+		}
+		if err := checkSceneToken(params[k]); err != nil {
+			return "", err
+		}
+		pairs = append(pairs, k+"="+params[k])
+	}
+	scene := strings.Join(pairs, "&")
+	if len(scene) > MaxSceneLength {
+		return "", fmt.Errorf("scene长度%d超过%d个字符", len(scene), MaxSceneLength)
+	}
+	return scene, nil
+}
+
+// DecodeScene 解析EncodeScene生成的scene
+func DecodeScene(scene string) (map[string]string, error) {
+	if len(scene) > MaxSceneLength {
+		return nil, fmt.Errorf("scene长度%d超过%d个字符", len(scene), MaxSceneLength)
+	}
+	params := map[string]string{}
+	if scene == "" {
+		return params, nil
+	}
+	for _, pair := range strings.Split(scene, "&") {
+		i := strings.Index(pair, "=")
+		if i <= 0 {
+			return nil, fmt.Errorf("scene格式错误:%s", pair)
+		}
+		k, v := pair[:i], pair[i+1:]
+		if err := checkSceneToken(k); err != nil {
+			return nil, err
+		}
+		if err := checkSceneToken(v); err != nil {
+			return nil, err
+		}
+		params[k] = v
+	}
+	return params, nil
+}